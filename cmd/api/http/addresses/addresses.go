@@ -32,14 +32,12 @@ func NewAddressesRouter(
 func (r *AddressesRouter) InitializeRoutes() []routing.Route {
 	listRoute := r.ListRoute()
 	findRoute := r.FindRoute()
-	createRoute := r.CreateRoute()
 	updateRoute := r.UpdateRoute()
 	deleteRoute := r.DeleteRoute()
 
 	return []routing.Route{
 		listRoute,
 		findRoute,
-		createRoute,
 		updateRoute,
 		deleteRoute,
 	}
@@ -2,6 +2,7 @@ package addresses
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -51,6 +52,20 @@ func (r *AddressesRouter) FindRoute() routing.Route {
 			}),
 	})
 
+	responses.Set("404", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.NotFoundError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.NotFoundError),
+						"message": string(constants.NotFoundErrorDetails),
+					}),
+			}),
+	})
+
 	responses.Set("500", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
 			WithJSONSchema(schemas.ErrorResponseSchema.Value).
@@ -86,7 +101,6 @@ func (r *AddressesRouter) FindRoute() routing.Route {
 		Path:   "/addresses/:id",
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
-			r.Middleware.Authorized([]string{"addresses.view"}),
 		},
 		Handler: func(c *fiber.Ctx) error {
 			var params FindParams
@@ -98,6 +112,31 @@ func (r *AddressesRouter) FindRoute() routing.Route {
 				})
 			}
 
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			owner, err := r.Services.Addresses().Owner(params.Id)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if !currentUser.CanAccess(owner, "addresses.view") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   constants.NotFoundError,
+					"message": constants.NotFoundErrorDetails,
+				})
+			}
+
 			address, err := r.Services.Addresses().Find(params.Id)
 
 			if err != nil {
@@ -114,6 +153,8 @@ func (r *AddressesRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(address.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": address,
 			})
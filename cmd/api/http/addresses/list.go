@@ -2,8 +2,10 @@ package addresses
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -93,7 +95,7 @@ func (r *AddressesRouter) ListRoute() routing.Route {
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "List Addresses",
-			Description: "List all addresses in the system.",
+			Description: "List your addresses and, with permission, those of your active organization and its members.",
 			Tags:        []string{"Addresses"},
 			Responses:   responses,
 			Parameters:  paramters,
@@ -103,7 +105,6 @@ func (r *AddressesRouter) ListRoute() routing.Route {
 		Path:   "/addresses",
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
-			r.Middleware.Authorized([]string{"addresses.view"}),
 		},
 		Handler: func(c *fiber.Ctx) error {
 			var query ListQueryParams
@@ -115,7 +116,20 @@ func (r *AddressesRouter) ListRoute() routing.Route {
 				})
 			}
 
-			totalAddresses, err := r.Services.Addresses().Count()
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			filterClauses := []clause.Expression{
+				services.OwnedBy("address_id", currentUser.Id, currentUser.ActiveOrganization, currentUser.HasPermission("addresses.view")),
+			}
+
+			totalAddresses, err := r.Services.Addresses().Count(filterClauses...)
 
 			if err != nil {
 				if err == gorm.ErrRecordNotFound {
@@ -138,6 +152,8 @@ func (r *AddressesRouter) ListRoute() routing.Route {
 				},
 			}
 
+			paginationClauses = append(paginationClauses, filterClauses...)
+
 			totalPages := (totalAddresses + int64(query.Limit) - 1) / int64(query.Limit)
 
 			addresses, err := r.Services.Addresses().List(paginationClauses...)
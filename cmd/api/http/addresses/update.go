@@ -5,6 +5,7 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -57,6 +58,48 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 			}),
 	})
 
+	responses.Set("404", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.NotFoundError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.NotFoundError),
+						"message": string(constants.NotFoundErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("412", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.PreconditionFailedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.PreconditionFailedError),
+						"message": string(constants.PreconditionFailedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("428", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.PreconditionRequiredError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.PreconditionRequiredError),
+						"message": string(constants.PreconditionRequiredErrorDetails),
+					}),
+			}),
+	})
+
 	responses.Set("500", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
 			WithJSONSchema(schemas.ErrorResponseSchema.Value).
@@ -77,6 +120,12 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 				WithRequired(true).
 				WithSchema(openapi3.NewUUIDSchema()),
 		},
+		{
+			Value: openapi3.NewHeaderParameter("If-Match").
+				WithRequired(true).
+				WithSchema(openapi3.NewStringSchema()).
+				WithDescription("ETag of the record as it was last read. The request fails when the record has changed since."),
+		},
 	}
 
 	body := &openapi3.RequestBodyRef{
@@ -103,7 +152,7 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 		Path:   "/addresses/:id",
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
-			r.Middleware.Authorized([]string{"addresses.update"}),
+			r.Middleware.IfMatch(),
 		},
 		Handler: func(c *fiber.Ctx) error {
 			var params UpdateParams
@@ -115,6 +164,31 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 				})
 			}
 
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			owner, err := r.Services.Addresses().Owner(params.Id)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if !currentUser.CanAccess(owner, "addresses.update") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   constants.NotFoundError,
+					"message": constants.NotFoundErrorDetails,
+				})
+			}
+
 			var payload models.UpdateAddressPayload
 
 			if err := c.BodyParser(&payload); err != nil {
@@ -124,7 +198,16 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 				})
 			}
 
-			if err := r.Services.Addresses().Update(params.Id, payload); err != nil {
+			version, ok := c.Locals("version").(int64)
+
+			if !ok {
+				return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+					"error":   constants.PreconditionRequiredError,
+					"message": constants.PreconditionRequiredErrorDetails,
+				})
+			}
+
+			if err := r.Services.Addresses().Update(params.Id, version, payload); err != nil {
 				if err == gorm.ErrRecordNotFound {
 					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 						"error":   constants.NotFoundError,
@@ -132,6 +215,34 @@ func (r *AddressesRouter) UpdateRoute() routing.Route {
 					})
 				}
 
+				if err == services.ErrUnsupportedCountry {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidProvince {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidPostalCode {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrVersionMismatch {
+					return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+						"error":   constants.PreconditionFailedError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
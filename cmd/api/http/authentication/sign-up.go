@@ -13,6 +13,7 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type SignUpPayload struct {
@@ -129,7 +130,7 @@ func (r *AuthenticationRouter) SignUpRoute() routing.Route {
 				},
 				Name:        "User",
 				Description: &organizationUserRoleDescription,
-				Permissions: []string{"users.view.self", "users.update.self", "users.delete.self"},
+				Permissions: []string{"users.view.self", "users.update.self", "users.delete.self", "collections.view.self"},
 			}
 
 			newUser := models.User{
@@ -146,7 +147,7 @@ func (r *AuthenticationRouter) SignUpRoute() routing.Route {
 			if payload.Email != nil {
 				emailUser, err := r.Services.Users().FindByEmail(*payload.Email)
 
-				if err != nil {
+				if err != nil && err != gorm.ErrRecordNotFound {
 					log.Errorf("🔥 Error retrieving user: %s", err.Error())
 
 					return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
@@ -155,7 +156,7 @@ func (r *AuthenticationRouter) SignUpRoute() routing.Route {
 					})
 				}
 
-				if emailUser.Id != uuid.Nil {
+				if emailUser != nil {
 					log.Warnf("⚠️ User with email %s already exists", *payload.Email)
 
 					return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
@@ -170,7 +171,7 @@ func (r *AuthenticationRouter) SignUpRoute() routing.Route {
 			if payload.Phone != nil {
 				phoneUser, err := r.Services.Users().FindByPhone(*payload.Phone)
 
-				if err != nil {
+				if err != nil && err != gorm.ErrRecordNotFound {
 					log.Errorf("🔥 Error retrieving user: %s", err.Error())
 
 					return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
@@ -179,7 +180,7 @@ func (r *AuthenticationRouter) SignUpRoute() routing.Route {
 					})
 				}
 
-				if phoneUser.Id != uuid.Nil {
+				if phoneUser != nil {
 					log.Warnf("⚠️ User with phone %s already exists", *payload.Phone)
 
 					return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
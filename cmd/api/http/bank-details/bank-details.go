@@ -30,16 +30,16 @@ func NewBankDetailsRouter(
 }
 
 func (r *BankDetailsRouter) InitializeRoutes() []routing.Route {
+	banksRoute := r.BanksRoute()
 	listRoute := r.ListRoute()
 	findRoute := r.FindRoute()
-	createRoute := r.CreateRoute()
 	updateRoute := r.UpdateRoute()
 	deleteRoute := r.DeleteRoute()
 
 	return []routing.Route{
+		banksRoute,
 		listRoute,
 		findRoute,
-		createRoute,
 		updateRoute,
 		deleteRoute,
 	}
@@ -0,0 +1,68 @@
+package bankDetails
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r *BankDetailsRouter) BanksRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful supported banks retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "List Supported Banks",
+			Description: "List the banks that bank details can be captured for, with their universal branch codes and accepted account number lengths.",
+			Tags:        []string{"Bank Details"},
+			Responses:   responses,
+			Parameters:  nil,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/bank-details/banks",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": constants.SupportedBanks,
+			})
+		},
+	}
+}
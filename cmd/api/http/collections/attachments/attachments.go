@@ -0,0 +1,46 @@
+package collectionAttachments
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type CollectionAttachmentsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewCollectionAttachmentsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) CollectionAttachmentsRouter {
+	return CollectionAttachmentsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *CollectionAttachmentsRouter) InitializeRoutes() []routing.Route {
+	listRoute := r.ListRoute()
+	uploadRoute := r.UploadRoute()
+	downloadRoute := r.DownloadRoute()
+	thumbnailRoute := r.ThumbnailRoute()
+	deleteRoute := r.DeleteRoute()
+
+	return []routing.Route{
+		listRoute,
+		uploadRoute,
+		downloadRoute,
+		thumbnailRoute,
+		deleteRoute,
+	}
+}
@@ -0,0 +1,263 @@
+package collectionAttachments
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ListParams struct {
+	CollectionId uuid.UUID `json:"collectionId"`
+}
+
+type ListQueryParams struct {
+	Page     int    `query:"page"`
+	Limit    int    `query:"limit"`
+	Category string `query:"category"`
+}
+
+func (r *CollectionAttachmentsRouter) ListRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful collection attachments retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("404", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.NotFoundError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.NotFoundError),
+						"message": string(constants.NotFoundErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewPathParameter("collectionId").
+				WithRequired(true).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+		{
+			Value: openapi3.NewQueryParameter("page").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(1).WithMin(1)).
+				WithDescription("Page number for pagination. Defaults to 1."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("limit").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(10).WithMin(10)).
+				WithDescription("Number of items per page. Defaults to 10."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("category").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("photo", "delivery_note", "id_document", "other")).
+				WithDescription("Category to filter attachments by."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "List Collection Attachments",
+			Description: "List the photos and documents attached to the collection, most recently uploaded first.",
+			Tags:        []string{"Collections"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/collections/:collectionId/attachments",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"collections.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var params ListParams
+
+			if err := c.ParamsParser(&params); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			var query ListQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			parties, err := r.Services.Attachments().Parties(models.CollectionAttachment, params.CollectionId)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if !currentUser.CanAccessAny(parties, "collections.view") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			filterClauses := []clause.Expression{
+				clause.Eq{
+					Column: clause.Column{
+						Name: "parent_type",
+					},
+					Value: models.CollectionAttachment,
+				},
+				clause.Eq{
+					Column: clause.Column{
+						Name: "parent_id",
+					},
+					Value: params.CollectionId,
+				},
+			}
+
+			if query.Category != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "category",
+					},
+					Value: query.Category,
+				})
+			}
+
+			totalAttachments, err := r.Services.Attachments().Count(filterClauses...)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			paginationClauses := []clause.Expression{
+				clause.Limit{
+					Limit:  &query.Limit,
+					Offset: (query.Page - 1) * query.Limit,
+				},
+			}
+
+			paginationClauses = append(paginationClauses, filterClauses...)
+
+			totalPages := (totalAttachments + int64(query.Limit) - 1) / int64(query.Limit)
+
+			attachments, err := r.Services.Attachments().List(paginationClauses...)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": attachments,
+				"pageDetails": map[string]any{
+					"count":        totalAttachments,
+					"nextPage":     query.Page + 1,
+					"previousPage": query.Page - 1,
+					"currentPage":  query.Page,
+					"pages":        totalPages,
+				},
+			})
+		},
+	}
+}
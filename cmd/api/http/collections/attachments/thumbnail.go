@@ -0,0 +1,216 @@
+package collectionAttachments
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ThumbnailParams struct {
+	CollectionId uuid.UUID `json:"collectionId"`
+	Id           uuid.UUID `json:"id"`
+}
+
+func (r *CollectionAttachmentsRouter) ThumbnailRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("A JPEG thumbnail of the attachment.").
+			WithContent(openapi3.Content{
+				"image/jpeg": openapi3.NewMediaType().
+					WithSchema(openapi3.NewStringSchema().WithFormat("binary")),
+			}),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("404", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.NotFoundError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.NotFoundError),
+						"message": string(constants.NotFoundErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewPathParameter("collectionId").
+				WithRequired(true).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+		{
+			Value: openapi3.NewPathParameter("id").
+				WithRequired(true).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "Download Collection Attachment Thumbnail",
+			Description: "Download the thumbnail of an image attached to the collection. Attachments that are not images have no thumbnail.",
+			Tags:        []string{"Collections"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/collections/:collectionId/attachments/:id/thumbnail",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"collections.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var params ThumbnailParams
+
+			if err := c.ParamsParser(&params); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			parties, err := r.Services.Attachments().Parties(models.CollectionAttachment, params.CollectionId)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if !currentUser.CanAccessAny(parties, "collections.view") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			attachment, err := r.Services.Attachments().Find(params.Id)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if attachment.ParentType != models.CollectionAttachment || attachment.ParentId != params.CollectionId {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   constants.NotFoundError,
+					"message": constants.NotFoundErrorDetails,
+				})
+			}
+
+			content, err := r.Services.Attachments().Open(*attachment, true)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			c.Set(fiber.HeaderContentType, "image/jpeg")
+			c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+			return c.Status(fiber.StatusOK).SendStream(content)
+		},
+	}
+}
@@ -0,0 +1,257 @@
+package collectionAttachments
+
+import (
+	"io"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UploadParams struct {
+	CollectionId uuid.UUID `json:"collectionId"`
+}
+
+func (r *CollectionAttachmentsRouter) UploadRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful attachment upload.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value).
+			WithContent(openapi3.Content{
+				"text/plain": openapi3.NewMediaType().
+					WithSchema(openapi3.NewUUIDSchema()).
+					WithExample("example", "3fa85f64-5717-4562-b3fc-2c963f66afa6"),
+			}),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("404", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.NotFoundError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.NotFoundError),
+						"message": string(constants.NotFoundErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewPathParameter("collectionId").
+				WithRequired(true).
+				WithSchema(openapi3.NewUUIDSchema()),
+		},
+	}
+
+	body := &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithDescription("Multipart form with the file to attach and optionally its category and a description.").
+			WithContent(openapi3.Content{
+				"multipart/form-data": openapi3.NewMediaType().
+					WithSchema(schemas.CreateAttachmentSchema.Value),
+			}),
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "Upload Collection Attachment",
+			Description: "Attach a photo or document of up to 10 MB to the collection. JPEG, PNG, GIF and WebP images and PDF documents are accepted, as detected from the content of the file, and images are given a thumbnail.",
+			Tags:        []string{"Collections"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: body,
+		},
+		Method: routing.PostMethod,
+		Path:   "/collections/:collectionId/attachments",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"collections.update"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var params UploadParams
+
+			if err := c.ParamsParser(&params); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			parties, err := r.Services.Attachments().Parties(models.CollectionAttachment, params.CollectionId)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			if !currentUser.CanAccessAny(parties, "collections.update") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			file, err := c.FormFile("file")
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			if file.Size > services.AttachmentMaxSize {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": services.ErrInvalidAttachmentSize.Error(),
+				})
+			}
+
+			opened, err := file.Open()
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			defer opened.Close()
+
+			content, err := io.ReadAll(opened)
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			payload := models.CreateAttachmentPayload{
+				Category: models.AttachmentCategory(c.FormValue("category")),
+			}
+
+			if description := c.FormValue("description"); description != "" {
+				payload.Description = &description
+			}
+
+			id, err := r.Services.Attachments().Create(models.CollectionAttachment, params.CollectionId, currentUser.Id, payload, file.Filename, content)
+
+			if err != nil {
+				if err == services.ErrInvalidAttachmentSize {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrUnsupportedAttachmentType {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidAttachmentCategory {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).SendString(id.String())
+		},
+	}
+}
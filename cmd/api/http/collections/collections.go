@@ -35,6 +35,11 @@ func (r *CollectionsRouter) InitializeRoutes() []routing.Route {
 	createRoute := r.CreateRoute()
 	updateRoute := r.UpdateRoute()
 	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
+	weighRoute := r.WeighRoute()
+	confirmRoute := r.ConfirmRoute()
+	payRoute := r.PayRoute()
+	voidRoute := r.VoidRoute()
 
 	return []routing.Route{
 		listRoute,
@@ -42,5 +47,10 @@ func (r *CollectionsRouter) InitializeRoutes() []routing.Route {
 		createRoute,
 		updateRoute,
 		deleteRoute,
+		restoreRoute,
+		weighRoute,
+		confirmRoute,
+		payRoute,
+		voidRoute,
 	}
 }
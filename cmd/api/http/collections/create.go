@@ -5,6 +5,7 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 )
@@ -104,6 +105,34 @@ func (r *CollectionsRouter) CreateRoute() routing.Route {
 			id, err := r.Services.Collections().Create(payload)
 
 			if err != nil {
+				if err == services.ErrInvalidSite {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrScaleReadingUnavailable {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrScaleReadingTampered {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrSellerNotVerified {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
@@ -4,6 +4,7 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -56,6 +57,34 @@ func (r *CollectionsRouter) DeleteRoute() routing.Route {
 			}),
 	})
 
+	responses.Set("412", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.PreconditionFailedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.PreconditionFailedError),
+						"message": string(constants.PreconditionFailedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("428", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.PreconditionRequiredError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.PreconditionRequiredError),
+						"message": string(constants.PreconditionRequiredErrorDetails),
+					}),
+			}),
+	})
+
 	responses.Set("500", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
 			WithJSONSchema(schemas.ErrorResponseSchema.Value).
@@ -76,6 +105,12 @@ func (r *CollectionsRouter) DeleteRoute() routing.Route {
 				WithRequired(true).
 				WithSchema(openapi3.NewUUIDSchema()),
 		},
+		{
+			Value: openapi3.NewHeaderParameter("If-Match").
+				WithRequired(true).
+				WithSchema(openapi3.NewStringSchema()).
+				WithDescription("ETag of the record as it was last read. The request fails when the record has changed since."),
+		},
 	}
 
 	return routing.Route{
@@ -92,6 +127,7 @@ func (r *CollectionsRouter) DeleteRoute() routing.Route {
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
 			r.Middleware.Authorized([]string{"collections.delete"}),
+			r.Middleware.IfMatch(),
 		},
 		Handler: func(c *fiber.Ctx) error {
 			var params DeleteParams
@@ -103,7 +139,23 @@ func (r *CollectionsRouter) DeleteRoute() routing.Route {
 				})
 			}
 
-			if err := r.Services.Collections().Delete(params.Id); err != nil {
+			version, ok := c.Locals("version").(int64)
+
+			if !ok {
+				return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+					"error":   constants.PreconditionRequiredError,
+					"message": constants.PreconditionRequiredErrorDetails,
+				})
+			}
+
+			if err := r.Services.Collections().Delete(params.Id, version); err != nil {
+				if err == services.ErrCollectionLocked {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
 				if err == gorm.ErrRecordNotFound {
 					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 						"error":   constants.NotFoundError,
@@ -111,6 +163,13 @@ func (r *CollectionsRouter) DeleteRoute() routing.Route {
 					})
 				}
 
+				if err == services.ErrVersionMismatch {
+					return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+						"error":   constants.PreconditionFailedError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
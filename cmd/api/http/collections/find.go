@@ -114,6 +114,8 @@ func (r *CollectionsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(collection.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": collection,
 			})
@@ -2,27 +2,34 @@ package collections
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/exports"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type ListQueryParams struct {
-	Page   int    `query:"page"`
-	Limit  int    `query:"limit"`
-	Search string `query:"search"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+	Search  string `query:"search"`
+	Status  string `query:"status"`
+	SiteId  string `query:"siteId"`
+	Format  string `query:"format"`
+	Columns string `query:"columns"`
 }
 
 func (r *CollectionsRouter) ListRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
+		Value: exports.WithContent(openapi3.NewResponse().
 			WithDescription("Successful collections retrieval.").
-			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+			WithJSONSchema(schemas.SuccessResponseSchema.Value)),
 	})
 
 	responses.Set("400", &openapi3.ResponseRef{
@@ -88,8 +95,21 @@ func (r *CollectionsRouter) ListRoute() routing.Route {
 				WithSchema(openapi3.NewStringSchema()).
 				WithDescription("Search term for filtering collections."),
 		},
+		{
+			Value: openapi3.NewQueryParameter("status").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("draft", "weighed", "confirmed", "paid", "voided")).
+				WithDescription("Lifecycle status to filter collections by."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("siteId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Site to filter collections by."),
+		},
 	}
 
+	paramters = append(paramters, exports.Parameters(exports.CollectionLineColumns)...)
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "List Collections",
@@ -115,7 +135,68 @@ func (r *CollectionsRouter) ListRoute() routing.Route {
 				})
 			}
 
-			totalCollections, err := r.Services.Collections().Count()
+			filterClauses := []clause.Expression{}
+
+			if query.Status != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "status",
+					},
+					Value: query.Status,
+				})
+			}
+
+			if query.SiteId != "" {
+				siteId, err := uuid.Parse(query.SiteId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "site_id",
+					},
+					Value: siteId,
+				})
+			}
+
+			format, err := exports.Requested(c, query.Format)
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": err.Error(),
+				})
+			}
+
+			if format != "" {
+				columns, err := exports.Select(exports.CollectionLineColumns, query.Columns)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return exports.Send(c, format, "collections", columns, func(handle func(exports.CollectionLine) error) error {
+					return r.Services.Collections().Stream(func(collection models.Collection) error {
+						for _, line := range exports.CollectionLines(collection) {
+							if err := handle(line); err != nil {
+								return err
+							}
+						}
+
+						return nil
+					}, filterClauses...)
+				})
+			}
+
+			totalCollections, err := r.Services.Collections().Count(filterClauses...)
 
 			if err != nil {
 				if err == gorm.ErrRecordNotFound {
@@ -138,6 +219,8 @@ func (r *CollectionsRouter) ListRoute() routing.Route {
 				},
 			}
 
+			paginationClauses = append(paginationClauses, filterClauses...)
+
 			totalPages := (totalCollections + int64(query.Limit) - 1) / int64(query.Limit)
 
 			collections, err := r.Services.Collections().List(paginationClauses...)
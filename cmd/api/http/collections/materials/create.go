@@ -5,9 +5,11 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type CreateParams struct {
@@ -123,9 +125,46 @@ func (r *CollectionMaterialsRouter) CreateRoute() routing.Route {
 				})
 			}
 
+			payload.CollectionId = params.CollectionId
+
 			id, err := r.Services.Collections().Materials().Create(payload)
 
 			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				if err == services.ErrCollectionLocked {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrScaleReadingUnavailable {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrScaleReadingTampered {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrSellerNotVerified {
+					return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+						"error":   constants.ConflictError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
@@ -120,6 +120,8 @@ func (r *CollectionMaterialsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(material.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": material,
 			})
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type EventsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewEventsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) EventsRouter {
+	return EventsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *EventsRouter) InitializeRoutes() []routing.Route {
+	streamRoute := r.StreamRoute()
+
+	return []routing.Route{
+		streamRoute,
+	}
+}
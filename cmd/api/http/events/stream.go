@@ -0,0 +1,319 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	// heartbeatInterval is how often a comment is sent on an idle stream so
+	// that proxies keep it open and disconnected clients are noticed.
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelay is how long clients wait before reconnecting a stream
+	// that was closed, in milliseconds.
+	reconnectDelay = 3000
+)
+
+type StreamQueryParams struct {
+	Resources   string `query:"resources"`
+	LastEventId string `query:"lastEventId"`
+}
+
+func (r *EventsRouter) StreamRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("A stream of server-sent events. Each change is sent as an event named after its resource with the change as its data. A reset event is sent when the stream could not be resumed, after which clients should reload what they show.").
+			WithContent(openapi3.Content{
+				"text/event-stream": openapi3.NewMediaType().
+					WithSchema(schemas.ChangeEventSchema.Value),
+			}),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("resources").
+				WithSchema(openapi3.NewStringSchema()).
+				WithDescription("Comma separated resources to stream the changes of: collections, transactions and inventory. Defaults to every resource the user may view."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("lastEventId").
+				WithSchema(openapi3.NewInt64Schema()).
+				WithDescription("The id of the last event received, for clients that cannot send the Last-Event-ID header."),
+		},
+		{
+			Value: openapi3.NewHeaderParameter("Last-Event-ID").
+				WithSchema(openapi3.NewInt64Schema()).
+				WithDescription("The id of the last event received, sent by browsers when they reconnect. Changes made since are sent first, along with changes made shortly before that may have been committed after it. Clients ignore events whose ids they have already received."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "Stream Changes",
+			Description: "Stream the changes to the collections, transactions and inventory of the active organization as server-sent events. Only changes to resources the user may view are sent.",
+			Tags:        []string{"Events"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/events/stream",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var query StreamQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			requested := []models.ChangeResource{}
+
+			if query.Resources != "" {
+				for _, resource := range strings.Split(query.Resources, ",") {
+					resource := models.ChangeResource(strings.TrimSpace(resource))
+
+					if _, ok := models.ChangeResources[resource]; !ok {
+						return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+							"error":   constants.BadRequestError,
+							"message": fmt.Sprintf("Unknown resource %q.", resource),
+						})
+					}
+
+					requested = append(requested, resource)
+				}
+			}
+
+			resources := []models.ChangeResource{}
+
+			for resource, permission := range models.ChangeResources {
+				if len(requested) > 0 && !slices.Contains(requested, resource) {
+					continue
+				}
+
+				if currentUser.HasPermission(permission) {
+					resources = append(resources, resource)
+				}
+			}
+
+			if len(resources) == 0 {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			lastEventId := c.Get("Last-Event-ID", query.LastEventId)
+
+			var afterId int64
+
+			if lastEventId != "" {
+				parsed, err := strconv.ParseInt(lastEventId, 10, 64)
+
+				if err != nil || parsed < 0 {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				afterId = parsed
+			}
+
+			// Subscribing before reading the changes to resume from means no
+			// change is missed between the two.
+			subscription := r.Services.Changes().Subscribe(currentUser.ActiveOrganization)
+
+			var missed []models.ChangeEvent
+
+			reset := false
+			resetId := int64(0)
+
+			if lastEventId != "" {
+				events, complete, err := r.Services.Changes().Resume(currentUser.ActiveOrganization, afterId, resources)
+
+				if err == nil && !complete {
+					resetId, err = r.Services.Changes().Latest()
+				}
+
+				if err != nil {
+					subscription.Close()
+
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+						"error":   constants.InternalServerError,
+						"message": constants.InternalServerErrorDetails,
+					})
+				}
+
+				missed = events
+				reset = !complete
+			}
+
+			c.Set(fiber.HeaderContentType, "text/event-stream")
+			c.Set(fiber.HeaderCacheControl, "no-cache")
+			c.Set(fiber.HeaderConnection, "keep-alive")
+			c.Set("X-Accel-Buffering", "no")
+
+			c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+				defer subscription.Close()
+
+				fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay)
+
+				if reset {
+					fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", resetId)
+				}
+
+				sent := map[int64]struct{}{}
+
+				for _, event := range missed {
+					if err := writeChangeEvent(w, event); err != nil {
+						return
+					}
+
+					sent[event.Id] = struct{}{}
+				}
+
+				if err := w.Flush(); err != nil {
+					return
+				}
+
+				heartbeat := time.NewTicker(heartbeatInterval)
+
+				defer heartbeat.Stop()
+
+				for {
+					select {
+					case event, ok := <-subscription.Events:
+						// The subscription is closed when the client falls
+						// behind, after which it reconnects and resumes.
+						if !ok {
+							return
+						}
+
+						if !slices.Contains(resources, event.Resource) {
+							continue
+						}
+
+						if _, ok := sent[event.Id]; ok {
+							continue
+						}
+
+						if err := writeChangeEvent(w, event); err != nil {
+							return
+						}
+					case <-heartbeat.C:
+						if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+							return
+						}
+					}
+
+					if err := w.Flush(); err != nil {
+						return
+					}
+				}
+			})
+
+			return nil
+		},
+	}
+}
+
+// writeChangeEvent writes a change as a server-sent event named after its
+// resource, with its id so that the stream can be resumed after it.
+func writeChangeEvent(w *bufio.Writer, event models.ChangeEvent) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		log.Errorf("🔥 Failed to encode change event %d: %s", event.Id, err.Error())
+
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Resource, data)
+
+	return err
+}
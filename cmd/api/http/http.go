@@ -3,20 +3,43 @@ package http
 import (
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/addresses"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/authentication"
 	bankDetails "github.com/connor-davis/threereco-nextgen/cmd/api/http/bank-details"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/collections"
+	collectionAttachments "github.com/connor-davis/threereco-nextgen/cmd/api/http/collections/attachments"
 	collectionMaterials "github.com/connor-davis/threereco-nextgen/cmd/api/http/collections/materials"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/events"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/imports"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/inventory"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/jobs"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/materials"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/me"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/organizations"
+	organizationAddress "github.com/connor-davis/threereco-nextgen/cmd/api/http/organizations/address"
+	organizationAttachments "github.com/connor-davis/threereco-nextgen/cmd/api/http/organizations/attachments"
+	organizationBankDetails "github.com/connor-davis/threereco-nextgen/cmd/api/http/organizations/bank-details"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/payouts"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/permissions"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/pickups"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/reports"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/roles"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/scales"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/sites"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/sync"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/transactions"
+	transactionAttachments "github.com/connor-davis/threereco-nextgen/cmd/api/http/transactions/attachments"
 	transactionMaterials "github.com/connor-davis/threereco-nextgen/cmd/api/http/transactions/materials"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/trash"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/users"
+	userAddress "github.com/connor-davis/threereco-nextgen/cmd/api/http/users/address"
+	userAttachments "github.com/connor-davis/threereco-nextgen/cmd/api/http/users/attachments"
+	userBankDetails "github.com/connor-davis/threereco-nextgen/cmd/api/http/users/bank-details"
+	userIdentity "github.com/connor-davis/threereco-nextgen/cmd/api/http/users/identity"
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/webhooks"
 	"github.com/connor-davis/threereco-nextgen/env"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
@@ -27,6 +50,15 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/session"
 )
 
+// idempotencyKeyParameter documents the Idempotency-Key header accepted by
+// every POST route, which is ignored by the routes that need no
+// authentication.
+var idempotencyKeyParameter = &openapi3.ParameterRef{
+	Value: openapi3.NewHeaderParameter(middleware.IdempotencyKeyHeader).
+		WithSchema(openapi3.NewStringSchema().WithMaxLength(255)).
+		WithDescription("Unique key for the request, such as a UUID. Retries of the request with the same key are answered with the first response instead of being applied again, and reusing the key for a different request fails with 409 Conflict. Ignored for requests that are not authenticated."),
+}
+
 // HttpRouter encapsulates the dependencies and configuration required to set up HTTP routing.
 // It includes references to storage, session management, service layer, middleware, and route definitions.
 type HttpRouter struct {
@@ -71,18 +103,84 @@ func NewHttpRouter(storage storage.Storage, sessions session.Store, services ser
 	collectionMaterialsRouter := collectionMaterials.NewCollectionMaterialsRouter(storage, sessions, services, middleware)
 	collectionMaterialsRoutes := collectionMaterialsRouter.InitializeRoutes()
 
+	collectionAttachmentsRouter := collectionAttachments.NewCollectionAttachmentsRouter(storage, sessions, services, middleware)
+	collectionAttachmentsRoutes := collectionAttachmentsRouter.InitializeRoutes()
+
 	transactionsRouter := transactions.NewTransactionsRouter(storage, sessions, services, middleware)
 	transactionsRoutes := transactionsRouter.InitializeRoutes()
 
 	transactionMaterialsRouter := transactionMaterials.NewTransactionsRouter(storage, sessions, services, middleware)
 	transactionMaterialsRoutes := transactionMaterialsRouter.InitializeRoutes()
 
+	transactionAttachmentsRouter := transactionAttachments.NewTransactionAttachmentsRouter(storage, sessions, services, middleware)
+	transactionAttachmentsRoutes := transactionAttachmentsRouter.InitializeRoutes()
+
+	sitesRouter := sites.NewSitesRouter(storage, sessions, services, middleware)
+	sitesRoutes := sitesRouter.InitializeRoutes()
+
+	inventoryRouter := inventory.NewInventoryRouter(storage, sessions, services, middleware)
+	inventoryRoutes := inventoryRouter.InitializeRoutes()
+
+	payoutsRouter := payouts.NewPayoutsRouter(storage, sessions, services, middleware)
+	payoutsRoutes := payoutsRouter.InitializeRoutes()
+
+	pickupsRouter := pickups.NewPickupsRouter(storage, sessions, services, middleware)
+	pickupsRoutes := pickupsRouter.InitializeRoutes()
+
+	meRouter := me.NewMeRouter(storage, sessions, services, middleware)
+	meRoutes := meRouter.InitializeRoutes()
+
+	reportsRouter := reports.NewReportsRouter(storage, sessions, services, middleware)
+	reportsRoutes := reportsRouter.InitializeRoutes()
+
+	importsRouter := imports.NewImportsRouter(storage, sessions, services, middleware)
+	importsRoutes := importsRouter.InitializeRoutes()
+
+	jobsRouter := jobs.NewJobsRouter(storage, sessions, services, middleware)
+	jobsRoutes := jobsRouter.InitializeRoutes()
+
+	webhooksRouter := webhooks.NewWebhooksRouter(storage, sessions, services, middleware)
+	webhooksRoutes := webhooksRouter.InitializeRoutes()
+
+	eventsRouter := events.NewEventsRouter(storage, sessions, services, middleware)
+	eventsRoutes := eventsRouter.InitializeRoutes()
+
+	scalesRouter := scales.NewScalesRouter(storage, sessions, services, middleware)
+	scalesRoutes := scalesRouter.InitializeRoutes()
+
 	addressesRouter := addresses.NewAddressesRouter(storage, sessions, services, middleware)
 	addressesRoutes := addressesRouter.InitializeRoutes()
 
 	bankDetailsRouter := bankDetails.NewBankDetailsRouter(storage, sessions, services, middleware)
 	bankDetailsRoutes := bankDetailsRouter.InitializeRoutes()
 
+	userAddressRouter := userAddress.NewUserAddressRouter(storage, sessions, services, middleware)
+	userAddressRoutes := userAddressRouter.InitializeRoutes()
+
+	userBankDetailsRouter := userBankDetails.NewUserBankDetailsRouter(storage, sessions, services, middleware)
+	userBankDetailsRoutes := userBankDetailsRouter.InitializeRoutes()
+
+	userAttachmentsRouter := userAttachments.NewUserAttachmentsRouter(storage, sessions, services, middleware)
+	userAttachmentsRoutes := userAttachmentsRouter.InitializeRoutes()
+
+	userIdentityRouter := userIdentity.NewUserIdentityRouter(storage, sessions, services, middleware)
+	userIdentityRoutes := userIdentityRouter.InitializeRoutes()
+
+	organizationAddressRouter := organizationAddress.NewOrganizationAddressRouter(storage, sessions, services, middleware)
+	organizationAddressRoutes := organizationAddressRouter.InitializeRoutes()
+
+	organizationBankDetailsRouter := organizationBankDetails.NewOrganizationBankDetailsRouter(storage, sessions, services, middleware)
+	organizationBankDetailsRoutes := organizationBankDetailsRouter.InitializeRoutes()
+
+	organizationAttachmentsRouter := organizationAttachments.NewOrganizationAttachmentsRouter(storage, sessions, services, middleware)
+	organizationAttachmentsRoutes := organizationAttachmentsRouter.InitializeRoutes()
+
+	trashRouter := trash.NewTrashRouter(storage, sessions, services, middleware)
+	trashRoutes := trashRouter.InitializeRoutes()
+
+	syncRouter := sync.NewSyncRouter(storage, sessions, services, middleware)
+	syncRoutes := syncRouter.InitializeRoutes()
+
 	permissionsRouter := permissions.NewPermissionsRouter(storage, sessions, services, middleware)
 	permissionsRoutes := permissionsRouter.InitializeRoutes()
 
@@ -95,10 +193,32 @@ func NewHttpRouter(storage storage.Storage, sessions session.Store, services ser
 	routes = append(routes, organizationsRoutes...)
 	routes = append(routes, collectionsRoutes...)
 	routes = append(routes, collectionMaterialsRoutes...)
+	routes = append(routes, collectionAttachmentsRoutes...)
 	routes = append(routes, transactionsRoutes...)
 	routes = append(routes, transactionMaterialsRoutes...)
+	routes = append(routes, transactionAttachmentsRoutes...)
+	routes = append(routes, sitesRoutes...)
+	routes = append(routes, inventoryRoutes...)
+	routes = append(routes, payoutsRoutes...)
+	routes = append(routes, pickupsRoutes...)
+	routes = append(routes, meRoutes...)
+	routes = append(routes, reportsRoutes...)
+	routes = append(routes, importsRoutes...)
+	routes = append(routes, jobsRoutes...)
+	routes = append(routes, webhooksRoutes...)
+	routes = append(routes, eventsRoutes...)
+	routes = append(routes, scalesRoutes...)
 	routes = append(routes, addressesRoutes...)
 	routes = append(routes, bankDetailsRoutes...)
+	routes = append(routes, userAddressRoutes...)
+	routes = append(routes, userBankDetailsRoutes...)
+	routes = append(routes, userAttachmentsRoutes...)
+	routes = append(routes, userIdentityRoutes...)
+	routes = append(routes, organizationAddressRoutes...)
+	routes = append(routes, organizationBankDetailsRoutes...)
+	routes = append(routes, organizationAttachmentsRoutes...)
+	routes = append(routes, trashRoutes...)
+	routes = append(routes, syncRoutes...)
 	routes = append(routes, permissionsRoutes...)
 
 	return HttpRouter{
@@ -114,6 +234,8 @@ func NewHttpRouter(storage storage.Storage, sessions session.Store, services ser
 // It converts route paths from the format "{param}" to Fiber's ":param" syntax using regular expressions.
 // For each route, it attaches the specified middlewares and handler to the corresponding HTTP method.
 // Supported methods include GET, POST, PUT, PATCH, OPTIONS, and DELETE.
+// POST routes are made idempotent, so that they can be retried with an
+// Idempotency-Key header without being applied twice.
 func (r *HttpRouter) InitializeRoutes(router fiber.Router) {
 	for _, route := range r.Routes {
 		path := regexp.MustCompile(`\{([^}]+)\}`).ReplaceAllString(route.Path, ":$1")
@@ -122,7 +244,7 @@ func (r *HttpRouter) InitializeRoutes(router fiber.Router) {
 		case routing.GetMethod:
 			router.Get(path, append(route.Middlewares, route.Handler)...)
 		case routing.PostMethod:
-			router.Post(path, append(route.Middlewares, route.Handler)...)
+			router.Post(path, append(slices.Clone(route.Middlewares), r.Middleware.Idempotent(), route.Handler)...)
 		case routing.PutMethod:
 			router.Put(path, append(route.Middlewares, route.Handler)...)
 		case routing.PatchMethod:
@@ -164,7 +286,7 @@ func (h *HttpRouter) InitializeOpenAPI() *openapi3.T {
 				Summary:     route.Summary,
 				Description: route.Description,
 				Tags:        route.Tags,
-				Parameters:  route.Parameters,
+				Parameters:  append(slices.Clone(route.Parameters), idempotencyKeyParameter),
 				RequestBody: route.RequestBody,
 				Responses:   route.Responses,
 			}
@@ -247,48 +369,132 @@ func (h *HttpRouter) InitializeOpenAPI() *openapi3.T {
 		Paths: paths,
 		Components: &openapi3.Components{
 			Schemas: openapi3.Schemas{
-				"SuccessResponse":           schemas.SuccessResponseSchema,
-				"ErrorResponse":             schemas.ErrorResponseSchema,
-				"AvailablePermissions":      schemas.AvailablePermissionsSchema,
-				"MfaVerifyPayload":          schemas.MfaVerifyPayloadSchema,
-				"LoginPayload":              schemas.LoginPayloadSchema,
-				"SignUpPayload":             schemas.SignUpPayloadSchema,
-				"User":                      schemas.UserSchema,
-				"Users":                     schemas.UsersSchema,
-				"Role":                      schemas.RoleSchema,
-				"Roles":                     schemas.RolesSchema,
-				"Organization":              schemas.OrganizationSchema,
-				"Organizations":             schemas.OrganizationsSchema,
-				"Material":                  schemas.MaterialSchema,
-				"Materials":                 schemas.MaterialsSchema,
-				"Address":                   schemas.AddressSchema,
-				"Addresses":                 schemas.AddressesSchema,
-				"BankDetail":                schemas.BankDetailSchema,
-				"BankDetails":               schemas.BankDetailsSchema,
-				"Collection":                schemas.CollectionSchema,
-				"Collections":               schemas.CollectionsSchema,
-				"Transaction":               schemas.TransactionSchema,
-				"Transactions":              schemas.TransactionsSchema,
-				"CreateUser":                schemas.CreateUserSchema,
-				"UpdateUser":                schemas.UpdateUserSchema,
-				"CreateRole":                schemas.CreateRoleSchema,
-				"UpdateRole":                schemas.UpdateRoleSchema,
-				"CreateOrganization":        schemas.CreateOrganizationSchema,
-				"UpdateOrganization":        schemas.UpdateOrganizationSchema,
-				"CreateMaterial":            schemas.CreateMaterialSchema,
-				"UpdateMaterial":            schemas.UpdateMaterialSchema,
-				"CreateCollection":          schemas.CreateCollectionSchema,
-				"UpdateCollection":          schemas.UpdateCollectionSchema,
-				"CreateCollectionMaterial":  schemas.CreateCollectionMaterialSchema,
-				"UpdateCollectionMaterial":  schemas.UpdateCollectionMaterialSchema,
-				"CreateTransaction":         schemas.CreateTransactionSchema,
-				"UpdateTransaction":         schemas.UpdateTransactionSchema,
-				"CreateTransactionMaterial": schemas.CreateTransactionMaterialSchema,
-				"UpdateTransactionMaterial": schemas.UpdateTransactionMaterialSchema,
-				"CreateAddress":             schemas.CreateAddressSchema,
-				"UpdateAddress":             schemas.UpdateAddressSchema,
-				"CreateBankDetail":          schemas.CreateBankDetailSchema,
-				"UpdateBankDetail":          schemas.UpdateBankDetailSchema,
+				"SuccessResponse":              schemas.SuccessResponseSchema,
+				"ErrorResponse":                schemas.ErrorResponseSchema,
+				"AvailablePermissions":         schemas.AvailablePermissionsSchema,
+				"MfaVerifyPayload":             schemas.MfaVerifyPayloadSchema,
+				"LoginPayload":                 schemas.LoginPayloadSchema,
+				"SignUpPayload":                schemas.SignUpPayloadSchema,
+				"User":                         schemas.UserSchema,
+				"Users":                        schemas.UsersSchema,
+				"Role":                         schemas.RoleSchema,
+				"Roles":                        schemas.RolesSchema,
+				"Organization":                 schemas.OrganizationSchema,
+				"Organizations":                schemas.OrganizationsSchema,
+				"Material":                     schemas.MaterialSchema,
+				"Materials":                    schemas.MaterialsSchema,
+				"Address":                      schemas.AddressSchema,
+				"Addresses":                    schemas.AddressesSchema,
+				"BankDetail":                   schemas.BankDetailSchema,
+				"BankDetails":                  schemas.BankDetailsSchema,
+				"Bank":                         schemas.BankSchema,
+				"Banks":                        schemas.BanksSchema,
+				"Collection":                   schemas.CollectionSchema,
+				"Collections":                  schemas.CollectionsSchema,
+				"Transaction":                  schemas.TransactionSchema,
+				"Transactions":                 schemas.TransactionsSchema,
+				"CreateUser":                   schemas.CreateUserSchema,
+				"UpdateUser":                   schemas.UpdateUserSchema,
+				"UserIdentity":                 schemas.UserIdentitySchema,
+				"ReviewUserIdentity":           schemas.ReviewUserIdentitySchema,
+				"CreateRole":                   schemas.CreateRoleSchema,
+				"UpdateRole":                   schemas.UpdateRoleSchema,
+				"CreateOrganization":           schemas.CreateOrganizationSchema,
+				"UpdateOrganization":           schemas.UpdateOrganizationSchema,
+				"CreateMaterial":               schemas.CreateMaterialSchema,
+				"UpdateMaterial":               schemas.UpdateMaterialSchema,
+				"CreateCollection":             schemas.CreateCollectionSchema,
+				"UpdateCollection":             schemas.UpdateCollectionSchema,
+				"VoidCollection":               schemas.VoidCollectionSchema,
+				"CollectionTransition":         schemas.CollectionTransitionSchema,
+				"CreateCollectionMaterial":     schemas.CreateCollectionMaterialSchema,
+				"UpdateCollectionMaterial":     schemas.UpdateCollectionMaterialSchema,
+				"CreateTransaction":            schemas.CreateTransactionSchema,
+				"UpdateTransaction":            schemas.UpdateTransactionSchema,
+				"TransactionTransition":        schemas.TransactionTransitionSchema,
+				"TransactionTransitionPayload": schemas.TransactionTransitionPayloadSchema,
+				"DeliverTransaction":           schemas.DeliverTransactionSchema,
+				"Invoice":                      schemas.InvoiceSchema,
+				"SettleInvoice":                schemas.SettleInvoiceSchema,
+				"Site":                         schemas.SiteSchema,
+				"Sites":                        schemas.SitesSchema,
+				"CreateSite":                   schemas.CreateSiteSchema,
+				"UpdateSite":                   schemas.UpdateSiteSchema,
+				"InventoryEntry":               schemas.InventoryEntrySchema,
+				"InventoryEntries":             schemas.InventoryEntriesSchema,
+				"InventoryBalance":             schemas.InventoryBalanceSchema,
+				"InventoryBalances":            schemas.InventoryBalancesSchema,
+				"CreateInventoryAdjustment":    schemas.CreateInventoryAdjustmentSchema,
+				"PayoutBatch":                  schemas.PayoutBatchSchema,
+				"PayoutBatches":                schemas.PayoutBatchesSchema,
+				"Payout":                       schemas.PayoutSchema,
+				"Payouts":                      schemas.PayoutsSchema,
+				"CreatePayoutBatch":            schemas.CreatePayoutBatchSchema,
+				"CreateCashPayout":             schemas.CreateCashPayoutSchema,
+				"ReversePayout":                schemas.ReversePayoutSchema,
+				"Pickup":                       schemas.PickupSchema,
+				"Pickups":                      schemas.PickupsSchema,
+				"PickupMaterial":               schemas.PickupMaterialSchema,
+				"CreatePickup":                 schemas.CreatePickupSchema,
+				"AssignPickup":                 schemas.AssignPickupSchema,
+				"CancelPickup":                 schemas.CancelPickupSchema,
+				"CompletePickup":               schemas.CompletePickupSchema,
+				"PickupRoute":                  schemas.PickupRouteSchema,
+				"MaterialTotal":                schemas.MaterialTotalSchema,
+				"PeriodTotal":                  schemas.PeriodTotalSchema,
+				"SellerEarnings":               schemas.SellerEarningsSchema,
+				"SellerImpact":                 schemas.SellerImpactSchema,
+				"VolumeReport":                 schemas.VolumeReportSchema,
+				"VolumeTotal":                  schemas.VolumeTotalSchema,
+				"VolumeBucket":                 schemas.VolumeBucketSchema,
+				"EprReport":                    schemas.EprReportSchema,
+				"EprReports":                   schemas.EprReportsSchema,
+				"CreateEprReport":              schemas.CreateEprReportSchema,
+				"ImportJob":                    schemas.ImportJobSchema,
+				"ImportJobs":                   schemas.ImportJobsSchema,
+				"ImportRowError":               schemas.ImportRowErrorSchema,
+				"ImportField":                  schemas.ImportFieldSchema,
+				"ImportFields":                 schemas.ImportFieldsSchema,
+				"CreateImport":                 schemas.CreateImportSchema,
+				"UpdateImportMapping":          schemas.UpdateImportMappingSchema,
+				"Job":                          schemas.JobSchema,
+				"Jobs":                         schemas.JobsSchema,
+				"JobSchedule":                  schemas.JobScheduleSchema,
+				"JobSchedules":                 schemas.JobSchedulesSchema,
+				"WebhookEndpoint":              schemas.WebhookEndpointSchema,
+				"WebhookEndpoints":             schemas.WebhookEndpointsSchema,
+				"WebhookEventDescription":      schemas.WebhookEventDescriptionSchema,
+				"WebhookEventDescriptions":     schemas.WebhookEventDescriptionsSchema,
+				"WebhookEvent":                 schemas.WebhookEventSchema,
+				"WebhookDelivery":              schemas.WebhookDeliverySchema,
+				"WebhookDeliveries":            schemas.WebhookDeliveriesSchema,
+				"WebhookDeliveryAttempt":       schemas.WebhookDeliveryAttemptSchema,
+				"Scale":                        schemas.ScaleSchema,
+				"Scales":                       schemas.ScalesSchema,
+				"CreateScale":                  schemas.CreateScaleSchema,
+				"UpdateScale":                  schemas.UpdateScaleSchema,
+				"ScaleReading":                 schemas.ScaleReadingSchema,
+				"ScaleReadings":                schemas.ScaleReadingsSchema,
+				"RecordScaleReading":           schemas.RecordScaleReadingSchema,
+				"ChangeEvent":                  schemas.ChangeEventSchema,
+				"TrashItem":                    schemas.TrashItemSchema,
+				"TrashItems":                   schemas.TrashItemsSchema,
+				"SyncCollection":               schemas.SyncCollectionSchema,
+				"SyncCollections":              schemas.SyncCollectionsSchema,
+				"SyncCollectionResult":         schemas.SyncCollectionResultSchema,
+				"SyncCollectionResults":        schemas.SyncCollectionResultsSchema,
+				"SyncChanges":                  schemas.SyncChangesSchema,
+				"Attachment":                   schemas.AttachmentSchema,
+				"Attachments":                  schemas.AttachmentsSchema,
+				"CreateAttachment":             schemas.CreateAttachmentSchema,
+				"CreateWebhookEndpoint":        schemas.CreateWebhookEndpointSchema,
+				"UpdateWebhookEndpoint":        schemas.UpdateWebhookEndpointSchema,
+				"CreateTransactionMaterial":    schemas.CreateTransactionMaterialSchema,
+				"UpdateTransactionMaterial":    schemas.UpdateTransactionMaterialSchema,
+				"CreateAddress":                schemas.CreateAddressSchema,
+				"UpdateAddress":                schemas.UpdateAddressSchema,
+				"CreateBankDetail":             schemas.CreateBankDetailSchema,
+				"UpdateBankDetail":             schemas.UpdateBankDetailSchema,
 			},
 		},
 	}
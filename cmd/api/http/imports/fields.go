@@ -1,26 +1,26 @@
-package bankDetails
+package imports
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 )
 
-func (r *BankDetailsRouter) CreateRoute() routing.Route {
+type FieldsQueryParams struct {
+	Kind string `query:"kind"`
+}
+
+func (r *ImportsRouter) FieldsRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithDescription("Successful bank details creation.").
-			WithJSONSchema(schemas.SuccessResponseSchema.Value).
-			WithContent(openapi3.Content{
-				"text/plain": openapi3.NewMediaType().
-					WithSchema(openapi3.NewUUIDSchema()).
-					WithExample("example", "3fa85f64-5717-4562-b3fc-2c963f66afa6"),
-			}),
+			WithDescription("Successful import fields retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
 	})
 
 	responses.Set("400", &openapi3.ResponseRef{
@@ -65,52 +65,59 @@ func (r *BankDetailsRouter) CreateRoute() routing.Route {
 			}),
 	})
 
-	body := &openapi3.RequestBodyRef{
-		Value: openapi3.NewRequestBody().
-			WithRequired(true).
-			WithDescription("Payload to create new bank details.").
-			WithContent(openapi3.Content{
-				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.CreateBankDetailSchema.Value).
-					WithExample("example", schemas.CreateBankDetailSchema.Value),
-			}),
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("kind").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("materials", "users", "collections")).
+				WithDescription("Kind of import to list the fields of."),
+		},
 	}
 
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
-			Summary:     "Create Bank Details",
-			Description: "Create new bank details in the system.",
-			Tags:        []string{"Bank Details"},
+			Summary:     "List Import Fields",
+			Description: "List the fields of the records of an import kind that the columns of a file can be mapped to.",
+			Tags:        []string{"Imports"},
 			Responses:   responses,
-			Parameters:  nil,
-			RequestBody: body,
+			Parameters:  parameters,
+			RequestBody: nil,
 		},
-		Method: routing.PostMethod,
-		Path:   "/bank-details",
+		Method: routing.GetMethod,
+		Path:   "/imports/fields",
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
-			r.Middleware.Authorized([]string{"bank_details.create"}),
+			r.Middleware.Authorized([]string{"imports.view"}),
 		},
 		Handler: func(c *fiber.Ctx) error {
-			var payload models.CreateBankDetailsPayload
+			var query FieldsQueryParams
 
-			if err := c.BodyParser(&payload); err != nil {
+			if err := c.QueryParser(&query); err != nil {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 					"error":   constants.BadRequestError,
 					"message": constants.BadRequestErrorDetails,
 				})
 			}
 
-			id, err := r.Services.BankDetails().Create(payload)
+			fields, err := r.Services.Imports().Fields(models.ImportKind(query.Kind))
 
 			if err != nil {
+				if err == services.ErrInvalidImportKind {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
 				})
 			}
 
-			return c.Status(fiber.StatusOK).SendString(id.String())
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": fields,
+			})
 		},
 	}
 }
@@ -0,0 +1,50 @@
+package imports
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type ImportsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewImportsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) ImportsRouter {
+	return ImportsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *ImportsRouter) InitializeRoutes() []routing.Route {
+	fieldsRoute := r.FieldsRoute()
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateMappingRoute := r.UpdateMappingRoute()
+	applyRoute := r.ApplyRoute()
+	errorsRoute := r.ErrorsRoute()
+
+	return []routing.Route{
+		fieldsRoute,
+		listRoute,
+		findRoute,
+		createRoute,
+		updateMappingRoute,
+		applyRoute,
+		errorsRoute,
+	}
+}
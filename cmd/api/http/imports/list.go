@@ -0,0 +1,202 @@
+package imports
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+type ListQueryParams struct {
+	Page   int    `query:"page"`
+	Limit  int    `query:"limit"`
+	Kind   string `query:"kind"`
+	Status string `query:"status"`
+}
+
+func (r *ImportsRouter) ListRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful imports retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("page").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(1).WithMin(1)).
+				WithDescription("Page number for pagination. Defaults to 1."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("limit").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(10).WithMin(10)).
+				WithDescription("Number of items per page. Defaults to 10."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("kind").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("materials", "users", "collections")).
+				WithDescription("Kind of import to filter by."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("status").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("mapping", "validating", "validated", "invalid", "applying", "applied", "failed")).
+				WithDescription("Status to filter imports by."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "List Imports",
+			Description: "List the imports of your active organization with their progress, most recent first.",
+			Tags:        []string{"Imports"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/imports",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"imports.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var query ListQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			filterClauses := []clause.Expression{
+				clause.Eq{
+					Column: clause.Column{
+						Name: "organization_id",
+					},
+					Value: currentUser.ActiveOrganization,
+				},
+			}
+
+			if query.Kind != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "kind",
+					},
+					Value: query.Kind,
+				})
+			}
+
+			if query.Status != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "status",
+					},
+					Value: query.Status,
+				})
+			}
+
+			totalImports, err := r.Services.Imports().Count(filterClauses...)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			paginationClauses := []clause.Expression{
+				clause.Limit{
+					Limit:  &query.Limit,
+					Offset: (query.Page - 1) * query.Limit,
+				},
+			}
+
+			paginationClauses = append(paginationClauses, filterClauses...)
+
+			totalPages := (totalImports + int64(query.Limit) - 1) / int64(query.Limit)
+
+			jobs, err := r.Services.Imports().List(paginationClauses...)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": jobs,
+				"pageDetails": map[string]any{
+					"count":        totalImports,
+					"nextPage":     query.Page + 1,
+					"previousPage": query.Page - 1,
+					"currentPage":  query.Page,
+					"pages":        totalPages,
+				},
+			})
+		},
+	}
+}
@@ -1,20 +1,21 @@
-package addresses
+package inventory
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 )
 
-func (r *AddressesRouter) CreateRoute() routing.Route {
+func (r *InventoryRouter) AdjustRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
-			WithDescription("Successful address creation.").
+			WithDescription("Successful inventory adjustment.").
 			WithJSONSchema(schemas.SuccessResponseSchema.Value).
 			WithContent(openapi3.Content{
 				"text/plain": openapi3.NewMediaType().
@@ -68,31 +69,31 @@ func (r *AddressesRouter) CreateRoute() routing.Route {
 	body := &openapi3.RequestBodyRef{
 		Value: openapi3.NewRequestBody().
 			WithRequired(true).
-			WithDescription("Payload to create a new address.").
+			WithDescription("Payload to adjust the stock on hand of a material.").
 			WithContent(openapi3.Content{
 				"application/json": openapi3.NewMediaType().
-					WithSchema(schemas.CreateAddressSchema.Value).
-					WithExample("example", schemas.CreateAddressSchema.Value),
+					WithSchema(schemas.CreateInventoryAdjustmentSchema.Value).
+					WithExample("example", schemas.CreateInventoryAdjustmentSchema.Value),
 			}),
 	}
 
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
-			Summary:     "Create Address",
-			Description: "Create a new address in the system.",
-			Tags:        []string{"Addresses"},
+			Summary:     "Adjust Inventory",
+			Description: "Post a manual adjustment, such as shrinkage or moisture loss, to the stock ledger of your active organization.",
+			Tags:        []string{"Inventory"},
 			Responses:   responses,
 			Parameters:  nil,
 			RequestBody: body,
 		},
 		Method: routing.PostMethod,
-		Path:   "/addresses",
+		Path:   "/inventory/adjustments",
 		Middlewares: []fiber.Handler{
 			r.Middleware.Authenticated(),
-			r.Middleware.Authorized([]string{"addresses.create"}),
+			r.Middleware.Authorized([]string{"inventory.adjust"}),
 		},
 		Handler: func(c *fiber.Ctx) error {
-			var payload models.CreateAddressPayload
+			var payload models.CreateInventoryAdjustmentPayload
 
 			if err := c.BodyParser(&payload); err != nil {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -101,9 +102,32 @@ func (r *AddressesRouter) CreateRoute() routing.Route {
 				})
 			}
 
-			id, err := r.Services.Addresses().Create(payload)
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			id, err := r.Services.Inventory().Adjust(currentUser.ActiveOrganization, currentUser.Id, payload)
 
 			if err != nil {
+				if err == services.ErrInvalidInventoryAdjustment {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidSite {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error":   constants.InternalServerError,
 					"message": constants.InternalServerErrorDetails,
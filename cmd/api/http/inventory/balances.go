@@ -0,0 +1,194 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type BalancesQueryParams struct {
+	AsOf       string `query:"asOf"`
+	MaterialId string `query:"materialId"`
+	SiteId     string `query:"siteId"`
+}
+
+func (r *InventoryRouter) BalancesRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful stock on hand retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("asOf").
+				WithSchema(openapi3.NewDateTimeSchema()).
+				WithDescription("Point in time to calculate the stock on hand at. Defaults to now."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("materialId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Material to limit the stock on hand to."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("siteId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Site to limit the stock on hand to."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "List Stock On Hand",
+			Description: "List the stock on hand per material and site for your active organization. Stock that was not recorded at a site is listed without one.",
+			Tags:        []string{"Inventory"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/inventory",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"inventory.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var query BalancesQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			asOf := time.Now()
+
+			if query.AsOf != "" {
+				parsedAsOf, err := time.Parse(time.RFC3339, query.AsOf)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				asOf = parsedAsOf
+			}
+
+			filterClauses := []clause.Expression{}
+
+			if query.MaterialId != "" {
+				materialId, err := uuid.Parse(query.MaterialId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Table: "inventory_entries",
+						Name:  "material_id",
+					},
+					Value: materialId,
+				})
+			}
+
+			if query.SiteId != "" {
+				siteId, err := uuid.Parse(query.SiteId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Table: "inventory_entries",
+						Name:  "site_id",
+					},
+					Value: siteId,
+				})
+			}
+
+			balances, err := r.Services.Inventory().Balances(currentUser.ActiveOrganization, asOf, filterClauses...)
+
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": balances,
+			})
+		},
+	}
+}
@@ -0,0 +1,250 @@
+package inventory
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EntriesQueryParams struct {
+	Page       int    `query:"page"`
+	Limit      int    `query:"limit"`
+	MaterialId string `query:"materialId"`
+	Type       string `query:"type"`
+	SiteId     string `query:"siteId"`
+}
+
+func (r *InventoryRouter) EntriesRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful inventory entries retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	paramters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("page").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(1).WithMin(1)).
+				WithDescription("Page number for pagination. Defaults to 1."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("limit").
+				WithRequired(true).
+				WithSchema(openapi3.NewInt64Schema().
+					WithDefault(10).WithMin(10)).
+				WithDescription("Number of items per page. Defaults to 10."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("materialId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Material to filter inventory entries by."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("type").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("receipt", "issue", "adjustment", "reversal")).
+				WithDescription("Entry type to filter inventory entries by."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("siteId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Site to filter inventory entries by."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "List Inventory Entries",
+			Description: "List the stock ledger entries of your active organization, most recent first.",
+			Tags:        []string{"Inventory"},
+			Responses:   responses,
+			Parameters:  paramters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/inventory/entries",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"inventory.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var query EntriesQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			filterClauses := []clause.Expression{
+				clause.Eq{
+					Column: clause.Column{
+						Name: "organization_id",
+					},
+					Value: currentUser.ActiveOrganization,
+				},
+			}
+
+			if query.MaterialId != "" {
+				materialId, err := uuid.Parse(query.MaterialId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "material_id",
+					},
+					Value: materialId,
+				})
+			}
+
+			if query.Type != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "type",
+					},
+					Value: query.Type,
+				})
+			}
+
+			if query.SiteId != "" {
+				siteId, err := uuid.Parse(query.SiteId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "site_id",
+					},
+					Value: siteId,
+				})
+			}
+
+			totalEntries, err := r.Services.Inventory().Count(filterClauses...)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			paginationClauses := []clause.Expression{
+				clause.Limit{
+					Limit:  &query.Limit,
+					Offset: (query.Page - 1) * query.Limit,
+				},
+			}
+
+			paginationClauses = append(paginationClauses, filterClauses...)
+
+			totalPages := (totalEntries + int64(query.Limit) - 1) / int64(query.Limit)
+
+			entries, err := r.Services.Inventory().List(paginationClauses...)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"message": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"items": entries,
+				"pageDetails": map[string]any{
+					"count":        totalEntries,
+					"nextPage":     query.Page + 1,
+					"previousPage": query.Page - 1,
+					"currentPage":  query.Page,
+					"pages":        totalPages,
+				},
+			})
+		},
+	}
+}
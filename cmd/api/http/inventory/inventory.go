@@ -0,0 +1,42 @@
+package inventory
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type InventoryRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewInventoryRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) InventoryRouter {
+	return InventoryRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *InventoryRouter) InitializeRoutes() []routing.Route {
+	balancesRoute := r.BalancesRoute()
+	entriesRoute := r.EntriesRoute()
+	adjustRoute := r.AdjustRoute()
+
+	return []routing.Route{
+		balancesRoute,
+		entriesRoute,
+		adjustRoute,
+	}
+}
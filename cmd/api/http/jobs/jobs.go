@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type JobsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewJobsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) JobsRouter {
+	return JobsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *JobsRouter) InitializeRoutes() []routing.Route {
+	schedulesRoute := r.SchedulesRoute()
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	retryRoute := r.RetryRoute()
+
+	return []routing.Route{
+		schedulesRoute,
+		listRoute,
+		findRoute,
+		retryRoute,
+	}
+}
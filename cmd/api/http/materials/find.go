@@ -114,6 +114,8 @@ func (r *MaterialsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(material.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": material,
 			})
@@ -0,0 +1,42 @@
+package me
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type MeRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewMeRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) MeRouter {
+	return MeRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *MeRouter) InitializeRoutes() []routing.Route {
+	collectionsRoute := r.CollectionsRoute()
+	earningsRoute := r.EarningsRoute()
+	impactRoute := r.ImpactRoute()
+
+	return []routing.Route{
+		collectionsRoute,
+		earningsRoute,
+		impactRoute,
+	}
+}
@@ -0,0 +1,47 @@
+package me
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+type ReportQueryParams struct {
+	From   string `query:"from"`
+	To     string `query:"to"`
+	Period string `query:"period"`
+}
+
+// Parse converts the report query parameters into a seller report query,
+// grouping by month unless another period is given.
+func (q ReportQueryParams) Parse() (models.SellerReportQuery, error) {
+	query := models.SellerReportQuery{
+		Period: models.MonthlyPeriod,
+	}
+
+	if q.Period != "" {
+		query.Period = models.ReportPeriod(q.Period)
+	}
+
+	if q.From != "" {
+		from, err := time.Parse(time.RFC3339, q.From)
+
+		if err != nil {
+			return query, err
+		}
+
+		query.From = &from
+	}
+
+	if q.To != "" {
+		to, err := time.Parse(time.RFC3339, q.To)
+
+		if err != nil {
+			return query, err
+		}
+
+		query.To = &to
+	}
+
+	return query, nil
+}
@@ -1,9 +1,6 @@
 package middleware
 
 import (
-	"slices"
-	"strings"
-
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/gofiber/fiber/v2"
@@ -20,23 +17,9 @@ func (m *Middleware) Authorized(requiredPermissions []string) fiber.Handler {
 			})
 		}
 
-		combinedPermissions := []string{}
-
-		for _, role := range currentUser.Roles {
-			combinedPermissions = append(combinedPermissions, role.Permissions...)
-		}
-
-		if slices.Contains(combinedPermissions, "*") {
-			return c.Next()
-		}
-
-		for _, permission := range combinedPermissions {
-			noWildCardPermission := strings.TrimSuffix(permission, ".*")
-
-			for _, requiredPermission := range requiredPermissions {
-				if strings.HasPrefix(requiredPermission, noWildCardPermission) {
-					return c.Next()
-				}
+		for _, requiredPermission := range requiredPermissions {
+			if currentUser.HasPermission(requiredPermission) {
+				return c.Next()
 			}
 		}
 
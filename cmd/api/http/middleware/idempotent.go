@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// IdempotencyKeyHeader is the header a client sends a key in to have a request
+// applied at most once.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses that were replayed for a retry
+// of a request instead of the request being handled again.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const idempotencyKeyMaxLength = 255
+
+// unreplayedHeaders are the response headers that are written by the server
+// for every response, which are not stored to be replayed.
+var unreplayedHeaders = []string{
+	fiber.HeaderContentLength,
+	fiber.HeaderTransferEncoding,
+	fiber.HeaderConnection,
+	fiber.HeaderDate,
+	fiber.HeaderServer,
+	IdempotentReplayedHeader,
+}
+
+// Idempotent lets clients safely retry a request by sending it with the same
+// Idempotency-Key header. The response to the first request is stored for the
+// idempotency window and replayed for its retries, while a key that is reused
+// for a request with a different method, path or body is refused with a
+// conflict. Requests without the header are handled as usual.
+//
+// Keys are scoped to the authenticated user or scale, so it runs after the
+// route's other middlewares. Requests that were not authenticated have no one
+// to scope their keys to, and are handled as usual even when they send one.
+// Responses with server errors are not stored, so that the request can be
+// retried with the same key. Responses are replayed with their headers, such
+// as the cookies they set.
+func (m *Middleware) Idempotent() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		scope := idempotencyScope(c)
+
+		if key == "" || scope == "" {
+			return c.Next()
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   constants.BadRequestError,
+				"details": constants.InvalidIdempotencyKeyErrorDetails,
+			})
+		}
+
+		hash := sha256.New()
+
+		fmt.Fprintf(hash, "%s %s\n", c.Method(), c.OriginalURL())
+		hash.Write(c.Body())
+
+		idempotencyKey, err := m.Services.Idempotency().Begin(scope, key, hex.EncodeToString(hash.Sum(nil)), m.IdempotencyWindow)
+
+		if err != nil {
+			if err == services.ErrIdempotencyKeyReused {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":   constants.ConflictError,
+					"details": constants.IdempotencyKeyReusedErrorDetails,
+				})
+			}
+
+			if err == services.ErrIdempotencyKeyInProgress {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":   constants.ConflictError,
+					"details": constants.IdempotencyKeyInProgressErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   constants.InternalServerError,
+				"details": constants.InternalServerErrorDetails,
+			})
+		}
+
+		if idempotencyKey.Status != nil {
+			for name, values := range idempotencyKey.Headers {
+				c.Response().Header.Del(name)
+
+				for _, value := range values {
+					c.Response().Header.Add(name, value)
+				}
+			}
+
+			c.Set(IdempotentReplayedHeader, "true")
+
+			return c.Status(*idempotencyKey.Status).Send(idempotencyKey.Body)
+		}
+
+		if err := c.Next(); err != nil {
+			if releaseErr := m.Services.Idempotency().Release(idempotencyKey.Id); releaseErr != nil {
+				log.Errorf("🔥 Failed to release idempotency key: %s", releaseErr.Error())
+			}
+
+			return err
+		}
+
+		status := c.Response().StatusCode()
+
+		if status >= fiber.StatusInternalServerError {
+			err = m.Services.Idempotency().Release(idempotencyKey.Id)
+		} else {
+			err = m.Services.Idempotency().Complete(
+				idempotencyKey.Id,
+				status,
+				replayedHeaders(c),
+				c.Response().Body(),
+			)
+		}
+
+		if err != nil {
+			log.Errorf("🔥 Failed to store idempotency key: %s", err.Error())
+		}
+
+		return nil
+	}
+}
+
+// replayedHeaders returns the headers of the response to a request, other
+// than those written by the server, to be stored along with it.
+func replayedHeaders(c *fiber.Ctx) map[string][]string {
+	headers := map[string][]string{}
+
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+
+		if slices.ContainsFunc(unreplayedHeaders, func(header string) bool {
+			return strings.EqualFold(header, name)
+		}) {
+			return
+		}
+
+		headers[name] = append(headers[name], string(value))
+	})
+
+	return headers
+}
+
+// idempotencyScope is the user or scale a request was authenticated as, whose
+// keys are kept apart from those of everyone else. It is empty for requests
+// that were not authenticated.
+func idempotencyScope(c *fiber.Ctx) string {
+	if user, ok := c.Locals("user").(*models.User); ok {
+		return fmt.Sprintf("user:%s", user.Id)
+	}
+
+	if scale, ok := c.Locals("scale").(*models.Scale); ok {
+		return fmt.Sprintf("scale:%s", scale.Id)
+	}
+
+	return ""
+}
@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testMiddleware returns middleware backed by the database named by
+// TEST_POSTGRES_DSN. Tests that need a database are skipped when it is not
+// set.
+func testMiddleware(tb testing.TB) *Middleware {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+
+	if dsn == "" {
+		tb.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+
+	if err != nil {
+		tb.Fatalf("failed to connect to Postgres: %v", err)
+	}
+
+	s := storage.Storage{
+		Postgres: database,
+	}
+
+	s.MigratePostgres()
+
+	return &Middleware{
+		Storage:           s,
+		Services:          services.NewServices(s, services.NewGazetteerGeocoder()),
+		IdempotencyWindow: time.Hour,
+	}
+}
+
+// testIdempotentApp serves POST /counter through the Idempotent middleware as
+// the user, counting the requests that reach the handler, which answers with
+// the status returned by respond.
+func testIdempotentApp(m *Middleware, user *models.User, handled *int, respond func() int) *fiber.App {
+	app := fiber.New()
+
+	app.Post(
+		"/counter",
+		func(c *fiber.Ctx) error {
+			if user != nil {
+				c.Locals("user", user)
+			}
+
+			return c.Next()
+		},
+		m.Idempotent(),
+		func(c *fiber.Ctx) error {
+			*handled++
+
+			c.Set(fiber.HeaderLocation, "/counter/"+strconv.Itoa(*handled))
+
+			return c.Status(respond()).SendString(strconv.Itoa(*handled))
+		},
+	)
+
+	return app
+}
+
+func testIdempotentRequest(tb testing.TB, app *fiber.App, key string, body string) (*http.Response, string) {
+	tb.Helper()
+
+	request := httptest.NewRequest(fiber.MethodPost, "/counter", strings.NewReader(body))
+
+	if key != "" {
+		request.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	response, err := app.Test(request, -1)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	content, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return response, string(content)
+}
+
+func TestIdempotentWithoutKeyOrScope(t *testing.T) {
+	created := func() int { return fiber.StatusCreated }
+
+	t.Run("requests without a key are handled every time", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(&Middleware{}, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, created)
+
+		testIdempotentRequest(t, app, "", "{}")
+		testIdempotentRequest(t, app, "", "{}")
+
+		if handled != 2 {
+			t.Errorf("handled %d requests, want 2", handled)
+		}
+	})
+
+	t.Run("requests that were not authenticated are handled every time", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(&Middleware{}, nil, &handled, created)
+		key := uuid.NewString()
+
+		testIdempotentRequest(t, app, key, "{}")
+		testIdempotentRequest(t, app, key, "{}")
+
+		if handled != 2 {
+			t.Errorf("handled %d requests, want 2", handled)
+		}
+	})
+
+	t.Run("keys that are too long are refused", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(&Middleware{}, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, created)
+
+		response, _ := testIdempotentRequest(t, app, strings.Repeat("k", idempotencyKeyMaxLength+1), "{}")
+
+		if response.StatusCode != fiber.StatusBadRequest || handled != 0 {
+			t.Errorf("status = %d, handled = %d, want %d and 0", response.StatusCode, handled, fiber.StatusBadRequest)
+		}
+	})
+}
+
+func TestIdempotent(t *testing.T) {
+	m := testMiddleware(t)
+
+	created := func() int { return fiber.StatusCreated }
+
+	t.Run("retries are replayed", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(m, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, created)
+		key := uuid.NewString()
+
+		first, firstBody := testIdempotentRequest(t, app, key, `{"weight":10}`)
+		retry, retryBody := testIdempotentRequest(t, app, key, `{"weight":10}`)
+
+		if handled != 1 {
+			t.Fatalf("handled %d requests, want 1", handled)
+		}
+
+		if retry.StatusCode != first.StatusCode || retryBody != firstBody {
+			t.Errorf("retry = %d %q, want %d %q", retry.StatusCode, retryBody, first.StatusCode, firstBody)
+		}
+
+		if retry.Header.Get(fiber.HeaderLocation) != "/counter/1" {
+			t.Errorf("retry Location = %q, want %q", retry.Header.Get(fiber.HeaderLocation), "/counter/1")
+		}
+
+		if first.Header.Get(IdempotentReplayedHeader) != "" || retry.Header.Get(IdempotentReplayedHeader) != "true" {
+			t.Errorf("%s = %q and %q, want none and true", IdempotentReplayedHeader, first.Header.Get(IdempotentReplayedHeader), retry.Header.Get(IdempotentReplayedHeader))
+		}
+	})
+
+	t.Run("keys are scoped to the user", func(t *testing.T) {
+		handled := 0
+		key := uuid.NewString()
+
+		for range 2 {
+			app := testIdempotentApp(m, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, created)
+
+			testIdempotentRequest(t, app, key, "{}")
+		}
+
+		if handled != 2 {
+			t.Errorf("handled %d requests, want 2", handled)
+		}
+	})
+
+	t.Run("keys reused for another request conflict", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(m, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, created)
+		key := uuid.NewString()
+
+		testIdempotentRequest(t, app, key, `{"weight":10}`)
+
+		response, _ := testIdempotentRequest(t, app, key, `{"weight":11}`)
+
+		if response.StatusCode != fiber.StatusConflict || handled != 1 {
+			t.Errorf("status = %d, handled = %d, want %d and 1", response.StatusCode, handled, fiber.StatusConflict)
+		}
+	})
+
+	t.Run("retries of a request in progress conflict", func(t *testing.T) {
+		entered := make(chan struct{})
+		proceed := make(chan struct{})
+		done := make(chan struct{})
+
+		handled := 0
+		app := testIdempotentApp(m, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, func() int {
+			if handled == 1 {
+				close(entered)
+				<-proceed
+			}
+
+			return fiber.StatusCreated
+		})
+		key := uuid.NewString()
+
+		go func() {
+			defer close(done)
+
+			testIdempotentRequest(t, app, key, "{}")
+		}()
+
+		<-entered
+
+		response, _ := testIdempotentRequest(t, app, key, "{}")
+
+		close(proceed)
+		<-done
+
+		if response.StatusCode != fiber.StatusConflict || handled != 1 {
+			t.Errorf("status = %d, handled = %d, want %d and 1", response.StatusCode, handled, fiber.StatusConflict)
+		}
+	})
+
+	t.Run("keys of requests that failed are released", func(t *testing.T) {
+		handled := 0
+		app := testIdempotentApp(m, &models.User{Base: models.Base{Id: uuid.New()}}, &handled, func() int {
+			if handled == 1 {
+				return fiber.StatusInternalServerError
+			}
+
+			return fiber.StatusCreated
+		})
+		key := uuid.NewString()
+
+		failed, _ := testIdempotentRequest(t, app, key, "{}")
+		retry, _ := testIdempotentRequest(t, app, key, "{}")
+
+		if failed.StatusCode != fiber.StatusInternalServerError || retry.StatusCode != fiber.StatusCreated || handled != 2 {
+			t.Errorf("statuses = %d and %d, handled = %d, want %d, %d and 2", failed.StatusCode, retry.StatusCode, handled, fiber.StatusInternalServerError, fiber.StatusCreated)
+		}
+
+		if retry.Header.Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("retry was replayed, want it handled again")
+		}
+	})
+}
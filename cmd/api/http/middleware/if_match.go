@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IfMatch requires the ETag of the record a request changes in the If-Match
+// header, so that changes made since the client read the record are not
+// overwritten. The version it names is stored in the "version" local for the
+// handler to pass on to the service, which refuses the change when the record
+// is no longer at that version.
+func (m *Middleware) IfMatch() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderIfMatch)
+
+		if header == "" {
+			return c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+				"error":   constants.PreconditionRequiredError,
+				"details": constants.PreconditionRequiredErrorDetails,
+			})
+		}
+
+		version, ok := routing.ParseETag(header)
+
+		if !ok {
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error":   constants.PreconditionFailedError,
+				"details": constants.PreconditionFailedErrorDetails,
+			})
+		}
+
+		c.Locals("version", version)
+
+		return c.Next()
+	}
+}
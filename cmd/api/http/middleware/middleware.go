@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/gofiber/fiber/v2/middleware/session"
@@ -13,6 +15,9 @@ type Middleware struct {
 	Storage  storage.Storage
 	Sessions session.Store
 	Services services.Services
+	// IdempotencyWindow is how long the responses to requests sent with an
+	// Idempotency-Key header are kept to be replayed for their retries.
+	IdempotencyWindow time.Duration
 }
 
 // NewMiddleware creates and returns a new Middleware instance, initializing it with the provided
@@ -24,14 +29,16 @@ type Middleware struct {
 //	storage  - Pointer to the application's storage layer.
 //	sessions - Pointer to the session store for managing user sessions.
 //	services - Pointer to the application's service layer.
+//	idempotencyWindow - How long responses are kept for idempotent retries.
 //
 // Returns:
 //
 //	A pointer to a newly constructed Middleware instance.
-func NewMiddleware(storage storage.Storage, sessions session.Store, services services.Services) Middleware {
+func NewMiddleware(storage storage.Storage, sessions session.Store, services services.Services, idempotencyWindow time.Duration) Middleware {
 	return Middleware{
-		Storage:  storage,
-		Sessions: sessions,
-		Services: services,
+		Storage:           storage,
+		Sessions:          sessions,
+		Services:          services,
+		IdempotencyWindow: idempotencyWindow,
 	}
 }
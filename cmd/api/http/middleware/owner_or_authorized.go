@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OwnerOrAuthorized allows the request when the authenticated user may access
+// the records of the user or organization identified by the "id" route
+// parameter. See models.User.CanAccess for the rules that apply. Users are
+// looked up for the organizations they are a member of.
+func (m *Middleware) OwnerOrAuthorized(ownerType models.OwnerType, requiredPermission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		currentUser, ok := c.Locals("user").(*models.User)
+
+		if !ok || currentUser == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   constants.UnauthorizedError,
+				"details": constants.UnauthorizedErrorDetails,
+			})
+		}
+
+		ownerId, err := uuid.Parse(c.Params("id"))
+
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   constants.BadRequestError,
+				"details": constants.BadRequestErrorDetails,
+			})
+		}
+
+		owner := &models.Owner{Type: ownerType, Id: ownerId}
+
+		if ownerType == models.UserOwner && ownerId != currentUser.Id {
+			owner, err = m.Services.Users().Owner(ownerId)
+
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+						"error":   constants.NotFoundError,
+						"details": constants.NotFoundErrorDetails,
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"details": constants.InternalServerErrorDetails,
+				})
+			}
+		}
+
+		if currentUser.CanAccess(owner, requiredPermission) {
+			return c.Next()
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   constants.ForbiddenError,
+			"details": constants.ForbiddenErrorDetails,
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+// ScaleKeyHeader is the header scales send their device key in.
+const ScaleKeyHeader = "X-Scale-Key"
+
+// ScaleAuthenticated allows the request when it carries the device key of an
+// active scale, storing the scale in the "scale" local.
+func (m *Middleware) ScaleAuthenticated() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(ScaleKeyHeader)
+
+		if key == "" {
+			log.Warn("🚫 Unauthorized scale access attempt: No device key")
+
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   constants.UnauthorizedError,
+				"details": constants.UnauthorizedErrorDetails,
+			})
+		}
+
+		scale, err := m.Services.Scales().Authenticate(key)
+
+		if err != nil {
+			if err != gorm.ErrRecordNotFound {
+				log.Errorf("🔥 Error retrieving scale: %s", err.Error())
+			}
+
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   constants.UnauthorizedError,
+				"details": constants.UnauthorizedErrorDetails,
+			})
+		}
+
+		c.Locals("scale", scale)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package organizationBankDetails
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type OrganizationBankDetailsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewOrganizationBankDetailsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) OrganizationBankDetailsRouter {
+	return OrganizationBankDetailsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *OrganizationBankDetailsRouter) InitializeRoutes() []routing.Route {
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateRoute := r.UpdateRoute()
+	deleteRoute := r.DeleteRoute()
+
+	return []routing.Route{
+		findRoute,
+		createRoute,
+		updateRoute,
+		deleteRoute,
+	}
+}
@@ -114,6 +114,8 @@ func (r *OrganizationsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(organization.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": organization,
 			})
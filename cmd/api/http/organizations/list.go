@@ -2,6 +2,8 @@ package organizations
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/exports"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -11,18 +13,20 @@ import (
 )
 
 type ListQueryParams struct {
-	Page   int    `query:"page"`
-	Limit  int    `query:"limit"`
-	Search string `query:"search"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+	Search  string `query:"search"`
+	Format  string `query:"format"`
+	Columns string `query:"columns"`
 }
 
 func (r *OrganizationsRouter) ListRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
+		Value: exports.WithContent(openapi3.NewResponse().
 			WithDescription("Successful organizations retrieval.").
-			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+			WithJSONSchema(schemas.SuccessResponseSchema.Value)),
 	})
 
 	responses.Set("400", &openapi3.ResponseRef{
@@ -90,6 +94,8 @@ func (r *OrganizationsRouter) ListRoute() routing.Route {
 		},
 	}
 
+	paramters = append(paramters, exports.Parameters(exports.OrganizationColumns)...)
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "List Organizations",
@@ -121,6 +127,30 @@ func (r *OrganizationsRouter) ListRoute() routing.Route {
 				),
 			}
 
+			format, err := exports.Requested(c, query.Format)
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": err.Error(),
+				})
+			}
+
+			if format != "" {
+				columns, err := exports.Select(exports.OrganizationColumns, query.Columns)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return exports.Send(c, format, "organizations", columns, func(handle func(models.Organization) error) error {
+					return r.Services.Organizations().Stream(handle, searchClauses...)
+				})
+			}
+
 			totalOrganizations, err := r.Services.Organizations().Count(searchClauses...)
 
 			if err != nil {
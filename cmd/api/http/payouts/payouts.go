@@ -0,0 +1,54 @@
+package payouts
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type PayoutsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewPayoutsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) PayoutsRouter {
+	return PayoutsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *PayoutsRouter) InitializeRoutes() []routing.Route {
+	listBatchesRoute := r.ListBatchesRoute()
+	findBatchRoute := r.FindBatchRoute()
+	createBatchRoute := r.CreateBatchRoute()
+	exportBatchRoute := r.ExportBatchRoute()
+	confirmBatchRoute := r.ConfirmBatchRoute()
+	reverseBatchRoute := r.ReverseBatchRoute()
+	listRoute := r.ListRoute()
+	createCashRoute := r.CreateCashRoute()
+	reverseRoute := r.ReverseRoute()
+
+	return []routing.Route{
+		listBatchesRoute,
+		findBatchRoute,
+		createBatchRoute,
+		exportBatchRoute,
+		confirmBatchRoute,
+		reverseBatchRoute,
+		listRoute,
+		createCashRoute,
+		reverseRoute,
+	}
+}
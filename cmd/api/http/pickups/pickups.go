@@ -0,0 +1,50 @@
+package pickups
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type PickupsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewPickupsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) PickupsRouter {
+	return PickupsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *PickupsRouter) InitializeRoutes() []routing.Route {
+	routeRoute := r.RouteRoute()
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	assignRoute := r.AssignRoute()
+	cancelRoute := r.CancelRoute()
+	completeRoute := r.CompleteRoute()
+
+	return []routing.Route{
+		routeRoute,
+		listRoute,
+		findRoute,
+		createRoute,
+		assignRoute,
+		cancelRoute,
+		completeRoute,
+	}
+}
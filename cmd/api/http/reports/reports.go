@@ -0,0 +1,46 @@
+package reports
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type ReportsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewReportsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) ReportsRouter {
+	return ReportsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *ReportsRouter) InitializeRoutes() []routing.Route {
+	volumesRoute := r.VolumesRoute()
+	listEprRoute := r.ListEprRoute()
+	findEprRoute := r.FindEprRoute()
+	createEprRoute := r.CreateEprRoute()
+	downloadEprRoute := r.DownloadEprRoute()
+
+	return []routing.Route{
+		volumesRoute,
+		listEprRoute,
+		findEprRoute,
+		createEprRoute,
+		downloadEprRoute,
+	}
+}
@@ -0,0 +1,283 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type VolumesQueryParams struct {
+	Source           string `query:"source"`
+	GroupBy          string `query:"groupBy"`
+	Period           string `query:"period"`
+	From             string `query:"from"`
+	To               string `query:"to"`
+	OrganizationId   string `query:"organizationId"`
+	AllOrganizations bool   `query:"allOrganizations"`
+}
+
+func (r *ReportsRouter) VolumesRoute() routing.Route {
+	responses := openapi3.NewResponses()
+
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Successful volume report retrieval.").
+			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+	})
+
+	responses.Set("400", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.BadRequestError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.BadRequestError),
+						"message": string(constants.BadRequestErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("401", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.UnauthorizedError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.UnauthorizedError),
+						"message": string(constants.UnauthorizedErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
+	responses.Set("500", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription("Internal server error.").
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.InternalServerError),
+						"message": string(constants.InternalServerErrorDetails),
+					}),
+			}),
+	})
+
+	parameters := []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("source").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("collections", "transactions").
+					WithDefault("collections")).
+				WithDescription("Lines to report on. Defaults to collections."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("groupBy").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("material", "organization", "site", "collector").
+					WithDefault("material")).
+				WithDescription("Group to total by. Collectors are only available for collections. Defaults to material."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("period").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("day", "week", "month", "quarter").
+					WithDefault("month")).
+				WithDescription("Period to bucket the series by in South African time. Defaults to month."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("from").
+				WithSchema(openapi3.NewDateTimeSchema()).
+				WithDescription("Start of the report range. Defaults to a month before the end."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("to").
+				WithSchema(openapi3.NewDateTimeSchema()).
+				WithDescription("End of the report range. Defaults to now."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("organizationId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Organization to report on. Defaults to your active organization."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("allOrganizations").
+				WithSchema(openapi3.NewBoolSchema()).
+				WithDescription("Report on every organization."),
+		},
+	}
+
+	return routing.Route{
+		OpenAPIMetadata: routing.OpenAPIMetadata{
+			Summary:     "Volume Report",
+			Description: "Total the weight and value of confirmed collections or delivered transactions by material, organization, site or collector, compared with the previous period of the same length, with a series bucketed by period. The range is widened to whole days in South African time.",
+			Tags:        []string{"Reports"},
+			Responses:   responses,
+			Parameters:  parameters,
+			RequestBody: nil,
+		},
+		Method: routing.GetMethod,
+		Path:   "/reports/volumes",
+		Middlewares: []fiber.Handler{
+			r.Middleware.Authenticated(),
+			r.Middleware.Authorized([]string{"reports.view"}),
+		},
+		Handler: func(c *fiber.Ctx) error {
+			var query VolumesQueryParams
+
+			if err := c.QueryParser(&query); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": constants.BadRequestErrorDetails,
+				})
+			}
+
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
+			reportQuery := models.VolumeReportQuery{
+				OrganizationId: &currentUser.ActiveOrganization,
+				Source:         models.CollectionsReportSource,
+				GroupBy:        models.ByMaterial,
+				Period:         models.MonthlyPeriod,
+				To:             time.Now(),
+			}
+
+			if query.Source != "" {
+				reportQuery.Source = models.ReportSource(query.Source)
+			}
+
+			if query.GroupBy != "" {
+				reportQuery.GroupBy = models.ReportGrouping(query.GroupBy)
+			}
+
+			if query.Period != "" {
+				reportQuery.Period = models.ReportPeriod(query.Period)
+			}
+
+			if query.To != "" {
+				to, err := time.Parse(time.RFC3339, query.To)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				reportQuery.To = to
+			}
+
+			reportQuery.From = reportQuery.To.AddDate(0, -1, 0)
+
+			if query.From != "" {
+				from, err := time.Parse(time.RFC3339, query.From)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				reportQuery.From = from
+			}
+
+			if query.OrganizationId != "" {
+				organizationId, err := uuid.Parse(query.OrganizationId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				reportQuery.OrganizationId = &organizationId
+			}
+
+			if query.AllOrganizations {
+				reportQuery.OrganizationId = nil
+			}
+
+			if (reportQuery.OrganizationId == nil || *reportQuery.OrganizationId != currentUser.ActiveOrganization) && !currentUser.HasPermission("reports.global") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			report, err := r.Services.Reports().Volumes(reportQuery)
+
+			if err != nil {
+				if err == services.ErrInvalidReportPeriod {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidReportRange {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidReportSource {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				if err == services.ErrInvalidReportGrouping {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   constants.InternalServerError,
+					"message": constants.InternalServerErrorDetails,
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"item": report,
+			})
+		},
+	}
+}
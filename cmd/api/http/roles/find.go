@@ -114,6 +114,8 @@ func (r *RolesRouter) FindRoute() routing.Route {
 				})
 			}
 
+			c.Set(fiber.HeaderETag, routing.ETag(role.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": role,
 			})
@@ -0,0 +1,56 @@
+package scales
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type ScalesRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewScalesRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) ScalesRouter {
+	return ScalesRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+// InitializeRoutes returns the routes of scales. The readings routes come
+// before the routes of a scale so that "readings" is not taken for its id.
+func (r *ScalesRouter) InitializeRoutes() []routing.Route {
+	readingsRoute := r.ReadingsRoute()
+	recordRoute := r.RecordRoute()
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateRoute := r.UpdateRoute()
+	rotateKeyRoute := r.RotateKeyRoute()
+	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
+
+	return []routing.Route{
+		readingsRoute,
+		recordRoute,
+		listRoute,
+		findRoute,
+		createRoute,
+		updateRoute,
+		rotateKeyRoute,
+		deleteRoute,
+		restoreRoute,
+	}
+}
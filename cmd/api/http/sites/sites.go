@@ -0,0 +1,48 @@
+package sites
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type SitesRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewSitesRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) SitesRouter {
+	return SitesRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *SitesRouter) InitializeRoutes() []routing.Route {
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateRoute := r.UpdateRoute()
+	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
+
+	return []routing.Route{
+		listRoute,
+		findRoute,
+		createRoute,
+		updateRoute,
+		deleteRoute,
+		restoreRoute,
+	}
+}
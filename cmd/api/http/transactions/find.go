@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -51,6 +52,20 @@ func (r *TransactionsRouter) FindRoute() routing.Route {
 			}),
 	})
 
+	responses.Set("403", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithJSONSchema(schemas.ErrorResponseSchema.Value).
+			WithDescription(string(constants.ForbiddenError)).
+			WithContent(openapi3.Content{
+				"application/json": openapi3.NewMediaType().
+					WithSchema(schemas.ErrorResponseSchema.Value).
+					WithExample("example", map[string]any{
+						"error":   string(constants.ForbiddenError),
+						"message": string(constants.ForbiddenErrorDetails),
+					}),
+			}),
+	})
+
 	responses.Set("500", &openapi3.ResponseRef{
 		Value: openapi3.NewResponse().
 			WithJSONSchema(schemas.ErrorResponseSchema.Value).
@@ -76,7 +91,7 @@ func (r *TransactionsRouter) FindRoute() routing.Route {
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "Find Transaction",
-			Description: "Find an existing transaction in the system.",
+			Description: "Find an existing transaction in the system. Only the seller and buyer organizations may view it.",
 			Tags:        []string{"Transactions"},
 			Responses:   responses,
 			Parameters:  parameters,
@@ -98,6 +113,15 @@ func (r *TransactionsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			currentUser, ok := c.Locals("user").(*models.User)
+
+			if !ok || currentUser == nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   constants.UnauthorizedError,
+					"message": constants.UnauthorizedErrorDetails,
+				})
+			}
+
 			transaction, err := r.Services.Transactions().Find(params.Id)
 
 			if err != nil {
@@ -114,6 +138,15 @@ func (r *TransactionsRouter) FindRoute() routing.Route {
 				})
 			}
 
+			if transaction.SideOf(currentUser.ActiveOrganization) == "" {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   constants.ForbiddenError,
+					"message": constants.ForbiddenErrorDetails,
+				})
+			}
+
+			c.Set(fiber.HeaderETag, routing.ETag(transaction.Version))
+
 			return c.Status(fiber.StatusOK).JSON(fiber.Map{
 				"item": transaction,
 			})
@@ -2,27 +2,34 @@ package transactions
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/exports"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type ListQueryParams struct {
-	Page   int    `query:"page"`
-	Limit  int    `query:"limit"`
-	Search string `query:"search"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+	Search  string `query:"search"`
+	Status  string `query:"status"`
+	SiteId  string `query:"siteId"`
+	Format  string `query:"format"`
+	Columns string `query:"columns"`
 }
 
 func (r *TransactionsRouter) ListRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
+		Value: exports.WithContent(openapi3.NewResponse().
 			WithDescription("Successful transactions retrieval.").
-			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+			WithJSONSchema(schemas.SuccessResponseSchema.Value)),
 	})
 
 	responses.Set("400", &openapi3.ResponseRef{
@@ -88,8 +95,21 @@ func (r *TransactionsRouter) ListRoute() routing.Route {
 				WithSchema(openapi3.NewStringSchema()).
 				WithDescription("Search term for filtering transactions."),
 		},
+		{
+			Value: openapi3.NewQueryParameter("status").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum("quoted", "accepted", "rejected", "cancelled", "delivered", "invoiced", "settled")).
+				WithDescription("Lifecycle status to filter transactions by."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("siteId").
+				WithSchema(openapi3.NewUUIDSchema()).
+				WithDescription("Site to filter transactions by."),
+		},
 	}
 
+	paramters = append(paramters, exports.Parameters(exports.TransactionLineColumns)...)
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "List Transactions",
@@ -115,7 +135,68 @@ func (r *TransactionsRouter) ListRoute() routing.Route {
 				})
 			}
 
-			totalTransactions, err := r.Services.Transactions().Count()
+			filterClauses := []clause.Expression{}
+
+			if query.Status != "" {
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "status",
+					},
+					Value: query.Status,
+				})
+			}
+
+			if query.SiteId != "" {
+				siteId, err := uuid.Parse(query.SiteId)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": constants.BadRequestErrorDetails,
+					})
+				}
+
+				filterClauses = append(filterClauses, clause.Eq{
+					Column: clause.Column{
+						Name: "site_id",
+					},
+					Value: siteId,
+				})
+			}
+
+			format, err := exports.Requested(c, query.Format)
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": err.Error(),
+				})
+			}
+
+			if format != "" {
+				columns, err := exports.Select(exports.TransactionLineColumns, query.Columns)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return exports.Send(c, format, "transactions", columns, func(handle func(exports.TransactionLine) error) error {
+					return r.Services.Transactions().Stream(func(transaction models.Transaction) error {
+						for _, line := range exports.TransactionLines(transaction) {
+							if err := handle(line); err != nil {
+								return err
+							}
+						}
+
+						return nil
+					}, filterClauses...)
+				})
+			}
+
+			totalTransactions, err := r.Services.Transactions().Count(filterClauses...)
 
 			if err != nil {
 				if err == gorm.ErrRecordNotFound {
@@ -138,6 +219,8 @@ func (r *TransactionsRouter) ListRoute() routing.Route {
 				},
 			}
 
+			paginationClauses = append(paginationClauses, filterClauses...)
+
 			totalPages := (totalTransactions + int64(query.Limit) - 1) / int64(query.Limit)
 
 			transactions, err := r.Services.Transactions().List(paginationClauses...)
@@ -31,16 +31,36 @@ func NewTransactionsRouter(
 
 func (r *TransactionsRouter) InitializeRoutes() []routing.Route {
 	listRoute := r.ListRoute()
+	salesRoute := r.SalesRoute()
+	purchasesRoute := r.PurchasesRoute()
 	findRoute := r.FindRoute()
 	createRoute := r.CreateRoute()
 	updateRoute := r.UpdateRoute()
 	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
+	acceptRoute := r.AcceptRoute()
+	rejectRoute := r.RejectRoute()
+	cancelRoute := r.CancelRoute()
+	deliverRoute := r.DeliverRoute()
+	issueInvoiceRoute := r.IssueInvoiceRoute()
+	findInvoiceRoute := r.FindInvoiceRoute()
+	settleRoute := r.SettleRoute()
 
 	return []routing.Route{
 		listRoute,
+		salesRoute,
+		purchasesRoute,
 		findRoute,
 		createRoute,
 		updateRoute,
 		deleteRoute,
+		restoreRoute,
+		acceptRoute,
+		rejectRoute,
+		cancelRoute,
+		deliverRoute,
+		issueInvoiceRoute,
+		findInvoiceRoute,
+		settleRoute,
 	}
 }
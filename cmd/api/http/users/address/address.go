@@ -0,0 +1,44 @@
+package userAddress
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type UserAddressRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewUserAddressRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) UserAddressRouter {
+	return UserAddressRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *UserAddressRouter) InitializeRoutes() []routing.Route {
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateRoute := r.UpdateRoute()
+	deleteRoute := r.DeleteRoute()
+
+	return []routing.Route{
+		findRoute,
+		createRoute,
+		updateRoute,
+		deleteRoute,
+	}
+}
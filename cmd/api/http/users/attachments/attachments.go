@@ -0,0 +1,51 @@
+package userAttachments
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// identityDocumentPermission is needed on top of the route's permission to
+// act on the identity documents attached to other users, which show their
+// unmasked identity numbers.
+const identityDocumentPermission = "users.identity.view"
+
+type UserAttachmentsRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewUserAttachmentsRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) UserAttachmentsRouter {
+	return UserAttachmentsRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *UserAttachmentsRouter) InitializeRoutes() []routing.Route {
+	listRoute := r.ListRoute()
+	uploadRoute := r.UploadRoute()
+	downloadRoute := r.DownloadRoute()
+	thumbnailRoute := r.ThumbnailRoute()
+	deleteRoute := r.DeleteRoute()
+
+	return []routing.Route{
+		listRoute,
+		uploadRoute,
+		downloadRoute,
+		thumbnailRoute,
+		deleteRoute,
+	}
+}
@@ -0,0 +1,40 @@
+package userIdentity
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type UserIdentityRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewUserIdentityRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) UserIdentityRouter {
+	return UserIdentityRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *UserIdentityRouter) InitializeRoutes() []routing.Route {
+	findRoute := r.FindRoute()
+	reviewRoute := r.ReviewRoute()
+
+	return []routing.Route{
+		findRoute,
+		reviewRoute,
+	}
+}
@@ -2,6 +2,7 @@ package users
 
 import (
 	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/exports"
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/routing"
 	"github.com/connor-davis/threereco-nextgen/internal/routing/schemas"
@@ -12,19 +13,21 @@ import (
 )
 
 type ListQueryParams struct {
-	Page   int             `query:"page"`
-	Limit  int             `query:"limit"`
-	Search string          `query:"search"`
-	Type   models.UserType `query:"type"`
+	Page    int             `query:"page"`
+	Limit   int             `query:"limit"`
+	Search  string          `query:"search"`
+	Type    models.UserType `query:"type"`
+	Format  string          `query:"format"`
+	Columns string          `query:"columns"`
 }
 
 func (r *UsersRouter) ListRoute() routing.Route {
 	responses := openapi3.NewResponses()
 
 	responses.Set("200", &openapi3.ResponseRef{
-		Value: openapi3.NewResponse().
+		Value: exports.WithContent(openapi3.NewResponse().
 			WithDescription("Successful users retrieval.").
-			WithJSONSchema(schemas.SuccessResponseSchema.Value),
+			WithJSONSchema(schemas.SuccessResponseSchema.Value)),
 	})
 
 	responses.Set("400", &openapi3.ResponseRef{
@@ -98,6 +101,8 @@ func (r *UsersRouter) ListRoute() routing.Route {
 		},
 	}
 
+	paramters = append(paramters, exports.Parameters(exports.UserColumns)...)
+
 	return routing.Route{
 		OpenAPIMetadata: routing.OpenAPIMetadata{
 			Summary:     "List Users",
@@ -132,6 +137,30 @@ func (r *UsersRouter) ListRoute() routing.Route {
 				clause.Eq{Column: "type", Value: query.Type},
 			}
 
+			format, err := exports.Requested(c, query.Format)
+
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   constants.BadRequestError,
+					"message": err.Error(),
+				})
+			}
+
+			if format != "" {
+				columns, err := exports.Select(exports.UserColumns, query.Columns)
+
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+						"error":   constants.BadRequestError,
+						"message": err.Error(),
+					})
+				}
+
+				return exports.Send(c, format, "users", columns, func(handle func(models.User) error) error {
+					return r.Services.Users().Stream(handle, searchClauses...)
+				})
+			}
+
 			totalUsers, err := r.Services.Users().Count(searchClauses...)
 
 			if err != nil {
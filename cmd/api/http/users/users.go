@@ -35,6 +35,7 @@ func (r *UsersRouter) InitializeRoutes() []routing.Route {
 	createRoute := r.CreateRoute()
 	updateRoute := r.UpdateRoute()
 	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
 
 	return []routing.Route{
 		listRoute,
@@ -42,5 +43,6 @@ func (r *UsersRouter) InitializeRoutes() []routing.Route {
 		createRoute,
 		updateRoute,
 		deleteRoute,
+		restoreRoute,
 	}
 }
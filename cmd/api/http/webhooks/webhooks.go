@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
+	"github.com/connor-davis/threereco-nextgen/internal/routing"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+type WebhooksRouter struct {
+	Storage    storage.Storage
+	Sessions   session.Store
+	Services   services.Services
+	Middleware middleware.Middleware
+}
+
+func NewWebhooksRouter(
+	storage storage.Storage,
+	sessions session.Store,
+	services services.Services,
+	middleware middleware.Middleware,
+) WebhooksRouter {
+	return WebhooksRouter{
+		Storage:    storage,
+		Sessions:   sessions,
+		Services:   services,
+		Middleware: middleware,
+	}
+}
+
+func (r *WebhooksRouter) InitializeRoutes() []routing.Route {
+	eventsRoute := r.EventsRoute()
+	listRoute := r.ListRoute()
+	findRoute := r.FindRoute()
+	createRoute := r.CreateRoute()
+	updateRoute := r.UpdateRoute()
+	deleteRoute := r.DeleteRoute()
+	restoreRoute := r.RestoreRoute()
+	deliveriesRoute := r.DeliveriesRoute()
+	redeliverRoute := r.RedeliverRoute()
+
+	return []routing.Route{
+		eventsRoute,
+		listRoute,
+		findRoute,
+		createRoute,
+		updateRoute,
+		deleteRoute,
+		restoreRoute,
+		deliveriesRoute,
+		redeliverRoute,
+	}
+}
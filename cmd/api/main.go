@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"time"
 
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http"
 	"github.com/connor-davis/threereco-nextgen/cmd/api/http/middleware"
 	"github.com/connor-davis/threereco-nextgen/env"
+	"github.com/connor-davis/threereco-nextgen/internal/jobs"
+	"github.com/connor-davis/threereco-nextgen/internal/jobs/handlers"
 	"github.com/connor-davis/threereco-nextgen/internal/services"
 	"github.com/connor-davis/threereco-nextgen/internal/sessions"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
@@ -17,6 +22,13 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
+// inProcessWorkers is how many jobs the API runs at a time when it runs jobs.
+const inProcessWorkers = 2
+
+// bodyLimit is the largest request body accepted, which fits an attachment of
+// services.AttachmentMaxSize with the rest of its multipart form.
+const bodyLimit = services.AttachmentMaxSize + 1024*1024
+
 // main initializes and starts the Zingfibre Reporting API server.
 // It sets up storage, database connections, migrations, and seeds initial data.
 // The function configures session management, service dependencies, and the Fiber web application,
@@ -24,11 +36,21 @@ import (
 // and API documentation, and registers additional routes via the HTTP router.
 // Finally, it starts the server on the configured port and logs startup or error messages.
 func main() {
+	runJobs := flag.Bool("jobs", true, "Run background jobs in the API process. Disable when jobs are run by separate worker processes.")
+	attachments := flag.String("attachments", "attachments", "Directory or s3://bucket/prefix?region=&endpoint= URL to store attachments in.")
+	trashRetention := flag.Int("trash-retention", 30, "Number of days deleted records are kept in the trash before they are purged.")
+	geocoderUrl := flag.String("geocoder", "", "Base URL of a Nominatim compatible search API to geocode addresses with. Addresses are geocoded to their town with the built in gazetteer when it is not set.")
+	geocoderUserAgent := flag.String("geocoder-user-agent", "3rEco-NextGen-API", "User agent sent to the geocoder, which most public instances require to identify the application.")
+	idempotencyWindow := flag.Duration("idempotency-window", 24*time.Hour, "How long responses to requests sent with an Idempotency-Key header are kept to be replayed for their retries.")
+
+	flag.Parse()
+
 	storage := storage.New()
 
 	storage.ConnectPostgres()
 	storage.MigratePostgres()
 	storage.SeedPostgres()
+	storage.ConnectFiles(*attachments)
 
 	sessions := sessions.NewSessions()
 
@@ -37,20 +59,41 @@ func main() {
 		return
 	}
 
-	services := services.NewServices(storage)
+	geocoder := services.NewGazetteerGeocoder()
+
+	if *geocoderUrl != "" {
+		geocoder = services.NewHttpGeocoder(*geocoderUrl, *geocoderUserAgent)
+	}
+
+	services := services.NewServices(storage, geocoder)
+
+	if *runJobs {
+		runner := jobs.NewRunner(storage, inProcessWorkers)
+
+		if err := handlers.Register(runner, services, *trashRetention); err != nil {
+			log.Errorf("🔥 Failed to register jobs: %s", err.Error())
+			return
+		}
+
+		go runner.Run(context.Background())
+	}
+
+	go services.Changes().Listen(context.Background())
 
-	middleware := middleware.NewMiddleware(storage, *sessions, services)
+	middleware := middleware.NewMiddleware(storage, *sessions, services, *idempotencyWindow)
 
 	app := fiber.New(fiber.Config{
 		AppName:      "3rEco API",
 		ServerHeader: "3rEco-NextGen-API",
 		JSONEncoder:  json.Marshal,
 		JSONDecoder:  json.Unmarshal,
+		BodyLimit:    bodyLimit,
 	})
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000,https://3reco.co.za",
 		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		ExposeHeaders:    "ETag,Idempotent-Replayed",
 		AllowCredentials: true,
 	}))
 
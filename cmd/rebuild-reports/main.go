@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// main discards the daily volumes that reports are read from and summarises
+// them again from the lines of every collection and transaction. The API keeps
+// the daily volumes up to date as records change, so a rebuild is only needed
+// after data has been changed outside of the API.
+func main() {
+	storage := storage.New()
+
+	storage.ConnectPostgres()
+	storage.MigratePostgres()
+
+	services := services.NewServices(storage, services.NewGazetteerGeocoder())
+
+	if err := services.ReportSummaries().Rebuild(); err != nil {
+		log.Errorf("🔥 Error rebuilding report summaries: %s", err.Error())
+
+		os.Exit(1)
+	}
+
+	log.Info("✅ Rebuilt report summaries")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/connor-davis/threereco-nextgen/internal/jobs"
+	"github.com/connor-davis/threereco-nextgen/internal/jobs/handlers"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// main runs background jobs without serving the API, so that jobs can be run
+// by as many worker processes as needed. Start the API with -jobs=false when
+// every job should be run by workers. A worker stops claiming jobs when it is
+// interrupted and exits once the jobs it is running have finished.
+func main() {
+	concurrency := flag.Int("concurrency", 4, "Number of jobs to run at a time.")
+	attachments := flag.String("attachments", "attachments", "Directory or s3://bucket/prefix?region=&endpoint= URL attachments are stored in, so that purged attachments can be deleted.")
+	trashRetention := flag.Int("trash-retention", 30, "Number of days deleted records are kept in the trash before they are purged.")
+
+	flag.Parse()
+
+	storage := storage.New()
+
+	storage.ConnectPostgres()
+	storage.MigratePostgres()
+	storage.ConnectFiles(*attachments)
+
+	services := services.NewServices(storage, services.NewGazetteerGeocoder())
+
+	runner := jobs.NewRunner(storage, *concurrency)
+
+	if err := handlers.Register(runner, services, *trashRetention); err != nil {
+		log.Errorf("🔥 Failed to register jobs: %s", err.Error())
+
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	defer stop()
+
+	runner.Run(ctx)
+
+	log.Info("✅ Stopped running jobs")
+}
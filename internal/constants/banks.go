@@ -0,0 +1,60 @@
+package constants
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+// SupportedBanks is the registry of banks that bank details may be captured
+// for. Account numbers are only checked for their length. Check digits are
+// not validated, because each bank's rules depend on the branch and account
+// type and must be taken from the tables the banks publish.
+var SupportedBanks = []models.Bank{
+	{
+		Name:                "ABSA",
+		UniversalBranchCode: "632005",
+		AccountLengths:      []int{9, 10, 11},
+	},
+	{
+		Name:                "Capitec",
+		UniversalBranchCode: "470010",
+		AccountLengths:      []int{10},
+	},
+	{
+		Name:                "FNB",
+		UniversalBranchCode: "250655",
+		AccountLengths:      []int{11},
+	},
+	{
+		Name:                "Nedbank",
+		UniversalBranchCode: "198765",
+		AccountLengths:      []int{10, 11},
+	},
+	{
+		Name:                "Standard Bank",
+		UniversalBranchCode: "051001",
+		AccountLengths:      []int{9, 10, 11},
+	},
+	{
+		Name:                "Investec",
+		UniversalBranchCode: "580105",
+		AccountLengths:      []int{10, 11},
+	},
+	{
+		Name:                "African Bank",
+		UniversalBranchCode: "430000",
+		AccountLengths:      []int{11},
+	},
+	{
+		Name:                "TymeBank",
+		UniversalBranchCode: "678910",
+		AccountLengths:      []int{11},
+	},
+	{
+		Name:                "Discovery Bank",
+		UniversalBranchCode: "679000",
+		AccountLengths:      []int{10, 11},
+	},
+	{
+		Name:                "Bank Zero",
+		UniversalBranchCode: "888000",
+		AccountLengths:      []int{10, 11},
+	},
+}
@@ -3,22 +3,29 @@ package constants
 import "github.com/connor-davis/threereco-nextgen/internal/models"
 
 const (
-	InternalServerError        string = "Internal server error"
-	InternalServerErrorDetails string = "An unexpected error occurred. Please try again later or contact support."
-	UnauthorizedError          string = "Unauthorized"
-	UnauthorizedErrorDetails   string = "You are not authorized to access this resource. Please log in or contact support."
-	NotFoundError              string = "Not Found"
-	NotFoundErrorDetails       string = "The requested resource could not be found. Please check the URL or contact support."
-	BadRequestError            string = "Bad Request"
-	BadRequestErrorDetails     string = "The request could not be understood or was missing required parameters."
-	ConflictError              string = "Conflict"
-	ConflictErrorDetails       string = "The request could not be completed due to a conflict with the current state of the resource."
-	ForbiddenError             string = "Forbidden"
-	ForbiddenErrorDetails      string = "You do not have permission to access this resource. Please check your permissions or contact support."
-	Created                    string = "Created"
-	CreatedDetails             string = "The resource has been successfully created."
-	Success                    string = "Success"
-	SuccessDetails             string = "The request was successful."
+	InternalServerError                  string = "Internal server error"
+	InternalServerErrorDetails           string = "An unexpected error occurred. Please try again later or contact support."
+	UnauthorizedError                    string = "Unauthorized"
+	UnauthorizedErrorDetails             string = "You are not authorized to access this resource. Please log in or contact support."
+	NotFoundError                        string = "Not Found"
+	NotFoundErrorDetails                 string = "The requested resource could not be found. Please check the URL or contact support."
+	BadRequestError                      string = "Bad Request"
+	BadRequestErrorDetails               string = "The request could not be understood or was missing required parameters."
+	ConflictError                        string = "Conflict"
+	ConflictErrorDetails                 string = "The request could not be completed due to a conflict with the current state of the resource."
+	ForbiddenError                       string = "Forbidden"
+	ForbiddenErrorDetails                string = "You do not have permission to access this resource. Please check your permissions or contact support."
+	PreconditionFailedError              string = "Precondition Failed"
+	PreconditionFailedErrorDetails       string = "The resource has changed since it was read. Please reload it and try again."
+	PreconditionRequiredError            string = "Precondition Required"
+	PreconditionRequiredErrorDetails     string = "The request must include the ETag of the resource it changes in an If-Match header."
+	IdempotencyKeyReusedErrorDetails     string = "The idempotency key has already been used for a different request. Use a new key for each request."
+	IdempotencyKeyInProgressErrorDetails string = "A request with the same idempotency key is still being handled. Please try again shortly."
+	InvalidIdempotencyKeyErrorDetails    string = "The Idempotency-Key header must be at most 255 characters long."
+	Created                              string = "Created"
+	CreatedDetails                       string = "The resource has been successfully created."
+	Success                              string = "Success"
+	SuccessDetails                       string = "The request was successful."
 )
 
 var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
@@ -82,6 +89,18 @@ var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
 				Value:       "users.delete.other",
 				Description: "Permission to delete other user.",
 			},
+			{
+				Value:       "users.identity.*",
+				Description: "Permission to view and review the identity documents of users.",
+			},
+			{
+				Value:       "users.identity.view",
+				Description: "Permission to view the unmasked identity documents of other users.",
+			},
+			{
+				Value:       "users.identity.review",
+				Description: "Permission to verify or reject the identity documents of users.",
+			},
 		},
 	},
 	{
@@ -113,6 +132,56 @@ var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
 			},
 		},
 	},
+	{
+		Name: "Addresses",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "addresses.*",
+				Description: "All permissions related to addresses.",
+			},
+			{
+				Value:       "addresses.view",
+				Description: "Permission to view the addresses of other users and of your organization.",
+			},
+			{
+				Value:       "addresses.create",
+				Description: "Permission to create addresses for other users and for your organization.",
+			},
+			{
+				Value:       "addresses.update",
+				Description: "Permission to update the addresses of other users and of your organization.",
+			},
+			{
+				Value:       "addresses.delete",
+				Description: "Permission to delete the addresses of other users and of your organization.",
+			},
+		},
+	},
+	{
+		Name: "Bank Details",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "bank_details.*",
+				Description: "All permissions related to bank details.",
+			},
+			{
+				Value:       "bank_details.view",
+				Description: "Permission to view the bank details of other users and of your organization.",
+			},
+			{
+				Value:       "bank_details.create",
+				Description: "Permission to create bank details for other users and for your organization.",
+			},
+			{
+				Value:       "bank_details.update",
+				Description: "Permission to update the bank details of other users and of your organization.",
+			},
+			{
+				Value:       "bank_details.delete",
+				Description: "Permission to delete the bank details of other users and of your organization.",
+			},
+		},
+	},
 	{
 		Name: "Roles",
 		Permissions: []models.AvailablePermission{
@@ -227,6 +296,34 @@ var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
 				Value:       "transactions.delete",
 				Description: "Permission to delete transactions.",
 			},
+			{
+				Value:       "transactions.sales.view",
+				Description: "Permission to view transactions where your organization is the seller.",
+			},
+			{
+				Value:       "transactions.purchases.view",
+				Description: "Permission to view transactions where your organization is the buyer.",
+			},
+			{
+				Value:       "transactions.accept",
+				Description: "Permission to accept or reject quotes on behalf of the buying organization.",
+			},
+			{
+				Value:       "transactions.cancel",
+				Description: "Permission to cancel transactions before delivery.",
+			},
+			{
+				Value:       "transactions.deliver",
+				Description: "Permission to confirm delivery and received weights on behalf of the buying organization.",
+			},
+			{
+				Value:       "transactions.invoice",
+				Description: "Permission to issue invoices on behalf of the selling organization.",
+			},
+			{
+				Value:       "transactions.settle",
+				Description: "Permission to record settlement of invoices on behalf of the selling organization.",
+			},
 		},
 	},
 	{
@@ -248,6 +345,10 @@ var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
 				Value:       "collections.view",
 				Description: "Permission to view collections.",
 			},
+			{
+				Value:       "collections.view.self",
+				Description: "Permission to view own collections, earnings and impact.",
+			},
 			{
 				Value:       "collections.update",
 				Description: "Permission to update collections.",
@@ -256,6 +357,260 @@ var AvailablePermissionsGroups = []models.AvailablePermissionsGroup{
 				Value:       "collections.delete",
 				Description: "Permission to delete collections.",
 			},
+			{
+				Value:       "collections.weigh",
+				Description: "Permission to mark collections as weighed.",
+			},
+			{
+				Value:       "collections.confirm",
+				Description: "Permission to confirm weighed collections.",
+			},
+			{
+				Value:       "collections.pay",
+				Description: "Permission to mark confirmed collections as paid.",
+			},
+			{
+				Value:       "collections.void",
+				Description: "Permission to void collections.",
+			},
+		},
+	},
+	{
+		Name: "Sites",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "sites.*",
+				Description: "All permissions related to sites.",
+			},
+			{
+				Value:       "sites.access",
+				Description: "Permission to access sites management.",
+			},
+			{
+				Value:       "sites.create",
+				Description: "Permission to create sites.",
+			},
+			{
+				Value:       "sites.view",
+				Description: "Permission to view sites.",
+			},
+			{
+				Value:       "sites.update",
+				Description: "Permission to update sites.",
+			},
+			{
+				Value:       "sites.delete",
+				Description: "Permission to delete sites.",
+			},
+		},
+	},
+	{
+		Name: "Inventory",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "inventory.*",
+				Description: "All permissions related to inventory.",
+			},
+			{
+				Value:       "inventory.view",
+				Description: "Permission to view stock on hand and the stock ledger.",
+			},
+			{
+				Value:       "inventory.adjust",
+				Description: "Permission to post manual stock adjustments.",
+			},
+			{
+				Value:       "inventory.override",
+				Description: "Permission to quote or deliver more material than is on hand.",
+			},
+		},
+	},
+	{
+		Name: "Payouts",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "payouts.*",
+				Description: "All permissions related to payouts.",
+			},
+			{
+				Value:       "payouts.view",
+				Description: "Permission to view payouts and payout batches.",
+			},
+			{
+				Value:       "payouts.create",
+				Description: "Permission to create payout batches.",
+			},
+			{
+				Value:       "payouts.export",
+				Description: "Permission to export payout batches as bank payment files.",
+			},
+			{
+				Value:       "payouts.confirm",
+				Description: "Permission to confirm that a payout batch has been paid.",
+			},
+			{
+				Value:       "payouts.reverse",
+				Description: "Permission to reverse payouts and payout batches.",
+			},
+			{
+				Value:       "payouts.cash",
+				Description: "Permission to record cash payouts.",
+			},
+		},
+	},
+	{
+		Name: "Pickups",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "pickups.*",
+				Description: "All permissions related to pickups.",
+			},
+			{
+				Value:       "pickups.view",
+				Description: "Permission to view pickups and the routes of other collectors.",
+			},
+			{
+				Value:       "pickups.create",
+				Description: "Permission to request pickups on behalf of other sellers.",
+			},
+			{
+				Value:       "pickups.assign",
+				Description: "Permission to assign pickups to collectors and dates.",
+			},
+			{
+				Value:       "pickups.complete",
+				Description: "Permission to complete pickups into collections.",
+			},
+			{
+				Value:       "pickups.cancel",
+				Description: "Permission to cancel pickups requested by other sellers.",
+			},
+		},
+	},
+	{
+		Name: "Reports",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "reports.*",
+				Description: "All permissions related to reports.",
+			},
+			{
+				Value:       "reports.view",
+				Description: "Permission to view reports of your active organization.",
+			},
+			{
+				Value:       "reports.global",
+				Description: "Permission to view reports of other organizations and across all organizations.",
+			},
+			{
+				Value:       "reports.epr",
+				Description: "Permission to generate and download EPR compliance reports.",
+			},
+		},
+	},
+	{
+		Name: "Imports",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "imports.*",
+				Description: "All permissions related to imports.",
+			},
+			{
+				Value:       "imports.view",
+				Description: "Permission to view the imports of your active organization and their errors.",
+			},
+			{
+				Value:       "imports.create",
+				Description: "Permission to upload imports and map their columns. The create permission of the records being imported is also needed.",
+			},
+			{
+				Value:       "imports.apply",
+				Description: "Permission to apply validated imports. The create permission of the records being imported is also needed.",
+			},
+		},
+	},
+	{
+		Name: "Jobs",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "jobs.*",
+				Description: "All permissions related to background jobs.",
+			},
+			{
+				Value:       "jobs.view",
+				Description: "Permission to view background jobs and their schedules.",
+			},
+			{
+				Value:       "jobs.retry",
+				Description: "Permission to retry background jobs that ran out of attempts.",
+			},
+		},
+	},
+	{
+		Name: "Webhooks",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "webhooks.*",
+				Description: "All permissions related to webhooks.",
+			},
+			{
+				Value:       "webhooks.view",
+				Description: "Permission to view the webhooks of your active organization, their secrets and their deliveries.",
+			},
+			{
+				Value:       "webhooks.create",
+				Description: "Permission to create webhooks for your active organization.",
+			},
+			{
+				Value:       "webhooks.update",
+				Description: "Permission to update the webhooks of your active organization and rotate their secrets.",
+			},
+			{
+				Value:       "webhooks.delete",
+				Description: "Permission to delete the webhooks of your active organization.",
+			},
+			{
+				Value:       "webhooks.redeliver",
+				Description: "Permission to deliver events to the webhooks of your active organization again.",
+			},
+		},
+	},
+	{
+		Name: "Scales",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "scales.*",
+				Description: "All permissions related to scales and weighbridges.",
+			},
+			{
+				Value:       "scales.view",
+				Description: "Permission to view the scales of your active organization and their readings.",
+			},
+			{
+				Value:       "scales.create",
+				Description: "Permission to register scales at the sites of your active organization.",
+			},
+			{
+				Value:       "scales.update",
+				Description: "Permission to update the scales of your active organization and rotate their device keys.",
+			},
+			{
+				Value:       "scales.delete",
+				Description: "Permission to delete the scales of your active organization that have not recorded readings.",
+			},
+		},
+	},
+	{
+		Name: "Trash",
+		Permissions: []models.AvailablePermission{
+			{
+				Value:       "trash.*",
+				Description: "All permissions related to the trash.",
+			},
+			{
+				Value:       "trash.view",
+				Description: "Permission to view the deleted records of every resource before they are purged.",
+			},
 		},
 	},
 }
@@ -0,0 +1,57 @@
+package constants
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+// Countries are the countries that addresses can be captured for.
+var Countries = []models.Country{
+	{Code: "ZA", Alpha3: "ZAF", Name: "South Africa"},
+	{Code: "BW", Alpha3: "BWA", Name: "Botswana"},
+	{Code: "LS", Alpha3: "LSO", Name: "Lesotho"},
+	{Code: "MZ", Alpha3: "MOZ", Name: "Mozambique"},
+	{Code: "NA", Alpha3: "NAM", Name: "Namibia"},
+	{Code: "SZ", Alpha3: "SWZ", Name: "Eswatini"},
+	{Code: "ZW", Alpha3: "ZWE", Name: "Zimbabwe"},
+}
+
+// Provinces are the provinces of South Africa.
+var Provinces = []models.Province{
+	{Code: "ZA-EC", CountryCode: "ZA", Name: "Eastern Cape"},
+	{Code: "ZA-FS", CountryCode: "ZA", Name: "Free State"},
+	{Code: "ZA-GP", CountryCode: "ZA", Name: "Gauteng"},
+	{Code: "ZA-KZN", CountryCode: "ZA", Name: "KwaZulu-Natal"},
+	{Code: "ZA-LP", CountryCode: "ZA", Name: "Limpopo"},
+	{Code: "ZA-MP", CountryCode: "ZA", Name: "Mpumalanga"},
+	{Code: "ZA-NC", CountryCode: "ZA", Name: "Northern Cape"},
+	{Code: "ZA-NW", CountryCode: "ZA", Name: "North West"},
+	{Code: "ZA-WC", CountryCode: "ZA", Name: "Western Cape"},
+}
+
+// Towns seed the offline gazetteer with the major towns of South Africa.
+var Towns = []models.Town{
+	{Name: "Johannesburg", ProvinceCode: "ZA-GP", Latitude: -26.2041, Longitude: 28.0473},
+	{Name: "Pretoria", Aliases: []string{"Tshwane"}, ProvinceCode: "ZA-GP", Latitude: -25.7479, Longitude: 28.2293},
+	{Name: "Soweto", ProvinceCode: "ZA-GP", Latitude: -26.2485, Longitude: 27.8540},
+	{Name: "Germiston", Aliases: []string{"Ekurhuleni"}, ProvinceCode: "ZA-GP", Latitude: -26.2309, Longitude: 28.1772},
+	{Name: "Vereeniging", ProvinceCode: "ZA-GP", Latitude: -26.6731, Longitude: 27.9261},
+	{Name: "Cape Town", ProvinceCode: "ZA-WC", Latitude: -33.9249, Longitude: 18.4241},
+	{Name: "Stellenbosch", ProvinceCode: "ZA-WC", Latitude: -33.9321, Longitude: 18.8602},
+	{Name: "Paarl", ProvinceCode: "ZA-WC", Latitude: -33.7342, Longitude: 18.9621},
+	{Name: "George", ProvinceCode: "ZA-WC", Latitude: -33.9630, Longitude: 22.4617},
+	{Name: "Durban", Aliases: []string{"eThekwini"}, ProvinceCode: "ZA-KZN", Latitude: -29.8587, Longitude: 31.0218},
+	{Name: "Pietermaritzburg", ProvinceCode: "ZA-KZN", Latitude: -29.6006, Longitude: 30.3794},
+	{Name: "Richards Bay", ProvinceCode: "ZA-KZN", Latitude: -28.7830, Longitude: 32.0377},
+	{Name: "Newcastle", ProvinceCode: "ZA-KZN", Latitude: -27.7580, Longitude: 29.9318},
+	{Name: "Gqeberha", Aliases: []string{"Port Elizabeth"}, ProvinceCode: "ZA-EC", Latitude: -33.9608, Longitude: 25.6022},
+	{Name: "East London", ProvinceCode: "ZA-EC", Latitude: -33.0153, Longitude: 27.9116},
+	{Name: "Mthatha", Aliases: []string{"Umtata"}, ProvinceCode: "ZA-EC", Latitude: -31.5889, Longitude: 28.7844},
+	{Name: "Bloemfontein", ProvinceCode: "ZA-FS", Latitude: -29.0852, Longitude: 26.1596},
+	{Name: "Welkom", ProvinceCode: "ZA-FS", Latitude: -27.9774, Longitude: 26.7351},
+	{Name: "Polokwane", Aliases: []string{"Pietersburg"}, ProvinceCode: "ZA-LP", Latitude: -23.9045, Longitude: 29.4689},
+	{Name: "Mbombela", Aliases: []string{"Nelspruit"}, ProvinceCode: "ZA-MP", Latitude: -25.4658, Longitude: 30.9853},
+	{Name: "eMalahleni", Aliases: []string{"Witbank"}, ProvinceCode: "ZA-MP", Latitude: -25.8713, Longitude: 29.2332},
+	{Name: "Kimberley", ProvinceCode: "ZA-NC", Latitude: -28.7282, Longitude: 24.7499},
+	{Name: "Upington", ProvinceCode: "ZA-NC", Latitude: -28.4478, Longitude: 21.2561},
+	{Name: "Mahikeng", Aliases: []string{"Mafikeng"}, ProvinceCode: "ZA-NW", Latitude: -25.8560, Longitude: 25.6403},
+	{Name: "Rustenburg", ProvinceCode: "ZA-NW", Latitude: -25.6676, Longitude: 27.2421},
+	{Name: "Potchefstroom", ProvinceCode: "ZA-NW", Latitude: -26.7145, Longitude: 27.0970},
+}
@@ -0,0 +1,225 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 50.0
+	bodySize     = 9.0
+	headingSize  = 14.0
+	lineSpacing  = 1.4
+	averageWidth = 0.5
+)
+
+// Pdf lays out a simple A4 document of headings, paragraphs and tables using
+// the standard Helvetica fonts. The output only depends on what was written,
+// so the same content always renders to the same bytes.
+type Pdf struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+	y       float64
+}
+
+func NewPdf() *Pdf {
+	pdf := &Pdf{}
+
+	pdf.addPage()
+
+	return pdf
+}
+
+// Heading writes a line of bold text in a larger size.
+func (p *Pdf) Heading(text string) {
+	p.space(headingSize * lineSpacing * 1.5)
+	p.write("F2", headingSize, pageMargin, text)
+	p.y -= headingSize * lineSpacing * 0.5
+}
+
+// Text writes a paragraph, wrapping it to the width of the page.
+func (p *Pdf) Text(text string) {
+	for _, line := range wrap(text, pageWidth-2*pageMargin, bodySize) {
+		p.space(bodySize * lineSpacing)
+		p.write("F1", bodySize, pageMargin, line)
+	}
+}
+
+// Gap leaves an empty line.
+func (p *Pdf) Gap() {
+	p.space(bodySize * lineSpacing)
+}
+
+// Table writes a header row in bold followed by the rows, in columns of the
+// given widths as fractions of the page width. Cells that do not fit are
+// shortened. The header is repeated at the top of every page.
+func (p *Pdf) Table(header []string, widths []float64, rows [][]string) {
+	p.row("F2", header, widths)
+	p.rule()
+
+	for _, row := range rows {
+		if p.y-bodySize*lineSpacing < pageMargin {
+			p.addPage()
+			p.row("F2", header, widths)
+			p.rule()
+		}
+
+		p.row("F1", row, widths)
+	}
+}
+
+// Bytes renders the document.
+func (p *Pdf) Bytes() []byte {
+	var buffer bytes.Buffer
+
+	offsets := []int{}
+
+	object := func(body string) {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buffer.WriteString("%PDF-1.4\n")
+
+	kids := []string{}
+
+	for index := range p.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+index*2))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for index, page := range p.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth,
+			pageHeight,
+			6+index*2,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+	}
+
+	xref := buffer.Len()
+
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buffer.Bytes()
+}
+
+func (p *Pdf) addPage() {
+	p.current = &bytes.Buffer{}
+	p.pages = append(p.pages, p.current)
+	p.y = pageHeight - pageMargin
+}
+
+// space moves down by height, starting a new page when it does not fit.
+func (p *Pdf) space(height float64) {
+	if p.y-height < pageMargin {
+		p.addPage()
+	}
+
+	p.y -= height
+}
+
+func (p *Pdf) row(font string, cells []string, widths []float64) {
+	p.space(bodySize * lineSpacing)
+
+	x := pageMargin
+
+	for index, cell := range cells {
+		width := widths[index] * (pageWidth - 2*pageMargin)
+
+		p.write(font, bodySize, x, fit(cell, width-4, bodySize))
+
+		x += width
+	}
+}
+
+func (p *Pdf) rule() {
+	y := p.y - bodySize*0.4
+
+	fmt.Fprintf(p.current, "%.2f w %.2f %.2f m %.2f %.2f l S\n", 0.5, pageMargin, y, pageWidth-pageMargin, y)
+
+	p.y -= bodySize * 0.4
+}
+
+func (p *Pdf) write(font string, size float64, x float64, text string) {
+	fmt.Fprintf(p.current, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.y, escape(text))
+}
+
+// escape encodes text for a PDF string in the WinAnsi encoding of the
+// standard fonts. Characters outside of Latin-1 are replaced.
+func escape(text string) string {
+	var builder strings.Builder
+
+	for _, character := range text {
+		switch {
+		case character == '(' || character == ')' || character == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(character)
+		case character < 32:
+			builder.WriteByte(' ')
+		case character < 128:
+			builder.WriteRune(character)
+		case character < 256:
+			fmt.Fprintf(&builder, "\\%03o", character)
+		default:
+			builder.WriteByte('?')
+		}
+	}
+
+	return builder.String()
+}
+
+// capacity estimates how many characters fit in width at the given size.
+func capacity(width float64, size float64) int {
+	return int(width / (size * averageWidth))
+}
+
+func fit(text string, width float64, size float64) string {
+	characters := []rune(text)
+	limit := capacity(width, size)
+
+	if len(characters) <= limit {
+		return text
+	}
+
+	if limit < 3 {
+		return string(characters[:max(limit, 0)])
+	}
+
+	return string(characters[:limit-3]) + "..."
+}
+
+func wrap(text string, width float64, size float64) []string {
+	limit := capacity(width, size)
+	lines := []string{}
+	line := ""
+
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > limit {
+			lines = append(lines, line)
+			line = ""
+		}
+
+		if line != "" {
+			line += " "
+		}
+
+		line += word
+	}
+
+	return append(lines, line)
+}
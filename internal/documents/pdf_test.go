@@ -0,0 +1,165 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Plastic", "Plastic"},
+		{"(a) \\ b", `\(a\) \\ b`},
+		{"line\nbreak\ttab", "line break tab"},
+		{"Café", `Caf\351`},
+		{"R 100 – €5", "R 100 ? ?5"},
+	}
+
+	for _, test := range tests {
+		if got := escape(test.text); got != test.want {
+			t.Errorf("escape(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	// At size 9 each character is estimated at 4.5 points.
+	tests := []struct {
+		text  string
+		width float64
+		want  string
+	}{
+		{"abcd", 18, "abcd"},
+		{"abcdef", 18, "a..."},
+		{"abcdef", 22.5, "ab..."},
+		{"abcdef", 9, "ab"},
+		{"abcdef", 0, ""},
+		{"éèêëà", 18, "é..."},
+	}
+
+	for _, test := range tests {
+		if got := fit(test.text, test.width, bodySize); got != test.want {
+			t.Errorf("fit(%q, %v) = %q, want %q", test.text, test.width, got, test.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	// A width of 45 at size 9 holds 10 characters.
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{""}},
+		{"short", []string{"short"}},
+		{"the quick brown fox", []string{"the quick", "brown fox"}},
+		{"  spaced   out  ", []string{"spaced out"}},
+		{"a verylongwordindeed b", []string{"a", "verylongwordindeed", "b"}},
+	}
+
+	for _, test := range tests {
+		if got := wrap(test.text, 45, bodySize); !slices.Equal(got, test.want) {
+			t.Errorf("wrap(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestPdfBytes(t *testing.T) {
+	render := func(rows int) []byte {
+		pdf := NewPdf()
+
+		pdf.Heading("Volumes")
+		pdf.Text("Collected material by site.")
+		pdf.Gap()
+
+		table := [][]string{}
+
+		for index := range rows {
+			table = append(table, []string{fmt.Sprintf("Site %d", index), "12.50"})
+		}
+
+		pdf.Table([]string{"Site", "Weight"}, []float64{0.7, 0.3}, table)
+
+		return pdf.Bytes()
+	}
+
+	tests := []struct {
+		name  string
+		rows  int
+		pages int
+	}{
+		{"one page", 10, 1},
+		{"several pages", 200, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content := render(test.rows)
+
+			if !bytes.Equal(content, render(test.rows)) {
+				t.Error("the same document rendered to different bytes")
+			}
+
+			if !bytes.HasPrefix(content, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(content, []byte("%%EOF\n")) {
+				t.Error("document is not framed as a PDF")
+			}
+
+			if got := bytes.Count(content, []byte("/Type /Page /Parent")); got != test.pages {
+				t.Errorf("document has %d pages, want %d", got, test.pages)
+			}
+
+			// The header row is repeated at the top of every page.
+			if got := bytes.Count(content, []byte("(Weight) Tj")); got != test.pages {
+				t.Errorf("table header written %d times, want %d", got, test.pages)
+			}
+
+			if got := bytes.Count(content, []byte("(Site ")); got != test.rows {
+				t.Errorf("table has %d rows, want %d", got, test.rows)
+			}
+
+			checkCrossReferences(t, content)
+		})
+	}
+}
+
+// checkCrossReferences checks that every entry of the cross reference table
+// points at the start of its object and that startxref points at the table.
+func checkCrossReferences(t *testing.T, content []byte) {
+	t.Helper()
+
+	text := string(content)
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(text)
+
+	if match == nil {
+		t.Fatal("document has no startxref")
+	}
+
+	xref, _ := strconv.Atoi(match[1])
+
+	if !strings.HasPrefix(text[xref:], "xref\n") {
+		t.Fatalf("startxref %d does not point at the cross reference table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(text[xref:], -1)
+
+	if len(entries) == 0 {
+		t.Fatal("cross reference table has no entries")
+	}
+
+	for index, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		object := fmt.Sprintf("%d 0 obj\n", index+1)
+
+		if !strings.HasPrefix(text[offset:], object) {
+			t.Errorf("offset %d of object %d does not point at it", offset, index+1)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Workbook builds an Office Open XML spreadsheet of one or more sheets, each
+// with a bold header row. Entries are stamped with the given time so that the
+// same content always renders to the same bytes.
+type Workbook struct {
+	modified time.Time
+	sheets   []worksheet
+}
+
+type worksheet struct {
+	name   string
+	header []string
+	rows   [][]any
+}
+
+func NewWorkbook(modified time.Time) *Workbook {
+	return &Workbook{
+		modified: modified,
+	}
+}
+
+// AddSheet adds a sheet. Cells may be strings or numbers.
+func (w *Workbook) AddSheet(name string, header []string, rows [][]any) {
+	w.sheets = append(w.sheets, worksheet{
+		name:   name,
+		header: header,
+		rows:   rows,
+	})
+}
+
+// Bytes renders the workbook.
+func (w *Workbook) Bytes() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+
+	names := []string{}
+
+	for _, sheet := range w.sheets {
+		names = append(names, sheet.name)
+	}
+
+	files := workbookParts(names)
+
+	for index, sheet := range w.sheets {
+		files = append(files, part{
+			name:    fmt.Sprintf("xl/worksheets/sheet%d.xml", index+1),
+			content: sheet.xml(),
+		})
+	}
+
+	for _, file := range files {
+		if _, err := writePart(archive, w.modified, file.name, file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// SheetWriter streams a workbook of a single sheet row by row, so that sheets
+// of any length can be written without holding their rows in memory.
+type SheetWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+}
+
+func NewSheetWriter(writer io.Writer, modified time.Time, name string, header []string) (*SheetWriter, error) {
+	archive := zip.NewWriter(writer)
+
+	for _, file := range workbookParts([]string{name}) {
+		if _, err := writePart(archive, modified, file.name, file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := writePart(archive, modified, "xl/worksheets/sheet1.xml", worksheetStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SheetWriter{
+		archive: archive,
+		sheet:   sheet,
+	}
+
+	if err := s.write(headerCells(header), 1); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WriteRow writes a row below the previous one. Cells may be strings or numbers.
+func (s *SheetWriter) WriteRow(cells []any) error {
+	return s.write(cells, 0)
+}
+
+// Close ends the sheet and the workbook. It does not close the underlying
+// writer.
+func (s *SheetWriter) Close() error {
+	if _, err := io.WriteString(s.sheet, worksheetEnd); err != nil {
+		return err
+	}
+
+	return s.archive.Close()
+}
+
+func (s *SheetWriter) write(cells []any, style int) error {
+	var builder strings.Builder
+
+	s.rows++
+
+	writeRow(&builder, s.rows, cells, style)
+
+	_, err := io.WriteString(s.sheet, builder.String())
+
+	return err
+}
+
+const (
+	worksheetStart = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	worksheetEnd   = `</sheetData></worksheet>`
+)
+
+type part struct {
+	name    string
+	content string
+}
+
+// workbookParts returns the parts of a workbook of the named sheets, other
+// than the sheets themselves.
+func workbookParts(names []string) []part {
+	sheets := []string{}
+	relationships := []string{}
+	overrides := []string{}
+
+	for index, name := range names {
+		number := index + 1
+
+		sheets = append(sheets, fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXml(name), number, number))
+		relationships = append(relationships, fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, number, number))
+		overrides = append(overrides, fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, number))
+	}
+
+	relationships = append(relationships, fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(names)+1))
+
+	return []part{
+		{
+			name: "[Content_Types].xml",
+			content: `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+				`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+				`<Default Extension="xml" ContentType="application/xml"/>` +
+				`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+				`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+				strings.Join(overrides, "") +
+				`</Types>`,
+		},
+		{
+			name: "_rels/.rels",
+			content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+				`</Relationships>`,
+		},
+		{
+			name: "xl/workbook.xml",
+			content: `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets>` + strings.Join(sheets, "") + `</sheets>` +
+				`</workbook>`,
+		},
+		{
+			name: "xl/_rels/workbook.xml.rels",
+			content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				strings.Join(relationships, "") +
+				`</Relationships>`,
+		},
+		{
+			name: "xl/styles.xml",
+			content: `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+				`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+				`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+				`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+				`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+				`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+				`</styleSheet>`,
+		},
+	}
+}
+
+// writePart starts an entry of the archive with the XML header and content,
+// returning the entry so that more content may be written to it.
+func writePart(archive *zip.Writer, modified time.Time, name string, content string) (io.Writer, error) {
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.WriteString(writer, xml.Header+content); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (s worksheet) xml() string {
+	var builder strings.Builder
+
+	builder.WriteString(worksheetStart)
+
+	writeRow(&builder, 1, headerCells(s.header), 1)
+
+	for index, row := range s.rows {
+		writeRow(&builder, index+2, row, 0)
+	}
+
+	builder.WriteString(worksheetEnd)
+
+	return builder.String()
+}
+
+func headerCells(header []string) []any {
+	cells := make([]any, len(header))
+
+	for index, value := range header {
+		cells[index] = value
+	}
+
+	return cells
+}
+
+func writeRow(builder *strings.Builder, number int, cells []any, style int) {
+	fmt.Fprintf(builder, `<row r="%d">`, number)
+
+	for index, cell := range cells {
+		reference := columnName(index) + strconv.Itoa(number)
+
+		switch value := cell.(type) {
+		case float64:
+			fmt.Fprintf(builder, `<c r="%s" s="%d"><v>%s</v></c>`, reference, style, strconv.FormatFloat(value, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(builder, `<c r="%s" s="%d"><v>%d</v></c>`, reference, style, value)
+		case int64:
+			fmt.Fprintf(builder, `<c r="%s" s="%d"><v>%d</v></c>`, reference, style, value)
+		default:
+			fmt.Fprintf(builder, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, reference, style, escapeXml(fmt.Sprint(value)))
+		}
+	}
+
+	builder.WriteString(`</row>`)
+}
+
+// columnName returns the letters of the zero based column, such as A or AB.
+func columnName(index int) string {
+	name := ""
+
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
+
+func escapeXml(value string) string {
+	var buffer bytes.Buffer
+
+	xml.EscapeText(&buffer, []byte(value))
+
+	return buffer.String()
+}
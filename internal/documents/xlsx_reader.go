@@ -0,0 +1,227 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maximumRows is the number of rows a sheet can hold in Excel.
+	maximumRows = 1048576
+	// maximumPartSize limits how large a part of a spreadsheet may be once
+	// decompressed, so that a small upload cannot exhaust memory.
+	maximumPartSize = 64 << 20
+)
+
+var (
+	ErrInvalidSpreadsheet = errors.New("the file is not a readable spreadsheet")
+	errMissingPart        = errors.New("the spreadsheet is missing a part")
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Relationship string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var builder strings.Builder
+
+	for _, run := range t.Runs {
+		builder.WriteString(run.Text)
+	}
+
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Reference string   `xml:"r,attr"`
+			Type      string   `xml:"t,attr"`
+			Value     string   `xml:"v"`
+			Inline    xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadSheet reads the first sheet of an Office Open XML spreadsheet as text,
+// one slice of cells per row. Rows and cells left out of the file are
+// returned empty so that the position of every cell matches the sheet.
+// Numbers, including dates, are returned as they are stored.
+func ReadSheet(content []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+
+	if err != nil {
+		return nil, ErrInvalidSpreadsheet
+	}
+
+	var workbook xlsxWorkbook
+
+	if err := readPart(archive, "xl/workbook.xml", &workbook); err != nil || len(workbook.Sheets) == 0 {
+		return nil, ErrInvalidSpreadsheet
+	}
+
+	var relationships xlsxRelationships
+
+	if err := readPart(archive, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, ErrInvalidSpreadsheet
+	}
+
+	sheetPath := ""
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id != workbook.Sheets[0].Relationship {
+			continue
+		}
+
+		if strings.HasPrefix(relationship.Target, "/") {
+			sheetPath = strings.TrimPrefix(relationship.Target, "/")
+		} else {
+			sheetPath = path.Join("xl", relationship.Target)
+		}
+	}
+
+	var sharedStrings xlsxSharedStrings
+
+	if err := readPart(archive, "xl/sharedStrings.xml", &sharedStrings); err != nil && err != errMissingPart {
+		return nil, ErrInvalidSpreadsheet
+	}
+
+	var worksheet xlsxWorksheet
+
+	if err := readPart(archive, sheetPath, &worksheet); err != nil {
+		return nil, ErrInvalidSpreadsheet
+	}
+
+	rows := [][]string{}
+
+	for _, row := range worksheet.Rows {
+		if row.Number == 0 {
+			row.Number = len(rows) + 1
+		}
+
+		if row.Number < len(rows)+1 || row.Number > maximumRows {
+			return nil, ErrInvalidSpreadsheet
+		}
+
+		for len(rows) < row.Number-1 {
+			rows = append(rows, []string{})
+		}
+
+		cells := []string{}
+
+		for _, cell := range row.Cells {
+			index := len(cells)
+
+			if cell.Reference != "" {
+				index = columnIndex(cell.Reference)
+			}
+
+			if index < len(cells) || index > 16383 {
+				return nil, ErrInvalidSpreadsheet
+			}
+
+			for len(cells) < index {
+				cells = append(cells, "")
+			}
+
+			value := cell.Value
+
+			switch cell.Type {
+			case "s":
+				item, err := strconv.Atoi(value)
+
+				if err != nil || item < 0 || item >= len(sharedStrings.Items) {
+					return nil, ErrInvalidSpreadsheet
+				}
+
+				value = sharedStrings.Items[item].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strings.ToUpper(strconv.FormatBool(value == "1"))
+			}
+
+			cells = append(cells, value)
+		}
+
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+func readPart(archive *zip.Reader, name string, target any) error {
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+
+		reader, err := file.Open()
+
+		if err != nil {
+			return err
+		}
+
+		defer reader.Close()
+
+		content, err := io.ReadAll(io.LimitReader(reader, maximumPartSize+1))
+
+		if err != nil {
+			return err
+		}
+
+		if len(content) > maximumPartSize {
+			return ErrInvalidSpreadsheet
+		}
+
+		return xml.Unmarshal(content, target)
+	}
+
+	return errMissingPart
+}
+
+// columnIndex returns the zero based column of a cell reference such as AB12.
+func columnIndex(reference string) int {
+	index := 0
+
+	for _, character := range reference {
+		if character < 'A' || character > 'Z' {
+			break
+		}
+
+		index = index*26 + int(character-'A'+1)
+	}
+
+	return index - 1
+}
@@ -0,0 +1,209 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      int
+	}{
+		{"A1", 0},
+		{"B7", 1},
+		{"Z10", 25},
+		{"AA1", 26},
+		{"AB12", 27},
+		{"ZZ3", 701},
+		{"AAA1", 702},
+		{"1", -1},
+	}
+
+	for _, test := range tests {
+		if got := columnIndex(test.reference); got != test.want {
+			t.Errorf("columnIndex(%q) = %d, want %d", test.reference, got, test.want)
+		}
+	}
+
+	for index := range 1000 {
+		if got := columnIndex(columnName(index) + "1"); got != index {
+			t.Fatalf("columnIndex(columnName(%d)) = %d", index, got)
+		}
+	}
+}
+
+// spreadsheet builds an archive of the given parts along with a workbook whose
+// first sheet is target.
+func spreadsheet(t *testing.T, target string, parts map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+
+	files := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="` + target + `"/></Relationships>`,
+	}
+
+	for name, content := range parts {
+		files[name] = content
+	}
+
+	for name, content := range files {
+		writer, err := archive.Create(name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func sheetXml(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func TestReadSheet(t *testing.T) {
+	sharedStrings := `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>Material</t></si><si><r><t>Pla</t></r><r><t>stic</t></r></si></sst>`
+
+	tests := []struct {
+		name    string
+		content func(t *testing.T) []byte
+		want    [][]string
+		err     error
+	}{
+		{
+			name: "written by Workbook",
+			content: func(t *testing.T) []byte {
+				workbook := NewWorkbook(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+				workbook.AddSheet("Materials", []string{"Name", "Weight"}, [][]any{
+					{"Glass & cans", 12.5},
+					{"Paper", 3},
+				})
+				workbook.AddSheet("Ignored", []string{"Other"}, nil)
+
+				content, err := workbook.Bytes()
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return content
+			},
+			want: [][]string{
+				{"Name", "Weight"},
+				{"Glass & cans", "12.5"},
+				{"Paper", "3"},
+			},
+		},
+		{
+			name: "shared strings, booleans and gaps",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", map[string]string{
+					"xl/sharedStrings.xml": sharedStrings,
+					"xl/worksheets/sheet1.xml": sheetXml(
+						`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="b"><v>1</v></c></row>` +
+							`<row r="3"><c r="B3" t="s"><v>1</v></c><c r="C3" t="b"><v>0</v></c></row>`,
+					),
+				})
+			},
+			want: [][]string{
+				{"Material", "", "TRUE"},
+				{},
+				{"", "Plastic", "FALSE"},
+			},
+		},
+		{
+			name: "absolute target without references",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "/xl/worksheets/data.xml", map[string]string{
+					"xl/worksheets/data.xml": sheetXml(`<row><c><v>1</v></c><c t="inlineStr"><is><t>a</t></is></c></row><row><c><v>2</v></c></row>`),
+				})
+			},
+			want: [][]string{
+				{"1", "a"},
+				{"2"},
+			},
+		},
+		{
+			name: "not an archive",
+			content: func(t *testing.T) []byte {
+				return []byte("Name,Weight\n")
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+		{
+			name: "missing sheet",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", nil)
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+		{
+			name: "shared string out of range",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", map[string]string{
+					"xl/sharedStrings.xml":     sharedStrings,
+					"xl/worksheets/sheet1.xml": sheetXml(`<row r="1"><c r="A1" t="s"><v>2</v></c></row>`),
+				})
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+		{
+			name: "rows out of order",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", map[string]string{
+					"xl/worksheets/sheet1.xml": sheetXml(`<row r="2"><c r="A2"><v>1</v></c></row><row r="1"><c r="A1"><v>2</v></c></row>`),
+				})
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+		{
+			name: "cells out of order",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", map[string]string{
+					"xl/worksheets/sheet1.xml": sheetXml(`<row r="1"><c r="B1"><v>1</v></c><c r="A1"><v>2</v></c></row>`),
+				})
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+		{
+			name: "too many rows",
+			content: func(t *testing.T) []byte {
+				return spreadsheet(t, "worksheets/sheet1.xml", map[string]string{
+					"xl/worksheets/sheet1.xml": sheetXml(`<row r="1048577"><c r="A1048577"><v>1</v></c></row>`),
+				})
+			},
+			err: ErrInvalidSpreadsheet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := ReadSheet(test.content(t))
+
+			if err != test.err {
+				t.Fatalf("ReadSheet() error = %v, want %v", err, test.err)
+			}
+
+			if err == nil && !reflect.DeepEqual(rows, test.want) {
+				t.Errorf("ReadSheet() = %q, want %q", rows, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, test := range tests {
+		if got := columnName(test.index); got != test.want {
+			t.Errorf("columnName(%d) = %q, want %q", test.index, got, test.want)
+		}
+	}
+}
+
+func TestWriteRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []any
+		style int
+		want  string
+	}{
+		{
+			name:  "text",
+			cells: []any{"Plastic"},
+			want:  `<row r="3"><c r="A3" s="0" t="inlineStr"><is><t>Plastic</t></is></c></row>`,
+		},
+		{
+			name:  "numbers",
+			cells: []any{12.5, 7, int64(9)},
+			want:  `<row r="3"><c r="A3" s="0"><v>12.5</v></c><c r="B3" s="0"><v>7</v></c><c r="C3" s="0"><v>9</v></c></row>`,
+		},
+		{
+			name:  "escaped",
+			cells: []any{`Glass & "cans" <mixed>`},
+			want:  `<row r="3"><c r="A3" s="0" t="inlineStr"><is><t>Glass &amp; &#34;cans&#34; &lt;mixed&gt;</t></is></c></row>`,
+		},
+		{
+			name:  "header style",
+			cells: []any{"Material"},
+			style: 1,
+			want:  `<row r="3"><c r="A3" s="1" t="inlineStr"><is><t>Material</t></is></c></row>`,
+		},
+		{
+			name: "empty",
+			want: `<row r="3"></row>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var builder strings.Builder
+
+			writeRow(&builder, 3, test.cells, test.style)
+
+			if got := builder.String(); got != test.want {
+				t.Errorf("writeRow() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func readParts(t *testing.T, content []byte) map[string]string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+
+	if err != nil {
+		t.Fatalf("failed to open workbook: %v", err)
+	}
+
+	parts := map[string]string{}
+
+	for _, file := range archive.File {
+		reader, err := file.Open()
+
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+
+		body, err := io.ReadAll(reader)
+
+		reader.Close()
+
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+
+		parts[file.Name] = string(body)
+	}
+
+	return parts
+}
+
+func TestWorkbookBytes(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	render := func() []byte {
+		workbook := NewWorkbook(modified)
+
+		workbook.AddSheet("Volumes", []string{"Material", "Weight"}, [][]any{
+			{"Plastic", 12.5},
+			{"Glass", 3},
+		})
+		workbook.AddSheet("Sites", []string{"Site"}, [][]any{
+			{"Depot"},
+		})
+
+		content, err := workbook.Bytes()
+
+		if err != nil {
+			t.Fatalf("Bytes() failed: %v", err)
+		}
+
+		return content
+	}
+
+	content := render()
+
+	if !bytes.Equal(content, render()) {
+		t.Error("the same workbook rendered to different bytes")
+	}
+
+	parts := readParts(t, content)
+
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook has no %s", name)
+		}
+	}
+
+	for _, name := range []string{`name="Volumes"`, `name="Sites"`} {
+		if !strings.Contains(parts["xl/workbook.xml"], name) {
+			t.Errorf("xl/workbook.xml does not contain %s", name)
+		}
+	}
+
+	want := xml.Header + worksheetStart +
+		`<row r="1"><c r="A1" s="1" t="inlineStr"><is><t>Material</t></is></c><c r="B1" s="1" t="inlineStr"><is><t>Weight</t></is></c></row>` +
+		`<row r="2"><c r="A2" s="0" t="inlineStr"><is><t>Plastic</t></is></c><c r="B2" s="0"><v>12.5</v></c></row>` +
+		`<row r="3"><c r="A3" s="0" t="inlineStr"><is><t>Glass</t></is></c><c r="B3" s="0"><v>3</v></c></row>` +
+		worksheetEnd
+
+	if got := parts["xl/worksheets/sheet1.xml"]; got != want {
+		t.Errorf("sheet1.xml = %s, want %s", got, want)
+	}
+}
+
+func TestSheetWriterMatchesWorkbook(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	header := []string{"Material", "Weight"}
+	rows := [][]any{
+		{"Plastic", 12.5},
+		{"Glass", 3},
+	}
+
+	workbook := NewWorkbook(modified)
+
+	workbook.AddSheet("Volumes", header, rows)
+
+	want, err := workbook.Bytes()
+
+	if err != nil {
+		t.Fatalf("Bytes() failed: %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	writer, err := NewSheetWriter(&buffer, modified, "Volumes", header)
+
+	if err != nil {
+		t.Fatalf("NewSheetWriter() failed: %v", err)
+	}
+
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() failed: %v", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if !bytes.Equal(buffer.Bytes(), want) {
+		t.Error("streamed sheet differs from the same sheet built as a workbook")
+	}
+}
@@ -0,0 +1,80 @@
+package exports
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+// CollectionLine is a row of a collections export, one for each material line
+// of a collection. Line is nil for a collection without lines.
+type CollectionLine struct {
+	Collection models.Collection
+	Line       *models.CollectionMaterial
+}
+
+// CollectionLines returns the rows of a collection.
+func CollectionLines(collection models.Collection) []CollectionLine {
+	if len(collection.Materials) == 0 {
+		return []CollectionLine{{Collection: collection}}
+	}
+
+	lines := []CollectionLine{}
+
+	for index := range collection.Materials {
+		lines = append(lines, CollectionLine{
+			Collection: collection,
+			Line:       &collection.Materials[index],
+		})
+	}
+
+	return lines
+}
+
+var CollectionLineColumns = []Column[CollectionLine]{
+	{Key: "id", Header: "Collection Id", Value: func(row CollectionLine) any { return row.Collection.Id.String() }},
+	{Key: "status", Header: "Status", Value: func(row CollectionLine) any { return string(row.Collection.Status) }},
+	{Key: "seller", Header: "Seller", Value: func(row CollectionLine) any { return row.Collection.Seller.Name }},
+	{Key: "buyer", Header: "Buyer", Value: func(row CollectionLine) any { return row.Collection.Buyer.Name }},
+	{Key: "site", Header: "Site", Value: func(row CollectionLine) any {
+		if row.Collection.Site == nil {
+			return ""
+		}
+
+		return row.Collection.Site.Name
+	}},
+	{Key: "voidReason", Header: "Void Reason", Value: func(row CollectionLine) any { return stringValue(row.Collection.VoidReason) }},
+	{Key: "createdAt", Header: "Created At", Value: func(row CollectionLine) any { return timeValue(&row.Collection.CreatedAt) }},
+	{Key: "updatedAt", Header: "Updated At", Value: func(row CollectionLine) any { return timeValue(&row.Collection.UpdatedAt) }},
+	{Key: "lineId", Header: "Line Id", Value: func(row CollectionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Id.String()
+	}},
+	{Key: "material", Header: "Material", Value: func(row CollectionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Material.Name
+	}},
+	{Key: "gwCode", Header: "GW Code", Value: func(row CollectionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Material.GWCode
+	}},
+	{Key: "weight", Header: "Weight (kg)", Value: func(row CollectionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Weight
+	}},
+	{Key: "value", Header: "Value", Value: func(row CollectionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Value
+	}},
+}
@@ -0,0 +1,75 @@
+package exports
+
+import (
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCollectionLines(t *testing.T) {
+	plastic := models.CollectionMaterial{
+		Material: models.Material{Name: "Plastic", GWCode: "GW01"},
+		Weight:   12.5,
+		Value:    40,
+	}
+	glass := models.CollectionMaterial{
+		Material: models.Material{Name: "Glass", GWCode: "GW02"},
+		Weight:   3,
+		Value:    6,
+	}
+
+	collection := models.Collection{
+		Base:   models.Base{Id: uuid.New()},
+		Status: models.CollectionConfirmed,
+		Seller: models.User{Name: "Thandi"},
+		Buyer:  models.Organization{Name: "Depot"},
+	}
+
+	tests := []struct {
+		name      string
+		materials []models.CollectionMaterial
+		want      [][]any
+	}{
+		{
+			name: "without lines",
+			want: [][]any{
+				{collection.Id.String(), "confirmed", "Thandi", "Depot", "", "", ""},
+			},
+		},
+		{
+			name:      "one row per line",
+			materials: []models.CollectionMaterial{plastic, glass},
+			want: [][]any{
+				{collection.Id.String(), "confirmed", "Thandi", "Depot", "Plastic", "GW01", 12.5},
+				{collection.Id.String(), "confirmed", "Thandi", "Depot", "Glass", "GW02", 3.0},
+			},
+		},
+	}
+
+	columns, err := Select(CollectionLineColumns, "id,status,seller,buyer,material,gwCode,weight")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collection.Materials = test.materials
+
+			lines := CollectionLines(collection)
+
+			if len(lines) != len(test.want) {
+				t.Fatalf("CollectionLines() returned %d rows, want %d", len(lines), len(test.want))
+			}
+
+			for row, line := range lines {
+				for index, column := range columns {
+					if got := column.Value(line); got != test.want[row][index] {
+						t.Errorf("row %d %s = %v, want %v", row, column.Key, got, test.want[row][index])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/documents"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type Format string
+
+const (
+	CsvFormat  Format = "csv"
+	XlsxFormat Format = "xlsx"
+)
+
+const (
+	CsvContentType  = "text/csv"
+	XlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("the requested export format is not supported")
+	ErrUnknownColumn     = errors.New("one or more of the requested export columns do not exist")
+)
+
+// Column is a column of an export, identified by its key in the columns query
+// parameter. Value returns the cell of a row, which should be a string or a
+// number.
+type Column[T any] struct {
+	Key    string
+	Header string
+	Value  func(T) any
+}
+
+// Requested returns the export format asked for by the format query parameter
+// or, when it is empty, by the Accept header. It returns an empty format when
+// JSON was asked for.
+func Requested(c *fiber.Ctx, format string) (Format, error) {
+	switch Format(format) {
+	case CsvFormat, XlsxFormat:
+		return Format(format), nil
+	case "":
+	default:
+		return "", ErrUnsupportedFormat
+	}
+
+	switch c.Accepts(fiber.MIMEApplicationJSON, CsvContentType, XlsxContentType) {
+	case CsvContentType:
+		return CsvFormat, nil
+	case XlsxContentType:
+		return XlsxFormat, nil
+	default:
+		return "", nil
+	}
+}
+
+// Select returns the columns named by a comma separated list of keys, in the
+// order they were named, or every column when no keys are given.
+func Select[T any](columns []Column[T], keys string) ([]Column[T], error) {
+	if strings.TrimSpace(keys) == "" {
+		return columns, nil
+	}
+
+	selected := []Column[T]{}
+
+	for _, key := range strings.Split(keys, ",") {
+		index := slices.IndexFunc(columns, func(column Column[T]) bool {
+			return column.Key == strings.TrimSpace(key)
+		})
+
+		if index < 0 {
+			return nil, ErrUnknownColumn
+		}
+
+		selected = append(selected, columns[index])
+	}
+
+	return selected, nil
+}
+
+// Send streams the rows passed to the handle function of each as a file in
+// the format. Rows are written as they are produced, so that every matching
+// row can be exported without holding them all in memory. The status has been
+// sent by the time each runs, so an error from it ends the file early and is
+// logged.
+func Send[T any](c *fiber.Ctx, format Format, name string, columns []Column[T], each func(handle func(T) error) error) error {
+	header := make([]string, len(columns))
+
+	for index, column := range columns {
+		header[index] = column.Header
+	}
+
+	c.Attachment(fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format))
+
+	if format == XlsxFormat {
+		c.Set(fiber.HeaderContentType, XlsxContentType)
+	} else {
+		c.Set(fiber.HeaderContentType, CsvContentType)
+	}
+
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		table, err := newTable(writer, format, header)
+
+		if err != nil {
+			log.Errorf("🔥 Error exporting %s: %s", name, err.Error())
+
+			return
+		}
+
+		if err := each(func(item T) error {
+			cells := make([]any, len(columns))
+
+			for index, column := range columns {
+				cells[index] = column.Value(item)
+			}
+
+			return table.WriteRow(cells)
+		}); err != nil {
+			log.Errorf("🔥 Error exporting %s: %s", name, err.Error())
+		}
+
+		if err := table.Close(); err != nil {
+			log.Errorf("🔥 Error exporting %s: %s", name, err.Error())
+		}
+	})
+
+	return nil
+}
+
+// Parameters documents the format and columns query parameters of a list
+// route that can be exported with the given columns.
+func Parameters[T any](columns []Column[T]) []*openapi3.ParameterRef {
+	return []*openapi3.ParameterRef{
+		{
+			Value: openapi3.NewQueryParameter("format").
+				WithSchema(openapi3.NewStringSchema().
+					WithEnum(string(CsvFormat), string(XlsxFormat))).
+				WithDescription("Export every matching item as a file instead of a page of JSON. An Accept header of text/csv or the XLSX content type does the same."),
+		},
+		{
+			Value: openapi3.NewQueryParameter("columns").
+				WithSchema(openapi3.NewStringSchema()).
+				WithDescription(fmt.Sprintf("Comma separated columns to export, in order. Defaults to all of: %s.", strings.Join(columnKeys(columns), ", "))),
+		},
+	}
+}
+
+// WithContent documents the file content types of a list route that can be
+// exported.
+func WithContent(response *openapi3.Response) *openapi3.Response {
+	if response.Content == nil {
+		response.Content = openapi3.Content{}
+	}
+
+	for _, contentType := range []string{CsvContentType, XlsxContentType} {
+		response.Content[contentType] = openapi3.NewMediaType().
+			WithSchema(openapi3.NewStringSchema().WithFormat("binary"))
+	}
+
+	return response
+}
+
+func columnKeys[T any](columns []Column[T]) []string {
+	keys := []string{}
+
+	for _, column := range columns {
+		keys = append(keys, column.Key)
+	}
+
+	return keys
+}
+
+type table interface {
+	WriteRow(cells []any) error
+	Close() error
+}
+
+func newTable(writer *bufio.Writer, format Format, header []string) (table, error) {
+	if format == XlsxFormat {
+		return documents.NewSheetWriter(writer, time.Now(), "Export", header)
+	}
+
+	table := &csvTable{
+		writer: csv.NewWriter(writer),
+	}
+
+	if err := table.writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+type csvTable struct {
+	writer *csv.Writer
+}
+
+func (t *csvTable) WriteRow(cells []any) error {
+	record := make([]string, len(cells))
+
+	for index, cell := range cells {
+		switch value := cell.(type) {
+		case float64:
+			record[index] = strconv.FormatFloat(value, 'f', -1, 64)
+		case int:
+			record[index] = strconv.Itoa(value)
+		case int64:
+			record[index] = strconv.FormatInt(value, 10)
+		default:
+			record[index] = neutralise(fmt.Sprint(value))
+		}
+	}
+
+	return t.writer.Write(record)
+}
+
+func (t *csvTable) Close() error {
+	t.writer.Flush()
+
+	return t.writer.Error()
+}
+
+// neutralise prefixes text that a spreadsheet would evaluate as a formula so
+// that it is shown as written.
+func neutralise(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// timeValue formats a time for a cell, leaving it empty when unset.
+func timeValue(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+
+	return value.Format(time.RFC3339)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func floatValue(value *float64) any {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,162 @@
+package exports
+
+import (
+	"bufio"
+	"bytes"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNeutralise(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Plastic", "Plastic"},
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"+27 82 000 0000", "'+27 82 000 0000"},
+		{"-1", "'-1"},
+		{"@handle", "'@handle"},
+		{"\tindented", "'\tindented"},
+		{"\rreturn", "'\rreturn"},
+		{"a=b", "a=b"},
+	}
+
+	for _, test := range tests {
+		if got := neutralise(test.value); got != test.want {
+			t.Errorf("neutralise(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	columns := []Column[string]{
+		{Key: "id", Header: "Id"},
+		{Key: "name", Header: "Name"},
+		{Key: "createdAt", Header: "Created At"},
+	}
+
+	tests := []struct {
+		name string
+		keys string
+		want []string
+		err  error
+	}{
+		{name: "every column", keys: "", want: []string{"id", "name", "createdAt"}},
+		{name: "blank", keys: "  ", want: []string{"id", "name", "createdAt"}},
+		{name: "ordered as named", keys: "createdAt,id", want: []string{"createdAt", "id"}},
+		{name: "spaces", keys: " name , id ", want: []string{"name", "id"}},
+		{name: "unknown", keys: "id,password", err: ErrUnknownColumn},
+		{name: "empty key", keys: "id,", err: ErrUnknownColumn},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected, err := Select(columns, test.keys)
+
+			if err != test.err {
+				t.Fatalf("Select(%q) error = %v, want %v", test.keys, err, test.err)
+			}
+
+			if got := columnKeys(selected); err == nil && !slices.Equal(got, test.want) {
+				t.Errorf("Select(%q) = %q, want %q", test.keys, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRequested(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		accept string
+		want   Format
+		err    error
+	}{
+		{name: "json by default", want: ""},
+		{name: "csv parameter", format: "csv", want: CsvFormat},
+		{name: "xlsx parameter", format: "xlsx", want: XlsxFormat},
+		{name: "unsupported parameter", format: "pdf", err: ErrUnsupportedFormat},
+		{name: "parameter wins", format: "csv", accept: XlsxContentType, want: CsvFormat},
+		{name: "csv accepted", accept: CsvContentType, want: CsvFormat},
+		{name: "xlsx accepted", accept: XlsxContentType, want: XlsxFormat},
+		{name: "json accepted", accept: fiber.MIMEApplicationJSON, want: ""},
+		{name: "json preferred", accept: fiber.MIMEApplicationJSON + ", " + CsvContentType + ";q=0.5", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := fiber.New()
+
+			var format Format
+			var err error
+
+			app.Get("/", func(c *fiber.Ctx) error {
+				format, err = Requested(c, c.Query("format"))
+
+				return nil
+			})
+
+			request := httptest.NewRequest(fiber.MethodGet, "/?format="+test.format, nil)
+
+			if test.accept != "" {
+				request.Header.Set(fiber.HeaderAccept, test.accept)
+			}
+
+			if _, testErr := app.Test(request); testErr != nil {
+				t.Fatal(testErr)
+			}
+
+			if err != test.err {
+				t.Fatalf("Requested() error = %v, want %v", err, test.err)
+			}
+
+			if format != test.want {
+				t.Errorf("Requested() = %q, want %q", format, test.want)
+			}
+		})
+	}
+}
+
+func TestCsvTable(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer := bufio.NewWriter(&buffer)
+
+	table, err := newTable(writer, CsvFormat, []string{"Name", "Weight", "Lines", "Total", "Note"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]any{
+		{"Plastic", 12.5, 3, int64(40), ""},
+		{"Glass, clear", 0.25, 0, int64(0), `said "hi"`},
+		{"=cmd", -1.5, -2, int64(-3), true},
+	}
+
+	for _, row := range rows {
+		if err := table.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := table.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	writer.Flush()
+
+	want := "Name,Weight,Lines,Total,Note\n" +
+		"Plastic,12.5,3,40,\n" +
+		"\"Glass, clear\",0.25,0,0,\"said \"\"hi\"\"\"\n" +
+		"'=cmd,-1.5,-2,-3,true\n"
+
+	if got := buffer.String(); got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package exports
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+var MaterialColumns = []Column[models.Material]{
+	{Key: "id", Header: "Id", Value: func(material models.Material) any { return material.Id.String() }},
+	{Key: "name", Header: "Name", Value: func(material models.Material) any { return material.Name }},
+	{Key: "gwCode", Header: "GW Code", Value: func(material models.Material) any { return material.GWCode }},
+	{Key: "carbonFactor", Header: "Carbon Factor", Value: func(material models.Material) any { return material.CarbonFactor }},
+	{Key: "regulated", Header: "Regulated", Value: func(material models.Material) any { return material.Regulated }},
+	{Key: "createdAt", Header: "Created At", Value: func(material models.Material) any { return timeValue(&material.CreatedAt) }},
+	{Key: "updatedAt", Header: "Updated At", Value: func(material models.Material) any { return timeValue(&material.UpdatedAt) }},
+}
@@ -0,0 +1,10 @@
+package exports
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+var OrganizationColumns = []Column[models.Organization]{
+	{Key: "id", Header: "Id", Value: func(organization models.Organization) any { return organization.Id.String() }},
+	{Key: "name", Header: "Name", Value: func(organization models.Organization) any { return organization.Name }},
+	{Key: "createdAt", Header: "Created At", Value: func(organization models.Organization) any { return timeValue(&organization.CreatedAt) }},
+	{Key: "updatedAt", Header: "Updated At", Value: func(organization models.Organization) any { return timeValue(&organization.UpdatedAt) }},
+}
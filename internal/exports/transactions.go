@@ -0,0 +1,87 @@
+package exports
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+// TransactionLine is a row of a transactions export, one for each material
+// line of a transaction. Line is nil for a transaction without lines.
+type TransactionLine struct {
+	Transaction models.Transaction
+	Line        *models.TransactionMaterial
+}
+
+// TransactionLines returns the rows of a transaction.
+func TransactionLines(transaction models.Transaction) []TransactionLine {
+	if len(transaction.Materials) == 0 {
+		return []TransactionLine{{Transaction: transaction}}
+	}
+
+	lines := []TransactionLine{}
+
+	for index := range transaction.Materials {
+		lines = append(lines, TransactionLine{
+			Transaction: transaction,
+			Line:        &transaction.Materials[index],
+		})
+	}
+
+	return lines
+}
+
+var TransactionLineColumns = []Column[TransactionLine]{
+	{Key: "id", Header: "Transaction Id", Value: func(row TransactionLine) any { return row.Transaction.Id.String() }},
+	{Key: "status", Header: "Status", Value: func(row TransactionLine) any { return string(row.Transaction.Status) }},
+	{Key: "seller", Header: "Seller", Value: func(row TransactionLine) any { return row.Transaction.Seller.Name }},
+	{Key: "buyer", Header: "Buyer", Value: func(row TransactionLine) any { return row.Transaction.Buyer.Name }},
+	{Key: "site", Header: "Site", Value: func(row TransactionLine) any {
+		if row.Transaction.Site == nil {
+			return ""
+		}
+
+		return row.Transaction.Site.Name
+	}},
+	{Key: "quoteExpiresAt", Header: "Quote Expires At", Value: func(row TransactionLine) any { return timeValue(row.Transaction.QuoteExpiresAt) }},
+	{Key: "createdAt", Header: "Created At", Value: func(row TransactionLine) any { return timeValue(&row.Transaction.CreatedAt) }},
+	{Key: "updatedAt", Header: "Updated At", Value: func(row TransactionLine) any { return timeValue(&row.Transaction.UpdatedAt) }},
+	{Key: "lineId", Header: "Line Id", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Id.String()
+	}},
+	{Key: "material", Header: "Material", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Material.Name
+	}},
+	{Key: "gwCode", Header: "GW Code", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Material.GWCode
+	}},
+	{Key: "weight", Header: "Weight (kg)", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Weight
+	}},
+	{Key: "receivedWeight", Header: "Received Weight (kg)", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return floatValue(row.Line.ReceivedWeight)
+	}},
+	{Key: "value", Header: "Value", Value: func(row TransactionLine) any {
+		if row.Line == nil {
+			return ""
+		}
+
+		return row.Line.Value
+	}},
+}
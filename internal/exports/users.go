@@ -0,0 +1,18 @@
+package exports
+
+import "github.com/connor-davis/threereco-nextgen/internal/models"
+
+var UserColumns = []Column[models.User]{
+	{Key: "id", Header: "Id", Value: func(user models.User) any { return user.Id.String() }},
+	{Key: "name", Header: "Name", Value: func(user models.User) any { return user.Name }},
+	{Key: "email", Header: "Email", Value: func(user models.User) any { return user.Email }},
+	{Key: "phone", Header: "Phone", Value: func(user models.User) any { return user.Phone }},
+	{Key: "type", Header: "Type", Value: func(user models.User) any { return string(user.Type) }},
+	{Key: "mfaEnabled", Header: "MFA Enabled", Value: func(user models.User) any { return user.MfaEnabled }},
+	{Key: "banned", Header: "Banned", Value: func(user models.User) any { return user.Banned }},
+	{Key: "banReason", Header: "Ban Reason", Value: func(user models.User) any { return stringValue(user.BanReason) }},
+	{Key: "identityNumber", Header: "Identity Number", Value: func(user models.User) any { return stringValue(user.MaskedIdentityNumber) }},
+	{Key: "identityStatus", Header: "Identity Status", Value: func(user models.User) any { return string(user.IdentityStatus) }},
+	{Key: "createdAt", Header: "Created At", Value: func(user models.User) any { return timeValue(&user.CreatedAt) }},
+	{Key: "updatedAt", Header: "Updated At", Value: func(user models.User) any { return timeValue(&user.UpdatedAt) }},
+}
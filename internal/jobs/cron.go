@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCron = errors.New("the cron expression is not valid")
+
+// cron is a parsed five field cron expression of minutes, hours, days of the
+// month, months and days of the week. Fields may be *, numbers, ranges such as
+// 1-5, steps such as */15 or 1-30/2, or comma separated lists of these.
+type cron struct {
+	minutes  []bool
+	hours    []bool
+	days     []bool
+	months   []bool
+	weekdays []bool
+	// anyDay and anyWeekday record unrestricted day fields, as a time
+	// matches either day field when both are restricted.
+	anyDay     bool
+	anyWeekday bool
+}
+
+func parseCron(spec string) (*cron, error) {
+	fields := strings.Fields(spec)
+
+	if len(fields) != 5 {
+		return nil, ErrInvalidCron
+	}
+
+	bounds := [][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	parsed := make([][]bool, len(fields))
+
+	for index, field := range fields {
+		values, err := parseCronField(field, bounds[index][0], bounds[index][1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[index] = values
+	}
+
+	// Sunday may be written as 0 or 7.
+	parsed[4][0] = parsed[4][0] || parsed[4][7]
+
+	return &cron{
+		minutes:    parsed[0],
+		hours:      parsed[1],
+		days:       parsed[2],
+		months:     parsed[3],
+		weekdays:   parsed[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, minimum int, maximum int) ([]bool, error) {
+	values := make([]bool, maximum+1)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if rangePart, stepPart, ok := strings.Cut(part, "/"); ok {
+			parsed, err := strconv.Atoi(stepPart)
+
+			if err != nil || parsed < 1 {
+				return nil, ErrInvalidCron
+			}
+
+			part = rangePart
+			step = parsed
+		}
+
+		start, end := minimum, maximum
+
+		if part != "*" {
+			startPart, endPart, isRange := strings.Cut(part, "-")
+
+			parsed, err := strconv.Atoi(startPart)
+
+			if err != nil {
+				return nil, ErrInvalidCron
+			}
+
+			start, end = parsed, parsed
+
+			if isRange {
+				if end, err = strconv.Atoi(endPart); err != nil {
+					return nil, ErrInvalidCron
+				}
+			} else if step > 1 {
+				end = maximum
+			}
+		}
+
+		if start < minimum || end > maximum || start > end {
+			return nil, ErrInvalidCron
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+// next returns the first minute after the given time that the expression
+// matches, in the location of the given time.
+func (c *cron) next(after time.Time) time.Time {
+	next := after.Truncate(time.Minute).Add(time.Minute)
+	// Every expression matches within a few years, as the 29th of February
+	// may be the only day it matches.
+	limit := next.AddDate(5, 0, 0)
+
+	for next.Before(limit) {
+		if !c.months[next.Month()] {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+
+			continue
+		}
+
+		if !c.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+
+			continue
+		}
+
+		if !c.hours[next.Hour()] {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+
+			continue
+		}
+
+		if !c.minutes[next.Minute()] {
+			next = next.Add(time.Minute)
+
+			continue
+		}
+
+		return next
+	}
+
+	return limit
+}
+
+func (c *cron) matchesDay(t time.Time) bool {
+	day := c.days[t.Day()]
+	weekday := c.weekdays[t.Weekday()]
+
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,159 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field string
+		want  []int
+		err   error
+	}{
+		{field: "*", want: []int{0, 1, 2, 3, 4, 5}},
+		{field: "3", want: []int{3}},
+		{field: "1-3", want: []int{1, 2, 3}},
+		{field: "*/2", want: []int{0, 2, 4}},
+		{field: "1-5/2", want: []int{1, 3, 5}},
+		{field: "2/3", want: []int{2, 5}},
+		{field: "0,4-5", want: []int{0, 4, 5}},
+		{field: "1,1", want: []int{1}},
+		{field: "6", err: ErrInvalidCron},
+		{field: "-1", err: ErrInvalidCron},
+		{field: "4-2", err: ErrInvalidCron},
+		{field: "*/0", err: ErrInvalidCron},
+		{field: "*/x", err: ErrInvalidCron},
+		{field: "a", err: ErrInvalidCron},
+		{field: "1-", err: ErrInvalidCron},
+		{field: "", err: ErrInvalidCron},
+		{field: "1,", err: ErrInvalidCron},
+	}
+
+	for _, test := range tests {
+		values, err := parseCronField(test.field, 0, 5)
+
+		if err != test.err {
+			t.Errorf("parseCronField(%q) error = %v, want %v", test.field, err, test.err)
+
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		got := []int{}
+
+		for value, set := range values {
+			if set {
+				got = append(got, value)
+			}
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("parseCronField(%q) = %v, want %v", test.field, got, test.want)
+
+			continue
+		}
+
+		for index := range got {
+			if got[index] != test.want[index] {
+				t.Errorf("parseCronField(%q) = %v, want %v", test.field, got, test.want)
+
+				break
+			}
+		}
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  error
+	}{
+		{spec: "* * * * *"},
+		{spec: "0 2 * * 1-5"},
+		{spec: "  */15   *  * * *  "},
+		{spec: "0 0 1 1 7"},
+		{spec: "* * * *", err: ErrInvalidCron},
+		{spec: "* * * * * *", err: ErrInvalidCron},
+		{spec: "60 * * * *", err: ErrInvalidCron},
+		{spec: "* 24 * * *", err: ErrInvalidCron},
+		{spec: "* * 0 * *", err: ErrInvalidCron},
+		{spec: "* * 32 * *", err: ErrInvalidCron},
+		{spec: "* * * 13 *", err: ErrInvalidCron},
+		{spec: "* * * * 8", err: ErrInvalidCron},
+		{spec: "@daily", err: ErrInvalidCron},
+	}
+
+	for _, test := range tests {
+		if _, err := parseCron(test.spec); err != test.err {
+			t.Errorf("parseCron(%q) error = %v, want %v", test.spec, err, test.err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	johannesburg, err := time.LoadLocation("Africa/Johannesburg")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 March 2025 was a Saturday.
+	at := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, johannesburg)
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", at(3, 1, 10, 0), at(3, 1, 10, 1)},
+		{"seconds are dropped", "* * * * *", at(3, 1, 10, 0).Add(30 * time.Second), at(3, 1, 10, 1)},
+		{"quarter hours", "*/15 * * * *", at(3, 1, 10, 7), at(3, 1, 10, 15)},
+		{"next hour", "*/15 * * * *", at(3, 1, 10, 45), at(3, 1, 11, 0)},
+		{"daily later today", "30 2 * * *", at(3, 1, 1, 0), at(3, 1, 2, 30)},
+		{"daily tomorrow", "30 2 * * *", at(3, 1, 2, 30), at(3, 2, 2, 30)},
+		{"weekdays skip the weekend", "0 9 * * 1-5", at(3, 1, 10, 0), at(3, 3, 9, 0)},
+		{"sunday as 0", "0 9 * * 0", at(3, 1, 10, 0), at(3, 2, 9, 0)},
+		{"sunday as 7", "0 9 * * 7", at(3, 1, 10, 0), at(3, 2, 9, 0)},
+		{"day of the month", "0 0 15 * *", at(3, 16, 0, 0), at(4, 15, 0, 0)},
+		{"either day field", "0 0 15 * 1", at(3, 1, 0, 0), at(3, 3, 0, 0)},
+		{"next month", "0 0 1 * *", at(3, 1, 0, 0), at(4, 1, 0, 0)},
+		{"next year", "0 0 1 1 *", at(3, 1, 0, 0), time.Date(2026, 1, 1, 0, 0, 0, 0, johannesburg)},
+		{"31st skips short months", "0 0 31 * *", at(3, 31, 0, 0), at(5, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(3, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, johannesburg)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expression, err := parseCron(test.spec)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := expression.next(test.after); !got.Equal(test.want) {
+				t.Errorf("next(%s) = %s, want %s", test.after, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCronNextNeverMatches(t *testing.T) {
+	expression, err := parseCron("0 0 30 2 *")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, want := expression.next(after), after.Add(time.Minute).AddDate(5, 0, 0); !got.Equal(want) {
+		t.Errorf("next(%s) = %s, want %s", after, got, want)
+	}
+}
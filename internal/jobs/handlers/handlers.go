@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/jobs"
+	"github.com/connor-davis/threereco-nextgen/internal/services"
+)
+
+// Register adds the handler of every kind of job to a runner and stores the
+// schedules of the jobs that run periodically. Deleted records are purged
+// once they have been in the trash for the given number of days.
+func Register(runner *jobs.Runner, s services.Services, trashRetentionDays int) error {
+	jobs.Register(runner, services.ProcessImportJob, func(ctx context.Context, payload services.ProcessImportPayload) error {
+		return s.Imports().Process(payload.ImportId, payload.Apply, jobs.FinalAttempt(ctx))
+	})
+
+	jobs.Register(runner, services.RefreshReportSummariesJob, func(ctx context.Context, payload struct{}) error {
+		return s.ReportSummaries().Refresh()
+	})
+
+	jobs.Register(runner, services.CleanupJobsJob, func(ctx context.Context, payload struct{}) error {
+		return s.Jobs().Cleanup()
+	})
+
+	jobs.Register(runner, services.DeliverWebhookJob, func(ctx context.Context, payload services.DeliverWebhookPayload) error {
+		return s.Webhooks().Deliver(payload.DeliveryId, jobs.FinalAttempt(ctx))
+	})
+
+	jobs.Register(runner, services.CleanupChangesJob, func(ctx context.Context, payload struct{}) error {
+		return s.Changes().Cleanup()
+	})
+
+	jobs.Register(runner, services.CleanupIdempotencyKeysJob, func(ctx context.Context, payload struct{}) error {
+		return s.Idempotency().Cleanup()
+	})
+
+	jobs.Register(runner, services.PurgeTrashJob, func(ctx context.Context, payload services.PurgeTrashPayload) error {
+		return s.Trash().Purge(time.Now().AddDate(0, 0, -payload.RetentionDays))
+	})
+
+	if err := runner.Schedule("report-summaries", "* * * * *", services.RefreshReportSummariesJob, struct{}{}); err != nil {
+		return err
+	}
+
+	if err := runner.Schedule("jobs-cleanup", "0 3 * * *", services.CleanupJobsJob, struct{}{}); err != nil {
+		return err
+	}
+
+	if err := runner.Schedule("changes-cleanup", "0 * * * *", services.CleanupChangesJob, struct{}{}); err != nil {
+		return err
+	}
+
+	if err := runner.Schedule("idempotency-keys-cleanup", "15 * * * *", services.CleanupIdempotencyKeysJob, struct{}{}); err != nil {
+		return err
+	}
+
+	return runner.Schedule("trash-purge", "30 2 * * *", services.PurgeTrashJob, services.PurgeTrashPayload{
+		RetentionDays: trashRetentionDays,
+	})
+}
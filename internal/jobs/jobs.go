@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"gorm.io/gorm"
+)
+
+// defaultMaxAttempts is how many times a job is run before it is dead.
+const defaultMaxAttempts = 5
+
+// Handler runs a job. A job whose handler returns an error or panics is run
+// again later until it runs out of attempts.
+type Handler func(ctx context.Context, job models.Job) error
+
+type jobContextKey struct{}
+
+// FinalAttempt reports whether the job a handler was given the context of is
+// on its last attempt, after which it is dead if it fails.
+func FinalAttempt(ctx context.Context) bool {
+	job, ok := ctx.Value(jobContextKey{}).(*models.Job)
+
+	return ok && job.Attempts >= job.MaxAttempts
+}
+
+// Option changes a job being enqueued.
+type Option func(job *models.Job)
+
+// RunAt delays a job until the given time.
+func RunAt(runAt time.Time) Option {
+	return func(job *models.Job) {
+		job.RunAt = runAt
+	}
+}
+
+// MaxAttempts sets how many times a job is run before it is dead.
+func MaxAttempts(attempts int) Option {
+	return func(job *models.Job) {
+		job.MaxAttempts = attempts
+	}
+}
+
+// Enqueue stores a job of a kind with its payload encoded as JSON. Passing the
+// database transaction that makes the change the job follows from commits the
+// job with the change, so that neither is stored without the other.
+func Enqueue(db *gorm.DB, kind string, payload any, options ...Option) (*models.Job, error) {
+	encoded, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		Kind:        kind,
+		Payload:     encoded,
+		Status:      models.JobPending,
+		RunAt:       time.Now(),
+		MaxAttempts: defaultMaxAttempts,
+	}
+
+	for _, option := range options {
+		option(&job)
+	}
+
+	if err := db.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// Register adds the handler of a kind of job to the runner, decoding the
+// payload of each job into the payload type of the handler.
+func Register[T any](runner *Runner, kind string, handle func(ctx context.Context, payload T) error) {
+	runner.handlers[kind] = func(ctx context.Context, job models.Job) error {
+		var payload T
+
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+
+		return handle(ctx, payload)
+	}
+}
@@ -0,0 +1,379 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// pollInterval is how long a worker waits before looking for jobs again
+	// when there were none to run.
+	pollInterval = time.Second
+	// maintenanceInterval is how often due schedules are enqueued and jobs
+	// of stopped workers are recovered.
+	maintenanceInterval = 15 * time.Second
+	// heartbeatInterval is how often a running job is marked as still running.
+	heartbeatInterval = 30 * time.Second
+	// staleAfter is how long a running job may go without a heartbeat before
+	// its worker is taken to have stopped.
+	staleAfter = 5 * time.Minute
+	// scheduleTimeZone is the time zone cron expressions are evaluated in.
+	scheduleTimeZone = "Africa/Johannesburg"
+)
+
+// Runner runs the jobs of the kinds it has handlers for with a number of
+// workers, and enqueues the jobs of schedules as they come due. Jobs are
+// claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of runners,
+// in the API or in worker processes, may share the queue.
+type Runner struct {
+	db          *gorm.DB
+	handlers    map[string]Handler
+	concurrency int
+	worker      string
+}
+
+func NewRunner(storage storage.Storage, concurrency int) *Runner {
+	hostname, err := os.Hostname()
+
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &Runner{
+		db:          storage.Postgres,
+		handlers:    map[string]Handler{},
+		concurrency: concurrency,
+		worker:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Schedule stores a schedule that enqueues a job of a kind with the payload
+// every time the cron expression matches. The next run of a schedule that is
+// already stored is only changed when its expression changes.
+func (r *Runner) Schedule(name string, spec string, kind string, payload any) error {
+	expression, err := parseCron(spec)
+
+	if err != nil {
+		return err
+	}
+
+	location, err := time.LoadLocation(scheduleTimeZone)
+
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	return r.db.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "name"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"kind":    gorm.Expr("excluded.kind"),
+				"payload": gorm.Expr("excluded.payload"),
+				"next_run_at": gorm.Expr(
+					"CASE WHEN job_schedules.spec = excluded.spec THEN job_schedules.next_run_at ELSE excluded.next_run_at END",
+				),
+				"spec":       gorm.Expr("excluded.spec"),
+				"updated_at": gorm.Expr("now()"),
+			}),
+		}).
+		Create(&models.JobSchedule{
+			Name:      name,
+			Kind:      kind,
+			Spec:      spec,
+			Payload:   encoded,
+			NextRunAt: expression.next(time.Now().In(location)),
+		}).Error
+}
+
+// Run runs jobs until the context is cancelled, then waits for the jobs that
+// are running to finish.
+func (r *Runner) Run(ctx context.Context) {
+	var group sync.WaitGroup
+
+	log.Infof("✅ Running jobs with %d workers as %s", r.concurrency, r.worker)
+
+	for range r.concurrency {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			r.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(maintenanceInterval)
+
+	defer ticker.Stop()
+
+	for {
+		if err := r.enqueueSchedules(); err != nil {
+			log.Errorf("🔥 Error enqueueing scheduled jobs: %s", err.Error())
+		}
+
+		if err := r.recoverStale(); err != nil {
+			log.Errorf("🔥 Error recovering stale jobs: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			group.Wait()
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// work runs jobs one at a time, waiting for more when there are none.
+func (r *Runner) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := r.claim()
+
+		if err != nil {
+			log.Errorf("🔥 Error claiming job: %s", err.Error())
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+
+			continue
+		}
+
+		r.execute(ctx, job)
+	}
+}
+
+// claim locks the next due job of a kind with a handler and marks it as
+// running, returning nil when there is none.
+func (r *Runner) claim() (*models.Job, error) {
+	if len(r.handlers) == 0 {
+		return nil, nil
+	}
+
+	kinds := []string{}
+
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	var jobs []models.Job
+
+	if err := r.db.Raw(`
+		UPDATE jobs
+		SET status = @running, attempts = attempts + 1, locked_at = now(), locked_by = @worker, updated_at = now()
+		WHERE id = (
+			SELECT id
+			FROM jobs
+			WHERE status = @pending AND run_at <= now() AND kind IN @kinds
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, map[string]any{
+		"running": models.JobRunning,
+		"pending": models.JobPending,
+		"worker":  r.worker,
+		"kinds":   kinds,
+	}).Scan(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return &jobs[0], nil
+}
+
+// execute runs a claimed job, keeping its lock fresh while it runs, and
+// records whether it succeeded.
+func (r *Runner) execute(ctx context.Context, job *models.Job) {
+	done := make(chan struct{})
+
+	go r.heartbeat(job, done)
+
+	err := r.handle(ctx, job)
+
+	close(done)
+
+	if err := r.finish(job, err); err != nil {
+		log.Errorf("🔥 Error recording result of job %s: %s", job.Id, err.Error())
+	}
+}
+
+func (r *Runner) handle(ctx context.Context, job *models.Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return r.handlers[job.Kind](context.WithValue(ctx, jobContextKey{}, job), *job)
+}
+
+func (r *Runner) heartbeat(job *models.Job, done chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := r.db.
+				Model(&models.Job{}).
+				Where("id = ? AND status = ? AND locked_by = ?", job.Id, models.JobRunning, r.worker).
+				Update("locked_at", time.Now()).Error; err != nil {
+				log.Errorf("🔥 Error extending lock of job %s: %s", job.Id, err.Error())
+			}
+		}
+	}
+}
+
+// finish records the result of a job. A failed job is run again after a
+// backoff that doubles with each attempt, unless it has run out of attempts.
+func (r *Runner) finish(job *models.Job, err error) error {
+	now := time.Now()
+
+	updates := map[string]any{
+		"locked_at": nil,
+		"locked_by": nil,
+	}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.JobSucceeded
+		updates["finished_at"] = now
+	case job.Attempts >= job.MaxAttempts:
+		log.Errorf("🔥 Job %s of kind %s is dead after %d attempts: %s", job.Id, job.Kind, job.Attempts, err.Error())
+
+		updates["status"] = models.JobDead
+		updates["last_error"] = err.Error()
+		updates["finished_at"] = now
+	default:
+		log.Warnf("⚠️ Job %s of kind %s failed and will be retried: %s", job.Id, job.Kind, err.Error())
+
+		updates["status"] = models.JobPending
+		updates["last_error"] = err.Error()
+		updates["run_at"] = now.Add(backoff(job.Attempts))
+	}
+
+	return r.db.
+		Model(&models.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.Id, models.JobRunning, r.worker).
+		Updates(updates).Error
+}
+
+// backoff is how long to wait before the next attempt of a job that has been
+// attempted the given number of times.
+func backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+
+	for range attempts - 1 {
+		delay *= 2
+
+		if delay >= time.Hour {
+			return time.Hour
+		}
+	}
+
+	return delay
+}
+
+// enqueueSchedules enqueues a job for every schedule that is due and moves the
+// schedule to its next run. Runs missed while no runner was running are not
+// made up, so a schedule is enqueued once however long it was overdue.
+func (r *Runner) enqueueSchedules() error {
+	location, err := time.LoadLocation(scheduleTimeZone)
+
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var schedules []models.JobSchedule
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_run_at <= now()").
+			Find(&schedules).Error; err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			expression, err := parseCron(schedule.Spec)
+
+			if err != nil {
+				log.Errorf("🔥 Schedule %s has an invalid cron expression: %s", schedule.Name, schedule.Spec)
+
+				continue
+			}
+
+			name := schedule.Name
+
+			if _, err := Enqueue(tx, schedule.Kind, schedule.Payload, func(job *models.Job) {
+				job.Schedule = &name
+			}); err != nil {
+				return err
+			}
+
+			now := time.Now()
+
+			if err := tx.
+				Model(&models.JobSchedule{}).
+				Where("name = ?", schedule.Name).
+				Updates(map[string]any{
+					"last_run_at": now,
+					"next_run_at": expression.next(now.In(location)),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// recoverStale makes the jobs of workers that stopped while running them
+// pending again, or dead when they have run out of attempts.
+func (r *Runner) recoverStale() error {
+	return r.db.Exec(`
+		UPDATE jobs
+		SET
+			status = CASE WHEN attempts >= max_attempts THEN @dead ELSE @pending END,
+			finished_at = CASE WHEN attempts >= max_attempts THEN now() ELSE NULL END,
+			run_at = now(),
+			locked_at = NULL,
+			locked_by = NULL,
+			last_error = 'the worker running the job stopped',
+			updated_at = now()
+		WHERE status = @running AND locked_at < @stale
+	`, map[string]any{
+		"dead":    models.JobDead,
+		"pending": models.JobPending,
+		"running": models.JobRunning,
+		"stale":   time.Now().Add(-staleAfter),
+	}).Error
+}
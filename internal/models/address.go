@@ -1,13 +1,20 @@
 package models
 
+// Address is a postal address. Province and country are normalised to their
+// registered names and ISO codes, and the address is geocoded when it is saved
+// so that it can be plotted on a map.
 type Address struct {
 	Base
-	LineOne  string  `json:"lineOne" gorm:"type:text;not null"`
-	LineTwo  *string `json:"lineTwo" gorm:"type:text;"`
-	City     string  `json:"city" gorm:"type:text;not null"`
-	ZipCode  string  `json:"zipCode" gorm:"type:text;not null"`
-	Province string  `json:"province" gorm:"type:text;not null"`
-	Country  string  `json:"country" gorm:"type:text;not null"`
+	LineOne      string   `json:"lineOne" gorm:"type:text;not null"`
+	LineTwo      *string  `json:"lineTwo" gorm:"type:text;"`
+	City         string   `json:"city" gorm:"type:text;not null"`
+	ZipCode      string   `json:"zipCode" gorm:"type:text;not null"`
+	Province     string   `json:"province" gorm:"type:text;not null"`
+	ProvinceCode *string  `json:"provinceCode" gorm:"type:text"`
+	Country      string   `json:"country" gorm:"type:text;not null"`
+	CountryCode  *string  `json:"countryCode" gorm:"type:text"`
+	Latitude     *float64 `json:"latitude" gorm:"type:decimal(9,6)"`
+	Longitude    *float64 `json:"longitude" gorm:"type:decimal(9,6)"`
 }
 
 type CreateAddressPayload struct {
@@ -0,0 +1,45 @@
+package models
+
+import "github.com/google/uuid"
+
+type AttachmentParent string
+
+const (
+	CollectionAttachment   AttachmentParent = "collections"
+	TransactionAttachment  AttachmentParent = "transactions"
+	UserAttachment         AttachmentParent = "users"
+	OrganizationAttachment AttachmentParent = "organizations"
+)
+
+type AttachmentCategory string
+
+const (
+	PhotoAttachment        AttachmentCategory = "photo"
+	DeliveryNoteAttachment AttachmentCategory = "delivery_note"
+	IdDocumentAttachment   AttachmentCategory = "id_document"
+	OtherAttachment        AttachmentCategory = "other"
+)
+
+// Attachment is a photo or document kept as proof for a collection,
+// transaction, user or organization. The file is kept in the file store,
+// along with a JPEG thumbnail when it is an image. Checksum is the hex SHA-256
+// of the file, so that a downloaded copy can be checked against it.
+type Attachment struct {
+	Base
+	ParentType   AttachmentParent   `json:"parentType" gorm:"type:text;not null;index:idx_attachments_parent"`
+	ParentId     uuid.UUID          `json:"parentId" gorm:"type:uuid;not null;index:idx_attachments_parent"`
+	Category     AttachmentCategory `json:"category" gorm:"type:text;not null;default:'other'"`
+	Description  *string            `json:"description" gorm:"type:text"`
+	FileName     string             `json:"fileName" gorm:"type:text;not null"`
+	ContentType  string             `json:"contentType" gorm:"type:text;not null"`
+	Size         int64              `json:"size" gorm:"type:bigint;not null"`
+	Checksum     string             `json:"checksum" gorm:"type:text;not null"`
+	StorageKey   string             `json:"-" gorm:"type:text;not null"`
+	HasThumbnail bool               `json:"hasThumbnail" gorm:"not null;default:false"`
+	UploadedById uuid.UUID          `json:"uploadedById" gorm:"type:uuid;not null"`
+}
+
+type CreateAttachmentPayload struct {
+	Category    AttachmentCategory `json:"category" form:"category"`
+	Description *string            `json:"description" form:"description"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Bank is a South African bank that accounts can be paid into. Account numbers
+// are validated against the lengths the bank accepts.
+type Bank struct {
+	Name                string `json:"name"`
+	UniversalBranchCode string `json:"universalBranchCode"`
+	AccountLengths      []int  `json:"accountLengths"`
+}
@@ -1,23 +1,34 @@
 package models
 
+type BankAccountType string
+
+const (
+	ChequeAccount       BankAccountType = "cheque"
+	SavingsAccount      BankAccountType = "savings"
+	TransmissionAccount BankAccountType = "transmission"
+)
+
 type BankDetails struct {
 	Base
-	AccountHolder string `json:"accountHolder" gorm:"type:text;not null"`
-	AccountNumber string `json:"accountNumber" gorm:"type:text;not null"`
-	BankName      string `json:"bankName" gorm:"type:text;not null"`
-	BranchCode    string `json:"branchCode" gorm:"type:text;not null"`
+	AccountHolder string          `json:"accountHolder" gorm:"type:text;not null"`
+	AccountNumber string          `json:"accountNumber" gorm:"type:text;not null"`
+	AccountType   BankAccountType `json:"accountType" gorm:"type:text;not null;default:'cheque'"`
+	BankName      string          `json:"bankName" gorm:"type:text;not null"`
+	BranchCode    string          `json:"branchCode" gorm:"type:text;not null"`
 }
 
 type CreateBankDetailsPayload struct {
-	AccountHolder string `json:"accountHolder"`
-	AccountNumber string `json:"accountNumber"`
-	BankName      string `json:"bankName"`
-	BranchCode    string `json:"branchCode"`
+	AccountHolder string          `json:"accountHolder"`
+	AccountNumber string          `json:"accountNumber"`
+	AccountType   BankAccountType `json:"accountType"`
+	BankName      string          `json:"bankName"`
+	BranchCode    string          `json:"branchCode"`
 }
 
 type UpdateBankDetailsPayload struct {
-	AccountHolder *string `json:"accountHolder"`
-	AccountNumber *string `json:"accountNumber"`
-	BankName      *string `json:"bankName"`
-	BranchCode    *string `json:"branchCode"`
+	AccountHolder *string          `json:"accountHolder"`
+	AccountNumber *string          `json:"accountNumber"`
+	AccountType   *BankAccountType `json:"accountType"`
+	BankName      *string          `json:"bankName"`
+	BranchCode    *string          `json:"branchCode"`
 }
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChangeResource string
+
+const (
+	CollectionsChangeResource  ChangeResource = "collections"
+	TransactionsChangeResource ChangeResource = "transactions"
+	InventoryChangeResource    ChangeResource = "inventory"
+)
+
+// ChangeResources are the resources changes are recorded for, with the
+// permission needed to be notified of their changes.
+var ChangeResources = map[ChangeResource]string{
+	CollectionsChangeResource:  "collections.view",
+	TransactionsChangeResource: "transactions.view",
+	InventoryChangeResource:    "inventory.view",
+}
+
+type ChangeAction string
+
+const (
+	InsertChangeAction ChangeAction = "insert"
+	UpdateChangeAction ChangeAction = "update"
+	DeleteChangeAction ChangeAction = "delete"
+)
+
+// ChangeEvent records that a record of an organization was inserted, updated
+// or deleted. Change events are recorded by database triggers and announced
+// with NOTIFY when their transaction commits. Their ids increase, so that a
+// stream of changes can be resumed after the last event it received.
+type ChangeEvent struct {
+	Id             int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrganizationId uuid.UUID      `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Resource       ChangeResource `json:"resource" gorm:"type:text;not null"`
+	Action         ChangeAction   `json:"action" gorm:"type:text;not null"`
+	RecordId       uuid.UUID      `json:"recordId" gorm:"type:uuid;not null"`
+	CreatedAt      time.Time      `json:"createdAt" gorm:"type:timestamptz;not null;index"`
+}
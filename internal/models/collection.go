@@ -1,46 +1,120 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type CollectionStatus string
+
+const (
+	CollectionDraft     CollectionStatus = "draft"
+	CollectionWeighed   CollectionStatus = "weighed"
+	CollectionConfirmed CollectionStatus = "confirmed"
+	CollectionPaid      CollectionStatus = "paid"
+	CollectionVoided    CollectionStatus = "voided"
+)
+
+// CollectionStatusTransitions lists the statuses a collection may move to from
+// each status. Once confirmed the only ways forward are payment or voiding. A
+// paid collection only returns to confirmed when its payout is reversed.
+var CollectionStatusTransitions = map[CollectionStatus][]CollectionStatus{
+	CollectionDraft:     {CollectionWeighed, CollectionVoided},
+	CollectionWeighed:   {CollectionConfirmed, CollectionVoided},
+	CollectionConfirmed: {CollectionPaid, CollectionVoided},
+	CollectionPaid:      {CollectionConfirmed},
+	CollectionVoided:    {},
+}
+
+// CanTransitionTo reports whether a collection in this status may move to next.
+func (s CollectionStatus) CanTransitionTo(next CollectionStatus) bool {
+	return slices.Contains(CollectionStatusTransitions[s], next)
+}
+
+// Locked reports whether the weights, values and parties of a collection in
+// this status are immutable.
+func (s CollectionStatus) Locked() bool {
+	return s == CollectionConfirmed || s == CollectionPaid || s == CollectionVoided
+}
 
 type Collection struct {
 	Base
-	Materials []CollectionMaterial `json:"materials" gorm:"many2many:collections_materials;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	SellerId  uuid.UUID            `json:"-" gorm:"type:uuid;not null"`
-	Seller    User                 `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	BuyerId   uuid.UUID            `json:"-" gorm:"type:uuid;not null"`
-	Buyer     Organization         `json:"buyer" gorm:"foreignKey:BuyerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Materials   []CollectionMaterial   `json:"materials" gorm:"many2many:collections_materials;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SellerId    uuid.UUID              `json:"-" gorm:"type:uuid;not null"`
+	Seller      User                   `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	BuyerId     uuid.UUID              `json:"-" gorm:"type:uuid;not null"`
+	Buyer       Organization           `json:"buyer" gorm:"foreignKey:BuyerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	SiteId      *uuid.UUID             `json:"siteId" gorm:"type:uuid;index"`
+	Site        *Site                  `json:"site" gorm:"foreignKey:SiteId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Status      CollectionStatus       `json:"status" gorm:"type:text;not null;default:'draft'"`
+	VoidReason  *string                `json:"voidReason" gorm:"type:text"`
+	Transitions []CollectionTransition `json:"transitions" gorm:"foreignKey:CollectionId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
 type CreateCollectionPayload struct {
 	SellerId  uuid.UUID                         `json:"sellerId"`
 	BuyerId   uuid.UUID                         `json:"buyerId"`
+	SiteId    *uuid.UUID                        `json:"siteId"`
 	Materials []CreateCollectionMaterialPayload `json:"materials"`
 }
 
 type UpdateCollectionPayload struct {
 	SellerId  *uuid.UUID                        `json:"sellerId"`
 	BuyerId   *uuid.UUID                        `json:"buyerId"`
+	SiteId    *uuid.UUID                        `json:"siteId"`
 	Materials []UpdateCollectionMaterialPayload `json:"materials"`
 }
 
+// CollectionTransition records a single status change of a collection, who
+// performed it and when.
+type CollectionTransition struct {
+	Base
+	CollectionId  uuid.UUID        `json:"collectionId" gorm:"type:uuid;not null;index"`
+	From          CollectionStatus `json:"from" gorm:"type:text;not null"`
+	To            CollectionStatus `json:"to" gorm:"type:text;not null"`
+	Reason        *string          `json:"reason" gorm:"type:text"`
+	PerformedById uuid.UUID        `json:"-" gorm:"type:uuid;not null"`
+	PerformedBy   User             `json:"performedBy" gorm:"foreignKey:PerformedById;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+type CollectionTransitionPayload struct {
+	Reason *string `json:"reason"`
+}
+
+// CollectionMaterial is a line of a collection. A line weighed on a scale
+// references the reading its weight was taken from. ManualOverride flags a
+// weight that was typed by hand while a reading from a scale at the site was
+// available to use instead.
 type CollectionMaterial struct {
 	Base
-	MaterialId uuid.UUID `json:"-" gorm:"type:uuid;not null"`
-	Material   Material  `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Weight     float64   `json:"weight" gorm:"type:decimal(10,2);not null"`
-	Value      float64   `json:"value" gorm:"type:decimal(10,2);not null"`
+	MaterialId     uuid.UUID     `json:"-" gorm:"type:uuid;not null"`
+	Material       Material      `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Weight         float64       `json:"weight" gorm:"type:decimal(10,2);not null"`
+	Value          float64       `json:"value" gorm:"type:decimal(10,2);not null"`
+	ScaleReadingId *uuid.UUID    `json:"scaleReadingId" gorm:"type:uuid;uniqueIndex"`
+	ScaleReading   *ScaleReading `json:"scaleReading,omitempty" gorm:"foreignKey:ScaleReadingId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	ManualOverride bool          `json:"manualOverride" gorm:"not null;default:false"`
 }
 
+// CreateCollectionMaterialPayload adds a line to a collection. When a scale
+// reading is referenced the weight of the line is the net weight of the
+// reading, and Weight is ignored.
 type CreateCollectionMaterialPayload struct {
-	CollectionId uuid.UUID `json:"collectionId"`
-	MaterialId   uuid.UUID `json:"materialId"`
-	Weight       float64   `json:"weight"`
-	Value        float64   `json:"value"`
+	CollectionId   uuid.UUID  `json:"collectionId"`
+	MaterialId     uuid.UUID  `json:"materialId"`
+	Weight         float64    `json:"weight"`
+	Value          float64    `json:"value"`
+	ScaleReadingId *uuid.UUID `json:"scaleReadingId"`
 }
 
+// UpdateCollectionMaterialPayload changes a line of a collection. Referencing
+// a scale reading takes the weight from the reading, while typing a weight
+// replaces the reading the line referenced.
 type UpdateCollectionMaterialPayload struct {
-	CollectionId *uuid.UUID `json:"collectionId"`
-	MaterialId   *uuid.UUID `json:"materialId"`
-	Weight       *float64   `json:"weight"`
-	Value        *float64   `json:"value"`
+	CollectionId   *uuid.UUID `json:"collectionId"`
+	MaterialId     *uuid.UUID `json:"materialId"`
+	Weight         *float64   `json:"weight"`
+	Value          *float64   `json:"value"`
+	ScaleReadingId *uuid.UUID `json:"scaleReadingId"`
 }
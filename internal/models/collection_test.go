@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestCollectionStatusCanTransitionTo(t *testing.T) {
+	statuses := []CollectionStatus{CollectionDraft, CollectionWeighed, CollectionConfirmed, CollectionPaid, CollectionVoided}
+
+	allowed := map[[2]CollectionStatus]bool{
+		{CollectionDraft, CollectionWeighed}:     true,
+		{CollectionDraft, CollectionVoided}:      true,
+		{CollectionWeighed, CollectionConfirmed}: true,
+		{CollectionWeighed, CollectionVoided}:    true,
+		{CollectionConfirmed, CollectionPaid}:    true,
+		{CollectionConfirmed, CollectionVoided}:  true,
+		{CollectionPaid, CollectionConfirmed}:    true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]CollectionStatus{from, to}]
+
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	if CollectionDraft.CanTransitionTo("unknown") || CollectionStatus("unknown").CanTransitionTo(CollectionDraft) {
+		t.Error("unknown statuses can be transitioned")
+	}
+}
+
+func TestCollectionStatusLocked(t *testing.T) {
+	tests := map[CollectionStatus]bool{
+		CollectionDraft:     false,
+		CollectionWeighed:   false,
+		CollectionConfirmed: true,
+		CollectionPaid:      true,
+		CollectionVoided:    true,
+	}
+
+	for status, want := range tests {
+		if got := status.Locked(); got != want {
+			t.Errorf("%s.Locked() = %v, want %v", status, got, want)
+		}
+	}
+}
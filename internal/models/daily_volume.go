@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyVolume is the weight and value of a material moved by an organization
+// at a site on a day, summarised from the lines of confirmed collections or
+// delivered transactions. Transactions are summarised once for each side so
+// that reports can be scoped to either party, with the other party recorded
+// as the counterparty. Days are in South African time.
+type DailyVolume struct {
+	Base
+	Source         ReportSource    `json:"source" gorm:"type:text;not null;index:idx_daily_volumes_source_day"`
+	Side           TransactionSide `json:"side" gorm:"type:text;not null"`
+	OrganizationId uuid.UUID       `json:"organizationId" gorm:"type:uuid;not null;index:idx_daily_volumes_organization_day"`
+	CounterpartyId *uuid.UUID      `json:"counterpartyId" gorm:"type:uuid"`
+	SiteId         *uuid.UUID      `json:"siteId" gorm:"type:uuid"`
+	MaterialId     uuid.UUID       `json:"materialId" gorm:"type:uuid;not null"`
+	Day            time.Time       `json:"day" gorm:"type:date;not null;index:idx_daily_volumes_source_day;index:idx_daily_volumes_organization_day"`
+	Weight         float64         `json:"weight" gorm:"type:decimal(14,2);not null"`
+	Value          float64         `json:"value" gorm:"type:decimal(14,2);not null"`
+	Lines          int64           `json:"lines" gorm:"not null"`
+}
+
+// DailyVolumeRefresh records up to when the daily volumes of a source have
+// been refreshed. Records changed after this time have not been summarised.
+type DailyVolumeRefresh struct {
+	Source      ReportSource `json:"source" gorm:"type:text;primaryKey"`
+	RefreshedAt time.Time    `json:"refreshedAt" gorm:"type:timestamptz"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EprReportFormat string
+
+const (
+	EprPdf  EprReportFormat = "pdf"
+	EprXlsx EprReportFormat = "xlsx"
+)
+
+// EprReport is an extended producer responsibility report of the material an
+// organization recovered in a period. Only collections confirmed and
+// transactions delivered by the cut-off are included, so generating a report
+// again with the same cut-off selects the same records. The data the report
+// was rendered from is kept with it, along with a checksum of each file for
+// audit.
+type EprReport struct {
+	Base
+	OrganizationId uuid.UUID         `json:"organizationId" gorm:"type:uuid;not null;index"`
+	PeriodStart    time.Time         `json:"periodStart" gorm:"type:timestamptz;not null"`
+	PeriodEnd      time.Time         `json:"periodEnd" gorm:"type:timestamptz;not null"`
+	CutOff         time.Time         `json:"cutOff" gorm:"type:timestamptz;not null"`
+	Tonnage        float64           `json:"tonnage" gorm:"type:decimal(14,3);not null"`
+	CarbonAvoided  float64           `json:"carbonAvoided" gorm:"type:decimal(14,2);not null"`
+	PdfChecksum    string            `json:"pdfChecksum" gorm:"type:text;not null"`
+	XlsxChecksum   string            `json:"xlsxChecksum" gorm:"type:text;not null"`
+	Snapshot       EprReportSnapshot `json:"-" gorm:"type:jsonb;serializer:json;not null"`
+	Pdf            []byte            `json:"-" gorm:"type:bytea;not null"`
+	Xlsx           []byte            `json:"-" gorm:"type:bytea;not null"`
+	GeneratedById  uuid.UUID         `json:"-" gorm:"type:uuid;not null"`
+	GeneratedBy    User              `json:"generatedBy" gorm:"foreignKey:GeneratedById;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+// EprReportSnapshot is the data an EPR report is rendered from.
+type EprReportSnapshot struct {
+	OrganizationName    string        `json:"organizationName"`
+	OrganizationAddress string        `json:"organizationAddress"`
+	PeriodStart         time.Time     `json:"periodStart"`
+	PeriodEnd           time.Time     `json:"periodEnd"`
+	CutOff              time.Time     `json:"cutOff"`
+	GeneratedAt         time.Time     `json:"generatedAt"`
+	Streams             []EprStream   `json:"streams"`
+	Evidence            []EprEvidence `json:"evidence"`
+}
+
+// EprStream is the material recovered in a single waste stream, identified by
+// its GW code, and the carbon avoided by recycling it.
+type EprStream struct {
+	GWCode        string   `json:"gwCode"`
+	Materials     []string `json:"materials"`
+	Weight        float64  `json:"weight"`
+	Tonnage       float64  `json:"tonnage"`
+	CarbonAvoided float64  `json:"carbonAvoided"`
+}
+
+// EprEvidence is a single collection or transaction line counted in a report.
+type EprEvidence struct {
+	Source        ReportSource `json:"source"`
+	RecordId      uuid.UUID    `json:"recordId"`
+	RecordedAt    time.Time    `json:"recordedAt"`
+	Counterparty  string       `json:"counterparty"`
+	Site          string       `json:"site"`
+	GWCode        string       `json:"gwCode"`
+	Material      string       `json:"material"`
+	CarbonFactor  float64      `json:"carbonFactor"`
+	Weight        float64      `json:"weight"`
+	Value         float64      `json:"value"`
+	CarbonAvoided float64      `json:"carbonAvoided"`
+}
+
+type CreateEprReportPayload struct {
+	PeriodStart time.Time  `json:"periodStart"`
+	PeriodEnd   time.Time  `json:"periodEnd"`
+	CutOff      *time.Time `json:"cutOff"`
+}
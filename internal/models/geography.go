@@ -0,0 +1,27 @@
+package models
+
+// Country is a country that addresses can be captured for, identified by its
+// ISO 3166-1 codes.
+type Country struct {
+	Code   string `json:"code"`
+	Alpha3 string `json:"alpha3"`
+	Name   string `json:"name"`
+}
+
+// Province is a first level subdivision of a country, identified by its
+// ISO 3166-2 code.
+type Province struct {
+	Code        string `json:"code"`
+	CountryCode string `json:"countryCode"`
+	Name        string `json:"name"`
+}
+
+// Town is a gazetteer entry used to geocode addresses without calling out to
+// an external service.
+type Town struct {
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases"`
+	ProvinceCode string   `json:"provinceCode"`
+	Latitude     float64  `json:"latitude"`
+	Longitude    float64  `json:"longitude"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey records a request that was sent with an Idempotency-Key
+// header and the response it was answered with, so that a retry of the
+// request is answered with the same response instead of being applied again.
+// Keys are scoped to the user or scale that sent them, and the request is
+// identified by a hash of its method, path and body. A key has no status while
+// its request is being handled. The headers of the response are kept along
+// with its body, so that cookies, locations and entity tags are replayed too.
+type IdempotencyKey struct {
+	Id          uuid.UUID           `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Scope       string              `json:"scope" gorm:"type:text;not null;uniqueIndex:idx_idempotency_keys_scope_key"`
+	Key         string              `json:"key" gorm:"type:text;not null;uniqueIndex:idx_idempotency_keys_scope_key"`
+	RequestHash string              `json:"requestHash" gorm:"type:text;not null"`
+	Status      *int                `json:"status"`
+	Headers     map[string][]string `json:"-" gorm:"type:jsonb;serializer:json"`
+	Body        []byte              `json:"-" gorm:"type:bytea"`
+	CreatedAt   time.Time           `json:"createdAt" gorm:"autoCreateTime"`
+	ExpiresAt   time.Time           `json:"expiresAt" gorm:"type:timestamptz;not null;index"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportKind string
+
+const (
+	MaterialsImport   ImportKind = "materials"
+	UsersImport       ImportKind = "users"
+	CollectionsImport ImportKind = "collections"
+)
+
+// Permission returns the permission needed to create the records of an
+// import of this kind.
+func (k ImportKind) Permission() string {
+	switch k {
+	case MaterialsImport:
+		return "materials.create"
+	case UsersImport:
+		return "users.create"
+	case CollectionsImport:
+		return "collections.create"
+	default:
+		return ""
+	}
+}
+
+type ImportStatus string
+
+const (
+	ImportMapping    ImportStatus = "mapping"
+	ImportValidating ImportStatus = "validating"
+	ImportValidated  ImportStatus = "validated"
+	ImportInvalid    ImportStatus = "invalid"
+	ImportApplying   ImportStatus = "applying"
+	ImportApplied    ImportStatus = "applied"
+	ImportFailed     ImportStatus = "failed"
+)
+
+// ImportJob is an uploaded CSV or XLSX file of records to create. Once its
+// columns are mapped to the fields of its kind it is validated in the
+// background by importing every row in a database transaction that is rolled
+// back, so that every row is checked exactly as it would be imported. A
+// validated import can then be applied, which imports every row again in a
+// single transaction that is only committed when every row succeeds.
+type ImportJob struct {
+	Base
+	OrganizationId uuid.UUID         `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Kind           ImportKind        `json:"kind" gorm:"type:text;not null"`
+	Status         ImportStatus      `json:"status" gorm:"type:text;not null;default:'mapping'"`
+	FileName       string            `json:"fileName" gorm:"type:text;not null"`
+	Content        []byte            `json:"-" gorm:"type:bytea;not null"`
+	Columns        []string          `json:"columns" gorm:"type:jsonb;serializer:json;not null"`
+	Mapping        map[string]string `json:"mapping" gorm:"type:jsonb;serializer:json;not null"`
+	TotalRows      int               `json:"totalRows" gorm:"type:integer;not null;default:0"`
+	ProcessedRows  int               `json:"processedRows" gorm:"type:integer;not null;default:0"`
+	FailedRows     int               `json:"failedRows" gorm:"type:integer;not null;default:0"`
+	Errors         []ImportRowError  `json:"errors" gorm:"type:jsonb;serializer:json"`
+	Failure        *string           `json:"failure" gorm:"type:text"`
+	AppliedAt      *time.Time        `json:"appliedAt" gorm:"type:timestamptz"`
+	CreatedById    uuid.UUID         `json:"-" gorm:"type:uuid;not null"`
+	CreatedBy      User              `json:"createdBy" gorm:"foreignKey:CreatedById;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+// ImportRowError is a problem with a row of an import. Rows are numbered as
+// in the file, so the header is row 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ImportField is a field of the records of an import kind that a column of
+// the file can be mapped to.
+type ImportField struct {
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateImportMappingPayload struct {
+	Mapping map[string]string `json:"mapping"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventoryEntryType string
+
+const (
+	InventoryReceipt    InventoryEntryType = "receipt"
+	InventoryIssue      InventoryEntryType = "issue"
+	InventoryAdjustment InventoryEntryType = "adjustment"
+	InventoryReversal   InventoryEntryType = "reversal"
+)
+
+type InventoryAdjustmentReason string
+
+const (
+	Shrinkage    InventoryAdjustmentReason = "shrinkage"
+	MoistureLoss InventoryAdjustmentReason = "moisture_loss"
+	Baling       InventoryAdjustmentReason = "baling"
+	Correction   InventoryAdjustmentReason = "correction"
+)
+
+type InventorySourceType string
+
+const (
+	CollectionMaterialSource  InventorySourceType = "collection_material"
+	TransactionMaterialSource InventorySourceType = "transaction_material"
+	AdjustmentSource          InventorySourceType = "adjustment"
+)
+
+// InventoryEntry is a single posting to an organization's stock ledger.
+// Weights and values are signed: receipts are positive and issues negative,
+// so the balance on hand is the sum of all entries up to a point in time.
+type InventoryEntry struct {
+	Base
+	OrganizationId uuid.UUID                  `json:"organizationId" gorm:"type:uuid;not null;index:idx_inventory_entries_balance,priority:1"`
+	MaterialId     uuid.UUID                  `json:"-" gorm:"type:uuid;not null;index:idx_inventory_entries_balance,priority:2"`
+	Material       Material                   `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	SiteId         *uuid.UUID                 `json:"siteId" gorm:"type:uuid;index"`
+	Type           InventoryEntryType         `json:"type" gorm:"type:text;not null"`
+	Reason         *InventoryAdjustmentReason `json:"reason" gorm:"type:text"`
+	Weight         float64                    `json:"weight" gorm:"type:decimal(12,2);not null"`
+	Value          float64                    `json:"value" gorm:"type:decimal(12,2);not null;default:0"`
+	SourceType     InventorySourceType        `json:"sourceType" gorm:"type:text;not null"`
+	SourceId       *uuid.UUID                 `json:"sourceId" gorm:"type:uuid;index"`
+	Notes          *string                    `json:"notes" gorm:"type:text"`
+	OccurredAt     time.Time                  `json:"occurredAt" gorm:"type:timestamptz;not null;index:idx_inventory_entries_balance,priority:3"`
+	CreatedById    *uuid.UUID                 `json:"createdById" gorm:"type:uuid"`
+}
+
+type CreateInventoryAdjustmentPayload struct {
+	MaterialId uuid.UUID                 `json:"materialId"`
+	SiteId     *uuid.UUID                `json:"siteId"`
+	Weight     float64                   `json:"weight"`
+	Value      float64                   `json:"value"`
+	Reason     InventoryAdjustmentReason `json:"reason"`
+	Notes      *string                   `json:"notes"`
+	OccurredAt *time.Time                `json:"occurredAt"`
+}
+
+// InventoryBalance is the stock on hand of a single material at a site for an
+// organization as of a point in time. Stock that was not recorded at a site
+// has no site.
+type InventoryBalance struct {
+	MaterialId   uuid.UUID  `json:"materialId"`
+	MaterialName string     `json:"materialName"`
+	SiteId       *uuid.UUID `json:"siteId"`
+	SiteName     *string    `json:"siteName"`
+	Weight       float64    `json:"weight"`
+	Value        float64    `json:"value"`
+}
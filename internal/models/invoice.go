@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SettlementStatus string
+
+const (
+	Unsettled        SettlementStatus = "unsettled"
+	PartiallySettled SettlementStatus = "partially_settled"
+	Settled          SettlementStatus = "settled"
+)
+
+type Invoice struct {
+	Base
+	TransactionId    uuid.UUID        `json:"transactionId" gorm:"type:uuid;not null;uniqueIndex"`
+	OrganizationId   uuid.UUID        `json:"organizationId" gorm:"type:uuid;not null;uniqueIndex:idx_invoices_organization_number"`
+	Number           int64            `json:"number" gorm:"not null;uniqueIndex:idx_invoices_organization_number"`
+	Reference        string           `json:"reference" gorm:"type:text;not null"`
+	Total            float64          `json:"total" gorm:"type:decimal(12,2);not null"`
+	IssuedAt         time.Time        `json:"issuedAt" gorm:"type:timestamptz;not null"`
+	SettledAmount    float64          `json:"settledAmount" gorm:"type:decimal(12,2);not null;default:0"`
+	SettlementStatus SettlementStatus `json:"settlementStatus" gorm:"type:text;not null;default:'unsettled'"`
+	SettledAt        *time.Time       `json:"settledAt" gorm:"type:timestamptz"`
+}
+
+// InvoiceSequence holds the last invoice number issued by an organization so
+// that invoice numbers are sequential and gap-free per organization.
+type InvoiceSequence struct {
+	OrganizationId uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LastNumber     int64     `gorm:"not null;default:0"`
+}
+
+type SettleInvoicePayload struct {
+	Amount float64 `json:"amount"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobDead      JobStatus = "dead"
+)
+
+// Job is a unit of background work of a kind with a handler registered with
+// the job runner. A job that fails is pending again until it runs out of
+// attempts, after which it is dead until retried.
+type Job struct {
+	Base
+	Kind        string          `json:"kind" gorm:"type:text;not null;index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb;serializer:json;not null"`
+	Status      JobStatus       `json:"status" gorm:"type:text;not null;default:'pending';index:idx_jobs_status_run_at"`
+	RunAt       time.Time       `json:"runAt" gorm:"type:timestamptz;not null;index:idx_jobs_status_run_at"`
+	Attempts    int             `json:"attempts" gorm:"type:integer;not null;default:0"`
+	MaxAttempts int             `json:"maxAttempts" gorm:"type:integer;not null;default:5"`
+	LockedAt    *time.Time      `json:"lockedAt" gorm:"type:timestamptz"`
+	LockedBy    *string         `json:"lockedBy" gorm:"type:text"`
+	LastError   *string         `json:"lastError" gorm:"type:text"`
+	Schedule    *string         `json:"schedule" gorm:"type:text"`
+	FinishedAt  *time.Time      `json:"finishedAt" gorm:"type:timestamptz"`
+}
+
+// JobSchedule enqueues a job of a kind every time its cron expression matches,
+// evaluated in South African time. Schedules are registered with the job
+// runner at startup and stored so that only one runner enqueues each run.
+type JobSchedule struct {
+	Name      string          `json:"name" gorm:"primaryKey;type:text"`
+	Kind      string          `json:"kind" gorm:"type:text;not null"`
+	Spec      string          `json:"spec" gorm:"type:text;not null"`
+	Payload   json.RawMessage `json:"payload" gorm:"type:jsonb;serializer:json;not null"`
+	NextRunAt time.Time       `json:"nextRunAt" gorm:"type:timestamptz;not null"`
+	LastRunAt *time.Time      `json:"lastRunAt" gorm:"type:timestamptz"`
+	CreatedAt time.Time       `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time       `json:"updatedAt" gorm:"autoUpdateTime"`
+}
@@ -1,20 +1,25 @@
 package models
 
+// Material is a kind of recyclable. Regulated materials, such as scrap metal,
+// may only be collected from sellers whose identity has been verified.
 type Material struct {
 	Base
 	Name         string `json:"name" gorm:"type:text;not null"`
 	GWCode       string `json:"gwCode" gorm:"type:text;not null"`
 	CarbonFactor string `json:"carbonFactor" gorm:"type:float;not null"`
+	Regulated    bool   `json:"regulated" gorm:"type:boolean;not null;default:false"`
 }
 
 type CreateMaterialPayload struct {
 	Name         string `json:"name"`
 	GWCode       string `json:"gwCode"`
 	CarbonFactor string `json:"carbonFactor"`
+	Regulated    bool   `json:"regulated"`
 }
 
 type UpdateMaterialPayload struct {
 	Name         *string `json:"name"`
 	GWCode       *string `json:"gwCode"`
 	CarbonFactor *string `json:"carbonFactor"`
+	Regulated    *bool   `json:"regulated"`
 }
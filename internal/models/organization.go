@@ -8,9 +8,9 @@ type Organization struct {
 	Roles         []Role       `json:"roles" gorm:"many2many:organization_roles;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Users         []User       `json:"users" gorm:"many2many:organization_users;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	AddressId     *uuid.UUID   `json:"-" gorm:"type:uuid"`
-	Address       *Address     `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Address       *Address     `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	BankDetailsId *uuid.UUID   `json:"-" gorm:"type:uuid"`
-	BankDetails   *BankDetails `json:"bankDetails" gorm:"foreignKey:BankDetailsId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	BankDetails   *BankDetails `json:"bankDetails" gorm:"foreignKey:BankDetailsId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
 
 type CreateOrganizationPayload struct {
@@ -0,0 +1,28 @@
+package models
+
+import "github.com/google/uuid"
+
+type OwnerType string
+
+const (
+	UserOwner         OwnerType = "user"
+	OrganizationOwner OwnerType = "organization"
+)
+
+// Owner identifies the user or organization that an address or bank details
+// belong to. The organizations of a user are those they are a member of,
+// through which their records are available to the other members.
+type Owner struct {
+	Type          OwnerType   `json:"type"`
+	Id            uuid.UUID   `json:"id"`
+	Organizations []uuid.UUID `json:"-"`
+}
+
+// Table returns the table that holds the owner's row.
+func (o Owner) Table() string {
+	if o.Type == OrganizationOwner {
+		return "organizations"
+	}
+
+	return "users"
+}
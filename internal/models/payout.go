@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PayoutBatchStatus string
+
+const (
+	PayoutBatchDraft     PayoutBatchStatus = "draft"
+	PayoutBatchConfirmed PayoutBatchStatus = "confirmed"
+	PayoutBatchReversed  PayoutBatchStatus = "reversed"
+)
+
+type PayoutMethod string
+
+const (
+	EftPayout  PayoutMethod = "eft"
+	CashPayout PayoutMethod = "cash"
+)
+
+type PayoutStatus string
+
+const (
+	PayoutPending  PayoutStatus = "pending"
+	PayoutPaid     PayoutStatus = "paid"
+	PayoutReversed PayoutStatus = "reversed"
+)
+
+type PayoutExportFormat string
+
+const (
+	GenericCsvExport PayoutExportFormat = "csv"
+	FnbBulkExport    PayoutExportFormat = "fnb"
+)
+
+// PayoutBatch groups the electronic payouts an organization makes to the
+// sellers of its confirmed collections so that they can be exported to the
+// bank as a single bulk payment.
+type PayoutBatch struct {
+	Base
+	OrganizationId uuid.UUID         `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Reference      string            `json:"reference" gorm:"type:text;not null"`
+	Status         PayoutBatchStatus `json:"status" gorm:"type:text;not null;default:'draft'"`
+	Total          float64           `json:"total" gorm:"type:decimal(12,2);not null"`
+	PayoutCount    int               `json:"payoutCount" gorm:"not null"`
+	ExportedAt     *time.Time        `json:"exportedAt" gorm:"type:timestamptz"`
+	ConfirmedAt    *time.Time        `json:"confirmedAt" gorm:"type:timestamptz"`
+	ReversedAt     *time.Time        `json:"reversedAt" gorm:"type:timestamptz"`
+	ReversalReason *string           `json:"reversalReason" gorm:"type:text"`
+	CreatedById    uuid.UUID         `json:"createdById" gorm:"type:uuid;not null"`
+	Payouts        []Payout          `json:"payouts" gorm:"foreignKey:BatchId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// PayoutBatchSequence holds the last batch number used by an organization so
+// that batch references are unique and sequential per organization.
+type PayoutBatchSequence struct {
+	OrganizationId uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LastNumber     int64     `gorm:"not null;default:0"`
+}
+
+// Payout is a single payment to a seller for one or more of their confirmed
+// collections, either as part of a batch or in cash at the depot. The seller's
+// bank details are copied onto electronic payouts when the batch is created so
+// that later changes do not alter what was sent to the bank.
+type Payout struct {
+	Base
+	BatchId        *uuid.UUID       `json:"batchId" gorm:"type:uuid;index"`
+	OrganizationId uuid.UUID        `json:"organizationId" gorm:"type:uuid;not null;uniqueIndex:idx_payouts_organization_receipt"`
+	SellerId       uuid.UUID        `json:"-" gorm:"type:uuid;not null;index"`
+	Seller         User             `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Method         PayoutMethod     `json:"method" gorm:"type:text;not null"`
+	Status         PayoutStatus     `json:"status" gorm:"type:text;not null;default:'pending'"`
+	Amount         float64          `json:"amount" gorm:"type:decimal(12,2);not null"`
+	AccountHolder  *string          `json:"accountHolder" gorm:"type:text"`
+	AccountNumber  *string          `json:"accountNumber" gorm:"type:text"`
+	AccountType    *BankAccountType `json:"accountType" gorm:"type:text"`
+	BankName       *string          `json:"bankName" gorm:"type:text"`
+	BranchCode     *string          `json:"branchCode" gorm:"type:text"`
+	ReceiptNumber  *string          `json:"receiptNumber" gorm:"type:text;uniqueIndex:idx_payouts_organization_receipt"`
+	PaidAt         *time.Time       `json:"paidAt" gorm:"type:timestamptz"`
+	PaidById       *uuid.UUID       `json:"paidById" gorm:"type:uuid"`
+	ReversedAt     *time.Time       `json:"reversedAt" gorm:"type:timestamptz"`
+	ReversalReason *string          `json:"reversalReason" gorm:"type:text"`
+	Collections    []Collection     `json:"collections" gorm:"many2many:payout_collections;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+type CreatePayoutBatchPayload struct {
+	SiteId         *uuid.UUID `json:"siteId"`
+	ConfirmedUntil *time.Time `json:"confirmedUntil"`
+}
+
+type CreateCashPayoutPayload struct {
+	CollectionIds []uuid.UUID `json:"collectionIds"`
+	ReceiptNumber string      `json:"receiptNumber"`
+}
+
+type ReversePayoutPayload struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PickupStatus string
+
+const (
+	PickupRequested PickupStatus = "requested"
+	PickupScheduled PickupStatus = "scheduled"
+	PickupCompleted PickupStatus = "completed"
+	PickupCancelled PickupStatus = "cancelled"
+)
+
+// Pickup is a request for an organization to collect material from a seller
+// at the seller's address. It is scheduled by assigning a collector and a date
+// and, once completed, results in a collection.
+type Pickup struct {
+	Base
+	OrganizationId     uuid.UUID        `json:"organizationId" gorm:"type:uuid;not null;index"`
+	SellerId           uuid.UUID        `json:"-" gorm:"type:uuid;not null;index"`
+	Seller             User             `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	AddressId          *uuid.UUID       `json:"-" gorm:"type:uuid"`
+	Address            *Address         `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	SiteId             *uuid.UUID       `json:"siteId" gorm:"type:uuid;index"`
+	Site               *Site            `json:"site" gorm:"foreignKey:SiteId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	CollectorId        *uuid.UUID       `json:"-" gorm:"type:uuid;index"`
+	Collector          *User            `json:"collector" gorm:"foreignKey:CollectorId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ScheduledFor       *time.Time       `json:"scheduledFor" gorm:"type:date;index"`
+	Status             PickupStatus     `json:"status" gorm:"type:text;not null;default:'requested'"`
+	Notes              *string          `json:"notes" gorm:"type:text"`
+	CancellationReason *string          `json:"cancellationReason" gorm:"type:text"`
+	CollectionId       *uuid.UUID       `json:"collectionId" gorm:"type:uuid"`
+	CompletedAt        *time.Time       `json:"completedAt" gorm:"type:timestamptz"`
+	RequestedById      uuid.UUID        `json:"requestedById" gorm:"type:uuid;not null"`
+	Materials          []PickupMaterial `json:"materials" gorm:"foreignKey:PickupId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// PickupMaterial is a material the seller expects to hand over at a pickup.
+type PickupMaterial struct {
+	Base
+	PickupId       uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	MaterialId     uuid.UUID `json:"-" gorm:"type:uuid;not null"`
+	Material       Material  `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ExpectedWeight float64   `json:"expectedWeight" gorm:"type:decimal(10,2);not null"`
+}
+
+type PickupMaterialPayload struct {
+	MaterialId     uuid.UUID `json:"materialId"`
+	ExpectedWeight float64   `json:"expectedWeight"`
+}
+
+type CreatePickupPayload struct {
+	OrganizationId *uuid.UUID              `json:"organizationId"`
+	SellerId       *uuid.UUID              `json:"sellerId"`
+	SiteId         *uuid.UUID              `json:"siteId"`
+	Notes          *string                 `json:"notes"`
+	Materials      []PickupMaterialPayload `json:"materials"`
+}
+
+type AssignPickupPayload struct {
+	CollectorId  uuid.UUID `json:"collectorId"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+}
+
+type CancelPickupPayload struct {
+	Reason string `json:"reason"`
+}
+
+// CompletePickupPayload carries the weighed materials of a completed pickup.
+// When no materials are given the collection is created from the expected
+// weights so that it can be weighed at the site.
+type CompletePickupPayload struct {
+	Materials []CreateCollectionMaterialPayload `json:"materials"`
+}
+
+// PickupRoute is the order in which a collector should visit the pickups
+// scheduled for a day. Pickups whose address has no coordinates cannot be
+// routed and are listed separately.
+type PickupRoute struct {
+	CollectorId uuid.UUID         `json:"collectorId"`
+	Date        string            `json:"date"`
+	Distance    float64           `json:"distance"`
+	Stops       []PickupRouteStop `json:"stops"`
+	Unrouted    []Pickup          `json:"unrouted"`
+}
+
+// PickupRouteStop is a pickup on a route with the distance in kilometres from
+// the previous stop, or from the start of the route for the first stop.
+type PickupRouteStop struct {
+	Sequence int     `json:"sequence"`
+	Distance float64 `json:"distance"`
+	Pickup   Pickup  `json:"pickup"`
+}
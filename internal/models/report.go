@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportSource string
+
+const (
+	CollectionsReportSource  ReportSource = "collections"
+	TransactionsReportSource ReportSource = "transactions"
+)
+
+type ReportGrouping string
+
+const (
+	ByMaterial     ReportGrouping = "material"
+	ByOrganization ReportGrouping = "organization"
+	BySite         ReportGrouping = "site"
+	ByCollector    ReportGrouping = "collector"
+)
+
+// VolumeReportQuery describes a volume report. Reports are scoped to a single
+// organization unless OrganizationId is nil, in which case every organization
+// is included.
+type VolumeReportQuery struct {
+	OrganizationId *uuid.UUID
+	Source         ReportSource
+	GroupBy        ReportGrouping
+	Period         ReportPeriod
+	From           time.Time
+	To             time.Time
+}
+
+// VolumeReport is the weight and value of material moved through collections
+// or transactions within a range, grouped by material, organization, site or
+// collector. Totals are compared with the range of the same length directly
+// before it.
+type VolumeReport struct {
+	Source       ReportSource   `json:"source"`
+	GroupBy      ReportGrouping `json:"groupBy"`
+	Period       ReportPeriod   `json:"period"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	PreviousFrom time.Time      `json:"previousFrom"`
+	PreviousTo   time.Time      `json:"previousTo"`
+	Totals       []VolumeTotal  `json:"totals"`
+	Series       []VolumeBucket `json:"series"`
+}
+
+// VolumeTotal is the weight and value of a group within the range of a report
+// and the range before it. The changes are percentages and are nil when there
+// was nothing in the previous range to compare with.
+type VolumeTotal struct {
+	Key            *uuid.UUID `json:"key"`
+	Name           string     `json:"name"`
+	Weight         float64    `json:"weight"`
+	Value          float64    `json:"value"`
+	PreviousWeight float64    `json:"previousWeight"`
+	PreviousValue  float64    `json:"previousValue"`
+	WeightChange   *float64   `json:"weightChange"`
+	ValueChange    *float64   `json:"valueChange"`
+}
+
+// VolumeBucket is the weight and value of a group within a single period of
+// a report, starting at Bucket.
+type VolumeBucket struct {
+	Bucket time.Time  `json:"bucket"`
+	Key    *uuid.UUID `json:"key"`
+	Name   string     `json:"name"`
+	Weight float64    `json:"weight"`
+	Value  float64    `json:"value"`
+}
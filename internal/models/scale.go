@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scale is a weighbridge or scale at a site that reports its readings with its
+// device key. Only a hash of the key is stored, so the key is only returned
+// when the scale is registered or its key is rotated.
+type Scale struct {
+	Base
+	OrganizationId uuid.UUID  `json:"organizationId" gorm:"type:uuid;not null;index"`
+	SiteId         uuid.UUID  `json:"siteId" gorm:"type:uuid;not null;index"`
+	Site           *Site      `json:"site,omitempty" gorm:"foreignKey:SiteId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Name           string     `json:"name" gorm:"type:text;not null"`
+	Active         bool       `json:"active" gorm:"not null;default:true"`
+	KeyHash        string     `json:"-" gorm:"type:text;not null;uniqueIndex"`
+	KeyPrefix      string     `json:"keyPrefix" gorm:"type:text;not null"`
+	Key            string     `json:"key,omitempty" gorm:"-"`
+	LastSeenAt     *time.Time `json:"lastSeenAt" gorm:"type:timestamptz"`
+}
+
+// ScaleReading is a weight captured by a scale, in kilograms. Readings are
+// never changed once recorded. Each reading's checksum covers its values and
+// the checksum of the reading before it from the same scale, so that a reading
+// that was altered, or removed from between others, can be detected.
+type ScaleReading struct {
+	Base
+	ScaleId          uuid.UUID `json:"scaleId" gorm:"type:uuid;not null;index"`
+	Scale            *Scale    `json:"scale,omitempty" gorm:"foreignKey:ScaleId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Gross            float64   `json:"gross" gorm:"type:decimal(10,2);not null"`
+	Tare             float64   `json:"tare" gorm:"type:decimal(10,2);not null"`
+	Net              float64   `json:"net" gorm:"type:decimal(10,2);not null"`
+	Stable           bool      `json:"stable" gorm:"not null"`
+	CapturedAt       time.Time `json:"capturedAt" gorm:"type:timestamptz;not null;index"`
+	Raw              *string   `json:"raw" gorm:"type:text"`
+	PreviousChecksum *string   `json:"previousChecksum" gorm:"type:text"`
+	Checksum         string    `json:"checksum" gorm:"type:text;not null"`
+}
+
+type CreateScalePayload struct {
+	SiteId uuid.UUID `json:"siteId"`
+	Name   string    `json:"name"`
+	Active *bool     `json:"active"`
+}
+
+type UpdateScalePayload struct {
+	SiteId *uuid.UUID `json:"siteId"`
+	Name   *string    `json:"name"`
+	Active *bool      `json:"active"`
+}
+
+// ScaleReadingPayload is a reading reported by a scale, either as weights or as
+// the raw lines its indicator printed. Weights are in the given unit, which
+// defaults to kilograms. A missing net, gross or tare is worked out from the
+// other two, and a reading without a time was captured when it is received.
+type ScaleReadingPayload struct {
+	Gross      *float64   `json:"gross"`
+	Tare       *float64   `json:"tare"`
+	Net        *float64   `json:"net"`
+	Unit       string     `json:"unit"`
+	Stable     *bool      `json:"stable"`
+	CapturedAt *time.Time `json:"capturedAt"`
+	Raw        string     `json:"raw"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportPeriod string
+
+const (
+	DailyPeriod     ReportPeriod = "day"
+	WeeklyPeriod    ReportPeriod = "week"
+	MonthlyPeriod   ReportPeriod = "month"
+	QuarterlyPeriod ReportPeriod = "quarter"
+	YearlyPeriod    ReportPeriod = "year"
+)
+
+// SellerReportQuery narrows the collections a seller report covers to those
+// created within the range and sets the period they are grouped by.
+type SellerReportQuery struct {
+	From   *time.Time
+	To     *time.Time
+	Period ReportPeriod
+}
+
+// MaterialTotal is the weight, value and carbon avoided of a single material
+// across the collections of a seller.
+type MaterialTotal struct {
+	MaterialId    uuid.UUID `json:"materialId"`
+	MaterialName  string    `json:"materialName"`
+	Weight        float64   `json:"weight"`
+	Value         float64   `json:"value"`
+	CarbonAvoided float64   `json:"carbonAvoided"`
+}
+
+// PeriodTotal is the weight, value and carbon avoided of the collections of a
+// seller within a single period starting at Period.
+type PeriodTotal struct {
+	Period        time.Time `json:"period"`
+	Weight        float64   `json:"weight"`
+	Value         float64   `json:"value"`
+	CarbonAvoided float64   `json:"carbonAvoided"`
+}
+
+// SellerEarnings is what a seller has earned from confirmed collections, split
+// into what has been paid and what is still outstanding.
+type SellerEarnings struct {
+	Period      ReportPeriod    `json:"period"`
+	Total       float64         `json:"total"`
+	Paid        float64         `json:"paid"`
+	Outstanding float64         `json:"outstanding"`
+	Materials   []MaterialTotal `json:"materials"`
+	Periods     []PeriodTotal   `json:"periods"`
+}
+
+// SellerImpact is the material a seller has diverted through confirmed
+// collections and the carbon that was avoided by recycling it.
+type SellerImpact struct {
+	Period        ReportPeriod    `json:"period"`
+	Collections   int64           `json:"collections"`
+	Weight        float64         `json:"weight"`
+	CarbonAvoided float64         `json:"carbonAvoided"`
+	Materials     []MaterialTotal `json:"materials"`
+	Periods       []PeriodTotal   `json:"periods"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "github.com/google/uuid"
+
+// Site is a depot, buy-back centre or drop-off point operated by an
+// organization.
+type Site struct {
+	Base
+	OrganizationId uuid.UUID           `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Name           string              `json:"name" gorm:"type:text;not null"`
+	AddressId      *uuid.UUID          `json:"-" gorm:"type:uuid"`
+	Address        *Address            `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Latitude       *float64            `json:"latitude" gorm:"type:decimal(9,6)"`
+	Longitude      *float64            `json:"longitude" gorm:"type:decimal(9,6)"`
+	OperatingHours []SiteOperatingHour `json:"operatingHours" gorm:"foreignKey:SiteId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Users          []User              `json:"users" gorm:"many2many:site_users;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// SiteOperatingHour is the time a site opens and closes on a day of the week,
+// where 0 is Sunday. Times are in 24 hour "15:04" format.
+type SiteOperatingHour struct {
+	Base
+	SiteId    uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	DayOfWeek int       `json:"dayOfWeek" gorm:"not null"`
+	Opens     string    `json:"opens" gorm:"type:text;not null"`
+	Closes    string    `json:"closes" gorm:"type:text;not null"`
+}
+
+type SiteOperatingHourPayload struct {
+	DayOfWeek int    `json:"dayOfWeek"`
+	Opens     string `json:"opens"`
+	Closes    string `json:"closes"`
+}
+
+type CreateSitePayload struct {
+	Name           string                     `json:"name"`
+	Address        *CreateAddressPayload      `json:"address"`
+	Latitude       *float64                   `json:"latitude"`
+	Longitude      *float64                   `json:"longitude"`
+	OperatingHours []SiteOperatingHourPayload `json:"operatingHours"`
+	Users          []User                     `json:"users"`
+}
+
+type UpdateSitePayload struct {
+	Name           *string                    `json:"name"`
+	Address        *CreateAddressPayload      `json:"address"`
+	Latitude       *float64                   `json:"latitude"`
+	Longitude      *float64                   `json:"longitude"`
+	OperatingHours []SiteOperatingHourPayload `json:"operatingHours"`
+	Users          []User                     `json:"users"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncCollectionPayload is a collection recorded by a client while it was
+// offline, with the id and creation time the client gave it. Uploading the same
+// collection again, such as when a sync is retried, does not create it twice.
+type SyncCollectionPayload struct {
+	Id        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreateCollectionPayload
+}
+
+type SyncCollectionsPayload struct {
+	Collections []SyncCollectionPayload `json:"collections"`
+}
+
+type SyncResultStatus string
+
+const (
+	// SyncCreated is a record that was created by the sync.
+	SyncCreated SyncResultStatus = "created"
+	// SyncUnchanged is a record that was created by an earlier sync, and
+	// matches the record that was uploaded again.
+	SyncUnchanged SyncResultStatus = "unchanged"
+	// SyncConflict is a record whose id is already used by a record that
+	// differs from the one uploaded, such as one that was changed or deleted
+	// since it was synced. The record on the server is kept.
+	SyncConflict SyncResultStatus = "conflict"
+	// SyncRejected is a record that failed validation and was not created.
+	SyncRejected SyncResultStatus = "rejected"
+)
+
+// SyncCollectionResult reports what became of an uploaded collection. A
+// conflict carries the collection as it is on the server, unless it was
+// deleted.
+type SyncCollectionResult struct {
+	Id         uuid.UUID        `json:"id"`
+	Status     SyncResultStatus `json:"status"`
+	Message    *string          `json:"message"`
+	Collection *Collection      `json:"collection,omitempty"`
+}
+
+// SyncSeller is a seller as a client recording collections offline needs
+// them, with their identity number masked and without their contact details.
+type SyncSeller struct {
+	Id                   uuid.UUID      `json:"id"`
+	Name                 string         `json:"name"`
+	MaskedIdentityNumber *string        `json:"identityNumber"`
+	IdentityStatus       IdentityStatus `json:"identityStatus"`
+	Version              int64          `json:"version"`
+}
+
+// SyncChanges are the changes to the reference data a client needs to record
+// collections offline, since the cursor of an earlier sync. Records are
+// identified by id and ordered by version, so a record that is returned again
+// replaces the copy the client has when its version is newer.
+type SyncChanges struct {
+	Cursor           time.Time    `json:"cursor"`
+	Materials        []Material   `json:"materials"`
+	Sellers          []SyncSeller `json:"sellers"`
+	DeletedMaterials []uuid.UUID  `json:"deletedMaterials"`
+	DeletedSellers   []uuid.UUID  `json:"deletedSellers"`
+}
@@ -1,44 +1,157 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionQuoted    TransactionStatus = "quoted"
+	TransactionAccepted  TransactionStatus = "accepted"
+	TransactionRejected  TransactionStatus = "rejected"
+	TransactionCancelled TransactionStatus = "cancelled"
+	TransactionDelivered TransactionStatus = "delivered"
+	TransactionInvoiced  TransactionStatus = "invoiced"
+	TransactionSettled   TransactionStatus = "settled"
+)
+
+// TransactionStatusTransitions lists the statuses a transaction may move to
+// from each status.
+var TransactionStatusTransitions = map[TransactionStatus][]TransactionStatus{
+	TransactionQuoted:    {TransactionAccepted, TransactionRejected, TransactionCancelled},
+	TransactionAccepted:  {TransactionDelivered, TransactionCancelled},
+	TransactionRejected:  {},
+	TransactionCancelled: {},
+	TransactionDelivered: {TransactionInvoiced},
+	TransactionInvoiced:  {TransactionSettled},
+	TransactionSettled:   {},
+}
+
+// CanTransitionTo reports whether a transaction in this status may move to next.
+func (s TransactionStatus) CanTransitionTo(next TransactionStatus) bool {
+	return slices.Contains(TransactionStatusTransitions[s], next)
+}
+
+// Locked reports whether the parties and lines of a transaction in this status
+// are immutable. Only quotes may be edited.
+func (s TransactionStatus) Locked() bool {
+	return s != TransactionQuoted
+}
+
+type TransactionSide string
+
+const (
+	SellerSide TransactionSide = "seller"
+	BuyerSide  TransactionSide = "buyer"
+)
 
 type Transaction struct {
 	Base
-	Materials []TransactionMaterial `json:"materials" gorm:"many2many:transactions_materials;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	SellerId  uuid.UUID             `json:"-" gorm:"type:uuid;not null"`
-	Seller    Organization          `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	BuyerId   uuid.UUID             `json:"-" gorm:"type:uuid;not null"`
-	Buyer     Organization          `json:"buyer" gorm:"foreignKey:BuyerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Materials      []TransactionMaterial   `json:"materials" gorm:"many2many:transactions_materials;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SellerId       uuid.UUID               `json:"-" gorm:"type:uuid;not null"`
+	Seller         Organization            `json:"seller" gorm:"foreignKey:SellerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	BuyerId        uuid.UUID               `json:"-" gorm:"type:uuid;not null"`
+	Buyer          Organization            `json:"buyer" gorm:"foreignKey:BuyerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	SiteId         *uuid.UUID              `json:"siteId" gorm:"type:uuid;index"`
+	Site           *Site                   `json:"site" gorm:"foreignKey:SiteId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Status         TransactionStatus       `json:"status" gorm:"type:text;not null;default:'quoted'"`
+	QuoteExpiresAt *time.Time              `json:"quoteExpiresAt" gorm:"type:timestamptz"`
+	Invoice        *Invoice                `json:"invoice" gorm:"foreignKey:TransactionId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Transitions    []TransactionTransition `json:"transitions" gorm:"foreignKey:TransactionId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// SideOf returns the side the given organization is on in the transaction, or
+// an empty side if it is not a party to it.
+func (t *Transaction) SideOf(organizationId uuid.UUID) TransactionSide {
+	switch organizationId {
+	case t.SellerId:
+		return SellerSide
+	case t.BuyerId:
+		return BuyerSide
+	default:
+		return ""
+	}
 }
 
 type CreateTransactionPayload struct {
-	SellerId  uuid.UUID                          `json:"sellerId"`
-	BuyerId   uuid.UUID                          `json:"buyerId"`
-	Materials []CreateTransactionMaterialPayload `json:"materials"`
+	SellerId       uuid.UUID                          `json:"sellerId"`
+	BuyerId        uuid.UUID                          `json:"buyerId"`
+	SiteId         *uuid.UUID                         `json:"siteId"`
+	QuoteExpiresAt *time.Time                         `json:"quoteExpiresAt"`
+	Materials      []CreateTransactionMaterialPayload `json:"materials"`
+	OverrideStock  bool                               `json:"overrideStock"`
 }
 
 type UpdateTransactionPayload struct {
-	SellerId  *uuid.UUID                         `json:"sellerId"`
-	BuyerId   *uuid.UUID                         `json:"buyerId"`
-	Materials []UpdateTransactionMaterialPayload `json:"materials"`
+	SellerId       *uuid.UUID                         `json:"sellerId"`
+	BuyerId        *uuid.UUID                         `json:"buyerId"`
+	SiteId         *uuid.UUID                         `json:"siteId"`
+	QuoteExpiresAt *time.Time                         `json:"quoteExpiresAt"`
+	Materials      []UpdateTransactionMaterialPayload `json:"materials"`
+}
+
+// TransactionTransition records a single status change of a transaction, the
+// organization on whose behalf it was made and the user who performed it.
+type TransactionTransition struct {
+	Base
+	TransactionId  uuid.UUID         `json:"transactionId" gorm:"type:uuid;not null;index"`
+	From           TransactionStatus `json:"from" gorm:"type:text;not null"`
+	To             TransactionStatus `json:"to" gorm:"type:text;not null"`
+	Reason         *string           `json:"reason" gorm:"type:text"`
+	OrganizationId uuid.UUID         `json:"organizationId" gorm:"type:uuid;not null"`
+	PerformedById  uuid.UUID         `json:"-" gorm:"type:uuid;not null"`
+	PerformedBy    User              `json:"performedBy" gorm:"foreignKey:PerformedById;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+}
+
+type TransactionTransitionPayload struct {
+	Reason *string `json:"reason"`
+}
+
+type DeliverTransactionPayload struct {
+	Materials     []DeliverTransactionMaterialPayload `json:"materials"`
+	OverrideStock bool                                `json:"overrideStock"`
+}
+
+type DeliverTransactionMaterialPayload struct {
+	Id             uuid.UUID `json:"id"`
+	ReceivedWeight float64   `json:"receivedWeight"`
 }
 
 type TransactionMaterial struct {
 	Base
-	MaterialId uuid.UUID `json:"-" gorm:"type:uuid;not null"`
-	Material   Material  `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Weight     float64   `json:"weight" gorm:"type:decimal(10,2);not null"`
-	Value      float64   `json:"value" gorm:"type:decimal(10,2);not null"`
+	MaterialId     uuid.UUID `json:"-" gorm:"type:uuid;not null"`
+	Material       Material  `json:"material" gorm:"foreignKey:MaterialId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Weight         float64   `json:"weight" gorm:"type:decimal(10,2);not null"`
+	Value          float64   `json:"value" gorm:"type:decimal(10,2);not null"`
+	ReceivedWeight *float64  `json:"receivedWeight" gorm:"type:decimal(10,2)"`
+}
+
+// InvoicedValue returns the value of the line adjusted for the weight the
+// buyer actually received. Lines without a received weight are invoiced at
+// their quoted value.
+func (m *TransactionMaterial) InvoicedValue() float64 {
+	if m.ReceivedWeight == nil || m.Weight == 0 {
+		return m.Value
+	}
+
+	return m.Value * (*m.ReceivedWeight / m.Weight)
 }
 
 type CreateTransactionMaterialPayload struct {
-	MaterialId uuid.UUID `json:"materialId"`
-	Weight     float64   `json:"weight"`
-	Value      float64   `json:"value"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	MaterialId    uuid.UUID `json:"materialId"`
+	Weight        float64   `json:"weight"`
+	Value         float64   `json:"value"`
+	OverrideStock bool      `json:"overrideStock"`
 }
 
 type UpdateTransactionMaterialPayload struct {
-	MaterialId *uuid.UUID `json:"materialId"`
-	Weight     *float64   `json:"weight"`
-	Value      *float64   `json:"value"`
+	MaterialId    *uuid.UUID `json:"materialId"`
+	Weight        *float64   `json:"weight"`
+	Value         *float64   `json:"value"`
+	OverrideStock bool       `json:"overrideStock"`
 }
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTransactionStatusCanTransitionTo(t *testing.T) {
+	statuses := []TransactionStatus{
+		TransactionQuoted,
+		TransactionAccepted,
+		TransactionRejected,
+		TransactionCancelled,
+		TransactionDelivered,
+		TransactionInvoiced,
+		TransactionSettled,
+	}
+
+	allowed := map[[2]TransactionStatus]bool{
+		{TransactionQuoted, TransactionAccepted}:    true,
+		{TransactionQuoted, TransactionRejected}:    true,
+		{TransactionQuoted, TransactionCancelled}:   true,
+		{TransactionAccepted, TransactionDelivered}: true,
+		{TransactionAccepted, TransactionCancelled}: true,
+		{TransactionDelivered, TransactionInvoiced}: true,
+		{TransactionInvoiced, TransactionSettled}:   true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]TransactionStatus{from, to}]
+
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+
+		if got, want := from.Locked(), from != TransactionQuoted; got != want {
+			t.Errorf("%s.Locked() = %v, want %v", from, got, want)
+		}
+	}
+}
+
+func TestTransactionSideOf(t *testing.T) {
+	transaction := Transaction{SellerId: uuid.New(), BuyerId: uuid.New()}
+
+	tests := []struct {
+		organizationId uuid.UUID
+		want           TransactionSide
+	}{
+		{transaction.SellerId, SellerSide},
+		{transaction.BuyerId, BuyerSide},
+		{uuid.New(), ""},
+		{uuid.Nil, ""},
+	}
+
+	for _, test := range tests {
+		if got := transaction.SideOf(test.organizationId); got != test.want {
+			t.Errorf("SideOf(%s) = %q, want %q", test.organizationId, got, test.want)
+		}
+	}
+}
+
+func TestTransactionMaterialInvoicedValue(t *testing.T) {
+	received := func(weight float64) *float64 {
+		return &weight
+	}
+
+	tests := []struct {
+		line TransactionMaterial
+		want float64
+	}{
+		{TransactionMaterial{Weight: 100, Value: 250}, 250},
+		{TransactionMaterial{Weight: 100, Value: 250, ReceivedWeight: received(100)}, 250},
+		{TransactionMaterial{Weight: 100, Value: 250, ReceivedWeight: received(80)}, 200},
+		{TransactionMaterial{Weight: 100, Value: 250, ReceivedWeight: received(0)}, 0},
+		{TransactionMaterial{Weight: 0, Value: 250, ReceivedWeight: received(10)}, 250},
+	}
+
+	for index, test := range tests {
+		if got := test.line.InvoicedValue(); got != test.want {
+			t.Errorf("line %d: InvoicedValue() = %v, want %v", index, got, test.want)
+		}
+	}
+}
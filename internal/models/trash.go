@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashResource string
+
+const (
+	UsersTrashResource         TrashResource = "users"
+	OrganizationsTrashResource TrashResource = "organizations"
+	MaterialsTrashResource     TrashResource = "materials"
+	RolesTrashResource         TrashResource = "roles"
+	SitesTrashResource         TrashResource = "sites"
+	CollectionsTrashResource   TrashResource = "collections"
+	TransactionsTrashResource  TrashResource = "transactions"
+	ScalesTrashResource        TrashResource = "scales"
+	WebhooksTrashResource      TrashResource = "webhooks"
+)
+
+// TrashItem is a deleted record that can still be restored until it is
+// purged.
+type TrashItem struct {
+	Resource  TrashResource `json:"resource"`
+	Id        uuid.UUID     `json:"id"`
+	Label     string        `json:"label"`
+	DeletedAt time.Time     `json:"deletedAt"`
+}
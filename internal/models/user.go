@@ -1,6 +1,12 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserType string
 
@@ -11,11 +17,33 @@ const (
 	System    UserType = "system"
 )
 
+type IdentityDocumentType string
+
+const (
+	SouthAfricanId IdentityDocumentType = "sa_id"
+	Passport       IdentityDocumentType = "passport"
+	AsylumPermit   IdentityDocumentType = "asylum_permit"
+	RefugeeId      IdentityDocumentType = "refugee_id"
+)
+
+type IdentityStatus string
+
+const (
+	IdentityUnverified IdentityStatus = "unverified"
+	IdentityPending    IdentityStatus = "pending"
+	IdentityVerified   IdentityStatus = "verified"
+	IdentityRejected   IdentityStatus = "rejected"
+)
+
+// User is a person who signs in. Email addresses and phone numbers are only
+// unique among users that are not in the trash, so that the details of a
+// deleted user can be used to sign up again, and restoring the deleted user
+// then conflicts.
 type User struct {
 	Base
 	Name               string       `json:"name" gorm:"type:text;not null"`
-	Email              string       `json:"email" gorm:"type:text;not null;unique"`
-	Phone              string       `json:"phone" gorm:"type:text;not null;unique"`
+	Email              string       `json:"email" gorm:"type:text;not null;uniqueIndex:idx_users_email,where:deleted_at IS NULL"`
+	Phone              string       `json:"phone" gorm:"type:text;not null;uniqueIndex:idx_users_phone,where:deleted_at IS NULL"`
 	Password           []byte       `json:"-" gorm:"type:bytea;not null"`
 	MfaSecret          []byte       `json:"-" gorm:"type:bytea"`
 	MfaEnabled         bool         `json:"mfaEnabled" gorm:"type:boolean;not null;default:false"`
@@ -26,25 +54,127 @@ type User struct {
 	ActiveOrganization uuid.UUID    `json:"activeOrganization" gorm:"type:uuid;not null"`
 	Type               UserType     `json:"-" gorm:"type:text;default:'standard';not null"`
 	AddressId          *uuid.UUID   `json:"-" gorm:"type:uuid"`
-	Address            *Address     `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Address            *Address     `json:"address" gorm:"foreignKey:AddressId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	BankDetailsId      *uuid.UUID   `json:"-" gorm:"type:uuid"`
-	BankDetails        *BankDetails `json:"bankDetails" gorm:"foreignKey:BankDetailsId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	BankDetails        *BankDetails `json:"bankDetails" gorm:"foreignKey:BankDetailsId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	// The identity number and date of birth are only returned by the identity
+	// routes. Everywhere else users carry the masked identity number.
+	IdentityDocumentType *IdentityDocumentType `json:"identityDocumentType" gorm:"type:text"`
+	IdentityNumber       *string               `json:"-" gorm:"type:text"`
+	MaskedIdentityNumber *string               `json:"identityNumber" gorm:"type:text"`
+	DateOfBirth          *time.Time            `json:"-" gorm:"type:date"`
+	IdentityStatus       IdentityStatus        `json:"identityStatus" gorm:"type:text;not null;default:'unverified'"`
+	IdentityReviewedAt   *time.Time            `json:"identityReviewedAt" gorm:"type:timestamptz"`
+	IdentityReviewedById *uuid.UUID            `json:"-" gorm:"type:uuid"`
+	IdentityNote         *string               `json:"identityNote" gorm:"type:text"`
+}
+
+// UserIdentity is the unmasked identity of a user, for those verifying it.
+type UserIdentity struct {
+	DocumentType *IdentityDocumentType `json:"documentType"`
+	Number       *string               `json:"number"`
+	DateOfBirth  *time.Time            `json:"dateOfBirth"`
+	Status       IdentityStatus        `json:"status"`
+	ReviewedAt   *time.Time            `json:"reviewedAt"`
+	ReviewedById *uuid.UUID            `json:"reviewedById"`
+	Note         *string               `json:"note"`
+}
+
+// Identity returns the unmasked identity of the user.
+func (u *User) Identity() UserIdentity {
+	return UserIdentity{
+		DocumentType: u.IdentityDocumentType,
+		Number:       u.IdentityNumber,
+		DateOfBirth:  u.DateOfBirth,
+		Status:       u.IdentityStatus,
+		ReviewedAt:   u.IdentityReviewedAt,
+		ReviewedById: u.IdentityReviewedById,
+		Note:         u.IdentityNote,
+	}
+}
+
+// HasPermission reports whether any of the user's roles grants the required
+// permission, either exactly, through a wildcard such as "collections.*", or
+// through the global "*" permission.
+func (u *User) HasPermission(requiredPermission string) bool {
+	combinedPermissions := []string{}
+
+	for _, role := range u.Roles {
+		combinedPermissions = append(combinedPermissions, role.Permissions...)
+	}
+
+	if slices.Contains(combinedPermissions, "*") {
+		return true
+	}
+
+	for _, permission := range combinedPermissions {
+		noWildCardPermission := strings.TrimSuffix(permission, ".*")
+
+		if strings.HasPrefix(requiredPermission, noWildCardPermission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanAccess reports whether the user may act on a record belonging to the
+// owner. Users may always act on their own records, and on those of the other
+// members of their active organization when they hold the required permission.
+// Records of an organization are only available to its members that hold the
+// required permission.
+func (u *User) CanAccess(owner *Owner, requiredPermission string) bool {
+	if owner == nil {
+		return u.HasPermission("*")
+	}
+
+	switch owner.Type {
+	case UserOwner:
+		return owner.Id == u.Id ||
+			(slices.Contains(owner.Organizations, u.ActiveOrganization) && u.HasPermission(requiredPermission))
+	case OrganizationOwner:
+		return owner.Id == u.ActiveOrganization && u.HasPermission(requiredPermission)
+	}
+
+	return false
+}
+
+// CanAccessAny reports whether the user may act on a record that belongs to
+// several owners, such as a collection of a buyer and a seller, through any of
+// them.
+func (u *User) CanAccessAny(owners []Owner, requiredPermission string) bool {
+	for _, owner := range owners {
+		if u.CanAccess(&owner, requiredPermission) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type CreateUserPayload struct {
-	Name     string   `json:"name"`
-	Email    string   `json:"email"`
-	Phone    string   `json:"phone"`
-	Password string   `json:"password"`
-	Roles    []Role   `json:"roles"`
-	Type     UserType `json:"type"`
+	Name                 string                `json:"name"`
+	Email                string                `json:"email"`
+	Phone                string                `json:"phone"`
+	Password             string                `json:"password"`
+	Roles                []Role                `json:"roles"`
+	Type                 UserType              `json:"type"`
+	IdentityDocumentType *IdentityDocumentType `json:"identityDocumentType"`
+	IdentityNumber       *string               `json:"identityNumber"`
 }
 
 type UpdateUserPayload struct {
-	Name     *string   `json:"name"`
-	Email    *string   `json:"email"`
-	Phone    *string   `json:"phone"`
-	Password *string   `json:"password"`
-	Roles    []Role    `json:"roles"`
-	Type     *UserType `json:"type"`
+	Name                 *string               `json:"name"`
+	Email                *string               `json:"email"`
+	Phone                *string               `json:"phone"`
+	Password             *string               `json:"password"`
+	Roles                []Role                `json:"roles"`
+	Type                 *UserType             `json:"type"`
+	IdentityDocumentType *IdentityDocumentType `json:"identityDocumentType"`
+	IdentityNumber       *string               `json:"identityNumber"`
+}
+
+type ReviewUserIdentityPayload struct {
+	Status IdentityStatus `json:"status"`
+	Note   *string        `json:"note"`
 }
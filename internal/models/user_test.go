@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUserCanAccess(t *testing.T) {
+	organization := uuid.New()
+	otherOrganization := uuid.New()
+
+	user := &User{
+		Base:               Base{Id: uuid.New()},
+		ActiveOrganization: organization,
+		Roles:              []Role{{Permissions: []string{"bank_details.view"}}},
+	}
+
+	tests := []struct {
+		name       string
+		owner      *Owner
+		permission string
+		want       bool
+	}{
+		{"own records", &Owner{Type: UserOwner, Id: user.Id}, "bank_details.update", true},
+		{"member of the active organization", &Owner{Type: UserOwner, Id: uuid.New(), Organizations: []uuid.UUID{otherOrganization, organization}}, "bank_details.view", true},
+		{"member without the permission", &Owner{Type: UserOwner, Id: uuid.New(), Organizations: []uuid.UUID{organization}}, "bank_details.update", false},
+		{"member of another organization", &Owner{Type: UserOwner, Id: uuid.New(), Organizations: []uuid.UUID{otherOrganization}}, "bank_details.view", false},
+		{"member of no organization", &Owner{Type: UserOwner, Id: uuid.New()}, "bank_details.view", false},
+		{"active organization", &Owner{Type: OrganizationOwner, Id: organization}, "bank_details.view", true},
+		{"another organization", &Owner{Type: OrganizationOwner, Id: otherOrganization}, "bank_details.view", false},
+		{"no owner", nil, "bank_details.view", false},
+	}
+
+	for _, test := range tests {
+		if got := user.CanAccess(test.owner, test.permission); got != test.want {
+			t.Errorf("%s: CanAccess = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
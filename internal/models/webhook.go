@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	CollectionCreatedEvent       WebhookEventType = "collection.created"
+	CollectionUpdatedEvent       WebhookEventType = "collection.updated"
+	CollectionStatusChangedEvent WebhookEventType = "collection.status_changed"
+	CollectionConfirmedEvent     WebhookEventType = "collection.confirmed"
+	TransactionCreatedEvent      WebhookEventType = "transaction.created"
+	TransactionUpdatedEvent      WebhookEventType = "transaction.updated"
+	TransactionSettledEvent      WebhookEventType = "transaction.settled"
+	UserCreatedEvent             WebhookEventType = "user.created"
+)
+
+// AllWebhookEvents subscribes a webhook to every event type, including types
+// added later.
+const AllWebhookEvents WebhookEventType = "*"
+
+// WebhookEndpoint is a URL of an organization that the events it subscribes
+// to are posted to, signed with its secret.
+type WebhookEndpoint struct {
+	Base
+	OrganizationId uuid.UUID          `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Url            string             `json:"url" gorm:"type:text;not null"`
+	Description    *string            `json:"description" gorm:"type:text"`
+	Events         []WebhookEventType `json:"events" gorm:"type:jsonb;serializer:json;not null"`
+	Active         bool               `json:"active" gorm:"not null;default:true"`
+	Secret         string             `json:"secret,omitempty" gorm:"type:text;not null"`
+}
+
+// WebhookEvent is a change that happened to the records of an organization,
+// recorded in the same database transaction as the change. Payload is the
+// body posted to each webhook subscribed to the event.
+type WebhookEvent struct {
+	Base
+	OrganizationId uuid.UUID        `json:"organizationId" gorm:"type:uuid;not null;index"`
+	Type           WebhookEventType `json:"type" gorm:"type:text;not null"`
+	Payload        json.RawMessage  `json:"payload" gorm:"type:jsonb;serializer:json;not null"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook, which is attempted
+// until the webhook responds with a 2xx status or the attempts run out.
+type WebhookDelivery struct {
+	Base
+	EndpointId  uuid.UUID                `json:"endpointId" gorm:"type:uuid;not null;index"`
+	Endpoint    WebhookEndpoint          `json:"-" gorm:"foreignKey:EndpointId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	EventId     uuid.UUID                `json:"-" gorm:"type:uuid;not null;index"`
+	Event       WebhookEvent             `json:"event" gorm:"foreignKey:EventId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Status      WebhookDeliveryStatus    `json:"status" gorm:"type:text;not null;default:'pending'"`
+	DeliveredAt *time.Time               `json:"deliveredAt" gorm:"type:timestamptz"`
+	Attempts    []WebhookDeliveryAttempt `json:"attempts" gorm:"foreignKey:DeliveryId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// WebhookDeliveryAttempt records the status a webhook responded with to one
+// attempt at a delivery, or why no response was received.
+type WebhookDeliveryAttempt struct {
+	Base
+	DeliveryId uuid.UUID `json:"deliveryId" gorm:"type:uuid;not null;index"`
+	StatusCode *int      `json:"statusCode" gorm:"type:integer"`
+	Error      *string   `json:"error" gorm:"type:text"`
+	Duration   int64     `json:"duration" gorm:"type:bigint;not null"`
+}
+
+// WebhookEventDescription describes an event type that webhooks can subscribe
+// to.
+type WebhookEventDescription struct {
+	Type        WebhookEventType `json:"type"`
+	Description string           `json:"description"`
+}
+
+type CreateWebhookEndpointPayload struct {
+	Url         string             `json:"url"`
+	Description *string            `json:"description"`
+	Events      []WebhookEventType `json:"events"`
+	Active      *bool              `json:"active"`
+}
+
+type UpdateWebhookEndpointPayload struct {
+	Url          *string            `json:"url"`
+	Description  *string            `json:"description"`
+	Events       []WebhookEventType `json:"events"`
+	Active       *bool              `json:"active"`
+	RotateSecret bool               `json:"rotateSecret"`
+}
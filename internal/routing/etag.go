@@ -0,0 +1,29 @@
+package routing
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag returns the entity tag of a record at the given version.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseETag returns the version of a record from its entity tag, as sent back
+// in an If-Match header. Weak entity tags are not accepted.
+func ParseETag(tag string) (int64, bool) {
+	tag = strings.TrimSpace(tag)
+
+	if len(tag) < 3 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
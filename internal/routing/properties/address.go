@@ -3,15 +3,20 @@ package properties
 import "github.com/getkin/kin-openapi/openapi3"
 
 var AddressProperties = map[string]*openapi3.Schema{
-	"id":        openapi3.NewUUIDSchema(),
-	"lineOne":   openapi3.NewStringSchema(),
-	"lineTwo":   openapi3.NewStringSchema().WithNullable(),
-	"city":      openapi3.NewStringSchema(),
-	"zipCode":   openapi3.NewStringSchema(),
-	"province":  openapi3.NewStringSchema(),
-	"country":   openapi3.NewStringSchema(),
-	"createdAt": openapi3.NewDateTimeSchema(),
-	"updatedAt": openapi3.NewDateTimeSchema(),
+	"id":           openapi3.NewUUIDSchema(),
+	"lineOne":      openapi3.NewStringSchema(),
+	"lineTwo":      openapi3.NewStringSchema().WithNullable(),
+	"city":         openapi3.NewStringSchema(),
+	"zipCode":      openapi3.NewStringSchema(),
+	"province":     openapi3.NewStringSchema(),
+	"provinceCode": openapi3.NewStringSchema().WithNullable(),
+	"country":      openapi3.NewStringSchema(),
+	"countryCode":  openapi3.NewStringSchema().WithNullable(),
+	"latitude":     openapi3.NewFloat64Schema().WithMin(-90).WithMax(90).WithNullable(),
+	"longitude":    openapi3.NewFloat64Schema().WithMin(-180).WithMax(180).WithNullable(),
+	"createdAt":    openapi3.NewDateTimeSchema(),
+	"updatedAt":    openapi3.NewDateTimeSchema(),
+	"version":      openapi3.NewInt64Schema(),
 }
 
 var CreateAddressProperties = map[string]*openapi3.Schema{
@@ -0,0 +1,28 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var attachmentCategories = []any{"photo", "delivery_note", "id_document", "other"}
+
+var AttachmentProperties = map[string]*openapi3.Schema{
+	"id":           openapi3.NewUUIDSchema(),
+	"parentType":   openapi3.NewStringSchema().WithEnum("collections", "transactions", "users", "organizations"),
+	"parentId":     openapi3.NewUUIDSchema(),
+	"category":     openapi3.NewStringSchema().WithEnum(attachmentCategories...),
+	"description":  openapi3.NewStringSchema().WithNullable(),
+	"fileName":     openapi3.NewStringSchema(),
+	"contentType":  openapi3.NewStringSchema(),
+	"size":         openapi3.NewInt64Schema(),
+	"checksum":     openapi3.NewStringSchema(),
+	"hasThumbnail": openapi3.NewBoolSchema(),
+	"uploadedById": openapi3.NewUUIDSchema(),
+	"createdAt":    openapi3.NewDateTimeSchema(),
+	"updatedAt":    openapi3.NewDateTimeSchema(),
+	"version":      openapi3.NewInt64Schema(),
+}
+
+var CreateAttachmentProperties = map[string]*openapi3.Schema{
+	"file":        openapi3.NewStringSchema().WithFormat("binary"),
+	"category":    openapi3.NewStringSchema().WithEnum(attachmentCategories...),
+	"description": openapi3.NewStringSchema(),
+}
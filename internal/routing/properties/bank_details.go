@@ -6,22 +6,32 @@ var BankDetailsProperties = map[string]*openapi3.Schema{
 	"id":            openapi3.NewUUIDSchema(),
 	"accountNumber": openapi3.NewStringSchema(),
 	"accountHolder": openapi3.NewStringSchema(),
+	"accountType":   openapi3.NewStringSchema().WithEnum("cheque", "savings", "transmission"),
 	"bankName":      openapi3.NewStringSchema(),
 	"branchCode":    openapi3.NewStringSchema(),
 	"createdAt":     openapi3.NewDateTimeSchema(),
 	"updatedAt":     openapi3.NewDateTimeSchema(),
+	"version":       openapi3.NewInt64Schema(),
 }
 
 var CreateBankDetailsProperties = map[string]*openapi3.Schema{
 	"accountNumber": openapi3.NewStringSchema(),
 	"accountHolder": openapi3.NewStringSchema(),
+	"accountType":   openapi3.NewStringSchema().WithEnum("cheque", "savings", "transmission").WithDefault("cheque"),
 	"bankName":      openapi3.NewStringSchema(),
-	"branchCode":    openapi3.NewStringSchema(),
+	"branchCode":    openapi3.NewStringSchema().WithNullable(),
 }
 
 var UpdateBankDetailsProperties = map[string]*openapi3.Schema{
 	"accountNumber": openapi3.NewStringSchema().WithNullable(),
 	"accountHolder": openapi3.NewStringSchema().WithNullable(),
+	"accountType":   openapi3.NewStringSchema().WithEnum("cheque", "savings", "transmission").WithNullable(),
 	"bankName":      openapi3.NewStringSchema().WithNullable(),
 	"branchCode":    openapi3.NewStringSchema().WithNullable(),
 }
+
+var BankProperties = map[string]*openapi3.Schema{
+	"name":                openapi3.NewStringSchema(),
+	"universalBranchCode": openapi3.NewStringSchema(),
+	"accountLengths":      openapi3.NewArraySchema().WithItems(openapi3.NewInt64Schema()),
+}
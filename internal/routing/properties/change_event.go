@@ -0,0 +1,12 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var ChangeEventProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewInt64Schema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"resource":       openapi3.NewStringSchema().WithEnum("collections", "transactions", "inventory"),
+	"action":         openapi3.NewStringSchema().WithEnum("insert", "update", "delete"),
+	"recordId":       openapi3.NewUUIDSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+}
@@ -3,39 +3,65 @@ package properties
 import "github.com/getkin/kin-openapi/openapi3"
 
 var CollectionProperties = map[string]*openapi3.Schema{
-	"id":        openapi3.NewUUIDSchema(),
-	"sellerId":  openapi3.NewUUIDSchema(),
-	"buyerId":   openapi3.NewUUIDSchema(),
-	"createdAt": openapi3.NewDateTimeSchema(),
-	"updatedAt": openapi3.NewDateTimeSchema(),
+	"id":         openapi3.NewUUIDSchema(),
+	"sellerId":   openapi3.NewUUIDSchema(),
+	"buyerId":    openapi3.NewUUIDSchema(),
+	"siteId":     openapi3.NewUUIDSchema().WithNullable(),
+	"status":     openapi3.NewStringSchema().WithEnum("draft", "weighed", "confirmed", "paid", "voided"),
+	"voidReason": openapi3.NewStringSchema().WithNullable(),
+	"createdAt":  openapi3.NewDateTimeSchema(),
+	"updatedAt":  openapi3.NewDateTimeSchema(),
+	"version":    openapi3.NewInt64Schema(),
 }
 
 var CreateCollectionProperties = map[string]*openapi3.Schema{
 	"sellerId": openapi3.NewUUIDSchema(),
 	"buyerId":  openapi3.NewUUIDSchema(),
+	"siteId":   openapi3.NewUUIDSchema().WithNullable(),
 }
 
 var UpdateCollectionProperties = map[string]*openapi3.Schema{
 	"sellerId": openapi3.NewUUIDSchema().WithNullable(),
 	"buyerId":  openapi3.NewUUIDSchema().WithNullable(),
+	"siteId":   openapi3.NewUUIDSchema().WithNullable(),
+}
+
+var CollectionTransitionProperties = map[string]*openapi3.Schema{
+	"id":           openapi3.NewUUIDSchema(),
+	"collectionId": openapi3.NewUUIDSchema(),
+	"from":         openapi3.NewStringSchema().WithEnum("draft", "weighed", "confirmed", "paid", "voided"),
+	"to":           openapi3.NewStringSchema().WithEnum("draft", "weighed", "confirmed", "paid", "voided"),
+	"reason":       openapi3.NewStringSchema().WithNullable(),
+	"createdAt":    openapi3.NewDateTimeSchema(),
+	"updatedAt":    openapi3.NewDateTimeSchema(),
+	"version":      openapi3.NewInt64Schema(),
+}
+
+var VoidCollectionProperties = map[string]*openapi3.Schema{
+	"reason": openapi3.NewStringSchema(),
 }
 
 var CollectionMaterialProperties = map[string]*openapi3.Schema{
-	"id":        openapi3.NewUUIDSchema(),
-	"weight":    openapi3.NewFloat64Schema(),
-	"value":     openapi3.NewFloat64Schema(),
-	"createdAt": openapi3.NewDateTimeSchema(),
-	"updatedAt": openapi3.NewDateTimeSchema(),
+	"id":             openapi3.NewUUIDSchema(),
+	"weight":         openapi3.NewFloat64Schema(),
+	"value":          openapi3.NewFloat64Schema(),
+	"scaleReadingId": openapi3.NewUUIDSchema().WithNullable(),
+	"manualOverride": openapi3.NewBoolSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
 }
 
 var CreateCollectionMaterialProperties = map[string]*openapi3.Schema{
-	"materialId": openapi3.NewUUIDSchema(),
-	"weight":     openapi3.NewFloat64Schema(),
-	"value":      openapi3.NewFloat64Schema(),
+	"materialId":     openapi3.NewUUIDSchema(),
+	"weight":         openapi3.NewFloat64Schema(),
+	"value":          openapi3.NewFloat64Schema(),
+	"scaleReadingId": openapi3.NewUUIDSchema().WithNullable(),
 }
 
 var UpdateCollectionMaterialProperties = map[string]*openapi3.Schema{
-	"materialId": openapi3.NewUUIDSchema().WithNullable(),
-	"weight":     openapi3.NewFloat64Schema().WithNullable(),
-	"value":      openapi3.NewFloat64Schema().WithNullable(),
+	"materialId":     openapi3.NewUUIDSchema().WithNullable(),
+	"weight":         openapi3.NewFloat64Schema().WithNullable(),
+	"value":          openapi3.NewFloat64Schema().WithNullable(),
+	"scaleReadingId": openapi3.NewUUIDSchema().WithNullable(),
 }
@@ -0,0 +1,24 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var EprReportProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"periodStart":    openapi3.NewDateTimeSchema(),
+	"periodEnd":      openapi3.NewDateTimeSchema(),
+	"cutOff":         openapi3.NewDateTimeSchema(),
+	"tonnage":        openapi3.NewFloat64Schema(),
+	"carbonAvoided":  openapi3.NewFloat64Schema(),
+	"pdfChecksum":    openapi3.NewStringSchema(),
+	"xlsxChecksum":   openapi3.NewStringSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var CreateEprReportProperties = map[string]*openapi3.Schema{
+	"periodStart": openapi3.NewDateTimeSchema(),
+	"periodEnd":   openapi3.NewDateTimeSchema(),
+	"cutOff":      openapi3.NewDateTimeSchema().WithNullable(),
+}
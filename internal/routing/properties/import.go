@@ -0,0 +1,44 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var ImportJobProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"kind":           openapi3.NewStringSchema().WithEnum("materials", "users", "collections"),
+	"status":         openapi3.NewStringSchema().WithEnum("mapping", "validating", "validated", "invalid", "applying", "applied", "failed"),
+	"fileName":       openapi3.NewStringSchema(),
+	"columns":        openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()),
+	"mapping":        openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema()),
+	"totalRows":      openapi3.NewIntegerSchema(),
+	"processedRows":  openapi3.NewIntegerSchema(),
+	"failedRows":     openapi3.NewIntegerSchema(),
+	"failure":        openapi3.NewStringSchema().WithNullable(),
+	"appliedAt":      openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var ImportRowErrorProperties = map[string]*openapi3.Schema{
+	"row":     openapi3.NewIntegerSchema(),
+	"field":   openapi3.NewStringSchema(),
+	"message": openapi3.NewStringSchema(),
+}
+
+var ImportFieldProperties = map[string]*openapi3.Schema{
+	"key":         openapi3.NewStringSchema(),
+	"label":       openapi3.NewStringSchema(),
+	"required":    openapi3.NewBoolSchema(),
+	"description": openapi3.NewStringSchema(),
+}
+
+var CreateImportProperties = map[string]*openapi3.Schema{
+	"kind":    openapi3.NewStringSchema().WithEnum("materials", "users", "collections"),
+	"file":    openapi3.NewStringSchema().WithFormat("binary"),
+	"mapping": openapi3.NewStringSchema(),
+}
+
+var UpdateImportMappingProperties = map[string]*openapi3.Schema{
+	"mapping": openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewStringSchema()),
+}
@@ -0,0 +1,40 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var InventoryEntryProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"type":           openapi3.NewStringSchema().WithEnum("receipt", "issue", "adjustment", "reversal"),
+	"reason":         openapi3.NewStringSchema().WithEnum("shrinkage", "moisture_loss", "baling", "correction").WithNullable(),
+	"weight":         openapi3.NewFloat64Schema(),
+	"value":          openapi3.NewFloat64Schema(),
+	"sourceType":     openapi3.NewStringSchema().WithEnum("collection_material", "transaction_material", "adjustment"),
+	"sourceId":       openapi3.NewUUIDSchema().WithNullable(),
+	"notes":          openapi3.NewStringSchema().WithNullable(),
+	"occurredAt":     openapi3.NewDateTimeSchema(),
+	"createdById":    openapi3.NewUUIDSchema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var InventoryBalanceProperties = map[string]*openapi3.Schema{
+	"materialId":   openapi3.NewUUIDSchema(),
+	"materialName": openapi3.NewStringSchema(),
+	"siteId":       openapi3.NewUUIDSchema().WithNullable(),
+	"siteName":     openapi3.NewStringSchema().WithNullable(),
+	"weight":       openapi3.NewFloat64Schema(),
+	"value":        openapi3.NewFloat64Schema(),
+}
+
+var CreateInventoryAdjustmentProperties = map[string]*openapi3.Schema{
+	"materialId": openapi3.NewUUIDSchema(),
+	"siteId":     openapi3.NewUUIDSchema().WithNullable(),
+	"weight":     openapi3.NewFloat64Schema(),
+	"value":      openapi3.NewFloat64Schema(),
+	"reason":     openapi3.NewStringSchema().WithEnum("shrinkage", "moisture_loss", "baling", "correction"),
+	"notes":      openapi3.NewStringSchema().WithNullable(),
+	"occurredAt": openapi3.NewDateTimeSchema().WithNullable(),
+}
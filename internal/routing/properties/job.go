@@ -0,0 +1,32 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var JobProperties = map[string]*openapi3.Schema{
+	"id":          openapi3.NewUUIDSchema(),
+	"kind":        openapi3.NewStringSchema(),
+	"payload":     openapi3.NewObjectSchema(),
+	"status":      openapi3.NewStringSchema().WithEnum("pending", "running", "succeeded", "dead"),
+	"runAt":       openapi3.NewDateTimeSchema(),
+	"attempts":    openapi3.NewIntegerSchema(),
+	"maxAttempts": openapi3.NewIntegerSchema(),
+	"lockedAt":    openapi3.NewDateTimeSchema().WithNullable(),
+	"lockedBy":    openapi3.NewStringSchema().WithNullable(),
+	"lastError":   openapi3.NewStringSchema().WithNullable(),
+	"schedule":    openapi3.NewStringSchema().WithNullable(),
+	"finishedAt":  openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":   openapi3.NewDateTimeSchema(),
+	"updatedAt":   openapi3.NewDateTimeSchema(),
+	"version":     openapi3.NewInt64Schema(),
+}
+
+var JobScheduleProperties = map[string]*openapi3.Schema{
+	"name":      openapi3.NewStringSchema(),
+	"kind":      openapi3.NewStringSchema(),
+	"spec":      openapi3.NewStringSchema(),
+	"payload":   openapi3.NewObjectSchema(),
+	"nextRunAt": openapi3.NewDateTimeSchema(),
+	"lastRunAt": openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt": openapi3.NewDateTimeSchema(),
+	"updatedAt": openapi3.NewDateTimeSchema(),
+}
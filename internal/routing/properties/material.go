@@ -7,18 +7,22 @@ var MaterialProperties = map[string]*openapi3.Schema{
 	"name":         openapi3.NewStringSchema(),
 	"gwCode":       openapi3.NewStringSchema(),
 	"carbonFactor": openapi3.NewStringSchema(),
+	"regulated":    openapi3.NewBoolSchema(),
 	"createdAt":    openapi3.NewDateTimeSchema(),
 	"updatedAt":    openapi3.NewDateTimeSchema(),
+	"version":      openapi3.NewInt64Schema(),
 }
 
 var CreateMaterialProperties = map[string]*openapi3.Schema{
 	"name":         openapi3.NewStringSchema(),
 	"gwCode":       openapi3.NewStringSchema(),
 	"carbonFactor": openapi3.NewStringSchema(),
+	"regulated":    openapi3.NewBoolSchema(),
 }
 
 var UpdateMaterialProperties = map[string]*openapi3.Schema{
 	"name":         openapi3.NewStringSchema(),
 	"gwCode":       openapi3.NewStringSchema(),
 	"carbonFactor": openapi3.NewStringSchema(),
+	"regulated":    openapi3.NewBoolSchema(),
 }
@@ -7,6 +7,7 @@ var OrganizationProperties = map[string]*openapi3.Schema{
 	"name":      openapi3.NewStringSchema(),
 	"createdAt": openapi3.NewDateTimeSchema(),
 	"updatedAt": openapi3.NewDateTimeSchema(),
+	"version":   openapi3.NewInt64Schema(),
 }
 
 var CreateOrganizationProperties = map[string]*openapi3.Schema{
@@ -0,0 +1,56 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var PayoutBatchProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"reference":      openapi3.NewStringSchema(),
+	"status":         openapi3.NewStringSchema().WithEnum("draft", "confirmed", "reversed"),
+	"total":          openapi3.NewFloat64Schema(),
+	"payoutCount":    openapi3.NewInt64Schema(),
+	"exportedAt":     openapi3.NewDateTimeSchema().WithNullable(),
+	"confirmedAt":    openapi3.NewDateTimeSchema().WithNullable(),
+	"reversedAt":     openapi3.NewDateTimeSchema().WithNullable(),
+	"reversalReason": openapi3.NewStringSchema().WithNullable(),
+	"createdById":    openapi3.NewUUIDSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var PayoutProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"batchId":        openapi3.NewUUIDSchema().WithNullable(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"method":         openapi3.NewStringSchema().WithEnum("eft", "cash"),
+	"status":         openapi3.NewStringSchema().WithEnum("pending", "paid", "reversed"),
+	"amount":         openapi3.NewFloat64Schema(),
+	"accountHolder":  openapi3.NewStringSchema().WithNullable(),
+	"accountNumber":  openapi3.NewStringSchema().WithNullable(),
+	"accountType":    openapi3.NewStringSchema().WithEnum("cheque", "savings", "transmission").WithNullable(),
+	"bankName":       openapi3.NewStringSchema().WithNullable(),
+	"branchCode":     openapi3.NewStringSchema().WithNullable(),
+	"receiptNumber":  openapi3.NewStringSchema().WithNullable(),
+	"paidAt":         openapi3.NewDateTimeSchema().WithNullable(),
+	"paidById":       openapi3.NewUUIDSchema().WithNullable(),
+	"reversedAt":     openapi3.NewDateTimeSchema().WithNullable(),
+	"reversalReason": openapi3.NewStringSchema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var CreatePayoutBatchProperties = map[string]*openapi3.Schema{
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"confirmedUntil": openapi3.NewDateTimeSchema().WithNullable(),
+}
+
+var CreateCashPayoutProperties = map[string]*openapi3.Schema{
+	"collectionIds": openapi3.NewArraySchema().WithItems(openapi3.NewUUIDSchema()),
+	"receiptNumber": openapi3.NewStringSchema(),
+}
+
+var ReversePayoutProperties = map[string]*openapi3.Schema{
+	"reason": openapi3.NewStringSchema(),
+}
@@ -0,0 +1,59 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var PickupProperties = map[string]*openapi3.Schema{
+	"id":                 openapi3.NewUUIDSchema(),
+	"organizationId":     openapi3.NewUUIDSchema(),
+	"siteId":             openapi3.NewUUIDSchema().WithNullable(),
+	"scheduledFor":       openapi3.NewDateTimeSchema().WithNullable(),
+	"status":             openapi3.NewStringSchema().WithEnum("requested", "scheduled", "completed", "cancelled"),
+	"notes":              openapi3.NewStringSchema().WithNullable(),
+	"cancellationReason": openapi3.NewStringSchema().WithNullable(),
+	"collectionId":       openapi3.NewUUIDSchema().WithNullable(),
+	"completedAt":        openapi3.NewDateTimeSchema().WithNullable(),
+	"requestedById":      openapi3.NewUUIDSchema(),
+	"createdAt":          openapi3.NewDateTimeSchema(),
+	"updatedAt":          openapi3.NewDateTimeSchema(),
+	"version":            openapi3.NewInt64Schema(),
+}
+
+var PickupMaterialProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"expectedWeight": openapi3.NewFloat64Schema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var CreatePickupMaterialProperties = map[string]*openapi3.Schema{
+	"materialId":     openapi3.NewUUIDSchema(),
+	"expectedWeight": openapi3.NewFloat64Schema().WithMin(0),
+}
+
+var CreatePickupProperties = map[string]*openapi3.Schema{
+	"organizationId": openapi3.NewUUIDSchema().WithNullable(),
+	"sellerId":       openapi3.NewUUIDSchema().WithNullable(),
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"notes":          openapi3.NewStringSchema().WithNullable(),
+}
+
+var AssignPickupProperties = map[string]*openapi3.Schema{
+	"collectorId":  openapi3.NewUUIDSchema(),
+	"scheduledFor": openapi3.NewDateTimeSchema(),
+}
+
+var CancelPickupProperties = map[string]*openapi3.Schema{
+	"reason": openapi3.NewStringSchema(),
+}
+
+var PickupRouteProperties = map[string]*openapi3.Schema{
+	"collectorId": openapi3.NewUUIDSchema(),
+	"date":        openapi3.NewStringSchema().WithFormat("date"),
+	"distance":    openapi3.NewFloat64Schema(),
+}
+
+var PickupRouteStopProperties = map[string]*openapi3.Schema{
+	"sequence": openapi3.NewInt64Schema().WithMin(1),
+	"distance": openapi3.NewFloat64Schema(),
+}
@@ -0,0 +1,32 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var VolumeReportProperties = map[string]*openapi3.Schema{
+	"source":       openapi3.NewStringSchema().WithEnum("collections", "transactions"),
+	"groupBy":      openapi3.NewStringSchema().WithEnum("material", "organization", "site", "collector"),
+	"period":       openapi3.NewStringSchema().WithEnum("day", "week", "month", "quarter"),
+	"from":         openapi3.NewDateTimeSchema(),
+	"to":           openapi3.NewDateTimeSchema(),
+	"previousFrom": openapi3.NewDateTimeSchema(),
+	"previousTo":   openapi3.NewDateTimeSchema(),
+}
+
+var VolumeTotalProperties = map[string]*openapi3.Schema{
+	"key":            openapi3.NewUUIDSchema().WithNullable(),
+	"name":           openapi3.NewStringSchema(),
+	"weight":         openapi3.NewFloat64Schema(),
+	"value":          openapi3.NewFloat64Schema(),
+	"previousWeight": openapi3.NewFloat64Schema(),
+	"previousValue":  openapi3.NewFloat64Schema(),
+	"weightChange":   openapi3.NewFloat64Schema().WithNullable(),
+	"valueChange":    openapi3.NewFloat64Schema().WithNullable(),
+}
+
+var VolumeBucketProperties = map[string]*openapi3.Schema{
+	"bucket": openapi3.NewDateTimeSchema(),
+	"key":    openapi3.NewUUIDSchema().WithNullable(),
+	"name":   openapi3.NewStringSchema(),
+	"weight": openapi3.NewFloat64Schema(),
+	"value":  openapi3.NewFloat64Schema(),
+}
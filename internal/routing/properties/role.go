@@ -9,6 +9,7 @@ var RoleProperties = map[string]*openapi3.Schema{
 	"permissions": openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()),
 	"createdAt":   openapi3.NewDateTimeSchema(),
 	"updatedAt":   openapi3.NewDateTimeSchema(),
+	"version":     openapi3.NewInt64Schema(),
 }
 
 var CreateRoleProperties = map[string]*openapi3.Schema{
@@ -0,0 +1,57 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var scaleWeightUnits = []any{"kg", "g", "t", "lb", "lbs"}
+
+var ScaleProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"siteId":         openapi3.NewUUIDSchema(),
+	"name":           openapi3.NewStringSchema(),
+	"active":         openapi3.NewBoolSchema(),
+	"keyPrefix":      openapi3.NewStringSchema(),
+	"key":            openapi3.NewStringSchema(),
+	"lastSeenAt":     openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var CreateScaleProperties = map[string]*openapi3.Schema{
+	"siteId": openapi3.NewUUIDSchema(),
+	"name":   openapi3.NewStringSchema(),
+	"active": openapi3.NewBoolSchema().WithNullable(),
+}
+
+var UpdateScaleProperties = map[string]*openapi3.Schema{
+	"siteId": openapi3.NewUUIDSchema().WithNullable(),
+	"name":   openapi3.NewStringSchema().WithNullable(),
+	"active": openapi3.NewBoolSchema().WithNullable(),
+}
+
+var ScaleReadingProperties = map[string]*openapi3.Schema{
+	"id":               openapi3.NewUUIDSchema(),
+	"scaleId":          openapi3.NewUUIDSchema(),
+	"gross":            openapi3.NewFloat64Schema(),
+	"tare":             openapi3.NewFloat64Schema(),
+	"net":              openapi3.NewFloat64Schema(),
+	"stable":           openapi3.NewBoolSchema(),
+	"capturedAt":       openapi3.NewDateTimeSchema(),
+	"raw":              openapi3.NewStringSchema().WithNullable(),
+	"previousChecksum": openapi3.NewStringSchema().WithNullable(),
+	"checksum":         openapi3.NewStringSchema(),
+	"createdAt":        openapi3.NewDateTimeSchema(),
+	"updatedAt":        openapi3.NewDateTimeSchema(),
+	"version":          openapi3.NewInt64Schema(),
+}
+
+var RecordScaleReadingProperties = map[string]*openapi3.Schema{
+	"gross":      openapi3.NewFloat64Schema().WithNullable(),
+	"tare":       openapi3.NewFloat64Schema().WithNullable(),
+	"net":        openapi3.NewFloat64Schema().WithNullable(),
+	"unit":       openapi3.NewStringSchema().WithEnum(scaleWeightUnits...),
+	"stable":     openapi3.NewBoolSchema().WithNullable(),
+	"capturedAt": openapi3.NewDateTimeSchema().WithNullable(),
+	"raw":        openapi3.NewStringSchema(),
+}
@@ -0,0 +1,32 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var MaterialTotalProperties = map[string]*openapi3.Schema{
+	"materialId":    openapi3.NewUUIDSchema(),
+	"materialName":  openapi3.NewStringSchema(),
+	"weight":        openapi3.NewFloat64Schema(),
+	"value":         openapi3.NewFloat64Schema(),
+	"carbonAvoided": openapi3.NewFloat64Schema(),
+}
+
+var PeriodTotalProperties = map[string]*openapi3.Schema{
+	"period":        openapi3.NewDateTimeSchema(),
+	"weight":        openapi3.NewFloat64Schema(),
+	"value":         openapi3.NewFloat64Schema(),
+	"carbonAvoided": openapi3.NewFloat64Schema(),
+}
+
+var SellerEarningsProperties = map[string]*openapi3.Schema{
+	"period":      openapi3.NewStringSchema().WithEnum("day", "week", "month", "year"),
+	"total":       openapi3.NewFloat64Schema(),
+	"paid":        openapi3.NewFloat64Schema(),
+	"outstanding": openapi3.NewFloat64Schema(),
+}
+
+var SellerImpactProperties = map[string]*openapi3.Schema{
+	"period":        openapi3.NewStringSchema().WithEnum("day", "week", "month", "year"),
+	"collections":   openapi3.NewInt64Schema(),
+	"weight":        openapi3.NewFloat64Schema(),
+	"carbonAvoided": openapi3.NewFloat64Schema(),
+}
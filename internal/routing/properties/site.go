@@ -0,0 +1,32 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var SiteProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"name":           openapi3.NewStringSchema(),
+	"latitude":       openapi3.NewFloat64Schema().WithMin(-90).WithMax(90).WithNullable(),
+	"longitude":      openapi3.NewFloat64Schema().WithMin(-180).WithMax(180).WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var SiteOperatingHourProperties = map[string]*openapi3.Schema{
+	"dayOfWeek": openapi3.NewIntegerSchema().WithMin(0).WithMax(6),
+	"opens":     openapi3.NewStringSchema().WithPattern(`^\d{2}:\d{2}$`),
+	"closes":    openapi3.NewStringSchema().WithPattern(`^\d{2}:\d{2}$`),
+}
+
+var CreateSiteProperties = map[string]*openapi3.Schema{
+	"name":      openapi3.NewStringSchema(),
+	"latitude":  openapi3.NewFloat64Schema().WithMin(-90).WithMax(90).WithNullable(),
+	"longitude": openapi3.NewFloat64Schema().WithMin(-180).WithMax(180).WithNullable(),
+}
+
+var UpdateSiteProperties = map[string]*openapi3.Schema{
+	"name":      openapi3.NewStringSchema().WithNullable(),
+	"latitude":  openapi3.NewFloat64Schema().WithMin(-90).WithMax(90).WithNullable(),
+	"longitude": openapi3.NewFloat64Schema().WithMin(-180).WithMax(180).WithNullable(),
+}
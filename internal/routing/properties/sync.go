@@ -0,0 +1,31 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var SyncCollectionProperties = map[string]*openapi3.Schema{
+	"id":        openapi3.NewUUIDSchema(),
+	"createdAt": openapi3.NewDateTimeSchema(),
+	"sellerId":  openapi3.NewUUIDSchema(),
+	"buyerId":   openapi3.NewUUIDSchema(),
+	"siteId":    openapi3.NewUUIDSchema().WithNullable(),
+}
+
+var SyncCollectionResultProperties = map[string]*openapi3.Schema{
+	"id":      openapi3.NewUUIDSchema(),
+	"status":  openapi3.NewStringSchema().WithEnum("created", "unchanged", "conflict", "rejected"),
+	"message": openapi3.NewStringSchema().WithNullable(),
+}
+
+var SyncChangesProperties = map[string]*openapi3.Schema{
+	"cursor":           openapi3.NewDateTimeSchema(),
+	"deletedMaterials": openapi3.NewArraySchema().WithItems(openapi3.NewUUIDSchema()),
+	"deletedSellers":   openapi3.NewArraySchema().WithItems(openapi3.NewUUIDSchema()),
+}
+
+var SyncSellerProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"name":           openapi3.NewStringSchema(),
+	"identityNumber": openapi3.NewStringSchema().WithNullable(),
+	"identityStatus": openapi3.NewStringSchema().WithEnum("unverified", "pending", "verified", "rejected"),
+	"version":        openapi3.NewInt64Schema(),
+}
@@ -3,39 +3,93 @@ package properties
 import "github.com/getkin/kin-openapi/openapi3"
 
 var TransactionProperties = map[string]*openapi3.Schema{
-	"id":        openapi3.NewUUIDSchema(),
-	"sellerId":  openapi3.NewUUIDSchema(),
-	"buyerId":   openapi3.NewUUIDSchema(),
-	"createdAt": openapi3.NewDateTimeSchema(),
-	"updatedAt": openapi3.NewDateTimeSchema(),
+	"id":             openapi3.NewUUIDSchema(),
+	"sellerId":       openapi3.NewUUIDSchema(),
+	"buyerId":        openapi3.NewUUIDSchema(),
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"status":         openapi3.NewStringSchema().WithEnum("quoted", "accepted", "rejected", "cancelled", "delivered", "invoiced", "settled"),
+	"quoteExpiresAt": openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
 }
 
 var CreateTransactionProperties = map[string]*openapi3.Schema{
-	"sellerId": openapi3.NewUUIDSchema(),
-	"buyerId":  openapi3.NewUUIDSchema(),
+	"sellerId":       openapi3.NewUUIDSchema(),
+	"buyerId":        openapi3.NewUUIDSchema(),
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"quoteExpiresAt": openapi3.NewDateTimeSchema().WithNullable(),
+	"overrideStock":  openapi3.NewBoolSchema(),
 }
 
 var UpdateTransactionProperties = map[string]*openapi3.Schema{
-	"sellerId": openapi3.NewUUIDSchema().WithNullable(),
-	"buyerId":  openapi3.NewUUIDSchema().WithNullable(),
+	"sellerId":       openapi3.NewUUIDSchema().WithNullable(),
+	"buyerId":        openapi3.NewUUIDSchema().WithNullable(),
+	"siteId":         openapi3.NewUUIDSchema().WithNullable(),
+	"quoteExpiresAt": openapi3.NewDateTimeSchema().WithNullable(),
+}
+
+var TransactionTransitionProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"transactionId":  openapi3.NewUUIDSchema(),
+	"from":           openapi3.NewStringSchema().WithEnum("quoted", "accepted", "rejected", "cancelled", "delivered", "invoiced", "settled"),
+	"to":             openapi3.NewStringSchema().WithEnum("quoted", "accepted", "rejected", "cancelled", "delivered", "invoiced", "settled"),
+	"reason":         openapi3.NewStringSchema().WithNullable(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var TransactionTransitionPayloadProperties = map[string]*openapi3.Schema{
+	"reason": openapi3.NewStringSchema().WithNullable(),
+}
+
+var DeliverTransactionMaterialProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"receivedWeight": openapi3.NewFloat64Schema().WithMin(0),
+}
+
+var InvoiceProperties = map[string]*openapi3.Schema{
+	"id":               openapi3.NewUUIDSchema(),
+	"transactionId":    openapi3.NewUUIDSchema(),
+	"organizationId":   openapi3.NewUUIDSchema(),
+	"number":           openapi3.NewInt64Schema(),
+	"reference":        openapi3.NewStringSchema(),
+	"total":            openapi3.NewFloat64Schema(),
+	"issuedAt":         openapi3.NewDateTimeSchema(),
+	"settledAmount":    openapi3.NewFloat64Schema(),
+	"settlementStatus": openapi3.NewStringSchema().WithEnum("unsettled", "partially_settled", "settled"),
+	"settledAt":        openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":        openapi3.NewDateTimeSchema(),
+	"updatedAt":        openapi3.NewDateTimeSchema(),
+	"version":          openapi3.NewInt64Schema(),
+}
+
+var SettleInvoiceProperties = map[string]*openapi3.Schema{
+	"amount": openapi3.NewFloat64Schema(),
 }
 
 var TransactionMaterialProperties = map[string]*openapi3.Schema{
-	"id":        openapi3.NewUUIDSchema(),
-	"weight":    openapi3.NewFloat64Schema(),
-	"value":     openapi3.NewFloat64Schema(),
-	"createdAt": openapi3.NewDateTimeSchema(),
-	"updatedAt": openapi3.NewDateTimeSchema(),
+	"id":             openapi3.NewUUIDSchema(),
+	"weight":         openapi3.NewFloat64Schema(),
+	"value":          openapi3.NewFloat64Schema(),
+	"receivedWeight": openapi3.NewFloat64Schema().WithNullable(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
 }
 
 var CreateTransactionMaterialProperties = map[string]*openapi3.Schema{
-	"materialId": openapi3.NewUUIDSchema(),
-	"weight":     openapi3.NewFloat64Schema(),
-	"value":      openapi3.NewFloat64Schema(),
+	"materialId":    openapi3.NewUUIDSchema(),
+	"weight":        openapi3.NewFloat64Schema(),
+	"value":         openapi3.NewFloat64Schema(),
+	"overrideStock": openapi3.NewBoolSchema(),
 }
 
 var UpdateTransactionMaterialProperties = map[string]*openapi3.Schema{
-	"materialId": openapi3.NewUUIDSchema().WithNullable(),
-	"weight":     openapi3.NewFloat64Schema().WithNullable(),
-	"value":      openapi3.NewFloat64Schema().WithNullable(),
+	"materialId":    openapi3.NewUUIDSchema().WithNullable(),
+	"weight":        openapi3.NewFloat64Schema().WithNullable(),
+	"value":         openapi3.NewFloat64Schema().WithNullable(),
+	"overrideStock": openapi3.NewBoolSchema(),
 }
@@ -0,0 +1,10 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var TrashItemProperties = map[string]*openapi3.Schema{
+	"resource":  openapi3.NewStringSchema().WithEnum("users", "organizations", "materials", "roles", "sites", "collections", "transactions", "scales", "webhooks"),
+	"id":        openapi3.NewUUIDSchema(),
+	"label":     openapi3.NewStringSchema(),
+	"deletedAt": openapi3.NewDateTimeSchema(),
+}
@@ -3,29 +3,37 @@ package properties
 import "github.com/getkin/kin-openapi/openapi3"
 
 var UserProperties = map[string]*openapi3.Schema{
-	"id":                 openapi3.NewUUIDSchema(),
-	"name":               openapi3.NewStringSchema(),
-	"email":              openapi3.NewStringSchema(),
-	"phone":              openapi3.NewStringSchema(),
-	"mfaEnabled":         openapi3.NewBoolSchema(),
-	"mfaVerified":        openapi3.NewBoolSchema(),
-	"banned":             openapi3.NewBoolSchema(),
-	"banReason":          openapi3.NewStringSchema().WithNullable(),
-	"activeOrganization": openapi3.NewUUIDSchema(),
-	"type":               openapi3.NewStringSchema().WithEnum("standard", "collector", "business", "system"),
-	"createdAt":          openapi3.NewDateTimeSchema(),
-	"updatedAt":          openapi3.NewDateTimeSchema(),
+	"id":                   openapi3.NewUUIDSchema(),
+	"name":                 openapi3.NewStringSchema(),
+	"email":                openapi3.NewStringSchema(),
+	"phone":                openapi3.NewStringSchema(),
+	"mfaEnabled":           openapi3.NewBoolSchema(),
+	"mfaVerified":          openapi3.NewBoolSchema(),
+	"banned":               openapi3.NewBoolSchema(),
+	"banReason":            openapi3.NewStringSchema().WithNullable(),
+	"activeOrganization":   openapi3.NewUUIDSchema(),
+	"type":                 openapi3.NewStringSchema().WithEnum("standard", "collector", "business", "system"),
+	"identityDocumentType": openapi3.NewStringSchema().WithEnum("sa_id", "passport", "asylum_permit", "refugee_id").WithNullable(),
+	"identityNumber":       openapi3.NewStringSchema().WithNullable(),
+	"identityStatus":       openapi3.NewStringSchema().WithEnum("unverified", "pending", "verified", "rejected"),
+	"identityReviewedAt":   openapi3.NewDateTimeSchema().WithNullable(),
+	"identityNote":         openapi3.NewStringSchema().WithNullable(),
+	"createdAt":            openapi3.NewDateTimeSchema(),
+	"updatedAt":            openapi3.NewDateTimeSchema(),
+	"version":              openapi3.NewInt64Schema(),
 }
 
 var CreateUserProperties = map[string]*openapi3.Schema{
-	"name":          openapi3.NewStringSchema(),
-	"email":         openapi3.NewStringSchema(),
-	"phone":         openapi3.NewStringSchema(),
-	"password":      openapi3.NewStringSchema(),
-	"roles":         openapi3.NewArraySchema().WithItems(openapi3.NewUUIDSchema()),
-	"type":          openapi3.NewStringSchema().WithEnum("standard", "collector", "business", "system"),
-	"addressId":     openapi3.NewUUIDSchema().WithNullable(),
-	"bankDetailsId": openapi3.NewUUIDSchema().WithNullable(),
+	"name":                 openapi3.NewStringSchema(),
+	"email":                openapi3.NewStringSchema(),
+	"phone":                openapi3.NewStringSchema(),
+	"password":             openapi3.NewStringSchema(),
+	"roles":                openapi3.NewArraySchema().WithItems(openapi3.NewUUIDSchema()),
+	"type":                 openapi3.NewStringSchema().WithEnum("standard", "collector", "business", "system"),
+	"addressId":            openapi3.NewUUIDSchema().WithNullable(),
+	"bankDetailsId":        openapi3.NewUUIDSchema().WithNullable(),
+	"identityDocumentType": openapi3.NewStringSchema().WithEnum("sa_id", "passport", "asylum_permit", "refugee_id").WithNullable(),
+	"identityNumber":       openapi3.NewStringSchema().WithNullable(),
 }
 
 var UpdateUserProperties = map[string]*openapi3.Schema{
@@ -38,7 +46,24 @@ var UpdateUserProperties = map[string]*openapi3.Schema{
 	"banned":   openapi3.NewBoolSchema().WithNullable(),
 	"banReason": openapi3.NewStringSchema().
 		WithNullable(),
-	"addressId":          openapi3.NewUUIDSchema().WithNullable(),
-	"bankDetailsId":      openapi3.NewUUIDSchema().WithNullable(),
-	"activeOrganization": openapi3.NewUUIDSchema().WithNullable(),
+	"addressId":            openapi3.NewUUIDSchema().WithNullable(),
+	"bankDetailsId":        openapi3.NewUUIDSchema().WithNullable(),
+	"activeOrganization":   openapi3.NewUUIDSchema().WithNullable(),
+	"identityDocumentType": openapi3.NewStringSchema().WithEnum("sa_id", "passport", "asylum_permit", "refugee_id").WithNullable(),
+	"identityNumber":       openapi3.NewStringSchema().WithNullable(),
+}
+
+var UserIdentityProperties = map[string]*openapi3.Schema{
+	"documentType": openapi3.NewStringSchema().WithEnum("sa_id", "passport", "asylum_permit", "refugee_id").WithNullable(),
+	"number":       openapi3.NewStringSchema().WithNullable(),
+	"dateOfBirth":  openapi3.NewDateTimeSchema().WithNullable(),
+	"status":       openapi3.NewStringSchema().WithEnum("unverified", "pending", "verified", "rejected"),
+	"reviewedAt":   openapi3.NewDateTimeSchema().WithNullable(),
+	"reviewedById": openapi3.NewUUIDSchema().WithNullable(),
+	"note":         openapi3.NewStringSchema().WithNullable(),
+}
+
+var ReviewUserIdentityProperties = map[string]*openapi3.Schema{
+	"status": openapi3.NewStringSchema().WithEnum("verified", "rejected"),
+	"note":   openapi3.NewStringSchema().WithNullable(),
 }
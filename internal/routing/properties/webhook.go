@@ -0,0 +1,79 @@
+package properties
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+var webhookEventTypes = []any{
+	"*",
+	"collection.created",
+	"collection.updated",
+	"collection.status_changed",
+	"collection.confirmed",
+	"transaction.created",
+	"transaction.updated",
+	"transaction.settled",
+	"user.created",
+}
+
+var WebhookEndpointProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"url":            openapi3.NewStringSchema().WithFormat("uri"),
+	"description":    openapi3.NewStringSchema().WithNullable(),
+	"events":         openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema().WithEnum(webhookEventTypes...)),
+	"active":         openapi3.NewBoolSchema(),
+	"secret":         openapi3.NewStringSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var WebhookEventDescriptionProperties = map[string]*openapi3.Schema{
+	"type":        openapi3.NewStringSchema().WithEnum(webhookEventTypes...),
+	"description": openapi3.NewStringSchema(),
+}
+
+var WebhookEventProperties = map[string]*openapi3.Schema{
+	"id":             openapi3.NewUUIDSchema(),
+	"organizationId": openapi3.NewUUIDSchema(),
+	"type":           openapi3.NewStringSchema().WithEnum(webhookEventTypes...),
+	"payload":        openapi3.NewObjectSchema(),
+	"createdAt":      openapi3.NewDateTimeSchema(),
+	"updatedAt":      openapi3.NewDateTimeSchema(),
+	"version":        openapi3.NewInt64Schema(),
+}
+
+var WebhookDeliveryProperties = map[string]*openapi3.Schema{
+	"id":          openapi3.NewUUIDSchema(),
+	"endpointId":  openapi3.NewUUIDSchema(),
+	"status":      openapi3.NewStringSchema().WithEnum("pending", "succeeded", "failed"),
+	"deliveredAt": openapi3.NewDateTimeSchema().WithNullable(),
+	"createdAt":   openapi3.NewDateTimeSchema(),
+	"updatedAt":   openapi3.NewDateTimeSchema(),
+	"version":     openapi3.NewInt64Schema(),
+}
+
+var WebhookDeliveryAttemptProperties = map[string]*openapi3.Schema{
+	"id":         openapi3.NewUUIDSchema(),
+	"deliveryId": openapi3.NewUUIDSchema(),
+	"statusCode": openapi3.NewIntegerSchema().WithNullable(),
+	"error":      openapi3.NewStringSchema().WithNullable(),
+	"duration":   openapi3.NewInt64Schema(),
+	"createdAt":  openapi3.NewDateTimeSchema(),
+	"updatedAt":  openapi3.NewDateTimeSchema(),
+	"version":    openapi3.NewInt64Schema(),
+}
+
+var CreateWebhookEndpointProperties = map[string]*openapi3.Schema{
+	"url":         openapi3.NewStringSchema().WithFormat("uri"),
+	"description": openapi3.NewStringSchema().WithNullable(),
+	"events":      openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema().WithEnum(webhookEventTypes...)),
+	"active":      openapi3.NewBoolSchema().WithNullable(),
+}
+
+var UpdateWebhookEndpointProperties = map[string]*openapi3.Schema{
+	"url":          openapi3.NewStringSchema().WithFormat("uri").WithNullable(),
+	"description":  openapi3.NewStringSchema().WithNullable(),
+	"events":       openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema().WithEnum(webhookEventTypes...)).WithNullable(),
+	"active":       openapi3.NewBoolSchema().WithNullable(),
+	"rotateSecret": openapi3.NewBoolSchema(),
+}
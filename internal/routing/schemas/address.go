@@ -17,6 +17,7 @@ var AddressSchema = openapi3.NewSchema().
 		"country",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var AddressesSchema = openapi3.NewArraySchema().WithItems(AddressSchema.Value).NewRef()
@@ -27,7 +28,6 @@ var CreateAddressSchema = openapi3.NewSchema().
 		"lineOne",
 		"city",
 		"zipCode",
-		"province",
 		"country",
 	}).NewRef()
 
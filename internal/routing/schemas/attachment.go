@@ -0,0 +1,32 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var AttachmentSchema = openapi3.NewSchema().
+	WithProperties(properties.AttachmentProperties).
+	WithRequired([]string{
+		"id",
+		"parentType",
+		"parentId",
+		"category",
+		"fileName",
+		"contentType",
+		"size",
+		"checksum",
+		"hasThumbnail",
+		"uploadedById",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var AttachmentsSchema = openapi3.NewArraySchema().WithItems(AttachmentSchema.Value).NewRef()
+
+var CreateAttachmentSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateAttachmentProperties).
+	WithRequired([]string{
+		"file",
+	}).NewRef()
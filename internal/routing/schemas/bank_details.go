@@ -11,10 +11,12 @@ var BankDetailSchema = openapi3.NewSchema().
 		"id",
 		"accountNumber",
 		"accountHolder",
+		"accountType",
 		"bankName",
-		"branchName",
+		"branchCode",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var BankDetailsSchema = openapi3.NewArraySchema().WithItems(BankDetailSchema.Value).NewRef()
@@ -25,9 +27,18 @@ var CreateBankDetailSchema = openapi3.NewSchema().
 		"accountNumber",
 		"accountHolder",
 		"bankName",
-		"branchName",
 	}).NewRef()
 
 var UpdateBankDetailSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateBankDetailsProperties).
 	NewRef()
+
+var BankSchema = openapi3.NewSchema().
+	WithProperties(properties.BankProperties).
+	WithRequired([]string{
+		"name",
+		"universalBranchCode",
+		"accountLengths",
+	}).NewRef()
+
+var BanksSchema = openapi3.NewArraySchema().WithItems(BankSchema.Value).NewRef()
@@ -0,0 +1,17 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var ChangeEventSchema = openapi3.NewSchema().
+	WithProperties(properties.ChangeEventProperties).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"resource",
+		"action",
+		"recordId",
+		"createdAt",
+	}).NewRef()
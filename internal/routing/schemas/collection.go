@@ -15,6 +15,7 @@ var CollectionMaterialSchema = openapi3.NewSchema().
 		"value",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var CollectionMaterialsArraySchema = openapi3.NewArraySchema().WithItems(CollectionMaterialSchema.Value).NewRef()
@@ -31,18 +32,40 @@ var UpdateCollectionMaterialSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateCollectionMaterialProperties).
 	NewRef()
 
+var CollectionTransitionSchema = openapi3.NewSchema().
+	WithProperties(properties.CollectionTransitionProperties).
+	WithProperty("performedBy", UserSchema.Value).
+	WithRequired([]string{
+		"id",
+		"collectionId",
+		"from",
+		"to",
+		"reason",
+		"performedBy",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var CollectionTransitionsArraySchema = openapi3.NewArraySchema().WithItems(CollectionTransitionSchema.Value).NewRef()
+
 var CollectionSchema = openapi3.NewSchema().
 	WithProperties(properties.CollectionProperties).
 	WithProperty("seller", UserSchema.Value).
 	WithProperty("buyer", OrganizationSchema.Value).
+	WithProperty("site", SiteSchema.Value.WithNullable()).
 	WithProperty("materials", CollectionMaterialsArraySchema.Value).
+	WithProperty("transitions", CollectionTransitionsArraySchema.Value).
 	WithRequired([]string{
 		"id",
 		"seller",
 		"buyer",
 		"materials",
+		"status",
+		"voidReason",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var CollectionsSchema = openapi3.NewArraySchema().WithItems(CollectionSchema.Value).NewRef()
@@ -59,3 +82,9 @@ var UpdateCollectionSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateCollectionProperties).
 	WithProperty("materials", openapi3.NewArraySchema().WithItems(UpdateCollectionMaterialSchema.Value)).
 	NewRef()
+
+var VoidCollectionSchema = openapi3.NewSchema().
+	WithProperties(properties.VoidCollectionProperties).
+	WithRequired([]string{
+		"reason",
+	}).NewRef()
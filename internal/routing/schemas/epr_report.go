@@ -0,0 +1,33 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var EprReportSchema = openapi3.NewSchema().
+	WithProperties(properties.EprReportProperties).
+	WithProperty("generatedBy", UserSchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"periodStart",
+		"periodEnd",
+		"cutOff",
+		"tonnage",
+		"carbonAvoided",
+		"pdfChecksum",
+		"xlsxChecksum",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var EprReportsSchema = openapi3.NewArraySchema().WithItems(EprReportSchema.Value).NewRef()
+
+var CreateEprReportSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateEprReportProperties).
+	WithRequired([]string{
+		"periodStart",
+		"periodEnd",
+	}).NewRef()
@@ -0,0 +1,60 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var ImportRowErrorSchema = openapi3.NewSchema().
+	WithProperties(properties.ImportRowErrorProperties).
+	WithRequired([]string{
+		"row",
+		"field",
+		"message",
+	}).NewRef()
+
+var ImportJobSchema = openapi3.NewSchema().
+	WithProperties(properties.ImportJobProperties).
+	WithProperty("errors", openapi3.NewArraySchema().WithItems(ImportRowErrorSchema.Value)).
+	WithProperty("createdBy", UserSchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"kind",
+		"status",
+		"fileName",
+		"columns",
+		"mapping",
+		"totalRows",
+		"processedRows",
+		"failedRows",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var ImportJobsSchema = openapi3.NewArraySchema().WithItems(ImportJobSchema.Value).NewRef()
+
+var ImportFieldSchema = openapi3.NewSchema().
+	WithProperties(properties.ImportFieldProperties).
+	WithRequired([]string{
+		"key",
+		"label",
+		"required",
+		"description",
+	}).NewRef()
+
+var ImportFieldsSchema = openapi3.NewArraySchema().WithItems(ImportFieldSchema.Value).NewRef()
+
+var CreateImportSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateImportProperties).
+	WithRequired([]string{
+		"kind",
+		"file",
+	}).NewRef()
+
+var UpdateImportMappingSchema = openapi3.NewSchema().
+	WithProperties(properties.UpdateImportMappingProperties).
+	WithRequired([]string{
+		"mapping",
+	}).NewRef()
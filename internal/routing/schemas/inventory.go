@@ -0,0 +1,46 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var InventoryEntrySchema = openapi3.NewSchema().
+	WithProperties(properties.InventoryEntryProperties).
+	WithProperty("material", MaterialSchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"material",
+		"type",
+		"weight",
+		"value",
+		"sourceType",
+		"occurredAt",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var InventoryEntriesSchema = openapi3.NewArraySchema().WithItems(InventoryEntrySchema.Value).NewRef()
+
+var InventoryBalanceSchema = openapi3.NewSchema().
+	WithProperties(properties.InventoryBalanceProperties).
+	WithRequired([]string{
+		"materialId",
+		"materialName",
+		"siteId",
+		"siteName",
+		"weight",
+		"value",
+	}).NewRef()
+
+var InventoryBalancesSchema = openapi3.NewArraySchema().WithItems(InventoryBalanceSchema.Value).NewRef()
+
+var CreateInventoryAdjustmentSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateInventoryAdjustmentProperties).
+	WithRequired([]string{
+		"materialId",
+		"weight",
+		"reason",
+	}).NewRef()
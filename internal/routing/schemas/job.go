@@ -0,0 +1,37 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var JobSchema = openapi3.NewSchema().
+	WithProperties(properties.JobProperties).
+	WithRequired([]string{
+		"id",
+		"kind",
+		"payload",
+		"status",
+		"runAt",
+		"attempts",
+		"maxAttempts",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var JobsSchema = openapi3.NewArraySchema().WithItems(JobSchema.Value).NewRef()
+
+var JobScheduleSchema = openapi3.NewSchema().
+	WithProperties(properties.JobScheduleProperties).
+	WithRequired([]string{
+		"name",
+		"kind",
+		"spec",
+		"payload",
+		"nextRunAt",
+		"createdAt",
+		"updatedAt",
+	}).NewRef()
+
+var JobSchedulesSchema = openapi3.NewArraySchema().WithItems(JobScheduleSchema.Value).NewRef()
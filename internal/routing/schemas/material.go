@@ -12,9 +12,11 @@ var MaterialSchema = openapi3.NewSchema().
 		"name",
 		"gwCode",
 		"carbonFactor",
+		"regulated",
 		"value",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var MaterialsSchema = openapi3.NewArraySchema().WithItems(MaterialSchema.Value).NewRef()
@@ -17,6 +17,7 @@ var OrganizationSchema = openapi3.NewSchema().
 		"bankDetails",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var OrganizationsSchema = openapi3.NewArraySchema().WithItems(OrganizationSchema.Value).NewRef()
@@ -0,0 +1,58 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var PayoutSchema = openapi3.NewSchema().
+	WithProperties(properties.PayoutProperties).
+	WithProperty("seller", UserSchema.Value).
+	WithProperty("collections", CollectionsSchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"seller",
+		"method",
+		"status",
+		"amount",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var PayoutsSchema = openapi3.NewArraySchema().WithItems(PayoutSchema.Value).NewRef()
+
+var PayoutBatchSchema = openapi3.NewSchema().
+	WithProperties(properties.PayoutBatchProperties).
+	WithProperty("payouts", PayoutsSchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"reference",
+		"status",
+		"total",
+		"payoutCount",
+		"createdById",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var PayoutBatchesSchema = openapi3.NewArraySchema().WithItems(PayoutBatchSchema.Value).NewRef()
+
+var CreatePayoutBatchSchema = openapi3.NewSchema().
+	WithProperties(properties.CreatePayoutBatchProperties).NewRef()
+
+var CreateCashPayoutSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateCashPayoutProperties).
+	WithRequired([]string{
+		"collectionIds",
+		"receiptNumber",
+	}).NewRef()
+
+var ReversePayoutSchema = openapi3.NewSchema().
+	WithProperties(properties.ReversePayoutProperties).
+	WithRequired([]string{
+		"reason",
+	}).NewRef()
@@ -0,0 +1,92 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var PickupMaterialSchema = openapi3.NewSchema().
+	WithProperties(properties.PickupMaterialProperties).
+	WithProperty("material", MaterialSchema.Value).
+	WithRequired([]string{
+		"id",
+		"material",
+		"expectedWeight",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var PickupMaterialsArraySchema = openapi3.NewArraySchema().WithItems(PickupMaterialSchema.Value).NewRef()
+
+var PickupSchema = openapi3.NewSchema().
+	WithProperties(properties.PickupProperties).
+	WithProperty("seller", UserSchema.Value).
+	WithProperty("address", AddressSchema.Value).
+	WithProperty("site", SiteSchema.Value).
+	WithProperty("collector", UserSchema.Value).
+	WithProperty("materials", PickupMaterialsArraySchema.Value).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"seller",
+		"status",
+		"requestedById",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var PickupsSchema = openapi3.NewArraySchema().WithItems(PickupSchema.Value).NewRef()
+
+var CreatePickupMaterialSchema = openapi3.NewSchema().
+	WithProperties(properties.CreatePickupMaterialProperties).
+	WithRequired([]string{
+		"materialId",
+		"expectedWeight",
+	}).NewRef()
+
+var CreatePickupSchema = openapi3.NewSchema().
+	WithProperties(properties.CreatePickupProperties).
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(CreatePickupMaterialSchema.Value)).
+	WithRequired([]string{
+		"materials",
+	}).NewRef()
+
+var AssignPickupSchema = openapi3.NewSchema().
+	WithProperties(properties.AssignPickupProperties).
+	WithRequired([]string{
+		"collectorId",
+		"scheduledFor",
+	}).NewRef()
+
+var CancelPickupSchema = openapi3.NewSchema().
+	WithProperties(properties.CancelPickupProperties).
+	WithRequired([]string{
+		"reason",
+	}).NewRef()
+
+var CompletePickupSchema = openapi3.NewSchema().
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(CreateCollectionMaterialSchema.Value)).
+	NewRef()
+
+var PickupRouteStopSchema = openapi3.NewSchema().
+	WithProperties(properties.PickupRouteStopProperties).
+	WithProperty("pickup", PickupSchema.Value).
+	WithRequired([]string{
+		"sequence",
+		"distance",
+		"pickup",
+	}).NewRef()
+
+var PickupRouteSchema = openapi3.NewSchema().
+	WithProperties(properties.PickupRouteProperties).
+	WithProperty("stops", openapi3.NewArraySchema().WithItems(PickupRouteStopSchema.Value)).
+	WithProperty("unrouted", PickupsSchema.Value).
+	WithRequired([]string{
+		"collectorId",
+		"date",
+		"distance",
+		"stops",
+		"unrouted",
+	}).NewRef()
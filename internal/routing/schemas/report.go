@@ -0,0 +1,41 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var VolumeTotalSchema = openapi3.NewSchema().
+	WithProperties(properties.VolumeTotalProperties).
+	WithRequired([]string{
+		"name",
+		"weight",
+		"value",
+		"previousWeight",
+		"previousValue",
+	}).NewRef()
+
+var VolumeBucketSchema = openapi3.NewSchema().
+	WithProperties(properties.VolumeBucketProperties).
+	WithRequired([]string{
+		"bucket",
+		"name",
+		"weight",
+		"value",
+	}).NewRef()
+
+var VolumeReportSchema = openapi3.NewSchema().
+	WithProperties(properties.VolumeReportProperties).
+	WithProperty("totals", openapi3.NewArraySchema().WithItems(VolumeTotalSchema.Value)).
+	WithProperty("series", openapi3.NewArraySchema().WithItems(VolumeBucketSchema.Value)).
+	WithRequired([]string{
+		"source",
+		"groupBy",
+		"period",
+		"from",
+		"to",
+		"previousFrom",
+		"previousTo",
+		"totals",
+		"series",
+	}).NewRef()
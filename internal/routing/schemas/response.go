@@ -14,9 +14,24 @@ var SuccessResponseSchema = openapi3.NewSchema().WithProperties(map[string]*open
 		OrganizationsSchema.Value,
 		AddressesSchema.Value,
 		BankDetailsSchema.Value,
+		BanksSchema.Value,
 		MaterialsSchema.Value,
 		CollectionsSchema.Value,
 		TransactionsSchema.Value,
+		SitesSchema.Value,
+		InventoryBalancesSchema.Value,
+		InventoryEntriesSchema.Value,
+		PayoutBatchesSchema.Value,
+		PayoutsSchema.Value,
+		PickupsSchema.Value,
+		EprReportsSchema.Value,
+		ImportJobsSchema.Value,
+		ImportFieldsSchema.Value,
+		JobsSchema.Value,
+		JobSchedulesSchema.Value,
+		WebhookEndpointsSchema.Value,
+		WebhookEventDescriptionsSchema.Value,
+		WebhookDeliveriesSchema.Value,
 		AvailablePermissionsSchema.Value,
 	),
 	"item": openapi3.NewAnyOfSchema(
@@ -28,6 +43,18 @@ var SuccessResponseSchema = openapi3.NewSchema().WithProperties(map[string]*open
 		MaterialSchema.Value,
 		CollectionSchema.Value,
 		TransactionSchema.Value,
+		InvoiceSchema.Value,
+		SiteSchema.Value,
+		PayoutBatchSchema.Value,
+		PickupSchema.Value,
+		PickupRouteSchema.Value,
+		SellerEarningsSchema.Value,
+		SellerImpactSchema.Value,
+		VolumeReportSchema.Value,
+		EprReportSchema.Value,
+		ImportJobSchema.Value,
+		JobSchema.Value,
+		WebhookEndpointSchema.Value,
 	),
 	"pageDetails": openapi3.NewObjectSchema().WithProperties(map[string]*openapi3.Schema{
 		"count":        openapi3.NewIntegerSchema().WithMin(0),
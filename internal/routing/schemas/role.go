@@ -14,6 +14,7 @@ var RoleSchema = openapi3.NewSchema().
 		"permissions",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var RolesSchema = openapi3.NewArraySchema().WithItems(RoleSchema.Value).NewRef()
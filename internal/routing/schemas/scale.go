@@ -0,0 +1,53 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var ScaleSchema = openapi3.NewSchema().
+	WithProperties(properties.ScaleProperties).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"siteId",
+		"name",
+		"active",
+		"keyPrefix",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var ScalesSchema = openapi3.NewArraySchema().WithItems(ScaleSchema.Value).NewRef()
+
+var CreateScaleSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateScaleProperties).
+	WithRequired([]string{
+		"siteId",
+		"name",
+	}).NewRef()
+
+var UpdateScaleSchema = openapi3.NewSchema().
+	WithProperties(properties.UpdateScaleProperties).NewRef()
+
+var ScaleReadingSchema = openapi3.NewSchema().
+	WithProperties(properties.ScaleReadingProperties).
+	WithRequired([]string{
+		"id",
+		"scaleId",
+		"gross",
+		"tare",
+		"net",
+		"stable",
+		"capturedAt",
+		"checksum",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var ScaleReadingsSchema = openapi3.NewArraySchema().WithItems(ScaleReadingSchema.Value).NewRef()
+
+var RecordScaleReadingSchema = openapi3.NewSchema().
+	WithProperties(properties.RecordScaleReadingProperties).NewRef()
@@ -0,0 +1,51 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var MaterialTotalSchema = openapi3.NewSchema().
+	WithProperties(properties.MaterialTotalProperties).
+	WithRequired([]string{
+		"materialId",
+		"materialName",
+		"weight",
+		"value",
+		"carbonAvoided",
+	}).NewRef()
+
+var PeriodTotalSchema = openapi3.NewSchema().
+	WithProperties(properties.PeriodTotalProperties).
+	WithRequired([]string{
+		"period",
+		"weight",
+		"value",
+		"carbonAvoided",
+	}).NewRef()
+
+var SellerEarningsSchema = openapi3.NewSchema().
+	WithProperties(properties.SellerEarningsProperties).
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(MaterialTotalSchema.Value)).
+	WithProperty("periods", openapi3.NewArraySchema().WithItems(PeriodTotalSchema.Value)).
+	WithRequired([]string{
+		"period",
+		"total",
+		"paid",
+		"outstanding",
+		"materials",
+		"periods",
+	}).NewRef()
+
+var SellerImpactSchema = openapi3.NewSchema().
+	WithProperties(properties.SellerImpactProperties).
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(MaterialTotalSchema.Value)).
+	WithProperty("periods", openapi3.NewArraySchema().WithItems(PeriodTotalSchema.Value)).
+	WithRequired([]string{
+		"period",
+		"collections",
+		"weight",
+		"carbonAvoided",
+		"materials",
+		"periods",
+	}).NewRef()
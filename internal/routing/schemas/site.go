@@ -0,0 +1,52 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var SiteOperatingHourSchema = openapi3.NewSchema().
+	WithProperties(properties.SiteOperatingHourProperties).
+	WithRequired([]string{
+		"dayOfWeek",
+		"opens",
+		"closes",
+	}).NewRef()
+
+var SiteOperatingHoursArraySchema = openapi3.NewArraySchema().WithItems(SiteOperatingHourSchema.Value).NewRef()
+
+var SiteSchema = openapi3.NewSchema().
+	WithProperties(properties.SiteProperties).
+	WithProperty("address", AddressSchema.Value.WithNullable()).
+	WithProperty("operatingHours", SiteOperatingHoursArraySchema.Value).
+	WithProperty("users", openapi3.NewArraySchema().WithItems(UserSchema.Value)).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"name",
+		"address",
+		"latitude",
+		"longitude",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var SitesSchema = openapi3.NewArraySchema().WithItems(SiteSchema.Value).NewRef()
+
+var CreateSiteSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateSiteProperties).
+	WithProperty("address", CreateAddressSchema.Value).
+	WithProperty("operatingHours", SiteOperatingHoursArraySchema.Value).
+	WithProperty("users", openapi3.NewArraySchema().WithItems(UserSchema.Value)).
+	WithRequired([]string{
+		"name",
+	}).
+	NewRef()
+
+var UpdateSiteSchema = openapi3.NewSchema().
+	WithProperties(properties.UpdateSiteProperties).
+	WithProperty("address", CreateAddressSchema.Value).
+	WithProperty("operatingHours", SiteOperatingHoursArraySchema.Value).
+	WithProperty("users", openapi3.NewArraySchema().WithItems(UserSchema.Value)).
+	NewRef()
@@ -0,0 +1,55 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var SyncCollectionSchema = openapi3.NewSchema().
+	WithProperties(properties.SyncCollectionProperties).
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(CreateCollectionMaterialSchema.Value)).
+	WithRequired([]string{
+		"id",
+		"createdAt",
+		"sellerId",
+		"buyerId",
+	}).NewRef()
+
+var SyncCollectionsSchema = openapi3.NewSchema().
+	WithProperty("collections", openapi3.NewArraySchema().WithItems(SyncCollectionSchema.Value).WithMaxItems(100)).
+	WithRequired([]string{
+		"collections",
+	}).NewRef()
+
+var SyncCollectionResultSchema = openapi3.NewSchema().
+	WithProperties(properties.SyncCollectionResultProperties).
+	WithProperty("collection", CollectionSchema.Value).
+	WithRequired([]string{
+		"id",
+		"status",
+		"message",
+	}).NewRef()
+
+var SyncCollectionResultsSchema = openapi3.NewArraySchema().WithItems(SyncCollectionResultSchema.Value).NewRef()
+
+var SyncSellerSchema = openapi3.NewSchema().
+	WithProperties(properties.SyncSellerProperties).
+	WithRequired([]string{
+		"id",
+		"name",
+		"identityNumber",
+		"identityStatus",
+		"version",
+	}).NewRef()
+
+var SyncChangesSchema = openapi3.NewSchema().
+	WithProperties(properties.SyncChangesProperties).
+	WithProperty("materials", MaterialsSchema.Value).
+	WithProperty("sellers", openapi3.NewArraySchema().WithItems(SyncSellerSchema.Value)).
+	WithRequired([]string{
+		"cursor",
+		"materials",
+		"sellers",
+		"deletedMaterials",
+		"deletedSellers",
+	}).NewRef()
@@ -15,6 +15,7 @@ var TransactionMaterialSchema = openapi3.NewSchema().
 		"value",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var TransactionMaterialsArraySchema = openapi3.NewArraySchema().WithItems(TransactionMaterialSchema.Value).NewRef()
@@ -30,18 +31,60 @@ var CreateTransactionMaterialSchema = openapi3.NewSchema().
 var UpdateTransactionMaterialSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateTransactionMaterialProperties).NewRef()
 
+var TransactionTransitionSchema = openapi3.NewSchema().
+	WithProperties(properties.TransactionTransitionProperties).
+	WithProperty("performedBy", UserSchema.Value).
+	WithRequired([]string{
+		"id",
+		"transactionId",
+		"from",
+		"to",
+		"reason",
+		"organizationId",
+		"performedBy",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var TransactionTransitionsArraySchema = openapi3.NewArraySchema().WithItems(TransactionTransitionSchema.Value).NewRef()
+
+var InvoiceSchema = openapi3.NewSchema().
+	WithProperties(properties.InvoiceProperties).
+	WithRequired([]string{
+		"id",
+		"transactionId",
+		"organizationId",
+		"number",
+		"reference",
+		"total",
+		"issuedAt",
+		"settledAmount",
+		"settlementStatus",
+		"settledAt",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
 var TransactionSchema = openapi3.NewSchema().
 	WithProperties(properties.TransactionProperties).
 	WithProperty("seller", OrganizationSchema.Value).
 	WithProperty("buyer", OrganizationSchema.Value).
+	WithProperty("site", SiteSchema.Value.WithNullable()).
 	WithProperty("materials", TransactionMaterialsArraySchema.Value).
+	WithProperty("invoice", InvoiceSchema.Value.WithNullable()).
+	WithProperty("transitions", TransactionTransitionsArraySchema.Value).
 	WithRequired([]string{
 		"id",
 		"seller",
 		"buyer",
 		"materials",
+		"status",
+		"quoteExpiresAt",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var TransactionsSchema = openapi3.NewArraySchema().WithItems(TransactionSchema.Value).NewRef()
@@ -58,3 +101,27 @@ var UpdateTransactionSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateTransactionProperties).
 	WithProperty("materials", openapi3.NewArraySchema().WithItems(UpdateTransactionMaterialSchema.Value)).
 	NewRef()
+
+var TransactionTransitionPayloadSchema = openapi3.NewSchema().
+	WithProperties(properties.TransactionTransitionPayloadProperties).
+	NewRef()
+
+var DeliverTransactionSchema = openapi3.NewSchema().
+	WithProperty("materials", openapi3.NewArraySchema().WithItems(
+		openapi3.NewSchema().
+			WithProperties(properties.DeliverTransactionMaterialProperties).
+			WithRequired([]string{
+				"id",
+				"receivedWeight",
+			}),
+	)).
+	WithProperty("overrideStock", openapi3.NewBoolSchema()).
+	WithRequired([]string{
+		"materials",
+	}).NewRef()
+
+var SettleInvoiceSchema = openapi3.NewSchema().
+	WithProperties(properties.SettleInvoiceProperties).
+	WithRequired([]string{
+		"amount",
+	}).NewRef()
@@ -0,0 +1,17 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var TrashItemSchema = openapi3.NewSchema().
+	WithProperties(properties.TrashItemProperties).
+	WithRequired([]string{
+		"resource",
+		"id",
+		"label",
+		"deletedAt",
+	}).NewRef()
+
+var TrashItemsSchema = openapi3.NewArraySchema().WithItems(TrashItemSchema.Value).NewRef()
@@ -22,10 +22,14 @@ var UserSchema = openapi3.NewSchema().
 		"banReason",
 		"activeOrganization",
 		"type",
+		"identityDocumentType",
+		"identityNumber",
+		"identityStatus",
 		"address",
 		"bankDetails",
 		"createdAt",
 		"updatedAt",
+		"version",
 	}).NewRef()
 
 var UsersSchema = openapi3.NewArraySchema().WithItems(UserSchema.Value).NewRef()
@@ -46,3 +50,21 @@ var UpdateUserSchema = openapi3.NewSchema().
 	WithProperties(properties.UpdateUserProperties).
 	WithProperty("roles", openapi3.NewArraySchema().WithItems(RoleSchema.Value)).
 	NewRef()
+
+var UserIdentitySchema = openapi3.NewSchema().
+	WithProperties(properties.UserIdentityProperties).
+	WithRequired([]string{
+		"documentType",
+		"number",
+		"dateOfBirth",
+		"status",
+		"reviewedAt",
+		"reviewedById",
+		"note",
+	}).NewRef()
+
+var ReviewUserIdentitySchema = openapi3.NewSchema().
+	WithProperties(properties.ReviewUserIdentityProperties).
+	WithRequired([]string{
+		"status",
+	}).NewRef()
@@ -0,0 +1,80 @@
+package schemas
+
+import (
+	"github.com/connor-davis/threereco-nextgen/internal/routing/properties"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var WebhookEndpointSchema = openapi3.NewSchema().
+	WithProperties(properties.WebhookEndpointProperties).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"url",
+		"events",
+		"active",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var WebhookEndpointsSchema = openapi3.NewArraySchema().WithItems(WebhookEndpointSchema.Value).NewRef()
+
+var WebhookEventDescriptionSchema = openapi3.NewSchema().
+	WithProperties(properties.WebhookEventDescriptionProperties).
+	WithRequired([]string{
+		"type",
+		"description",
+	}).NewRef()
+
+var WebhookEventDescriptionsSchema = openapi3.NewArraySchema().WithItems(WebhookEventDescriptionSchema.Value).NewRef()
+
+var WebhookEventSchema = openapi3.NewSchema().
+	WithProperties(properties.WebhookEventProperties).
+	WithRequired([]string{
+		"id",
+		"organizationId",
+		"type",
+		"payload",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var WebhookDeliveryAttemptSchema = openapi3.NewSchema().
+	WithProperties(properties.WebhookDeliveryAttemptProperties).
+	WithRequired([]string{
+		"id",
+		"deliveryId",
+		"duration",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var WebhookDeliverySchema = openapi3.NewSchema().
+	WithProperties(properties.WebhookDeliveryProperties).
+	WithProperty("event", WebhookEventSchema.Value).
+	WithProperty("attempts", openapi3.NewArraySchema().WithItems(WebhookDeliveryAttemptSchema.Value)).
+	WithRequired([]string{
+		"id",
+		"endpointId",
+		"status",
+		"event",
+		"attempts",
+		"createdAt",
+		"updatedAt",
+		"version",
+	}).NewRef()
+
+var WebhookDeliveriesSchema = openapi3.NewArraySchema().WithItems(WebhookDeliverySchema.Value).NewRef()
+
+var CreateWebhookEndpointSchema = openapi3.NewSchema().
+	WithProperties(properties.CreateWebhookEndpointProperties).
+	WithRequired([]string{
+		"url",
+		"events",
+	}).NewRef()
+
+var UpdateWebhookEndpointSchema = openapi3.NewSchema().
+	WithProperties(properties.UpdateWebhookEndpointProperties).NewRef()
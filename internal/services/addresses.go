@@ -4,47 +4,71 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type addressesService interface {
-	Create(payload models.CreateAddressPayload) (uuid.UUID, error)
-	Update(addressId uuid.UUID, payload models.UpdateAddressPayload) error
-	Delete(addressId uuid.UUID) error
+	Create(owner models.Owner, payload models.CreateAddressPayload) (uuid.UUID, error)
+	Update(addressId uuid.UUID, version int64, payload models.UpdateAddressPayload) error
+	Delete(addressId uuid.UUID, version int64) error
 	Find(addressId uuid.UUID) (*models.Address, error)
+	FindByOwner(owner models.Owner) (*models.Address, error)
+	Owner(addressId uuid.UUID) (*models.Owner, error)
 	List(clauses ...clause.Expression) ([]models.Address, error)
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
 type addresses struct {
-	storage storage.Storage
+	storage  storage.Storage
+	geocoder Geocoder
 }
 
-func newAddressesService(storage storage.Storage) addressesService {
+func newAddressesService(storage storage.Storage, geocoder Geocoder) addressesService {
 	return &addresses{
-		storage: storage,
+		storage:  storage,
+		geocoder: geocoder,
 	}
 }
 
-func (s *addresses) Create(payload models.CreateAddressPayload) (uuid.UUID, error) {
-	var address models.Address
+// Create creates the address of a user or organization. An owner can only
+// have one address.
+func (s *addresses) Create(owner models.Owner, payload models.CreateAddressPayload) (uuid.UUID, error) {
+	address := newAddress(payload)
 
-	address.LineOne = payload.LineOne
-	address.LineTwo = payload.LineTwo
-	address.City = payload.City
-	address.ZipCode = payload.ZipCode
-	address.Province = payload.Province
-	address.Country = payload.Country
+	if err := prepareAddress(s.geocoder, &address); err != nil {
+		return uuid.Nil, err
+	}
 
-	if err := s.storage.Postgres.
-		Create(&address).Error; err != nil {
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		existingId, err := lockOwnerReference(tx, owner, "address_id")
+
+		if err != nil {
+			return err
+		}
+
+		if existingId != nil {
+			return ErrAddressExists
+		}
+
+		if err := tx.Create(&address).Error; err != nil {
+			return err
+		}
+
+		return tx.
+			Table(owner.Table()).
+			Where("id = ?", owner.Id).
+			Update("address_id", address.Id).Error
+	})
+
+	if err != nil {
 		return uuid.Nil, err
 	}
 
 	return address.Id, nil
 }
 
-func (s *addresses) Update(addressId uuid.UUID, payload models.UpdateAddressPayload) error {
+func (s *addresses) Update(addressId uuid.UUID, version int64, payload models.UpdateAddressPayload) error {
 	var address models.Address
 
 	if err := s.storage.Postgres.
@@ -77,31 +101,32 @@ func (s *addresses) Update(addressId uuid.UUID, payload models.UpdateAddressPayl
 		address.Country = *payload.Country
 	}
 
-	if err := s.storage.Postgres.
-		Model(&models.Address{}).
-		Where("id = ?", addressId).
-		Updates(&map[string]any{
-			"line_one": address.LineOne,
-			"line_two": address.LineTwo,
-			"city":     address.City,
-			"zip_code": address.ZipCode,
-			"province": address.Province,
-			"country":  address.Country,
-		}).Error; err != nil {
+	if err := prepareAddress(s.geocoder, &address); err != nil {
 		return err
 	}
 
-	return nil
+	columns := addressColumns(address)
+
+	result := s.storage.Postgres.
+		Model(&models.Address{}).
+		Where("id = ? AND version = ?", addressId, version).
+		Updates(&columns)
+
+	return checkVersion(s.storage.Postgres, &models.Address{}, addressId, result)
 }
 
-func (s *addresses) Delete(addressId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", addressId).
-		Delete(&models.Address{}).Error; err != nil {
-		return err
-	}
+func (s *addresses) Delete(addressId uuid.UUID, version int64) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("id = ? AND version = ?", addressId, version).
+			Delete(&models.Address{})
+
+		if err := checkVersion(tx, &models.Address{}, addressId, result); err != nil {
+			return err
+		}
 
-	return nil
+		return clearOwnerReferences(tx, "address_id", addressId)
+	})
 }
 
 func (s *addresses) Find(addressId uuid.UUID) (*models.Address, error) {
@@ -116,6 +141,22 @@ func (s *addresses) Find(addressId uuid.UUID) (*models.Address, error) {
 	return address, nil
 }
 
+func (s *addresses) FindByOwner(owner models.Owner) (*models.Address, error) {
+	addressId, err := findOwnerReference(s.storage.Postgres, owner, "address_id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Find(addressId)
+}
+
+// Owner returns the user or organization the address belongs to, or nil when
+// it has no owner.
+func (s *addresses) Owner(addressId uuid.UUID) (*models.Owner, error) {
+	return findOwner(s.storage.Postgres, "address_id", addressId)
+}
+
 func (s *addresses) List(clauses ...clause.Expression) ([]models.Address, error) {
 	var addresses []models.Address
 
@@ -139,3 +180,14 @@ func (s *addresses) Count(clauses ...clause.Expression) (int64, error) {
 
 	return count, nil
 }
+
+func newAddress(payload models.CreateAddressPayload) models.Address {
+	return models.Address{
+		LineOne:  payload.LineOne,
+		LineTwo:  payload.LineTwo,
+		City:     payload.City,
+		ZipCode:  payload.ZipCode,
+		Province: payload.Province,
+		Country:  payload.Country,
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type attachmentsService interface {
+	Parties(parentType models.AttachmentParent, parentId uuid.UUID) ([]models.Owner, error)
+	Create(parentType models.AttachmentParent, parentId uuid.UUID, uploadedById uuid.UUID, payload models.CreateAttachmentPayload, fileName string, content []byte) (uuid.UUID, error)
+	Open(attachment models.Attachment, thumbnail bool) (io.ReadCloser, error)
+	Delete(attachmentId uuid.UUID, version int64) error
+	Find(attachmentId uuid.UUID) (*models.Attachment, error)
+	List(clauses ...clause.Expression) ([]models.Attachment, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type attachments struct {
+	storage storage.Storage
+}
+
+func newAttachmentsService(storage storage.Storage) attachmentsService {
+	return &attachments{
+		storage: storage,
+	}
+}
+
+// AttachmentMaxSize is the largest file that can be attached, in bytes.
+const AttachmentMaxSize = 10 * 1024 * 1024
+
+// attachmentContentTypes are the types of file that can be attached, as
+// detected from their content.
+var attachmentContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+}
+
+var attachmentCategories = []models.AttachmentCategory{
+	models.PhotoAttachment,
+	models.DeliveryNoteAttachment,
+	models.IdDocumentAttachment,
+	models.OtherAttachment,
+}
+
+// Parties returns the users and organizations whose records the parent of
+// attachments is, so that access to its attachments follows access to it. A
+// collection belongs to its buyer and its seller, a transaction to both of its
+// organizations, and users and organizations to themselves. Users come with
+// the organizations they are a member of.
+func (s *attachments) Parties(parentType models.AttachmentParent, parentId uuid.UUID) ([]models.Owner, error) {
+	switch parentType {
+	case models.CollectionAttachment:
+		var collection models.Collection
+
+		if err := s.storage.Postgres.
+			Select("id", "seller_id", "buyer_id").
+			Where("id = ?", parentId).
+			First(&collection).Error; err != nil {
+			return nil, err
+		}
+
+		seller := models.Owner{Type: models.UserOwner, Id: collection.SellerId}
+
+		if err := addOwnerOrganizations(s.storage.Postgres, &seller); err != nil {
+			return nil, err
+		}
+
+		return []models.Owner{
+			{Type: models.OrganizationOwner, Id: collection.BuyerId},
+			seller,
+		}, nil
+	case models.TransactionAttachment:
+		var transaction models.Transaction
+
+		if err := s.storage.Postgres.
+			Select("id", "seller_id", "buyer_id").
+			Where("id = ?", parentId).
+			First(&transaction).Error; err != nil {
+			return nil, err
+		}
+
+		return []models.Owner{
+			{Type: models.OrganizationOwner, Id: transaction.SellerId},
+			{Type: models.OrganizationOwner, Id: transaction.BuyerId},
+		}, nil
+	case models.UserAttachment:
+		owner, err := userOwner(s.storage.Postgres, parentId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []models.Owner{*owner}, nil
+	case models.OrganizationAttachment:
+		owner := models.Owner{Type: models.OrganizationOwner, Id: parentId}
+
+		var count int64
+
+		if err := s.storage.Postgres.
+			Table(owner.Table()).
+			Where("id = ?", parentId).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+
+		if count == 0 {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		return []models.Owner{owner}, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Create stores a file attached to a record with its checksum and, for images,
+// a thumbnail. The type of the file is detected from its content rather than
+// trusted from the upload.
+func (s *attachments) Create(parentType models.AttachmentParent, parentId uuid.UUID, uploadedById uuid.UUID, payload models.CreateAttachmentPayload, fileName string, content []byte) (uuid.UUID, error) {
+	if s.storage.Files == nil {
+		return uuid.Nil, ErrAttachmentsUnavailable
+	}
+
+	if len(content) == 0 || len(content) > AttachmentMaxSize {
+		return uuid.Nil, ErrInvalidAttachmentSize
+	}
+
+	contentType := http.DetectContentType(content)
+
+	if !slices.Contains(attachmentContentTypes, contentType) {
+		return uuid.Nil, ErrUnsupportedAttachmentType
+	}
+
+	if payload.Category == "" {
+		payload.Category = models.OtherAttachment
+	}
+
+	if !slices.Contains(attachmentCategories, payload.Category) {
+		return uuid.Nil, ErrInvalidAttachmentCategory
+	}
+
+	checksum := sha256.Sum256(content)
+
+	attachment := models.Attachment{
+		Base: models.Base{
+			Id: uuid.New(),
+		},
+		ParentType:   parentType,
+		ParentId:     parentId,
+		Category:     payload.Category,
+		Description:  payload.Description,
+		FileName:     attachmentFileName(fileName),
+		ContentType:  contentType,
+		Size:         int64(len(content)),
+		Checksum:     hex.EncodeToString(checksum[:]),
+		UploadedById: uploadedById,
+	}
+
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s/%s", parentType, parentId, attachment.Id)
+
+	if err := s.storage.Files.Put(attachment.StorageKey, content, contentType); err != nil {
+		return uuid.Nil, err
+	}
+
+	if thumbnail, ok := makeThumbnail(content); ok {
+		if err := s.storage.Files.Put(thumbnailKey(attachment), thumbnail, "image/jpeg"); err != nil {
+			log.Errorf("🔥 Failed to store thumbnail of attachment %s: %s", attachment.Id, err.Error())
+		} else {
+			attachment.HasThumbnail = true
+		}
+	}
+
+	if err := s.storage.Postgres.
+		Create(&attachment).Error; err != nil {
+		deleteAttachmentFiles(s.storage.Files, attachment)
+
+		return uuid.Nil, err
+	}
+
+	return attachment.Id, nil
+}
+
+// Open returns the content of an attachment or of its thumbnail.
+func (s *attachments) Open(attachment models.Attachment, thumbnail bool) (io.ReadCloser, error) {
+	if s.storage.Files == nil {
+		return nil, ErrAttachmentsUnavailable
+	}
+
+	key := attachment.StorageKey
+
+	if thumbnail {
+		if !attachment.HasThumbnail {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		key = thumbnailKey(attachment)
+	}
+
+	content, err := s.storage.Files.Get(key)
+
+	if err == storage.ErrFileNotFound {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return content, err
+}
+
+// Delete moves an attachment to the trash. Its files are kept until it is
+// purged.
+func (s *attachments) Delete(attachmentId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", attachmentId, version).
+		Delete(&models.Attachment{})
+
+	return checkVersion(s.storage.Postgres, &models.Attachment{}, attachmentId, result)
+}
+
+func (s *attachments) Find(attachmentId uuid.UUID) (*models.Attachment, error) {
+	var attachment *models.Attachment
+
+	if err := s.storage.Postgres.
+		Where("id = ?", attachmentId).
+		First(&attachment).Error; err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *attachments) List(clauses ...clause.Expression) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+
+	if err := s.storage.Postgres.
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (s *attachments) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Attachment{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// deleteAttachmentFiles deletes the files of an attachment that was purged or
+// could not be recorded. Files that could not be deleted are logged rather
+// than failing, as they can no longer be reached.
+func deleteAttachmentFiles(files storage.FileStore, attachment models.Attachment) {
+	if files == nil {
+		return
+	}
+
+	keys := []string{attachment.StorageKey}
+
+	if attachment.HasThumbnail {
+		keys = append(keys, thumbnailKey(attachment))
+	}
+
+	for _, key := range keys {
+		if err := files.Delete(key); err != nil {
+			log.Errorf("🔥 Failed to delete file %s of attachment %s: %s", key, attachment.Id, err.Error())
+		}
+	}
+}
+
+func thumbnailKey(attachment models.Attachment) string {
+	return attachment.StorageKey + "-thumbnail.jpg"
+}
+
+// attachmentFileName keeps only the name of an uploaded file, without any
+// directories or characters that would break a Content-Disposition header.
+func attachmentFileName(fileName string) string {
+	fileName = filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+
+	fileName = strings.Map(func(r rune) rune {
+		if r < ' ' || r == '"' || r == 0x7f {
+			return -1
+		}
+
+		return r
+	}, fileName)
+
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "attachment"
+	}
+
+	return fileName
+}
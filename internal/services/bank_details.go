@@ -4,45 +4,77 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type bankDetailsService interface {
-	Create(payload models.CreateBankDetailsPayload) (uuid.UUID, error)
-	Update(bankDetailsId uuid.UUID, payload models.UpdateBankDetailsPayload) error
-	Delete(bankDetailsId uuid.UUID) error
+	Create(owner models.Owner, payload models.CreateBankDetailsPayload) (uuid.UUID, error)
+	Update(bankDetailsId uuid.UUID, version int64, payload models.UpdateBankDetailsPayload) error
+	Delete(bankDetailsId uuid.UUID, version int64) error
 	Find(bankDetailsId uuid.UUID) (*models.BankDetails, error)
+	FindByOwner(owner models.Owner) (*models.BankDetails, error)
+	Owner(bankDetailsId uuid.UUID) (*models.Owner, error)
 	List(clauses ...clause.Expression) ([]models.BankDetails, error)
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
 type bankDetails struct {
-	storage storage.Storage
+	storage  storage.Storage
+	verifier AccountVerifier
 }
 
-func newBankDetailsService(storage storage.Storage) bankDetailsService {
+func newBankDetailsService(storage storage.Storage, verifier AccountVerifier) bankDetailsService {
 	return &bankDetails{
-		storage: storage,
+		storage:  storage,
+		verifier: verifier,
 	}
 }
 
-func (s *bankDetails) Create(payload models.CreateBankDetailsPayload) (uuid.UUID, error) {
+// Create creates the bank details of a user or organization. An owner can only
+// have one set of bank details.
+func (s *bankDetails) Create(owner models.Owner, payload models.CreateBankDetailsPayload) (uuid.UUID, error) {
 	var bankDetails models.BankDetails
 
 	bankDetails.AccountHolder = payload.AccountHolder
 	bankDetails.AccountNumber = payload.AccountNumber
+	bankDetails.AccountType = payload.AccountType
 	bankDetails.BankName = payload.BankName
 	bankDetails.BranchCode = payload.BranchCode
 
-	if err := s.storage.Postgres.
-		Create(&bankDetails).Error; err != nil {
+	if err := s.validate(&bankDetails); err != nil {
+		return uuid.Nil, err
+	}
+
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		existingId, err := lockOwnerReference(tx, owner, "bank_details_id")
+
+		if err != nil {
+			return err
+		}
+
+		if existingId != nil {
+			return ErrBankDetailsExist
+		}
+
+		if err := tx.Create(&bankDetails).Error; err != nil {
+			return err
+		}
+
+		return tx.
+			Table(owner.Table()).
+			Where("id = ?", owner.Id).
+			Update("bank_details_id", bankDetails.Id).Error
+	})
+
+	if err != nil {
 		return uuid.Nil, err
 	}
 
 	return bankDetails.Id, nil
 }
 
-func (s *bankDetails) Update(bankDetailsId uuid.UUID, payload models.UpdateBankDetailsPayload) error {
+func (s *bankDetails) Update(bankDetailsId uuid.UUID, version int64, payload models.UpdateBankDetailsPayload) error {
 	var bankDetails models.BankDetails
 
 	if err := s.storage.Postgres.
@@ -59,6 +91,10 @@ func (s *bankDetails) Update(bankDetailsId uuid.UUID, payload models.UpdateBankD
 		bankDetails.AccountNumber = *payload.AccountNumber
 	}
 
+	if payload.AccountType != nil {
+		bankDetails.AccountType = *payload.AccountType
+	}
+
 	if payload.BankName != nil {
 		bankDetails.BankName = *payload.BankName
 	}
@@ -67,29 +103,36 @@ func (s *bankDetails) Update(bankDetailsId uuid.UUID, payload models.UpdateBankD
 		bankDetails.BranchCode = *payload.BranchCode
 	}
 
-	if err := s.storage.Postgres.
+	if err := s.validate(&bankDetails); err != nil {
+		return err
+	}
+
+	result := s.storage.Postgres.
 		Model(&models.BankDetails{}).
-		Where("id = ?", bankDetailsId).
+		Where("id = ? AND version = ?", bankDetailsId, version).
 		Updates(&map[string]any{
 			"account_holder": bankDetails.AccountHolder,
 			"account_number": bankDetails.AccountNumber,
+			"account_type":   bankDetails.AccountType,
 			"bank_name":      bankDetails.BankName,
 			"branch_code":    bankDetails.BranchCode,
-		}).Error; err != nil {
-		return err
-	}
+		})
 
-	return nil
+	return checkVersion(s.storage.Postgres, &models.BankDetails{}, bankDetailsId, result)
 }
 
-func (s *bankDetails) Delete(bankDetailsId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", bankDetailsId).
-		Delete(&models.BankDetails{}).Error; err != nil {
-		return err
-	}
+func (s *bankDetails) Delete(bankDetailsId uuid.UUID, version int64) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("id = ? AND version = ?", bankDetailsId, version).
+			Delete(&models.BankDetails{})
 
-	return nil
+		if err := checkVersion(tx, &models.BankDetails{}, bankDetailsId, result); err != nil {
+			return err
+		}
+
+		return clearOwnerReferences(tx, "bank_details_id", bankDetailsId)
+	})
 }
 
 func (s *bankDetails) Find(bankDetailsId uuid.UUID) (*models.BankDetails, error) {
@@ -104,6 +147,22 @@ func (s *bankDetails) Find(bankDetailsId uuid.UUID) (*models.BankDetails, error)
 	return bankDetails, nil
 }
 
+func (s *bankDetails) FindByOwner(owner models.Owner) (*models.BankDetails, error) {
+	bankDetailsId, err := findOwnerReference(s.storage.Postgres, owner, "bank_details_id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Find(bankDetailsId)
+}
+
+// Owner returns the user or organization the bank details belong to, or nil
+// when they have no owner.
+func (s *bankDetails) Owner(bankDetailsId uuid.UUID) (*models.Owner, error) {
+	return findOwner(s.storage.Postgres, "bank_details_id", bankDetailsId)
+}
+
 func (s *bankDetails) List(clauses ...clause.Expression) ([]models.BankDetails, error) {
 	var bankDetails []models.BankDetails
 
@@ -128,3 +187,17 @@ func (s *bankDetails) Count(clauses ...clause.Expression) (int64, error) {
 
 	return count, nil
 }
+
+// validate checks bank details against the bank registry and then with the
+// account verifier.
+func (s *bankDetails) validate(bankDetails *models.BankDetails) error {
+	if err := validateBankDetails(bankDetails); err != nil {
+		return err
+	}
+
+	if err := s.verifier.Verify(*bankDetails); err != nil {
+		return ErrAccountVerificationFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+var bankAccountTypes = []models.BankAccountType{
+	models.ChequeAccount,
+	models.SavingsAccount,
+	models.TransmissionAccount,
+}
+
+// AccountVerifier confirms with an external account verification service that
+// an account exists, is open and is held by the named account holder.
+type AccountVerifier interface {
+	Verify(bankDetails models.BankDetails) error
+}
+
+// stubAccountVerifier accepts every account. It is used until an account
+// verification service is configured.
+type stubAccountVerifier struct{}
+
+func (stubAccountVerifier) Verify(bankDetails models.BankDetails) error {
+	return nil
+}
+
+// validateBankDetails normalizes bank details against the bank registry and
+// checks the branch code, account type and account number. The bank name is
+// replaced with the registry's spelling and a missing branch code defaults to
+// the bank's universal branch code.
+func validateBankDetails(bankDetails *models.BankDetails) error {
+	bankIndex := slices.IndexFunc(constants.SupportedBanks, func(bank models.Bank) bool {
+		return strings.EqualFold(bank.Name, strings.TrimSpace(bankDetails.BankName))
+	})
+
+	if bankIndex < 0 {
+		return ErrUnsupportedBank
+	}
+
+	bank := constants.SupportedBanks[bankIndex]
+
+	bankDetails.BankName = bank.Name
+	bankDetails.AccountHolder = strings.TrimSpace(bankDetails.AccountHolder)
+	bankDetails.AccountNumber = strings.ReplaceAll(strings.TrimSpace(bankDetails.AccountNumber), " ", "")
+	bankDetails.BranchCode = strings.TrimSpace(bankDetails.BranchCode)
+
+	if bankDetails.BranchCode == "" {
+		bankDetails.BranchCode = bank.UniversalBranchCode
+	}
+
+	if len(bankDetails.BranchCode) != 6 || !isDigits(bankDetails.BranchCode) {
+		return ErrInvalidBranchCode
+	}
+
+	if bankDetails.AccountType == "" {
+		bankDetails.AccountType = models.ChequeAccount
+	}
+
+	if !slices.Contains(bankAccountTypes, bankDetails.AccountType) {
+		return ErrInvalidAccountType
+	}
+
+	if !isDigits(bankDetails.AccountNumber) ||
+		!slices.Contains(bank.AccountLengths, len(bankDetails.AccountNumber)) {
+		return ErrInvalidAccountNumber
+	}
+
+	return nil
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+func TestValidateBankDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		bankDetails models.BankDetails
+		want        error
+		wantBank    string
+		wantBranch  string
+	}{
+		{
+			name:        "normalizes the bank name and defaults the branch code",
+			bankDetails: models.BankDetails{BankName: " absa ", AccountHolder: "T Mokoena", AccountNumber: "40 1234 5678"},
+			wantBank:    "ABSA",
+			wantBranch:  "632005",
+		},
+		{
+			name:        "keeps a given branch code",
+			bankDetails: models.BankDetails{BankName: "FNB", AccountNumber: "62123456789", BranchCode: "250655"},
+			wantBank:    "FNB",
+			wantBranch:  "250655",
+		},
+		{
+			name:        "accepts any account number of an accepted length",
+			bankDetails: models.BankDetails{BankName: "Standard Bank", AccountNumber: "000000001"},
+			wantBank:    "Standard Bank",
+			wantBranch:  "051001",
+		},
+		{
+			name:        "rejects an unknown bank",
+			bankDetails: models.BankDetails{BankName: "Bank of Nowhere", AccountNumber: "1234567890"},
+			want:        ErrUnsupportedBank,
+		},
+		{
+			name:        "rejects a short branch code",
+			bankDetails: models.BankDetails{BankName: "Capitec", AccountNumber: "1234567890", BranchCode: "47001"},
+			want:        ErrInvalidBranchCode,
+		},
+		{
+			name:        "rejects an unknown account type",
+			bankDetails: models.BankDetails{BankName: "Capitec", AccountNumber: "1234567890", AccountType: "bond"},
+			want:        ErrInvalidAccountType,
+		},
+		{
+			name:        "rejects an account number of the wrong length",
+			bankDetails: models.BankDetails{BankName: "Capitec", AccountNumber: "123456789"},
+			want:        ErrInvalidAccountNumber,
+		},
+		{
+			name:        "rejects an account number with letters",
+			bankDetails: models.BankDetails{BankName: "Capitec", AccountNumber: "12345678AB"},
+			want:        ErrInvalidAccountNumber,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bankDetails := test.bankDetails
+
+			err := validateBankDetails(&bankDetails)
+
+			if err != test.want {
+				t.Fatalf("validateBankDetails() = %v, want %v", err, test.want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if bankDetails.BankName != test.wantBank {
+				t.Errorf("BankName = %q, want %q", bankDetails.BankName, test.wantBank)
+			}
+
+			if bankDetails.BranchCode != test.wantBranch {
+				t.Errorf("BranchCode = %q, want %q", bankDetails.BranchCode, test.wantBranch)
+			}
+
+			if bankDetails.AccountType != models.ChequeAccount && test.bankDetails.AccountType == "" {
+				t.Errorf("AccountType = %q, want %q", bankDetails.AccountType, models.ChequeAccount)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type changesService interface {
+	Listen(ctx context.Context)
+	Subscribe(organizationId uuid.UUID) *ChangeSubscription
+	Resume(organizationId uuid.UUID, afterId int64, resources []models.ChangeResource) ([]models.ChangeEvent, bool, error)
+	Latest() (int64, error)
+	Cleanup() error
+}
+
+type changes struct {
+	storage     storage.Storage
+	mutex       sync.Mutex
+	subscribers map[uuid.UUID]map[*ChangeSubscription]struct{}
+}
+
+func newChangesService(storage storage.Storage) changesService {
+	return &changes{
+		storage:     storage,
+		subscribers: map[uuid.UUID]map[*ChangeSubscription]struct{}{},
+	}
+}
+
+// CleanupChangesJob is the kind of the scheduled jobs that delete old change
+// events.
+const CleanupChangesJob = "changes.cleanup"
+
+const (
+	// changeRetention is how long change events are kept for streams to be
+	// resumed from.
+	changeRetention = 24 * time.Hour
+	// changeResumeLimit is the most change events replayed when a stream is
+	// resumed. Clients further behind reload instead.
+	changeResumeLimit = 1000
+	// changeCommitOverlap is how long before the last event received events
+	// are read again when resuming. Ids are taken in the order changes are
+	// made rather than committed, so a change made shortly before the last
+	// event may have been committed after it. Only transactions that run for
+	// longer than this can be missed. Events already received are sent again
+	// and are dropped by their ids.
+	changeCommitOverlap = 2 * time.Minute
+	// changeBufferSize is how many change events a subscription holds before
+	// it is closed for falling behind.
+	changeBufferSize = 256
+	// listenerPingInterval is how often an idle listener checks that its
+	// connection is still alive.
+	listenerPingInterval = 90 * time.Second
+)
+
+// ChangeSubscription receives the change events of an organization as they are
+// announced. Events is closed when the subscription is closed, including when
+// the subscriber fell too far behind, after which it should resume from the
+// last event it received.
+type ChangeSubscription struct {
+	Events <-chan models.ChangeEvent
+
+	events       chan models.ChangeEvent
+	organization uuid.UUID
+	once         sync.Once
+	service      *changes
+}
+
+// Close stops the subscription.
+func (s *ChangeSubscription) Close() {
+	s.service.mutex.Lock()
+
+	defer s.service.mutex.Unlock()
+
+	s.close()
+}
+
+// close stops the subscription while the mutex of the service is held.
+func (s *ChangeSubscription) close() {
+	s.once.Do(func() {
+		delete(s.service.subscribers[s.organization], s)
+
+		if len(s.service.subscribers[s.organization]) == 0 {
+			delete(s.service.subscribers, s.organization)
+		}
+
+		close(s.events)
+	})
+}
+
+// Listen fans the change events announced by Postgres out to subscriptions
+// until the context is cancelled. Every API instance listens, so subscribers
+// receive the changes made through any instance. Changes announced while the
+// connection was lost are read back once it is restored.
+func (s *changes) Listen(ctx context.Context) {
+	listener := s.storage.NewListener(func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Errorf("🔥 Change event listener error: %s", err.Error())
+		}
+	})
+
+	defer listener.Close()
+
+	if err := listener.Listen(storage.ChangeEventsChannel); err != nil {
+		log.Errorf("🔥 Failed to listen for change events: %s", err.Error())
+
+		return
+	}
+
+	lastId, err := s.Latest()
+
+	if err != nil {
+		log.Errorf("🔥 Failed to find the last change event: %s", err.Error())
+	}
+
+	// dispatched holds the recently announced events, so that those read
+	// again when the connection is restored are not sent twice.
+	dispatched := map[int64]time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was restored.
+			if notification == nil {
+				var missed []models.ChangeEvent
+
+				if err := s.storage.Postgres.
+					Where(changesAfter(lastId)).
+					Order("id ASC").
+					Find(&missed).Error; err != nil {
+					log.Errorf("🔥 Failed to read missed change events: %s", err.Error())
+
+					continue
+				}
+
+				for _, event := range missed {
+					if _, ok := dispatched[event.Id]; ok {
+						continue
+					}
+
+					s.dispatch(event)
+
+					dispatched[event.Id] = event.CreatedAt
+					lastId = max(lastId, event.Id)
+				}
+
+				continue
+			}
+
+			var event models.ChangeEvent
+
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				log.Errorf("🔥 Failed to decode change event: %s", err.Error())
+
+				continue
+			}
+
+			s.dispatch(event)
+
+			dispatched[event.Id] = event.CreatedAt
+			lastId = max(lastId, event.Id)
+		case <-time.After(listenerPingInterval):
+			for id, createdAt := range dispatched {
+				if time.Since(createdAt) > 2*changeCommitOverlap {
+					delete(dispatched, id)
+				}
+			}
+
+			go func() {
+				if err := listener.Ping(); err != nil {
+					log.Errorf("🔥 Change event listener ping failed: %s", err.Error())
+				}
+			}()
+		}
+	}
+}
+
+func (s *changes) dispatch(event models.ChangeEvent) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	for subscription := range s.subscribers[event.OrganizationId] {
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.close()
+		}
+	}
+}
+
+func (s *changes) Subscribe(organizationId uuid.UUID) *ChangeSubscription {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	events := make(chan models.ChangeEvent, changeBufferSize)
+
+	subscription := &ChangeSubscription{
+		Events:       events,
+		events:       events,
+		organization: organizationId,
+		service:      s,
+	}
+
+	if s.subscribers[organizationId] == nil {
+		s.subscribers[organizationId] = map[*ChangeSubscription]struct{}{}
+	}
+
+	s.subscribers[organizationId][subscription] = struct{}{}
+
+	return subscription
+}
+
+// Resume returns the change events of an organization to the resources after
+// the given event, along with those made shortly before it that may have been
+// committed after it. The events are not complete when some may have been
+// deleted since or there are too many to replay, in which case the client
+// should reload what it shows instead.
+func (s *changes) Resume(organizationId uuid.UUID, afterId int64, resources []models.ChangeResource) ([]models.ChangeEvent, bool, error) {
+	var oldestId int64
+
+	if err := s.storage.Postgres.
+		Model(&models.ChangeEvent{}).
+		Select("COALESCE(MIN(id), 0)").
+		Scan(&oldestId).Error; err != nil {
+		return nil, false, err
+	}
+
+	// Clients only resume after events that were committed, so events after
+	// it may have been deleted when it no longer exists. Ids that were never
+	// committed leave gaps in the sequence, so the next id is not expected.
+	if oldestId > max(afterId, 1) {
+		return nil, false, nil
+	}
+
+	var events []models.ChangeEvent
+
+	if err := s.storage.Postgres.
+		Where("organization_id = ? AND resource IN ?", organizationId, resources).
+		Where(changesAfter(afterId)).
+		Order("id ASC").
+		Limit(changeResumeLimit + 1).
+		Find(&events).Error; err != nil {
+		return nil, false, err
+	}
+
+	if len(events) > changeResumeLimit {
+		return nil, false, nil
+	}
+
+	return events, true, nil
+}
+
+// changesAfter selects the change events after the given one and those made
+// within changeCommitOverlap before it.
+func changesAfter(afterId int64) clause.Expr {
+	return gorm.Expr(
+		"(change_events.id > ? OR (change_events.id < ? AND change_events.created_at >= (SELECT created_at FROM change_events WHERE id = ?) - make_interval(secs => ?)))",
+		afterId,
+		afterId,
+		afterId,
+		changeCommitOverlap.Seconds(),
+	)
+}
+
+// Latest returns the id of the newest change event of any organization, which
+// a stream that is reset resumes after.
+func (s *changes) Latest() (int64, error) {
+	var latestId int64
+
+	if err := s.storage.Postgres.
+		Model(&models.ChangeEvent{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&latestId).Error; err != nil {
+		return 0, err
+	}
+
+	return latestId, nil
+}
+
+// Cleanup deletes the change events older than streams can be resumed from.
+func (s *changes) Cleanup() error {
+	return s.storage.Postgres.
+		Where("created_at < ?", time.Now().Add(-changeRetention)).
+		Delete(&models.ChangeEvent{}).Error
+}
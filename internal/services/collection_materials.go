@@ -9,8 +9,8 @@ import (
 
 type collectionMaterialsService interface {
 	Create(payload models.CreateCollectionMaterialPayload) (uuid.UUID, error)
-	Update(collectionMaterialId uuid.UUID, payload models.UpdateCollectionMaterialPayload) error
-	Delete(collectionMaterialId uuid.UUID) error
+	Update(collectionMaterialId uuid.UUID, version int64, payload models.UpdateCollectionMaterialPayload) error
+	Delete(collectionMaterialId uuid.UUID, version int64) error
 	Find(collectionMaterialId uuid.UUID) (*models.CollectionMaterial, error)
 	List(clauses ...clause.Expression) ([]models.CollectionMaterial, error)
 	Count(clauses ...clause.Expression) (int64, error)
@@ -27,21 +27,48 @@ func newCollectionMaterialsService(storage storage.Storage) collectionMaterialsS
 }
 
 func (s *collectionMaterials) Create(payload models.CreateCollectionMaterialPayload) (uuid.UUID, error) {
+	var collection models.Collection
+
+	if err := s.storage.Postgres.
+		Where("id = ?", payload.CollectionId).
+		First(&collection).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if collection.Status.Locked() {
+		return uuid.Nil, ErrCollectionLocked
+	}
+
+	if err := ensureSellerMayCollect(s.storage.Postgres, collection.SellerId, payload.MaterialId); err != nil {
+		return uuid.Nil, err
+	}
+
 	var collectionMaterial models.CollectionMaterial
 
 	collectionMaterial.MaterialId = payload.MaterialId
 	collectionMaterial.Weight = payload.Weight
 	collectionMaterial.Value = payload.Value
+	collectionMaterial.ScaleReadingId = payload.ScaleReadingId
+
+	if err := weighCollectionMaterial(s.storage.Postgres, collection, &collectionMaterial); err != nil {
+		return uuid.Nil, err
+	}
 
 	if err := s.storage.Postgres.
-		Create(&collectionMaterial).Error; err != nil {
+		Model(&collection).
+		Association("Materials").
+		Append(&collectionMaterial); err != nil {
 		return uuid.Nil, err
 	}
 
 	return collectionMaterial.Id, nil
 }
 
-func (s *collectionMaterials) Update(collectionMaterialId uuid.UUID, payload models.UpdateCollectionMaterialPayload) error {
+func (s *collectionMaterials) Update(collectionMaterialId uuid.UUID, version int64, payload models.UpdateCollectionMaterialPayload) error {
+	if err := s.ensureUnlocked(collectionMaterialId); err != nil {
+		return err
+	}
+
 	var collectionMaterial models.CollectionMaterial
 
 	if err := s.storage.Postgres.
@@ -51,39 +78,69 @@ func (s *collectionMaterials) Update(collectionMaterialId uuid.UUID, payload mod
 	}
 
 	if payload.MaterialId != nil {
-		collectionMaterial.MaterialId = *payload.MaterialId
-	}
+		collection, err := s.collectionOf(collectionMaterialId)
 
-	if payload.Weight != nil {
-		collectionMaterial.Weight = *payload.Weight
+		if err != nil {
+			return err
+		}
+
+		if err := ensureSellerMayCollect(s.storage.Postgres, collection.SellerId, *payload.MaterialId); err != nil {
+			return err
+		}
+
+		collectionMaterial.MaterialId = *payload.MaterialId
 	}
 
 	if payload.Value != nil {
 		collectionMaterial.Value = *payload.Value
 	}
 
-	if err := s.storage.Postgres.
-		Model(&models.CollectionMaterial{}).
-		Where("id = ?", collectionMaterialId).
-		Updates(&map[string]any{
-			"material_id": collectionMaterial.MaterialId,
-			"weight":      collectionMaterial.Weight,
-			"value":       collectionMaterial.Value,
-		}).Error; err != nil {
-		return err
+	// A weight typed by hand replaces the reading the line was weighed with.
+	if payload.ScaleReadingId != nil || payload.Weight != nil {
+		collection, err := s.collectionOf(collectionMaterialId)
+
+		if err != nil {
+			return err
+		}
+
+		collectionMaterial.ScaleReadingId = payload.ScaleReadingId
+
+		if payload.Weight != nil {
+			collectionMaterial.Weight = *payload.Weight
+		}
+
+		if err := weighCollectionMaterial(s.storage.Postgres, *collection, &collectionMaterial); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	result := s.storage.Postgres.
+		Model(&models.CollectionMaterial{}).
+		Where("id = ? AND version = ?", collectionMaterialId, version).
+		Updates(&map[string]any{
+			"material_id":      collectionMaterial.MaterialId,
+			"weight":           collectionMaterial.Weight,
+			"value":            collectionMaterial.Value,
+			"scale_reading_id": collectionMaterial.ScaleReadingId,
+			"manual_override":  collectionMaterial.ManualOverride,
+		})
+
+	return checkVersion(s.storage.Postgres, &models.CollectionMaterial{}, collectionMaterialId, result)
 }
 
-func (s *collectionMaterials) Delete(collectionMaterialId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", collectionMaterialId).
-		Delete(&models.CollectionMaterial{}).Error; err != nil {
+func (s *collectionMaterials) Delete(collectionMaterialId uuid.UUID, version int64) error {
+	if err := s.ensureUnlocked(collectionMaterialId); err != nil {
 		return err
 	}
 
-	return nil
+	// Lines can only be removed while their parent is a draft, so they are
+	// removed outright rather than kept in the trash.
+	result := s.storage.Postgres.
+		Unscoped().
+		Where("id = ? AND version = ?", collectionMaterialId, version).
+		Delete(&models.CollectionMaterial{})
+
+	return checkVersion(s.storage.Postgres, &models.CollectionMaterial{}, collectionMaterialId, result)
 }
 
 func (s *collectionMaterials) Find(collectionMaterialId uuid.UUID) (*models.CollectionMaterial, error) {
@@ -91,6 +148,7 @@ func (s *collectionMaterials) Find(collectionMaterialId uuid.UUID) (*models.Coll
 
 	if err := s.storage.Postgres.
 		Where("id = ?", collectionMaterialId).
+		Preload("ScaleReading").
 		First(&collectionMaterial).Error; err != nil {
 		return nil, err
 	}
@@ -122,3 +180,39 @@ func (s *collectionMaterials) Count(clauses ...clause.Expression) (int64, error)
 
 	return count, nil
 }
+
+// ensureUnlocked returns ErrCollectionLocked when the collection that owns the
+// given line has been confirmed, paid or voided.
+func (s *collectionMaterials) ensureUnlocked(collectionMaterialId uuid.UUID) error {
+	var statuses []models.CollectionStatus
+
+	if err := s.storage.Postgres.
+		Model(&models.Collection{}).
+		Joins("JOIN collections_materials ON collections_materials.collection_id = collections.id").
+		Where("collections_materials.collection_material_id = ?", collectionMaterialId).
+		Pluck("collections.status", &statuses).Error; err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Locked() {
+			return ErrCollectionLocked
+		}
+	}
+
+	return nil
+}
+
+// collectionOf returns the collection that owns the given line.
+func (s *collectionMaterials) collectionOf(collectionMaterialId uuid.UUID) (*models.Collection, error) {
+	var collection models.Collection
+
+	if err := s.storage.Postgres.
+		Joins("JOIN collections_materials ON collections_materials.collection_id = collections.id").
+		Where("collections_materials.collection_material_id = ?", collectionMaterialId).
+		First(&collection).Error; err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
@@ -1,19 +1,26 @@
 package services
 
 import (
+	"strings"
+	"time"
+
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type collectionsService interface {
 	Materials() collectionMaterialsService
 	Create(payload models.CreateCollectionPayload) (uuid.UUID, error)
-	Update(collectionId uuid.UUID, payload models.UpdateCollectionPayload) error
-	Delete(collectionId uuid.UUID) error
+	CreateWithId(collectionId uuid.UUID, createdAt time.Time, payload models.CreateCollectionPayload) error
+	Update(collectionId uuid.UUID, version int64, payload models.UpdateCollectionPayload) error
+	Delete(collectionId uuid.UUID, version int64) error
+	Transition(collectionId uuid.UUID, status models.CollectionStatus, performedById uuid.UUID, reason *string) error
 	Find(collectionId uuid.UUID) (*models.Collection, error)
 	List(clauses ...clause.Expression) ([]models.Collection, error)
+	Stream(handle func(models.Collection) error, clauses ...clause.Expression) error
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
@@ -36,26 +43,137 @@ func (s *collections) Materials() collectionMaterialsService {
 }
 
 func (s *collections) Create(payload models.CreateCollectionPayload) (uuid.UUID, error) {
-	var collection models.Collection
+	return s.create(models.Collection{}, payload)
+}
+
+// CreateWithId creates a collection with the id and creation time it was given
+// by a client, such as one recorded while the client was offline. It fails with
+// ErrCollectionExists when the id is already used, including by a collection
+// in the trash.
+func (s *collections) CreateWithId(collectionId uuid.UUID, createdAt time.Time, payload models.CreateCollectionPayload) error {
+	if err := s.ensureCollectionIdUnused(collectionId); err != nil {
+		return err
+	}
+
+	collection := models.Collection{
+		Base: models.Base{
+			Id:        collectionId,
+			CreatedAt: createdAt,
+		},
+	}
+
+	_, err := s.create(collection, payload)
 
+	if translateError(s.storage.Postgres, err) == gorm.ErrDuplicatedKey {
+		// The id may have been taken by a collection created at the same
+		// time, or a line may use a scale reading that was taken.
+		if err := s.ensureCollectionIdUnused(collectionId); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (s *collections) ensureCollectionIdUnused(collectionId uuid.UUID) error {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Unscoped().
+		Model(&models.Collection{}).
+		Where("id = ?", collectionId).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrCollectionExists
+	}
+
+	return nil
+}
+
+// create creates the collection with the parties and lines of the payload.
+func (s *collections) create(collection models.Collection, payload models.CreateCollectionPayload) (uuid.UUID, error) {
 	collection.SellerId = payload.SellerId
 	collection.BuyerId = payload.BuyerId
+	collection.SiteId = payload.SiteId
+	collection.Status = models.CollectionDraft
 
-	if err := s.storage.Postgres.
-		Create(&collection).Error; err != nil {
+	if err := addCollectionMaterials(s.storage.Postgres, &collection, payload.Materials); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&collection).Error; err != nil {
+			return err
+		}
+
+		return publishCollectionEvents(tx, collection.Id, models.CollectionCreatedEvent)
+	}); err != nil {
 		return uuid.Nil, err
 	}
 
 	return collection.Id, nil
 }
 
-func (s *collections) Update(collectionId uuid.UUID, payload models.UpdateCollectionPayload) error {
+// addCollectionMaterials checks that the site of a new collection is operated
+// by its buyer and that its seller may collect the materials, then adds the
+// lines to it, weighed by the scale readings they reference.
+func addCollectionMaterials(tx *gorm.DB, collection *models.Collection, materials []models.CreateCollectionMaterialPayload) error {
+	if err := ensureSiteOperatedBy(tx, collection.SiteId, collection.BuyerId); err != nil {
+		return err
+	}
+
+	materialIds := []uuid.UUID{}
+
+	for _, material := range materials {
+		materialIds = append(materialIds, material.MaterialId)
+	}
+
+	if err := ensureSellerMayCollect(tx, collection.SellerId, materialIds...); err != nil {
+		return err
+	}
+
+	readingIds := map[uuid.UUID]bool{}
+
+	for _, material := range materials {
+		if material.ScaleReadingId != nil {
+			if readingIds[*material.ScaleReadingId] {
+				return ErrScaleReadingUnavailable
+			}
+
+			readingIds[*material.ScaleReadingId] = true
+		}
+
+		line := models.CollectionMaterial{
+			MaterialId:     material.MaterialId,
+			Weight:         material.Weight,
+			Value:          material.Value,
+			ScaleReadingId: material.ScaleReadingId,
+		}
+
+		if err := weighCollectionMaterial(tx, *collection, &line); err != nil {
+			return err
+		}
+
+		collection.Materials = append(collection.Materials, line)
+	}
+
+	return nil
+}
+
+func (s *collections) Update(collectionId uuid.UUID, version int64, payload models.UpdateCollectionPayload) error {
 	var collection models.Collection
 
 	if err := s.storage.Postgres.Where("id = ?", collectionId).First(&collection).Error; err != nil {
 		return err
 	}
 
+	if collection.Status.Locked() {
+		return ErrCollectionLocked
+	}
+
 	if payload.SellerId != nil {
 		collection.SellerId = *payload.SellerId
 	}
@@ -64,34 +182,182 @@ func (s *collections) Update(collectionId uuid.UUID, payload models.UpdateCollec
 		collection.BuyerId = *payload.BuyerId
 	}
 
-	if err := s.storage.Postgres.
-		Model(&models.Collection{}).
-		Where("id = ?", collectionId).
-		Updates(&map[string]any{
-			"seller_id": collection.SellerId,
-			"buyer_id":  collection.BuyerId,
-		}).Error; err != nil {
+	if payload.SiteId != nil {
+		collection.SiteId = payload.SiteId
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, collection.SiteId, collection.BuyerId); err != nil {
 		return err
 	}
 
-	return nil
+	if payload.SellerId != nil {
+		var materialIds []uuid.UUID
+
+		if err := s.storage.Postgres.
+			Model(&models.CollectionMaterial{}).
+			Where("id IN (SELECT collection_material_id FROM collections_materials WHERE collection_id = ?)", collectionId).
+			Pluck("material_id", &materialIds).Error; err != nil {
+			return err
+		}
+
+		if err := ensureSellerMayCollect(s.storage.Postgres, collection.SellerId, materialIds...); err != nil {
+			return err
+		}
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Collection{}).
+			Where("id = ? AND version = ?", collectionId, version).
+			Updates(&map[string]any{
+				"seller_id": collection.SellerId,
+				"buyer_id":  collection.BuyerId,
+				"site_id":   collection.SiteId,
+			})
+
+		if err := checkVersion(tx, &models.Collection{}, collectionId, result); err != nil {
+			return err
+		}
+
+		return publishCollectionEvents(tx, collectionId, models.CollectionUpdatedEvent)
+	})
 }
 
-func (s *collections) Delete(collectionId uuid.UUID) error {
-	if err := s.storage.Postgres.
+// Delete removes a collection that has not yet been confirmed. Confirmed
+// collections form part of the financial history and must be voided instead.
+func (s *collections) Delete(collectionId uuid.UUID, version int64) error {
+	var collection models.Collection
+
+	if err := s.storage.Postgres.Where("id = ?", collectionId).First(&collection).Error; err != nil {
+		return err
+	}
+
+	if collection.Status.Locked() {
+		return ErrCollectionLocked
+	}
+
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", collectionId, version).
+		Delete(&models.Collection{})
+
+	return checkVersion(s.storage.Postgres, &models.Collection{}, collectionId, result)
+}
+
+// Transition moves a collection to the given status, recording who performed
+// the change. The collection row is locked for the duration of the change so
+// that concurrent transitions cannot both succeed.
+func (s *collections) Transition(collectionId uuid.UUID, status models.CollectionStatus, performedById uuid.UUID, reason *string) error {
+	if status == models.CollectionVoided && (reason == nil || strings.TrimSpace(*reason) == "") {
+		return ErrVoidReasonRequired
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		// The collection is locked before its payouts are checked, so that it
+		// cannot be added to a payout batch in between.
+		var collection models.Collection
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", collectionId).
+			First(&collection).Error; err != nil {
+			return err
+		}
+
+		if status == models.CollectionPaid || status == models.CollectionVoided {
+			if err := ensureNotInOpenPayout(tx, collectionId); err != nil {
+				return err
+			}
+		}
+
+		// Paid collections return to confirmed only when their payout is
+		// reversed, which returns the payment along with them.
+		if status == models.CollectionConfirmed && collection.Status == models.CollectionPaid {
+			return ErrInvalidCollectionTransition
+		}
+
+		return transitionCollection(tx, collectionId, status, performedById, reason)
+	})
+}
+
+// transitionCollection locks the collection, checks the transition is allowed
+// and applies it along with any stock movements it causes. It must be called
+// within a database transaction.
+func transitionCollection(tx *gorm.DB, collectionId uuid.UUID, status models.CollectionStatus, performedById uuid.UUID, reason *string) error {
+	var collection models.Collection
+
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("id = ?", collectionId).
-		Delete(&models.Collection{}).Error; err != nil {
+		First(&collection).Error; err != nil {
 		return err
 	}
 
+	if !collection.Status.CanTransitionTo(status) {
+		return ErrInvalidCollectionTransition
+	}
+
+	if err := recordCollectionTransition(tx, &collection, status, performedById, reason); err != nil {
+		return err
+	}
+
+	switch {
+	case status == models.CollectionConfirmed:
+		return postCollectionReceipts(tx, &collection, time.Now())
+	case status == models.CollectionVoided && collection.Status.Locked():
+		return reverseCollectionReceipts(tx, &collection, time.Now())
+	}
+
 	return nil
 }
 
+// recordCollectionTransition updates the status of a collection and records
+// the transition without checking whether it is allowed.
+func recordCollectionTransition(tx *gorm.DB, collection *models.Collection, status models.CollectionStatus, performedById uuid.UUID, reason *string) error {
+	updates := map[string]any{
+		"status": status,
+	}
+
+	if status == models.CollectionVoided {
+		updates["void_reason"] = reason
+	}
+
+	if err := tx.
+		Model(&models.Collection{}).
+		Where("id = ?", collection.Id).
+		Updates(&updates).Error; err != nil {
+		return err
+	}
+
+	transition := models.CollectionTransition{
+		CollectionId:  collection.Id,
+		From:          collection.Status,
+		To:            status,
+		Reason:        reason,
+		PerformedById: performedById,
+	}
+
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	eventTypes := []models.WebhookEventType{models.CollectionStatusChangedEvent}
+
+	if status == models.CollectionConfirmed {
+		eventTypes = append(eventTypes, models.CollectionConfirmedEvent)
+	}
+
+	return publishCollectionEvents(tx, collection.Id, eventTypes...)
+}
+
 func (s *collections) Find(collectionId uuid.UUID) (*models.Collection, error) {
 	var collection *models.Collection
 
 	if err := s.storage.Postgres.
 		Where("id = ?", collectionId).
+		Preload("Site").
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
 		First(&collection).Error; err != nil {
 		return nil, err
 	}
@@ -103,6 +369,7 @@ func (s *collections) List(clauses ...clause.Expression) ([]models.Collection, e
 	var collections []models.Collection
 
 	if err := s.storage.Postgres.
+		Preload("Materials.Material").
 		Clauses(clauses...).
 		Find(&collections).Error; err != nil {
 		return nil, err
@@ -111,6 +378,28 @@ func (s *collections) List(clauses ...clause.Expression) ([]models.Collection, e
 	return collections, nil
 }
 
+// Stream passes each collection matching the clauses to handle, loading them in
+// batches so that they are never all held in memory.
+func (s *collections) Stream(handle func(models.Collection) error, clauses ...clause.Expression) error {
+	var collections []models.Collection
+
+	return s.storage.Postgres.
+		Preload("Materials.Material").
+		Preload("Seller").
+		Preload("Buyer").
+		Preload("Site").
+		Clauses(clauses...).
+		FindInBatches(&collections, streamBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, collection := range collections {
+				if err := handle(collection); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Error
+}
+
 func (s *collections) Count(clauses ...clause.Expression) (int64, error) {
 	var count int64
 
@@ -123,3 +412,39 @@ func (s *collections) Count(clauses ...clause.Expression) (int64, error) {
 
 	return count, nil
 }
+
+// ensureSellerMayCollect returns ErrSellerNotVerified when any of the materials
+// is regulated and the identity of the seller has not been verified.
+func ensureSellerMayCollect(tx *gorm.DB, sellerId uuid.UUID, materialIds ...uuid.UUID) error {
+	if len(materialIds) == 0 {
+		return nil
+	}
+
+	var regulated int64
+
+	if err := tx.
+		Model(&models.Material{}).
+		Where("id IN ? AND regulated", materialIds).
+		Count(&regulated).Error; err != nil {
+		return err
+	}
+
+	if regulated == 0 {
+		return nil
+	}
+
+	var verified int64
+
+	if err := tx.
+		Model(&models.User{}).
+		Where("id = ? AND identity_status = ?", sellerId, models.IdentityVerified).
+		Count(&verified).Error; err != nil {
+		return err
+	}
+
+	if verified == 0 {
+		return ErrSellerNotVerified
+	}
+
+	return nil
+}
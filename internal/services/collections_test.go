@@ -0,0 +1,173 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCollectionsUpdateSeller(t *testing.T) {
+	s := testStorage(t)
+
+	buyer := testOrganization(t, s)
+	verified := testUser(t, s, buyer.Id, models.IdentityVerified)
+	otherVerified := testUser(t, s, buyer.Id, models.IdentityVerified)
+	unverified := testUser(t, s, buyer.Id, models.IdentityUnverified)
+	regulated := testMaterial(t, s, true)
+	unregulated := testMaterial(t, s, false)
+
+	service := newCollectionsService(s)
+
+	tests := []struct {
+		name     string
+		material models.Material
+		seller   models.User
+		err      error
+	}{
+		{name: "verified seller of a regulated material", material: regulated, seller: otherVerified},
+		{name: "unverified seller of a regulated material", material: regulated, seller: unverified, err: ErrSellerNotVerified},
+		{name: "unverified seller of an unregulated material", material: unregulated, seller: unverified},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionId, err := service.Create(models.CreateCollectionPayload{
+				SellerId: verified.Id,
+				BuyerId:  buyer.Id,
+				Materials: []models.CreateCollectionMaterialPayload{
+					{MaterialId: test.material.Id, Weight: 10, Value: 25},
+				},
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			collection, err := service.Find(collectionId)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = service.Update(collectionId, collection.Version, models.UpdateCollectionPayload{
+				SellerId: &test.seller.Id,
+			})
+
+			if err != test.err {
+				t.Fatalf("Update() error = %v, want %v", err, test.err)
+			}
+
+			updated, err := service.Find(collectionId)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := test.seller.Id
+
+			if test.err != nil {
+				want = verified.Id
+			}
+
+			if updated.SellerId != want {
+				t.Errorf("seller = %s, want %s", updated.SellerId, want)
+			}
+		})
+	}
+}
+
+func TestCollectionsTransition(t *testing.T) {
+	s := testStorage(t)
+
+	buyer := testOrganization(t, s)
+	seller := testUser(t, s, buyer.Id, models.IdentityVerified)
+	material := testMaterial(t, s, false)
+
+	service := newCollectionsService(s)
+
+	create := func() uuid.UUID {
+		t.Helper()
+
+		collectionId, err := service.Create(models.CreateCollectionPayload{
+			SellerId: seller.Id,
+			BuyerId:  buyer.Id,
+			Materials: []models.CreateCollectionMaterialPayload{
+				{MaterialId: material.Id, Weight: 10, Value: 25},
+			},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return collectionId
+	}
+
+	reason := "weighed on the wrong scale"
+	blank := "  "
+
+	tests := []struct {
+		name     string
+		statuses []models.CollectionStatus
+		reason   *string
+		err      error
+		want     models.CollectionStatus
+	}{
+		{name: "weighed and confirmed", statuses: []models.CollectionStatus{models.CollectionWeighed, models.CollectionConfirmed}, want: models.CollectionConfirmed},
+		{name: "confirmed without being weighed", statuses: []models.CollectionStatus{models.CollectionConfirmed}, err: ErrInvalidCollectionTransition, want: models.CollectionDraft},
+		{name: "voided with a reason", statuses: []models.CollectionStatus{models.CollectionVoided}, reason: &reason, want: models.CollectionVoided},
+		{name: "voided without a reason", statuses: []models.CollectionStatus{models.CollectionVoided}, err: ErrVoidReasonRequired, want: models.CollectionDraft},
+		{name: "voided with a blank reason", statuses: []models.CollectionStatus{models.CollectionVoided}, reason: &blank, err: ErrVoidReasonRequired, want: models.CollectionDraft},
+		{name: "voided twice", statuses: []models.CollectionStatus{models.CollectionVoided, models.CollectionVoided}, reason: &reason, err: ErrInvalidCollectionTransition, want: models.CollectionVoided},
+		{name: "paid without a payout and returned", statuses: []models.CollectionStatus{models.CollectionWeighed, models.CollectionConfirmed, models.CollectionPaid, models.CollectionConfirmed}, err: ErrInvalidCollectionTransition, want: models.CollectionPaid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collectionId := create()
+
+			var err error
+
+			for _, status := range test.statuses {
+				if err = service.Transition(collectionId, status, seller.Id, test.reason); err != nil {
+					break
+				}
+			}
+
+			if err != test.err {
+				t.Fatalf("Transition() error = %v, want %v", err, test.err)
+			}
+
+			collection, err := service.Find(collectionId)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if collection.Status != test.want {
+				t.Errorf("status = %s, want %s", collection.Status, test.want)
+			}
+
+			var transitions int64
+
+			if err := s.Postgres.
+				Model(&models.CollectionTransition{}).
+				Where("collection_id = ?", collectionId).
+				Count(&transitions).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			// A failed transition is the last one tried, and is not recorded.
+			want := len(test.statuses)
+
+			if test.err != nil {
+				want--
+			}
+
+			if transitions != int64(want) {
+				t.Errorf("recorded %d transitions, want %d", transitions, want)
+			}
+		})
+	}
+}
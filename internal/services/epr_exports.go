@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/documents"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+const eprReportNote = "Only collections confirmed and transactions delivered by the cut-off are included. " +
+	"Weights are in kilograms and carbon avoided is in kilograms of CO2 equivalent, using the carbon factor of each material at the time the report was generated."
+
+// renderEprPdf renders a report as a PDF with the organization details, the
+// totals of each material stream and the supporting evidence.
+func renderEprPdf(snapshot models.EprReportSnapshot) ([]byte, error) {
+	location, err := time.LoadLocation(reportTimeZone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := documents.NewPdf()
+
+	pdf.Heading("Extended Producer Responsibility Report")
+	pdf.Text(fmt.Sprintf("Organization: %s", snapshot.OrganizationName))
+
+	if snapshot.OrganizationAddress != "" {
+		pdf.Text(fmt.Sprintf("Address: %s", snapshot.OrganizationAddress))
+	}
+
+	pdf.Text(fmt.Sprintf("Period: %s to %s", formatReportTime(snapshot.PeriodStart, location), formatReportTime(snapshot.PeriodEnd, location)))
+	pdf.Text(fmt.Sprintf("Cut-off: %s", formatReportTime(snapshot.CutOff, location)))
+	pdf.Text(fmt.Sprintf("Generated: %s", formatReportTime(snapshot.GeneratedAt, location)))
+	pdf.Gap()
+	pdf.Text(eprReportNote)
+
+	pdf.Heading("Material Streams")
+
+	streams := [][]string{}
+	weight, tonnage, carbonAvoided := 0.0, 0.0, 0.0
+
+	for _, stream := range snapshot.Streams {
+		streams = append(streams, []string{
+			stream.GWCode,
+			strings.Join(stream.Materials, ", "),
+			fmt.Sprintf("%.2f", stream.Weight),
+			fmt.Sprintf("%.3f", stream.Tonnage),
+			fmt.Sprintf("%.2f", stream.CarbonAvoided),
+		})
+
+		weight += stream.Weight
+		tonnage += stream.Tonnage
+		carbonAvoided += stream.CarbonAvoided
+	}
+
+	streams = append(streams, []string{
+		"Total",
+		"",
+		fmt.Sprintf("%.2f", weight),
+		fmt.Sprintf("%.3f", tonnage),
+		fmt.Sprintf("%.2f", carbonAvoided),
+	})
+
+	pdf.Table(
+		[]string{"GW Code", "Materials", "Weight (kg)", "Tonnage (t)", "Carbon Avoided"},
+		[]float64{0.12, 0.4, 0.16, 0.14, 0.18},
+		streams,
+	)
+
+	pdf.Heading("Supporting Evidence")
+
+	evidence := [][]string{}
+
+	for _, line := range snapshot.Evidence {
+		evidence = append(evidence, []string{
+			line.RecordedAt.In(location).Format(time.DateOnly),
+			string(line.Source),
+			line.RecordId.String()[:8],
+			line.Counterparty,
+			line.Site,
+			line.GWCode,
+			line.Material,
+			fmt.Sprintf("%.2f", line.Weight),
+			fmt.Sprintf("%.2f", line.CarbonAvoided),
+		})
+	}
+
+	pdf.Table(
+		[]string{"Date", "Source", "Record", "Counterparty", "Site", "GW Code", "Material", "Weight (kg)", "Carbon"},
+		[]float64{0.11, 0.11, 0.09, 0.16, 0.12, 0.09, 0.14, 0.09, 0.09},
+		evidence,
+	)
+
+	return pdf.Bytes(), nil
+}
+
+// renderEprXlsx renders a report as a workbook with a summary sheet, a sheet
+// of material streams and a sheet of supporting evidence.
+func renderEprXlsx(snapshot models.EprReportSnapshot) ([]byte, error) {
+	location, err := time.LoadLocation(reportTimeZone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	workbook := documents.NewWorkbook(snapshot.GeneratedAt)
+
+	workbook.AddSheet("Summary", []string{"Field", "Value"}, [][]any{
+		{"Organization", snapshot.OrganizationName},
+		{"Address", snapshot.OrganizationAddress},
+		{"Period Start", formatReportTime(snapshot.PeriodStart, location)},
+		{"Period End", formatReportTime(snapshot.PeriodEnd, location)},
+		{"Cut-off", formatReportTime(snapshot.CutOff, location)},
+		{"Generated", formatReportTime(snapshot.GeneratedAt, location)},
+		{"Note", eprReportNote},
+	})
+
+	streams := [][]any{}
+
+	for _, stream := range snapshot.Streams {
+		streams = append(streams, []any{
+			stream.GWCode,
+			strings.Join(stream.Materials, ", "),
+			stream.Weight,
+			stream.Tonnage,
+			stream.CarbonAvoided,
+		})
+	}
+
+	workbook.AddSheet("Material Streams", []string{"GW Code", "Materials", "Weight (kg)", "Tonnage (t)", "Carbon Avoided (kg CO2e)"}, streams)
+
+	evidence := [][]any{}
+
+	for _, line := range snapshot.Evidence {
+		evidence = append(evidence, []any{
+			formatReportTime(line.RecordedAt, location),
+			string(line.Source),
+			line.RecordId.String(),
+			line.Counterparty,
+			line.Site,
+			line.GWCode,
+			line.Material,
+			line.CarbonFactor,
+			line.Weight,
+			line.Value,
+			line.CarbonAvoided,
+		})
+	}
+
+	workbook.AddSheet("Evidence", []string{"Recorded At", "Source", "Record", "Counterparty", "Site", "GW Code", "Material", "Carbon Factor", "Weight (kg)", "Value", "Carbon Avoided (kg CO2e)"}, evidence)
+
+	return workbook.Bytes()
+}
+
+func formatReportTime(value time.Time, location *time.Location) string {
+	return value.In(location).Format("2006-01-02 15:04")
+}
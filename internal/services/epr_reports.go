@@ -0,0 +1,327 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type eprReportsService interface {
+	Generate(organizationId uuid.UUID, generatedById uuid.UUID, payload models.CreateEprReportPayload) (uuid.UUID, error)
+	Download(reportId uuid.UUID, organizationId uuid.UUID, format models.EprReportFormat) ([]byte, error)
+	Find(reportId uuid.UUID) (*models.EprReport, error)
+	List(clauses ...clause.Expression) ([]models.EprReport, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type eprReports struct {
+	storage storage.Storage
+}
+
+func newEprReportsService(storage storage.Storage) eprReportsService {
+	return &eprReports{
+		storage: storage,
+	}
+}
+
+// eprEvidenceQuery selects the lines of the collections an organization bought
+// that were confirmed in the period and not voided by the cut-off, and of the
+// transactions it bought that were delivered in the period, at the weight it
+// received. Records and lines in the trash are left out.
+const eprEvidenceQuery = `
+	SELECT
+		@collections AS source,
+		collections.id AS record_id,
+		confirmed.created_at AS recorded_at,
+		users.name AS counterparty,
+		COALESCE(sites.name, '') AS site,
+		materials.gw_code AS gw_code,
+		materials.name AS material,
+		materials.carbon_factor AS carbon_factor,
+		collection_materials.weight AS weight,
+		collection_materials.value AS value
+	FROM collections
+	JOIN collection_transitions AS confirmed ON confirmed.collection_id = collections.id AND confirmed."to" = @confirmed
+	JOIN users ON users.id = collections.seller_id
+	LEFT JOIN sites ON sites.id = collections.site_id
+	JOIN collections_materials ON collections_materials.collection_id = collections.id
+	JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id
+	JOIN materials ON materials.id = collection_materials.material_id
+	WHERE collections.buyer_id = @organization
+	AND collections.deleted_at IS NULL
+	AND collection_materials.deleted_at IS NULL
+	AND confirmed.created_at >= @start AND confirmed.created_at < @end AND confirmed.created_at <= @cutOff
+	AND NOT EXISTS (
+		SELECT 1
+		FROM collection_transitions AS voided
+		WHERE voided.collection_id = collections.id
+		AND voided."to" = @voided
+		AND voided.created_at <= @cutOff
+	)
+	UNION ALL
+	SELECT
+		@transactions AS source,
+		transactions.id AS record_id,
+		delivered.created_at AS recorded_at,
+		organizations.name AS counterparty,
+		COALESCE(sites.name, '') AS site,
+		materials.gw_code AS gw_code,
+		materials.name AS material,
+		materials.carbon_factor AS carbon_factor,
+		COALESCE(transaction_materials.received_weight, transaction_materials.weight) AS weight,
+		transaction_materials.value AS value
+	FROM transactions
+	JOIN transaction_transitions AS delivered ON delivered.transaction_id = transactions.id AND delivered."to" = @delivered
+	JOIN organizations ON organizations.id = transactions.seller_id
+	LEFT JOIN sites ON sites.id = transactions.site_id
+	JOIN transactions_materials ON transactions_materials.transaction_id = transactions.id
+	JOIN transaction_materials ON transaction_materials.id = transactions_materials.transaction_material_id
+	JOIN materials ON materials.id = transaction_materials.material_id
+	WHERE transactions.buyer_id = @organization
+	AND transactions.deleted_at IS NULL
+	AND transaction_materials.deleted_at IS NULL
+	AND delivered.created_at >= @start AND delivered.created_at < @end AND delivered.created_at <= @cutOff
+	ORDER BY recorded_at, record_id, gw_code, material
+`
+
+// Generate selects the records of an organization for the period as they
+// stood at the cut-off, renders them as PDF and XLSX and records the report.
+// The cut-off defaults to now and may not be in the future, as records could
+// still be added before it.
+func (s *eprReports) Generate(organizationId uuid.UUID, generatedById uuid.UUID, payload models.CreateEprReportPayload) (uuid.UUID, error) {
+	generatedAt := time.Now()
+
+	cutOff := generatedAt
+
+	if payload.CutOff != nil {
+		cutOff = *payload.CutOff
+	}
+
+	if !payload.PeriodStart.Before(payload.PeriodEnd) {
+		return uuid.Nil, ErrInvalidReportRange
+	}
+
+	if cutOff.After(generatedAt) {
+		return uuid.Nil, ErrInvalidCutOff
+	}
+
+	var organization models.Organization
+
+	if err := s.storage.Postgres.
+		Preload("Address").
+		Where("id = ?", organizationId).
+		First(&organization).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	evidence := []models.EprEvidence{}
+
+	if err := s.storage.Postgres.
+		Raw(eprEvidenceQuery, map[string]any{
+			"collections":  models.CollectionsReportSource,
+			"transactions": models.TransactionsReportSource,
+			"confirmed":    models.CollectionConfirmed,
+			"voided":       models.CollectionVoided,
+			"delivered":    models.TransactionDelivered,
+			"organization": organizationId,
+			"start":        payload.PeriodStart,
+			"end":          payload.PeriodEnd,
+			"cutOff":       cutOff,
+		}).
+		Scan(&evidence).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	for index := range evidence {
+		evidence[index].CarbonAvoided = math.Round(evidence[index].Weight*evidence[index].CarbonFactor*100) / 100
+	}
+
+	snapshot := models.EprReportSnapshot{
+		OrganizationName:    organization.Name,
+		OrganizationAddress: formatAddress(organization.Address),
+		PeriodStart:         payload.PeriodStart,
+		PeriodEnd:           payload.PeriodEnd,
+		CutOff:              cutOff,
+		GeneratedAt:         generatedAt,
+		Streams:             eprStreams(evidence),
+		Evidence:            evidence,
+	}
+
+	pdf, err := renderEprPdf(snapshot)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	xlsx, err := renderEprXlsx(snapshot)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	report := models.EprReport{
+		OrganizationId: organizationId,
+		PeriodStart:    payload.PeriodStart,
+		PeriodEnd:      payload.PeriodEnd,
+		CutOff:         cutOff,
+		PdfChecksum:    checksum(pdf),
+		XlsxChecksum:   checksum(xlsx),
+		Snapshot:       snapshot,
+		Pdf:            pdf,
+		Xlsx:           xlsx,
+		GeneratedById:  generatedById,
+	}
+
+	for _, stream := range snapshot.Streams {
+		report.Tonnage += stream.Tonnage
+		report.CarbonAvoided += stream.CarbonAvoided
+	}
+
+	report.Tonnage = math.Round(report.Tonnage*1000) / 1000
+	report.CarbonAvoided = math.Round(report.CarbonAvoided*100) / 100
+
+	if err := s.storage.Postgres.
+		Create(&report).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	return report.Id, nil
+}
+
+// Download returns the file of a report of the organization in the format.
+func (s *eprReports) Download(reportId uuid.UUID, organizationId uuid.UUID, format models.EprReportFormat) ([]byte, error) {
+	if format != models.EprPdf && format != models.EprXlsx {
+		return nil, ErrUnsupportedExportFormat
+	}
+
+	var report models.EprReport
+
+	if err := s.storage.Postgres.
+		Select("id", string(format)).
+		Where("id = ? AND organization_id = ?", reportId, organizationId).
+		First(&report).Error; err != nil {
+		return nil, err
+	}
+
+	if format == models.EprXlsx {
+		return report.Xlsx, nil
+	}
+
+	return report.Pdf, nil
+}
+
+func (s *eprReports) Find(reportId uuid.UUID) (*models.EprReport, error) {
+	var report *models.EprReport
+
+	if err := s.storage.Postgres.
+		Omit("snapshot", "pdf", "xlsx").
+		Preload("GeneratedBy").
+		Where("id = ?", reportId).
+		First(&report).Error; err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *eprReports) List(clauses ...clause.Expression) ([]models.EprReport, error) {
+	var reports []models.EprReport
+
+	if err := s.storage.Postgres.
+		Omit("snapshot", "pdf", "xlsx").
+		Preload("GeneratedBy").
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (s *eprReports) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.EprReport{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// eprStreams totals the evidence of a report by GW code.
+func eprStreams(evidence []models.EprEvidence) []models.EprStream {
+	streams := []models.EprStream{}
+	indices := map[string]int{}
+
+	for _, line := range evidence {
+		index, ok := indices[line.GWCode]
+
+		if !ok {
+			index = len(streams)
+			indices[line.GWCode] = index
+			streams = append(streams, models.EprStream{
+				GWCode:    line.GWCode,
+				Materials: []string{},
+			})
+		}
+
+		stream := &streams[index]
+
+		if !slices.Contains(stream.Materials, line.Material) {
+			stream.Materials = append(stream.Materials, line.Material)
+		}
+
+		stream.Weight += line.Weight
+		stream.CarbonAvoided += line.CarbonAvoided
+	}
+
+	for index := range streams {
+		slices.Sort(streams[index].Materials)
+
+		streams[index].Weight = math.Round(streams[index].Weight*100) / 100
+		streams[index].Tonnage = math.Round(streams[index].Weight) / 1000
+		streams[index].CarbonAvoided = math.Round(streams[index].CarbonAvoided*100) / 100
+	}
+
+	slices.SortFunc(streams, func(a models.EprStream, b models.EprStream) int {
+		return strings.Compare(a.GWCode, b.GWCode)
+	})
+
+	return streams
+}
+
+func formatAddress(address *models.Address) string {
+	if address == nil {
+		return ""
+	}
+
+	parts := []string{address.LineOne}
+
+	if address.LineTwo != nil && *address.LineTwo != "" {
+		parts = append(parts, *address.LineTwo)
+	}
+
+	parts = append(parts, address.City, address.Province, address.ZipCode, address.Country)
+
+	return strings.Join(slices.DeleteFunc(parts, func(part string) bool {
+		return strings.TrimSpace(part) == ""
+	}), ", ")
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package services
+
+import "errors"
+
+var (
+	ErrCollectionLocked            = errors.New("collection has been confirmed and can no longer be modified")
+	ErrInvalidCollectionTransition = errors.New("collection cannot move to the requested status")
+	ErrVoidReasonRequired          = errors.New("a reason is required to void a collection")
+
+	ErrTransactionLocked            = errors.New("transaction is no longer a quote and can no longer be modified")
+	ErrInvalidTransactionTransition = errors.New("transaction cannot move to the requested status")
+	ErrNotTransactionParty          = errors.New("your organization is not permitted to perform this action on the transaction")
+	ErrQuoteExpired                 = errors.New("the quote for this transaction has expired")
+	ErrInvalidReceivedWeight        = errors.New("received weights must reference lines of the transaction and may not be negative")
+	ErrInvalidSettlementAmount      = errors.New("settlement amount must be positive and may not exceed the outstanding balance")
+
+	ErrInsufficientStock          = errors.New("the selling organization does not have enough stock on hand")
+	ErrInvalidInventoryAdjustment = errors.New("adjustments require a non-zero weight and a valid reason")
+
+	ErrInvalidSite = errors.New("site is invalid or is not operated by a party to this record")
+
+	ErrNoPayableCollections    = errors.New("there are no confirmed, unpaid collections of a single seller to pay out")
+	ErrCollectionInPayout      = errors.New("collection is already part of a payout and can only be paid or reversed through it")
+	ErrInvalidPayoutTransition = errors.New("payout cannot move to the requested status")
+	ErrReversalReasonRequired  = errors.New("a reason is required to reverse a payout")
+	ErrReceiptNumberRequired   = errors.New("a receipt number is required for cash payouts")
+	ErrReceiptNumberInUse      = errors.New("the receipt number has already been used for another payout")
+	ErrMissingBankDetails      = errors.New("your organization has no bank details to pay from")
+	ErrUnsupportedExportFormat = errors.New("the requested export format is not supported")
+
+	ErrUnsupportedBank           = errors.New("bank is not supported")
+	ErrInvalidBranchCode         = errors.New("branch code must be 6 digits")
+	ErrInvalidAccountType        = errors.New("account type must be cheque, savings or transmission")
+	ErrInvalidAccountNumber      = errors.New("account number is not valid for the selected bank")
+	ErrAccountVerificationFailed = errors.New("the account could not be verified with the bank")
+
+	ErrAddressExists    = errors.New("an address already exists for this owner")
+	ErrBankDetailsExist = errors.New("bank details already exist for this owner")
+
+	ErrUnsupportedCountry = errors.New("country is not supported")
+	ErrInvalidProvince    = errors.New("province is not a province of South Africa")
+	ErrInvalidPostalCode  = errors.New("postal code must be 4 digits")
+	ErrAddressNotGeocoded = errors.New("address could not be geocoded")
+
+	ErrInvalidPickup               = errors.New("pickups need an organization, a seller and expected materials with a positive weight")
+	ErrPickupAddressRequired       = errors.New("the seller needs an address before a pickup can be requested")
+	ErrInvalidCollector            = errors.New("pickups can only be assigned to collectors")
+	ErrInvalidPickupTransition     = errors.New("pickup cannot move to the requested status")
+	ErrCancellationReasonRequired  = errors.New("a reason is required to cancel a pickup")
+	ErrInvalidReportPeriod         = errors.New("report period is not supported")
+	ErrInvalidReportRange          = errors.New("report range must start before it ends")
+	ErrInvalidReportSource         = errors.New("report source must be collections or transactions")
+	ErrInvalidReportGrouping       = errors.New("report grouping is not available for this source")
+	ErrInvalidCutOff               = errors.New("report cut-off cannot be in the future")
+	ErrInvalidImportKind           = errors.New("import kind must be materials, users or collections")
+	ErrInvalidImportFile           = errors.New("the file must be a CSV or XLSX file with a header row and at least one row")
+	ErrInvalidImportMapping        = errors.New("the mapping must map known fields to columns of the file, including every required field")
+	ErrInvalidImportTransition     = errors.New("import cannot be changed in its current status")
+	ErrInvalidJobTransition        = errors.New("only dead jobs can be retried")
+	ErrInvalidWebhookUrl           = errors.New("webhook url must be an absolute https url of a public host")
+	ErrInvalidWebhookEvents        = errors.New("webhooks must subscribe to one or more known event types")
+	ErrWebhookAddressNotPublic     = errors.New("the webhook host does not resolve to a public address")
+	ErrInvalidScale                = errors.New("scales need a name and a site of the organization")
+	ErrInvalidScaleReading         = errors.New("scale readings need a gross or net weight of at least zero in kg, g, t or lb, with the net weight equal to the gross less the tare")
+	ErrScaleHasReadings            = errors.New("scales with readings cannot be deleted, deactivate them instead")
+	ErrScaleReadingUnavailable     = errors.New("the reading must be a stable reading of a scale at the site of the collection that no other line uses")
+	ErrScaleReadingTampered        = errors.New("the reading does not match its checksum and may have been altered")
+	ErrAttachmentsUnavailable      = errors.New("attachments cannot be stored because no file store is configured")
+	ErrInvalidAttachmentSize       = errors.New("attachments must be larger than 0 bytes and no larger than 10 MB")
+	ErrUnsupportedAttachmentType   = errors.New("attachments must be JPEG, PNG, GIF or WebP images or PDF documents")
+	ErrInvalidAttachmentCategory   = errors.New("attachment category must be photo, delivery_note, id_document or other")
+	ErrInvalidIdentityDocumentType = errors.New("identity document type must be sa_id, passport, asylum_permit or refugee_id")
+	ErrInvalidIdentityNumber       = errors.New("identity number is not valid for the document type")
+	ErrInvalidIdentityStatus       = errors.New("identity status must be verified or rejected")
+	ErrIdentityMissing             = errors.New("the user has not recorded an identity document")
+	ErrSellerNotVerified           = errors.New("regulated materials can only be collected from sellers whose identity has been verified")
+	ErrInvalidTrashResource        = errors.New("resource must be users, organizations, materials, roles, sites, collections, transactions, scales or webhooks")
+	ErrRestoreConflict             = errors.New("the record cannot be restored because it conflicts with a record created since it was deleted")
+	ErrVersionMismatch             = errors.New("the record has changed since it was read")
+	ErrIdempotencyKeyReused        = errors.New("the idempotency key has already been used for a different request")
+	ErrIdempotencyKeyInProgress    = errors.New("a request with the idempotency key is still being handled")
+	ErrCollectionExists            = errors.New("a collection with the id already exists")
+	ErrMissingReference            = errors.New("the record refers to a seller, buyer, site or material that does not exist")
+	ErrTooManySyncRecords          = errors.New("a sync may upload at most 100 records at a time")
+	ErrInvalidSyncId               = errors.New("records must be uploaded with the id the client gave them")
+	ErrSyncChanged                 = errors.New("the record has been changed since it was synced")
+	ErrSyncDeleted                 = errors.New("the record has been deleted since it was synced")
+	ErrSyncBuyerNotActive          = errors.New("collections may only be synced for the active organization as the buyer")
+	ErrSyncIdUnavailable           = errors.New("the id cannot be used for the record")
+)
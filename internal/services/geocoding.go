@@ -0,0 +1,281 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/connor-davis/threereco-nextgen/internal/constants"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Coordinates is a point on the map in decimal degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Geocoder resolves the coordinates of a normalised address. It returns
+// ErrAddressNotGeocoded when the address could not be found.
+type Geocoder interface {
+	Geocode(address models.Address) (*Coordinates, error)
+}
+
+// gazetteerGeocoder geocodes addresses to the town they are in using the
+// built in gazetteer, without calling out to an external service.
+type gazetteerGeocoder struct{}
+
+// NewGazetteerGeocoder returns a geocoder that resolves addresses to their
+// town with the built in gazetteer.
+func NewGazetteerGeocoder() Geocoder {
+	return gazetteerGeocoder{}
+}
+
+func (gazetteerGeocoder) Geocode(address models.Address) (*Coordinates, error) {
+	town := findTown(address.City, address.ProvinceCode)
+
+	if town == nil {
+		return nil, ErrAddressNotGeocoded
+	}
+
+	return &Coordinates{
+		Latitude:  town.Latitude,
+		Longitude: town.Longitude,
+	}, nil
+}
+
+// httpGeocoder geocodes addresses with a Nominatim compatible search API.
+type httpGeocoder struct {
+	baseUrl   string
+	userAgent string
+	client    *http.Client
+}
+
+// NewHttpGeocoder returns a geocoder that queries the search endpoint of a
+// Nominatim compatible service at baseUrl. Most public instances require a
+// descriptive user agent.
+func NewHttpGeocoder(baseUrl string, userAgent string) Geocoder {
+	return &httpGeocoder{
+		baseUrl:   strings.TrimSuffix(baseUrl, "/"),
+		userAgent: userAgent,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (g *httpGeocoder) Geocode(address models.Address) (*Coordinates, error) {
+	parts := []string{address.LineOne}
+
+	if address.LineTwo != nil && *address.LineTwo != "" {
+		parts = append(parts, *address.LineTwo)
+	}
+
+	parts = append(parts, address.City, address.ZipCode, address.Province, address.Country)
+
+	query := url.Values{}
+	query.Set("q", strings.Join(parts, ", "))
+	query.Set("format", "json")
+	query.Set("limit", "1")
+
+	if address.CountryCode != nil {
+		query.Set("countrycodes", strings.ToLower(*address.CountryCode))
+	}
+
+	request, err := http.NewRequest(http.MethodGet, g.baseUrl+"/search?"+query.Encode(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", g.userAgent)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := g.client.Do(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed with status %d", response.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ErrAddressNotGeocoded
+	}
+
+	latitude, err := strconv.ParseFloat(results[0].Lat, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	longitude, err := strconv.ParseFloat(results[0].Lon, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coordinates{
+		Latitude:  latitude,
+		Longitude: longitude,
+	}, nil
+}
+
+// prepareAddress normalises an address and geocodes it. An address that the
+// geocoder cannot find is still saved, without coordinates, but any other
+// geocoding error is returned.
+func prepareAddress(geocoder Geocoder, address *models.Address) error {
+	if err := normaliseAddress(address); err != nil {
+		return err
+	}
+
+	address.Latitude = nil
+	address.Longitude = nil
+
+	coordinates, err := geocoder.Geocode(*address)
+
+	if errors.Is(err, ErrAddressNotGeocoded) {
+		return nil
+	}
+
+	if err != nil {
+		log.Errorf("🔥 Failed to geocode address in %s: %s", address.City, err.Error())
+
+		return err
+	}
+
+	address.Latitude = &coordinates.Latitude
+	address.Longitude = &coordinates.Longitude
+
+	return nil
+}
+
+// normaliseAddress replaces the country and, for South African addresses, the
+// province with their registered names and codes and validates the postal
+// code. A missing province is inferred from the town when it is known.
+func normaliseAddress(address *models.Address) error {
+	address.LineOne = strings.TrimSpace(address.LineOne)
+	address.City = strings.TrimSpace(address.City)
+	address.ZipCode = strings.TrimSpace(address.ZipCode)
+	address.Province = strings.TrimSpace(address.Province)
+
+	countryIndex := slices.IndexFunc(constants.Countries, func(country models.Country) bool {
+		key := gazetteerKey(address.Country)
+
+		return key == gazetteerKey(country.Code) ||
+			key == gazetteerKey(country.Alpha3) ||
+			key == gazetteerKey(country.Name)
+	})
+
+	if countryIndex < 0 {
+		return ErrUnsupportedCountry
+	}
+
+	country := constants.Countries[countryIndex]
+
+	address.Country = country.Name
+	address.CountryCode = &country.Code
+	address.ProvinceCode = nil
+
+	if country.Code != "ZA" {
+		return nil
+	}
+
+	if len(address.ZipCode) != 4 || !isDigits(address.ZipCode) {
+		return ErrInvalidPostalCode
+	}
+
+	if address.Province == "" {
+		if town := findTown(address.City, nil); town != nil {
+			address.Province = town.ProvinceCode
+		}
+	}
+
+	provinceIndex := slices.IndexFunc(constants.Provinces, func(province models.Province) bool {
+		key := gazetteerKey(address.Province)
+
+		return key == gazetteerKey(province.Code) ||
+			key == gazetteerKey(strings.TrimPrefix(province.Code, "ZA-")) ||
+			key == gazetteerKey(province.Name)
+	})
+
+	if provinceIndex < 0 {
+		return ErrInvalidProvince
+	}
+
+	province := constants.Provinces[provinceIndex]
+
+	address.Province = province.Name
+	address.ProvinceCode = &province.Code
+
+	return nil
+}
+
+// findTown looks a town up in the gazetteer by name or alias, limited to the
+// province when one is given.
+func findTown(name string, provinceCode *string) *models.Town {
+	key := gazetteerKey(name)
+
+	for _, town := range constants.Towns {
+		if provinceCode != nil && town.ProvinceCode != *provinceCode {
+			continue
+		}
+
+		if key == gazetteerKey(town.Name) || slices.ContainsFunc(town.Aliases, func(alias string) bool {
+			return key == gazetteerKey(alias)
+		}) {
+			return &town
+		}
+	}
+
+	return nil
+}
+
+// gazetteerKey reduces a name to lower case letters and digits so that
+// spelling variants such as "KwaZulu-Natal" and "Kwazulu Natal" match.
+func gazetteerKey(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, value)
+}
+
+// addressColumns returns the columns written when an address is updated.
+func addressColumns(address models.Address) map[string]any {
+	return map[string]any{
+		"line_one":      address.LineOne,
+		"line_two":      address.LineTwo,
+		"city":          address.City,
+		"zip_code":      address.ZipCode,
+		"province":      address.Province,
+		"province_code": address.ProvinceCode,
+		"country":       address.Country,
+		"country_code":  address.CountryCode,
+		"latitude":      address.Latitude,
+		"longitude":     address.Longitude,
+	}
+}
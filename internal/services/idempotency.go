@@ -0,0 +1,122 @@
+package services
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyService interface {
+	Begin(scope string, key string, requestHash string, window time.Duration) (*models.IdempotencyKey, error)
+	Complete(id uuid.UUID, status int, headers map[string][]string, body []byte) error
+	Release(id uuid.UUID) error
+	Cleanup() error
+}
+
+type idempotency struct {
+	storage storage.Storage
+}
+
+func newIdempotencyService(storage storage.Storage) idempotencyService {
+	return &idempotency{
+		storage: storage,
+	}
+}
+
+// CleanupIdempotencyKeysJob is the kind of the scheduled jobs that delete
+// idempotency keys that have expired.
+const CleanupIdempotencyKeysJob = "idempotency-keys.cleanup"
+
+// idempotencyPendingTimeout is how long a key is held for a request that is
+// being handled. A key that is held for longer belongs to a request that never
+// finished, such as one that was being handled when the API stopped, and is
+// taken over by the next request sent with it.
+const idempotencyPendingTimeout = 10 * time.Minute
+
+// Begin claims a key for a request, which is kept for the given window. The
+// key is returned without a status when the request should be handled, and
+// with the response it was answered with when it has been handled before. A
+// key that is being used for another request is refused.
+func (s *idempotency) Begin(scope string, key string, requestHash string, window time.Duration) (*models.IdempotencyKey, error) {
+	now := time.Now()
+
+	if err := s.storage.Postgres.
+		Where("scope = ? AND key = ?", scope, key).
+		Where("expires_at < ? OR (status IS NULL AND created_at < ?)", now, now.Add(-idempotencyPendingTimeout)).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	idempotencyKey := models.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(window),
+	}
+
+	result := s.storage.Postgres.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&idempotencyKey)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return &idempotencyKey, nil
+	}
+
+	var existing models.IdempotencyKey
+
+	if err := s.storage.Postgres.
+		Where("scope = ? AND key = ?", scope, key).
+		First(&existing).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrIdempotencyKeyInProgress
+		}
+
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	if existing.Status == nil {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &existing, nil
+}
+
+// Complete records the response a request was answered with, which is
+// replayed when the request is retried.
+func (s *idempotency) Complete(id uuid.UUID, status int, headers map[string][]string, body []byte) error {
+	return s.storage.Postgres.
+		Model(&models.IdempotencyKey{Id: id}).
+		Select("status", "headers", "body").
+		Updates(&models.IdempotencyKey{
+			Status:  &status,
+			Headers: headers,
+			Body:    body,
+		}).Error
+}
+
+// Release gives up a key whose request failed, so that the request can be
+// retried with it.
+func (s *idempotency) Release(id uuid.UUID) error {
+	return s.storage.Postgres.
+		Where("id = ? AND status IS NULL", id).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+// Cleanup deletes the keys that have expired.
+func (s *idempotency) Cleanup() error {
+	return s.storage.Postgres.
+		Where("expires_at < ?", time.Now()).
+		Delete(&models.IdempotencyKey{}).Error
+}
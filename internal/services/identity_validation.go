@@ -0,0 +1,142 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+var identityDocumentTypes = []models.IdentityDocumentType{
+	models.SouthAfricanId,
+	models.Passport,
+	models.AsylumPermit,
+	models.RefugeeId,
+}
+
+var identityReviewStatuses = []models.IdentityStatus{
+	models.IdentityVerified,
+	models.IdentityRejected,
+}
+
+// setIdentity validates an identity document and records it on the user,
+// pending verification. South African ID numbers are checked against their
+// check digit and the date of birth they encode is recorded. Other documents
+// are only checked for a plausible number.
+func setIdentity(user *models.User, documentType models.IdentityDocumentType, number string) error {
+	if !slices.Contains(identityDocumentTypes, documentType) {
+		return ErrInvalidIdentityDocumentType
+	}
+
+	number = strings.ToUpper(strings.Join(strings.Fields(number), ""))
+
+	var dateOfBirth *time.Time
+
+	if documentType == models.SouthAfricanId {
+		birth, ok := southAfricanIdDateOfBirth(number, time.Now())
+
+		if !ok || !southAfricanIdValid(number) {
+			return ErrInvalidIdentityNumber
+		}
+
+		dateOfBirth = &birth
+	} else if len(number) < 5 || len(number) > 20 || !isAlphanumeric(number) {
+		return ErrInvalidIdentityNumber
+	}
+
+	masked := maskIdentityNumber(number)
+
+	user.IdentityDocumentType = &documentType
+	user.IdentityNumber = &number
+	user.MaskedIdentityNumber = &masked
+	user.DateOfBirth = dateOfBirth
+	user.IdentityStatus = models.IdentityPending
+	user.IdentityReviewedAt = nil
+	user.IdentityReviewedById = nil
+	user.IdentityNote = nil
+
+	return nil
+}
+
+// southAfricanIdValid checks the 13 digits of a South African ID number: the
+// citizenship digit must be 0 for citizens, 1 for permanent residents or 2
+// for refugees, and the last digit is a Luhn check digit.
+func southAfricanIdValid(number string) bool {
+	if len(number) != 13 || !isDigits(number) {
+		return false
+	}
+
+	if number[10] < '0' || number[10] > '2' {
+		return false
+	}
+
+	sum := 0
+
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+
+		if i%2 == 1 {
+			digit *= 2
+
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+	}
+
+	return sum%10 == 0
+}
+
+// southAfricanIdDateOfBirth reads the date of birth from the YYMMDD digits
+// that start a South African ID number. The century is the latest that does
+// not put the date of birth after now.
+func southAfricanIdDateOfBirth(number string, now time.Time) (time.Time, bool) {
+	if len(number) < 6 || !isDigits(number[:6]) {
+		return time.Time{}, false
+	}
+
+	year := int(number[0]-'0')*10 + int(number[1]-'0')
+	month := time.Month(int(number[2]-'0')*10 + int(number[3]-'0'))
+	day := int(number[4]-'0')*10 + int(number[5]-'0')
+
+	year += now.Year() / 100 * 100
+
+	birth := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	if birth.After(now) {
+		year -= 100
+
+		birth = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Dates such as the 31st of April are normalized by time.Date, which
+	// shows up as a different month or day.
+	if birth.Year() != year || birth.Month() != month || birth.Day() != day {
+		return time.Time{}, false
+	}
+
+	return birth, true
+}
+
+// maskIdentityNumber hides all but the last four characters of an identity
+// number.
+func maskIdentityNumber(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("*", len(number))
+	}
+
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+func isAlphanumeric(value string) bool {
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
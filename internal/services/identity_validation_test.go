@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+func TestSouthAfricanIdValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"8001015009087", true},
+		{"8001015009186", true},
+		{"8001015009285", true},
+		{"0002295123182", true},
+		{"8001015009088", false},
+		{"8001015009384", false},
+		{"800101500908", false},
+		{"80010150090871", false},
+		{"80010150090A7", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := southAfricanIdValid(test.number); got != test.want {
+			t.Errorf("southAfricanIdValid(%q) = %v, want %v", test.number, got, test.want)
+		}
+	}
+}
+
+func TestSouthAfricanIdDateOfBirth(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		number string
+		want   time.Time
+		ok     bool
+	}{
+		{"8001015009087", date(1980, 1, 1), true},
+		{"0002295123182", date(2000, 2, 29), true},
+		{"2403150123184", date(2024, 3, 15), true},
+		{"2503015009087", date(2025, 3, 1), true},
+		{"2503025009087", date(1925, 3, 2), true},
+		{"9912315009180", date(1999, 12, 31), true},
+		{"9502290123088", time.Time{}, false},
+		{"2504315009083", time.Time{}, false},
+		{"8013015009087", time.Time{}, false},
+		{"8000015009087", time.Time{}, false},
+		{"8001325009087", time.Time{}, false},
+		{"80A101", time.Time{}, false},
+		{"80010", time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := southAfricanIdDateOfBirth(test.number, now)
+
+		if ok != test.ok || !got.Equal(test.want) {
+			t.Errorf("southAfricanIdDateOfBirth(%q) = %s, %v, want %s, %v", test.number, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestMaskIdentityNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{"8001015009087", "*********9087"},
+		{"A12345", "**2345"},
+		{"12345", "*2345"},
+		{"1234", "****"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := maskIdentityNumber(test.number); got != test.want {
+			t.Errorf("maskIdentityNumber(%q) = %q, want %q", test.number, got, test.want)
+		}
+	}
+}
+
+func TestSetIdentity(t *testing.T) {
+	tests := []struct {
+		name         string
+		documentType models.IdentityDocumentType
+		number       string
+		wantNumber   string
+		wantBirth    bool
+		err          error
+	}{
+		{name: "south african id", documentType: models.SouthAfricanId, number: "800101 5009 087", wantNumber: "8001015009087", wantBirth: true},
+		{name: "bad check digit", documentType: models.SouthAfricanId, number: "8001015009088", err: ErrInvalidIdentityNumber},
+		{name: "impossible date", documentType: models.SouthAfricanId, number: "9502290123088", err: ErrInvalidIdentityNumber},
+		{name: "passport", documentType: models.Passport, number: "a01234567", wantNumber: "A01234567"},
+		{name: "short passport", documentType: models.Passport, number: "A123", err: ErrInvalidIdentityNumber},
+		{name: "passport with symbols", documentType: models.Passport, number: "A0123-4567", err: ErrInvalidIdentityNumber},
+		{name: "unknown document", documentType: "library-card", number: "12345678", err: ErrInvalidIdentityDocumentType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			note := "Blurry photo"
+			user := models.User{
+				IdentityStatus: models.IdentityRejected,
+				IdentityNote:   &note,
+			}
+
+			err := setIdentity(&user, test.documentType, test.number)
+
+			if err != test.err {
+				t.Fatalf("setIdentity() error = %v, want %v", err, test.err)
+			}
+
+			if err != nil {
+				if user.IdentityStatus != models.IdentityRejected {
+					t.Error("a rejected identity was changed")
+				}
+
+				return
+			}
+
+			if user.IdentityNumber == nil || *user.IdentityNumber != test.wantNumber {
+				t.Errorf("IdentityNumber = %v, want %s", user.IdentityNumber, test.wantNumber)
+			}
+
+			if masked := maskIdentityNumber(test.wantNumber); user.MaskedIdentityNumber == nil || *user.MaskedIdentityNumber != masked {
+				t.Errorf("MaskedIdentityNumber = %v, want %s", user.MaskedIdentityNumber, masked)
+			}
+
+			if (user.DateOfBirth != nil) != test.wantBirth {
+				t.Errorf("DateOfBirth = %v, want one recorded: %v", user.DateOfBirth, test.wantBirth)
+			}
+
+			if user.IdentityStatus != models.IdentityPending || user.IdentityNote != nil {
+				t.Errorf("identity is %s with note %v, want pending without a note", user.IdentityStatus, user.IdentityNote)
+			}
+		})
+	}
+}
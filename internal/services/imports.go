@@ -0,0 +1,1106 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/connor-davis/threereco-nextgen/internal/documents"
+	"github.com/connor-davis/threereco-nextgen/internal/jobs"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type importsService interface {
+	Fields(kind models.ImportKind) ([]models.ImportField, error)
+	Create(organizationId uuid.UUID, createdById uuid.UUID, kind models.ImportKind, fileName string, content []byte, mapping map[string]string) (uuid.UUID, error)
+	UpdateMapping(importId uuid.UUID, organizationId uuid.UUID, version int64, mapping map[string]string) error
+	Apply(importId uuid.UUID, organizationId uuid.UUID) error
+	ErrorReport(importId uuid.UUID, organizationId uuid.UUID) ([]byte, error)
+	Process(importId uuid.UUID, apply bool, final bool) error
+	Find(importId uuid.UUID) (*models.ImportJob, error)
+	List(clauses ...clause.Expression) ([]models.ImportJob, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type imports struct {
+	storage storage.Storage
+}
+
+func newImportsService(storage storage.Storage) importsService {
+	return &imports{
+		storage: storage,
+	}
+}
+
+var importFields = map[models.ImportKind][]models.ImportField{
+	models.MaterialsImport: {
+		{Key: "name", Label: "Name", Required: true, Description: "Name of the material."},
+		{Key: "gwCode", Label: "GW Code", Required: true, Description: "Waste classification code of the material."},
+		{Key: "carbonFactor", Label: "Carbon Factor", Required: true, Description: "Kilograms of carbon avoided per kilogram recycled."},
+		{Key: "regulated", Label: "Regulated", Description: "yes or no. Regulated materials are only collected from sellers whose identity has been verified. Defaults to no."},
+	},
+	models.UsersImport: {
+		{Key: "name", Label: "Name", Required: true, Description: "Full name of the user."},
+		{Key: "email", Label: "Email", Required: true, Description: "Email address, which must not belong to another user."},
+		{Key: "phone", Label: "Phone", Required: true, Description: "Phone number, which must not belong to another user."},
+		{Key: "type", Label: "Type", Description: "standard, collector or business. Defaults to standard."},
+		{Key: "role", Label: "Role", Description: "Name of a role of your organization to give the user."},
+		{Key: "identityDocumentType", Label: "Identity Document Type", Description: "sa_id, passport, asylum_permit or refugee_id. Required with an identity number."},
+		{Key: "identityNumber", Label: "Identity Number", Description: "Number of the identity document, which is recorded pending verification."},
+	},
+	models.CollectionsImport: {
+		{Key: "reference", Label: "Reference", Description: "Rows with the same reference are imported as the lines of one collection. Each row is a collection of its own when not mapped."},
+		{Key: "date", Label: "Date", Description: "Date the collection was made, such as 2024-03-31 or 31/03/2024, in South African time. Defaults to when the import is applied."},
+		{Key: "seller", Label: "Seller", Required: true, Description: "Email or phone number of the seller."},
+		{Key: "site", Label: "Site", Description: "Name of the site of your organization the collection was made at."},
+		{Key: "material", Label: "Material", Required: true, Description: "Name or GW code of the material."},
+		{Key: "weight", Label: "Weight", Required: true, Description: "Weight in kilograms."},
+		{Key: "value", Label: "Value", Required: true, Description: "Value paid for the material."},
+		{Key: "status", Label: "Status", Description: "draft, weighed, confirmed or paid. Historical collections are recorded in this status without moving stock. Defaults to draft."},
+	},
+}
+
+// ProcessImportJob is the kind of the background jobs that validate and apply
+// imports.
+const ProcessImportJob = "imports.process"
+
+type ProcessImportPayload struct {
+	ImportId uuid.UUID `json:"importId"`
+	Apply    bool      `json:"apply"`
+}
+
+// importProgressInterval is how many records are imported between updates of
+// the progress of an import.
+const importProgressInterval = 25
+
+const importSavePoint = "import_record"
+
+// errImportRolledBack rolls back the transaction of an import that was only
+// validated or that had errors.
+var errImportRolledBack = errors.New("import rolled back")
+
+func (s *imports) Fields(kind models.ImportKind) ([]models.ImportField, error) {
+	fields, ok := importFields[kind]
+
+	if !ok {
+		return nil, ErrInvalidImportKind
+	}
+
+	return fields, nil
+}
+
+// Create stores an uploaded file and maps its columns to the fields of the
+// kind, by the given mapping of fields to column names and otherwise by
+// matching column names to field names. Validation starts in the background
+// once every required field is mapped.
+func (s *imports) Create(organizationId uuid.UUID, createdById uuid.UUID, kind models.ImportKind, fileName string, content []byte, mapping map[string]string) (uuid.UUID, error) {
+	if _, ok := importFields[kind]; !ok {
+		return uuid.Nil, ErrInvalidImportKind
+	}
+
+	columns, _, err := readImportFile(content)
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	resolved := defaultImportMapping(kind, columns)
+
+	for field, column := range mapping {
+		if column == "" {
+			delete(resolved, field)
+
+			continue
+		}
+
+		resolved[field] = column
+	}
+
+	if err := checkImportMapping(kind, columns, resolved, false); err != nil {
+		return uuid.Nil, err
+	}
+
+	job := models.ImportJob{
+		OrganizationId: organizationId,
+		Kind:           kind,
+		Status:         models.ImportMapping,
+		FileName:       fileName,
+		Content:        content,
+		Columns:        columns,
+		Mapping:        resolved,
+		Errors:         []models.ImportRowError{},
+		CreatedById:    createdById,
+	}
+
+	mapped := checkImportMapping(kind, columns, resolved, true) == nil
+
+	if mapped {
+		job.Status = models.ImportValidating
+	}
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+
+		if !mapped {
+			return nil
+		}
+
+		_, err := jobs.Enqueue(tx, ProcessImportJob, ProcessImportPayload{
+			ImportId: job.Id,
+		})
+
+		return err
+	}); err != nil {
+		return uuid.Nil, err
+	}
+
+	return job.Id, nil
+}
+
+// UpdateMapping replaces the mapping of columns to fields, which must map
+// every required field, and validates the import again in the background.
+func (s *imports) UpdateMapping(importId uuid.UUID, organizationId uuid.UUID, version int64, mapping map[string]string) error {
+	var job models.ImportJob
+
+	if err := s.storage.Postgres.
+		Omit("content").
+		Where("id = ? AND organization_id = ?", importId, organizationId).
+		First(&job).Error; err != nil {
+		return err
+	}
+
+	// Starting the validation is itself limited to the statuses it can start
+	// from, so the version only needs to be checked against this read.
+	if job.Version != version {
+		return ErrVersionMismatch
+	}
+
+	if err := checkImportMapping(job.Kind, job.Columns, mapping, true); err != nil {
+		return err
+	}
+
+	return s.start(importId, false, []models.ImportStatus{
+		models.ImportMapping,
+		models.ImportValidated,
+		models.ImportInvalid,
+		models.ImportFailed,
+	}, mapping)
+}
+
+// Apply imports the rows of a validated import in the background.
+func (s *imports) Apply(importId uuid.UUID, organizationId uuid.UUID) error {
+	var job models.ImportJob
+
+	if err := s.storage.Postgres.
+		Omit("content").
+		Where("id = ? AND organization_id = ?", importId, organizationId).
+		First(&job).Error; err != nil {
+		return err
+	}
+
+	return s.start(importId, true, []models.ImportStatus{
+		models.ImportValidated,
+	}, job.Mapping)
+}
+
+// ErrorReport renders the errors of the last validation or application of an
+// import as CSV, naming the columns of the file the errors are in.
+func (s *imports) ErrorReport(importId uuid.UUID, organizationId uuid.UUID) ([]byte, error) {
+	var job models.ImportJob
+
+	if err := s.storage.Postgres.
+		Omit("content").
+		Where("id = ? AND organization_id = ?", importId, organizationId).
+		First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	records := [][]string{
+		{"Row", "Column", "Message"},
+	}
+
+	for _, rowError := range job.Errors {
+		column := job.Mapping[rowError.Field]
+
+		if column == "" {
+			column = rowError.Field
+		}
+
+		records = append(records, []string{
+			strconv.Itoa(rowError.Row),
+			column,
+			rowError.Message,
+		})
+	}
+
+	return writeCsv(records)
+}
+
+func (s *imports) Find(importId uuid.UUID) (*models.ImportJob, error) {
+	var job *models.ImportJob
+
+	if err := s.storage.Postgres.
+		Omit("content").
+		Preload("CreatedBy").
+		Where("id = ?", importId).
+		First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *imports) List(clauses ...clause.Expression) ([]models.ImportJob, error) {
+	var jobs []models.ImportJob
+
+	if err := s.storage.Postgres.
+		Omit("content", "errors").
+		Preload("CreatedBy").
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (s *imports) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.ImportJob{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// start moves an import in one of the given statuses to validating or applying
+// with the mapping, clearing the results of its last run, and enqueues the job
+// that processes it in the same transaction.
+func (s *imports) start(importId uuid.UUID, apply bool, from []models.ImportStatus, mapping map[string]string) error {
+	status := models.ImportValidating
+
+	if apply {
+		status = models.ImportApplying
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.ImportJob{}).
+			Select("status", "mapping", "total_rows", "processed_rows", "failed_rows", "errors", "failure").
+			Where("id = ? AND status IN ?", importId, from).
+			Updates(&models.ImportJob{
+				Status:  status,
+				Mapping: mapping,
+				Errors:  []models.ImportRowError{},
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrInvalidImportTransition
+		}
+
+		_, err := jobs.Enqueue(tx, ProcessImportJob, ProcessImportPayload{
+			ImportId: importId,
+			Apply:    apply,
+		})
+
+		return err
+	})
+}
+
+// Process validates or applies an import. An unexpected error is recorded as
+// the failure of the import and returned so that the job is tried again. The
+// import stays running while it is retried and only fails on the final attempt.
+func (s *imports) Process(importId uuid.UUID, apply bool, final bool) error {
+	if err := s.process(importId, apply); err != nil {
+		updates := map[string]any{
+			"failure": err.Error(),
+		}
+
+		if final {
+			updates["status"] = models.ImportFailed
+		}
+
+		if err := s.storage.Postgres.
+			Model(&models.ImportJob{}).
+			Where("id = ?", importId).
+			Updates(updates).Error; err != nil {
+			log.Errorf("🔥 Error recording failure of import %s: %s", importId, err.Error())
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// process imports every record of an import in a transaction, each behind a
+// savepoint so that a record with errors is undone without affecting the
+// others. The transaction is only committed when applying an import without
+// errors.
+func (s *imports) process(importId uuid.UUID, apply bool) error {
+	var job models.ImportJob
+
+	if err := s.storage.Postgres.
+		Where("id = ?", importId).
+		First(&job).Error; err != nil {
+		return err
+	}
+
+	// The import was changed since the job was enqueued, so a later job
+	// processes it.
+	if (apply && job.Status != models.ImportApplying) || (!apply && job.Status != models.ImportValidating) {
+		return nil
+	}
+
+	columns, rows, err := readImportFile(job.Content)
+
+	if err != nil {
+		return err
+	}
+
+	mapped := mapImportRows(columns, rows, job.Mapping)
+	records := groupImportRecords(job.Kind, mapped)
+
+	if err := s.storage.Postgres.
+		Model(&models.ImportJob{}).
+		Where("id = ?", importId).
+		Updates(map[string]any{
+			"total_rows":     len(mapped),
+			"processed_rows": 0,
+		}).Error; err != nil {
+		return err
+	}
+
+	rowErrors := []models.ImportRowError{}
+	processed := 0
+
+	err = s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		for index, record := range records {
+			if err := tx.SavePoint(importSavePoint).Error; err != nil {
+				return err
+			}
+
+			recordErrors := requireImportFields(job.Kind, record)
+
+			if len(recordErrors) == 0 {
+				recordErrors = importRecord(tx, &job, record)
+			}
+
+			if len(recordErrors) > 0 {
+				rowErrors = append(rowErrors, recordErrors...)
+
+				if err := tx.RollbackTo(importSavePoint).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Exec("RELEASE SAVEPOINT " + importSavePoint).Error; err != nil {
+				return err
+			}
+
+			processed += len(record)
+
+			if (index+1)%importProgressInterval == 0 {
+				if err := s.storage.Postgres.
+					Model(&models.ImportJob{}).
+					Where("id = ?", importId).
+					Update("processed_rows", processed).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if !apply || len(rowErrors) > 0 {
+			return errImportRolledBack
+		}
+
+		return nil
+	})
+
+	if err != nil && err != errImportRolledBack {
+		return err
+	}
+
+	slices.SortStableFunc(rowErrors, func(a models.ImportRowError, b models.ImportRowError) int {
+		return a.Row - b.Row
+	})
+
+	failedRows := map[int]bool{}
+
+	for _, rowError := range rowErrors {
+		failedRows[rowError.Row] = true
+	}
+
+	job.ProcessedRows = processed
+	job.FailedRows = len(failedRows)
+	job.Errors = rowErrors
+	job.Failure = nil
+
+	switch {
+	case len(rowErrors) > 0:
+		job.Status = models.ImportInvalid
+	case apply:
+		appliedAt := time.Now()
+
+		job.Status = models.ImportApplied
+		job.AppliedAt = &appliedAt
+	default:
+		job.Status = models.ImportValidated
+	}
+
+	return s.storage.Postgres.
+		Model(&models.ImportJob{}).
+		Select("status", "processed_rows", "failed_rows", "errors", "failure", "applied_at").
+		Where("id = ?", importId).
+		Updates(&job).Error
+}
+
+// importRow is a row of an import with its values by field.
+type importRow struct {
+	number int
+	values map[string]string
+}
+
+func (r importRow) error(field string, message string) models.ImportRowError {
+	return models.ImportRowError{
+		Row:     r.number,
+		Field:   field,
+		Message: message,
+	}
+}
+
+// readImportFile reads the header and rows of a CSV or XLSX file. Files are
+// recognised by their content, as XLSX files are zip archives.
+func readImportFile(content []byte) ([]string, [][]string, error) {
+	var rows [][]string
+
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		sheet, err := documents.ReadSheet(content)
+
+		if err != nil {
+			return nil, nil, ErrInvalidImportFile
+		}
+
+		rows = sheet
+	} else {
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+
+		records, err := reader.ReadAll()
+
+		if err != nil {
+			return nil, nil, ErrInvalidImportFile
+		}
+
+		rows = records
+	}
+
+	if len(rows) < 2 {
+		return nil, nil, ErrInvalidImportFile
+	}
+
+	columns := []string{}
+
+	for _, column := range rows[0] {
+		columns = append(columns, strings.TrimSpace(column))
+	}
+
+	return columns, rows[1:], nil
+}
+
+// defaultImportMapping maps the fields of a kind to the columns whose names
+// match their keys or labels, ignoring case, spaces and punctuation.
+func defaultImportMapping(kind models.ImportKind, columns []string) map[string]string {
+	mapping := map[string]string{}
+
+	for _, field := range importFields[kind] {
+		for _, column := range columns {
+			name := normaliseImportName(column)
+
+			if name != "" && (name == normaliseImportName(field.Key) || name == normaliseImportName(field.Label)) {
+				mapping[field.Key] = column
+
+				break
+			}
+		}
+	}
+
+	return mapping
+}
+
+func normaliseImportName(name string) string {
+	return strings.Map(func(character rune) rune {
+		if unicode.IsLetter(character) || unicode.IsDigit(character) {
+			return unicode.ToLower(character)
+		}
+
+		return -1
+	}, name)
+}
+
+// checkImportMapping returns ErrInvalidImportMapping when the mapping names a
+// field the kind does not have or a column the file does not have, or when
+// complete is set and a required field is not mapped.
+func checkImportMapping(kind models.ImportKind, columns []string, mapping map[string]string, complete bool) error {
+	fields := importFields[kind]
+
+	for field, column := range mapping {
+		if !slices.ContainsFunc(fields, func(candidate models.ImportField) bool {
+			return candidate.Key == field
+		}) {
+			return ErrInvalidImportMapping
+		}
+
+		if !slices.Contains(columns, column) {
+			return ErrInvalidImportMapping
+		}
+	}
+
+	if complete {
+		for _, field := range fields {
+			if field.Required && mapping[field.Key] == "" {
+				return ErrInvalidImportMapping
+			}
+		}
+	}
+
+	return nil
+}
+
+// mapImportRows returns the values of the mapped columns of each row that is
+// not empty. Rows are numbered as in the file.
+func mapImportRows(columns []string, rows [][]string, mapping map[string]string) []importRow {
+	mapped := []importRow{}
+
+	for index, row := range rows {
+		if !slices.ContainsFunc(row, func(cell string) bool {
+			return strings.TrimSpace(cell) != ""
+		}) {
+			continue
+		}
+
+		values := map[string]string{}
+
+		for field, column := range mapping {
+			position := slices.Index(columns, column)
+
+			if position >= 0 && position < len(row) {
+				values[field] = strings.TrimSpace(row[position])
+			}
+		}
+
+		mapped = append(mapped, importRow{
+			number: index + 2,
+			values: values,
+		})
+	}
+
+	return mapped
+}
+
+// groupImportRecords splits rows into the records they are imported as. The
+// rows of a collection are grouped by their reference, in the order each
+// reference first appears. Every other row is a record of its own.
+func groupImportRecords(kind models.ImportKind, rows []importRow) [][]importRow {
+	records := [][]importRow{}
+	references := map[string]int{}
+
+	for _, row := range rows {
+		reference := row.values["reference"]
+
+		if kind != models.CollectionsImport || reference == "" {
+			records = append(records, []importRow{row})
+
+			continue
+		}
+
+		index, ok := references[reference]
+
+		if !ok {
+			index = len(records)
+			references[reference] = index
+			records = append(records, []importRow{})
+		}
+
+		records[index] = append(records[index], row)
+	}
+
+	return records
+}
+
+func requireImportFields(kind models.ImportKind, record []importRow) []models.ImportRowError {
+	rowErrors := []models.ImportRowError{}
+
+	for _, row := range record {
+		for _, field := range importFields[kind] {
+			if field.Required && row.values[field.Key] == "" {
+				rowErrors = append(rowErrors, row.error(field.Key, field.Label+" is required"))
+			}
+		}
+	}
+
+	return rowErrors
+}
+
+// importRecord creates a record through the services of the import's kind,
+// bound to the transaction of the import.
+func importRecord(tx *gorm.DB, job *models.ImportJob, record []importRow) []models.ImportRowError {
+	bound := storage.Storage{
+		Postgres: tx,
+	}
+
+	switch job.Kind {
+	case models.MaterialsImport:
+		return importMaterial(newMaterialsService(bound), record[0])
+	case models.UsersImport:
+		return importUser(tx, newUsersService(bound), job, record[0])
+	case models.CollectionsImport:
+		return importCollection(tx, newCollectionsService(bound), job, record)
+	default:
+		return []models.ImportRowError{record[0].error("", ErrInvalidImportKind.Error())}
+	}
+}
+
+func importMaterial(materials materialsService, row importRow) []models.ImportRowError {
+	if _, err := parseImportNumber(row.values["carbonFactor"]); err != nil {
+		return []models.ImportRowError{row.error("carbonFactor", "Carbon Factor must be a number")}
+	}
+
+	regulated, err := parseImportBool(row.values["regulated"])
+
+	if err != nil {
+		return []models.ImportRowError{row.error("regulated", "Regulated must be yes or no")}
+	}
+
+	if _, err := materials.Create(models.CreateMaterialPayload{
+		Name:         row.values["name"],
+		GWCode:       row.values["gwCode"],
+		CarbonFactor: row.values["carbonFactor"],
+		Regulated:    regulated,
+	}); err != nil {
+		return []models.ImportRowError{row.error("", err.Error())}
+	}
+
+	return nil
+}
+
+func importUser(tx *gorm.DB, users usersService, job *models.ImportJob, row importRow) []models.ImportRowError {
+	rowErrors := []models.ImportRowError{}
+
+	email := strings.ToLower(row.values["email"])
+	phone := row.values["phone"]
+
+	if !strings.Contains(email, "@") {
+		rowErrors = append(rowErrors, row.error("email", "Email is not an email address"))
+	}
+
+	for _, check := range []struct {
+		field string
+		query string
+		value string
+	}{
+		{field: "email", query: "LOWER(email) = ?", value: email},
+		{field: "phone", query: "phone = ?", value: phone},
+	} {
+		var count int64
+
+		if err := tx.
+			Model(&models.User{}).
+			Where(check.query, check.value).
+			Count(&count).Error; err != nil {
+			return []models.ImportRowError{row.error("", err.Error())}
+		}
+
+		if count > 0 {
+			rowErrors = append(rowErrors, row.error(check.field, "a user with this "+check.field+" already exists"))
+		}
+	}
+
+	userType := models.Standard
+
+	if value := strings.ToLower(row.values["type"]); value != "" {
+		userType = models.UserType(value)
+
+		if !slices.Contains([]models.UserType{models.Standard, models.Collector, models.Business}, userType) {
+			rowErrors = append(rowErrors, row.error("type", "Type must be standard, collector or business"))
+		}
+	}
+
+	var roles []models.Role
+
+	if name := row.values["role"]; name != "" {
+		var role models.Role
+
+		if err := tx.
+			Joins("JOIN organization_roles ON organization_roles.role_id = roles.id").
+			Where("organization_roles.organization_id = ? AND LOWER(roles.name) = LOWER(?)", job.OrganizationId, name).
+			First(&role).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				return []models.ImportRowError{row.error("", err.Error())}
+			}
+
+			rowErrors = append(rowErrors, row.error("role", "your organization has no role with this name"))
+		} else {
+			roles = []models.Role{role}
+		}
+	}
+
+	var identityDocumentType *models.IdentityDocumentType
+	var identityNumber *string
+
+	if value := row.values["identityNumber"]; value != "" {
+		documentType := models.IdentityDocumentType(strings.ToLower(row.values["identityDocumentType"]))
+
+		if err := setIdentity(&models.User{}, documentType, value); err != nil {
+			field := "identityNumber"
+
+			if err == ErrInvalidIdentityDocumentType {
+				field = "identityDocumentType"
+			}
+
+			rowErrors = append(rowErrors, row.error(field, err.Error()))
+		} else {
+			identityDocumentType = &documentType
+			identityNumber = &value
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return rowErrors
+	}
+
+	password := make([]byte, 24)
+
+	if _, err := rand.Read(password); err != nil {
+		return []models.ImportRowError{row.error("", err.Error())}
+	}
+
+	userId, err := users.Create(models.CreateUserPayload{
+		Name:                 row.values["name"],
+		Email:                email,
+		Phone:                phone,
+		Password:             hex.EncodeToString(password),
+		Roles:                roles,
+		Type:                 userType,
+		IdentityDocumentType: identityDocumentType,
+		IdentityNumber:       identityNumber,
+	})
+
+	if err != nil {
+		return []models.ImportRowError{row.error("", err.Error())}
+	}
+
+	if err := tx.
+		Model(&models.Organization{Base: models.Base{Id: job.OrganizationId}}).
+		Association("Users").
+		Append(&models.User{Base: models.Base{Id: userId}}); err != nil {
+		return []models.ImportRowError{row.error("", err.Error())}
+	}
+
+	if err := tx.
+		Model(&models.User{}).
+		Where("id = ?", userId).
+		Update("active_organization", job.OrganizationId).Error; err != nil {
+		return []models.ImportRowError{row.error("", err.Error())}
+	}
+
+	// Users created with a role of the organization were already published
+	// to it.
+	if len(roles) == 0 {
+		if err := publishUserCreated(tx, userId, []uuid.UUID{job.OrganizationId}); err != nil {
+			return []models.ImportRowError{row.error("", err.Error())}
+		}
+	}
+
+	return nil
+}
+
+// importedCollectionStatuses are the statuses a collection may be imported in,
+// in the order it moves through them.
+var importedCollectionStatuses = []models.CollectionStatus{
+	models.CollectionDraft,
+	models.CollectionWeighed,
+	models.CollectionConfirmed,
+	models.CollectionPaid,
+}
+
+// importCollection creates a collection bought by the organization of the
+// import from the rows sharing a reference. Rows after the first may leave
+// the seller, site, date and status empty, but may not differ from it.
+// Imported collections are recorded in their status without moving stock, as
+// historical stock has long since been sold.
+func importCollection(tx *gorm.DB, collections collectionsService, job *models.ImportJob, record []importRow) []models.ImportRowError {
+	rowErrors := []models.ImportRowError{}
+	first := record[0]
+
+	for _, row := range record[1:] {
+		for _, field := range []string{"seller", "site", "date", "status"} {
+			if value := row.values[field]; value != "" && !strings.EqualFold(value, first.values[field]) {
+				rowErrors = append(rowErrors, row.error(field, "differs from the first row of the collection"))
+			}
+		}
+	}
+
+	var seller models.User
+
+	if err := tx.
+		Where("LOWER(email) = LOWER(?) OR phone = ?", first.values["seller"], first.values["seller"]).
+		First(&seller).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return []models.ImportRowError{first.error("", err.Error())}
+		}
+
+		rowErrors = append(rowErrors, first.error("seller", "no user has this email or phone number"))
+	}
+
+	var siteId *uuid.UUID
+
+	if name := first.values["site"]; name != "" {
+		var site models.Site
+
+		if err := tx.
+			Where("organization_id = ? AND LOWER(name) = LOWER(?)", job.OrganizationId, name).
+			First(&site).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				return []models.ImportRowError{first.error("", err.Error())}
+			}
+
+			rowErrors = append(rowErrors, first.error("site", "your organization has no site with this name"))
+		} else {
+			siteId = &site.Id
+		}
+	}
+
+	var date *time.Time
+
+	if value := first.values["date"]; value != "" {
+		parsed, err := parseImportDate(value)
+
+		if err != nil {
+			rowErrors = append(rowErrors, first.error("date", "Date is not a date"))
+		} else if parsed.After(time.Now()) {
+			rowErrors = append(rowErrors, first.error("date", "Date cannot be in the future"))
+		} else {
+			date = &parsed
+		}
+	}
+
+	status := models.CollectionDraft
+
+	if value := strings.ToLower(first.values["status"]); value != "" {
+		status = models.CollectionStatus(value)
+
+		if !slices.Contains(importedCollectionStatuses, status) {
+			rowErrors = append(rowErrors, first.error("status", "Status must be draft, weighed, confirmed or paid"))
+		}
+	}
+
+	lines := []models.CreateCollectionMaterialPayload{}
+
+	for _, row := range record {
+		material, err := findImportMaterial(tx, row.values["material"])
+
+		if err != nil {
+			if err != gorm.ErrRecordNotFound && err != errAmbiguousMaterial {
+				return []models.ImportRowError{row.error("", err.Error())}
+			}
+
+			message := "no material has this name or GW code"
+
+			if err == errAmbiguousMaterial {
+				message = "more than one material has this GW code, use the name of the material"
+			}
+
+			rowErrors = append(rowErrors, row.error("material", message))
+		}
+
+		weight, err := parseImportNumber(row.values["weight"])
+
+		if err != nil || weight <= 0 {
+			rowErrors = append(rowErrors, row.error("weight", "Weight must be a number greater than zero"))
+		}
+
+		value, err := parseImportNumber(row.values["value"])
+
+		if err != nil || value < 0 {
+			rowErrors = append(rowErrors, row.error("value", "Value must be a number of at least zero"))
+		}
+
+		if material != nil {
+			lines = append(lines, models.CreateCollectionMaterialPayload{
+				MaterialId: material.Id,
+				Weight:     weight,
+				Value:      value,
+			})
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return rowErrors
+	}
+
+	collectionId, err := collections.Create(models.CreateCollectionPayload{
+		SellerId:  seller.Id,
+		BuyerId:   job.OrganizationId,
+		SiteId:    siteId,
+		Materials: lines,
+	})
+
+	if err != nil {
+		return []models.ImportRowError{first.error("", err.Error())}
+	}
+
+	collection := models.Collection{
+		Base: models.Base{
+			Id: collectionId,
+		},
+		Status: models.CollectionDraft,
+	}
+
+	for _, next := range importedCollectionStatuses[1 : slices.Index(importedCollectionStatuses, status)+1] {
+		if err := recordCollectionTransition(tx, &collection, next, job.CreatedById, nil); err != nil {
+			return []models.ImportRowError{first.error("", err.Error())}
+		}
+
+		collection.Status = next
+	}
+
+	if date != nil {
+		if err := tx.
+			Model(&models.Collection{}).
+			Where("id = ?", collectionId).
+			UpdateColumn("created_at", *date).Error; err != nil {
+			return []models.ImportRowError{first.error("", err.Error())}
+		}
+
+		if err := tx.
+			Model(&models.CollectionTransition{}).
+			Where("collection_id = ?", collectionId).
+			UpdateColumn("created_at", *date).Error; err != nil {
+			return []models.ImportRowError{first.error("", err.Error())}
+		}
+	}
+
+	return nil
+}
+
+var (
+	errAmbiguousMaterial = errors.New("more than one material matches")
+	errInvalidImportDate = errors.New("not a date")
+)
+
+// findImportMaterial finds a material by its name or, failing that, by its GW
+// code, ignoring case.
+func findImportMaterial(tx *gorm.DB, value string) (*models.Material, error) {
+	var materials []models.Material
+
+	if err := tx.
+		Where("LOWER(name) = LOWER(?)", value).
+		Limit(2).
+		Find(&materials).Error; err != nil {
+		return nil, err
+	}
+
+	if len(materials) == 0 {
+		if err := tx.
+			Where("LOWER(gw_code) = LOWER(?)", value).
+			Limit(2).
+			Find(&materials).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	switch len(materials) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return &materials[0], nil
+	default:
+		return nil, errAmbiguousMaterial
+	}
+}
+
+// parseImportNumber parses a number written with either a decimal point or a
+// decimal comma, ignoring spaces used to group thousands.
+func parseImportNumber(value string) (float64, error) {
+	value = strings.ReplaceAll(value, " ", "")
+
+	if strings.Contains(value, ",") && !strings.Contains(value, ".") {
+		value = strings.ReplaceAll(value, ",", ".")
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseImportBool parses yes or no, as well as true or false, 1 or 0 and y or
+// n. An empty value is no.
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "no", "n", "false", "0":
+		return false, nil
+	case "yes", "y", "true", "1":
+		return true, nil
+	}
+
+	return false, strconv.ErrSyntax
+}
+
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"02/01/2006 15:04",
+	"02/01/2006",
+}
+
+// parseImportDate parses a date in South African time, or a date as stored by
+// spreadsheets, in days since 30 December 1899.
+func parseImportDate(value string) (time.Time, error) {
+	location, err := time.LoadLocation(reportTimeZone)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed, nil
+		}
+	}
+
+	days, err := strconv.ParseFloat(value, 64)
+
+	if err != nil || days < 1 || days >= 2958466 {
+		return time.Time{}, errInvalidImportDate
+	}
+
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, location).
+		Add(time.Duration(days * float64(24*time.Hour))).Round(time.Second), nil
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type inventoryService interface {
+	Adjust(organizationId uuid.UUID, createdById uuid.UUID, payload models.CreateInventoryAdjustmentPayload) (uuid.UUID, error)
+	Balances(organizationId uuid.UUID, asOf time.Time, clauses ...clause.Expression) ([]models.InventoryBalance, error)
+	List(clauses ...clause.Expression) ([]models.InventoryEntry, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type inventory struct {
+	storage storage.Storage
+}
+
+func newInventoryService(storage storage.Storage) inventoryService {
+	return &inventory{
+		storage: storage,
+	}
+}
+
+var inventoryAdjustmentReasons = []models.InventoryAdjustmentReason{
+	models.Shrinkage,
+	models.MoistureLoss,
+	models.Baling,
+	models.Correction,
+}
+
+func (s *inventory) Adjust(organizationId uuid.UUID, createdById uuid.UUID, payload models.CreateInventoryAdjustmentPayload) (uuid.UUID, error) {
+	if payload.Weight == 0 || !slices.Contains(inventoryAdjustmentReasons, payload.Reason) {
+		return uuid.Nil, ErrInvalidInventoryAdjustment
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, payload.SiteId, organizationId); err != nil {
+		return uuid.Nil, err
+	}
+
+	occurredAt := time.Now()
+
+	if payload.OccurredAt != nil {
+		occurredAt = *payload.OccurredAt
+	}
+
+	entry := models.InventoryEntry{
+		OrganizationId: organizationId,
+		MaterialId:     payload.MaterialId,
+		SiteId:         payload.SiteId,
+		Type:           models.InventoryAdjustment,
+		Reason:         &payload.Reason,
+		Weight:         payload.Weight,
+		Value:          payload.Value,
+		SourceType:     models.AdjustmentSource,
+		Notes:          payload.Notes,
+		OccurredAt:     occurredAt,
+		CreatedById:    &createdById,
+	}
+
+	if err := s.storage.Postgres.
+		Create(&entry).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	return entry.Id, nil
+}
+
+// Balances returns the stock on hand per material and site for an organization
+// as of the given time. Additional clauses narrow the entries that are summed.
+func (s *inventory) Balances(organizationId uuid.UUID, asOf time.Time, clauses ...clause.Expression) ([]models.InventoryBalance, error) {
+	var balances []models.InventoryBalance
+
+	if err := s.storage.Postgres.
+		Model(&models.InventoryEntry{}).
+		Select(`
+			inventory_entries.material_id AS material_id,
+			materials.name AS material_name,
+			inventory_entries.site_id AS site_id,
+			sites.name AS site_name,
+			SUM(inventory_entries.weight) AS weight,
+			SUM(inventory_entries.value) AS value
+		`).
+		Joins("JOIN materials ON materials.id = inventory_entries.material_id").
+		Joins("LEFT JOIN sites ON sites.id = inventory_entries.site_id").
+		Where("inventory_entries.organization_id = ?", organizationId).
+		Where("inventory_entries.occurred_at <= ?", asOf).
+		Clauses(clauses...).
+		Group("inventory_entries.material_id, materials.name, inventory_entries.site_id, sites.name").
+		Order("materials.name ASC, sites.name ASC NULLS FIRST").
+		Scan(&balances).Error; err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+func (s *inventory) List(clauses ...clause.Expression) ([]models.InventoryEntry, error) {
+	var entries []models.InventoryEntry
+
+	if err := s.storage.Postgres.
+		Preload("Material").
+		Clauses(clauses...).
+		Order("occurred_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *inventory) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.InventoryEntry{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ensureStockAvailable returns ErrInsufficientStock when the organization does
+// not have enough of each requested material on hand. The stock of each
+// material is locked first, and inside a transaction stays locked until it
+// ends, so that two deliveries cannot both pass the check before either has
+// issued the stock.
+func ensureStockAvailable(tx *gorm.DB, organizationId uuid.UUID, requested map[uuid.UUID]float64) error {
+	materialIds := make([]uuid.UUID, 0, len(requested))
+
+	for materialId := range requested {
+		materialIds = append(materialIds, materialId)
+	}
+
+	// Locks are always taken in the same order so that deliveries of the same
+	// materials cannot deadlock.
+	slices.SortFunc(materialIds, func(a uuid.UUID, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, materialId := range materialIds {
+		if err := tx.
+			Exec("SELECT pg_advisory_xact_lock(hashtext(?))", organizationId.String()+materialId.String()).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, materialId := range materialIds {
+		weight := requested[materialId]
+
+		var onHand float64
+
+		if err := tx.
+			Model(&models.InventoryEntry{}).
+			Select("COALESCE(SUM(weight), 0)").
+			Where("organization_id = ? AND material_id = ?", organizationId, materialId).
+			Scan(&onHand).Error; err != nil {
+			return err
+		}
+
+		if weight > onHand {
+			return ErrInsufficientStock
+		}
+	}
+
+	return nil
+}
+
+// postCollectionReceipts posts a receipt to the buying organization for every
+// line of a confirmed collection.
+func postCollectionReceipts(tx *gorm.DB, collection *models.Collection, occurredAt time.Time) error {
+	var lines []models.CollectionMaterial
+
+	if err := tx.Model(collection).Association("Materials").Find(&lines); err != nil {
+		return err
+	}
+
+	entries := []models.InventoryEntry{}
+
+	for _, line := range lines {
+		entries = append(entries, models.InventoryEntry{
+			OrganizationId: collection.BuyerId,
+			MaterialId:     line.MaterialId,
+			SiteId:         collection.SiteId,
+			Type:           models.InventoryReceipt,
+			Weight:         line.Weight,
+			Value:          line.Value,
+			SourceType:     models.CollectionMaterialSource,
+			SourceId:       &line.Id,
+			OccurredAt:     occurredAt,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return tx.Create(&entries).Error
+}
+
+// reverseCollectionReceipts posts an equal and opposite entry for every
+// receipt previously posted for the lines of a collection.
+func reverseCollectionReceipts(tx *gorm.DB, collection *models.Collection, occurredAt time.Time) error {
+	var receipts []models.InventoryEntry
+
+	if err := tx.
+		Joins("JOIN collections_materials ON collections_materials.collection_material_id = inventory_entries.source_id").
+		Where("collections_materials.collection_id = ?", collection.Id).
+		Where("inventory_entries.source_type = ?", models.CollectionMaterialSource).
+		Where("inventory_entries.type = ?", models.InventoryReceipt).
+		Find(&receipts).Error; err != nil {
+		return err
+	}
+
+	entries := []models.InventoryEntry{}
+
+	for _, receipt := range receipts {
+		entries = append(entries, models.InventoryEntry{
+			OrganizationId: receipt.OrganizationId,
+			MaterialId:     receipt.MaterialId,
+			SiteId:         receipt.SiteId,
+			Type:           models.InventoryReversal,
+			Weight:         -receipt.Weight,
+			Value:          -receipt.Value,
+			SourceType:     receipt.SourceType,
+			SourceId:       receipt.SourceId,
+			OccurredAt:     occurredAt,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return tx.Create(&entries).Error
+}
+
+// postTransactionDelivery issues the dispatched weight of every line from the
+// selling organization and receipts the received weight to the buying
+// organization. Unless overridden, the seller must have the dispatched weight
+// on hand. Each side's entries are attributed to the transaction's site when
+// it is operated by that side.
+func postTransactionDelivery(tx *gorm.DB, transaction *models.Transaction, lines []models.TransactionMaterial, overrideStock bool, occurredAt time.Time) error {
+	if !overrideStock {
+		requested := map[uuid.UUID]float64{}
+
+		for _, line := range lines {
+			requested[line.MaterialId] += line.Weight
+		}
+
+		if err := ensureStockAvailable(tx, transaction.SellerId, requested); err != nil {
+			return err
+		}
+	}
+
+	sellerSiteId, err := siteForOrganization(tx, transaction.SiteId, transaction.SellerId)
+
+	if err != nil {
+		return err
+	}
+
+	buyerSiteId, err := siteForOrganization(tx, transaction.SiteId, transaction.BuyerId)
+
+	if err != nil {
+		return err
+	}
+
+	entries := []models.InventoryEntry{}
+
+	for _, line := range lines {
+		receivedWeight := line.Weight
+
+		if line.ReceivedWeight != nil {
+			receivedWeight = *line.ReceivedWeight
+		}
+
+		entries = append(entries,
+			models.InventoryEntry{
+				OrganizationId: transaction.SellerId,
+				MaterialId:     line.MaterialId,
+				SiteId:         sellerSiteId,
+				Type:           models.InventoryIssue,
+				Weight:         -line.Weight,
+				Value:          -line.Value,
+				SourceType:     models.TransactionMaterialSource,
+				SourceId:       &line.Id,
+				OccurredAt:     occurredAt,
+			},
+			models.InventoryEntry{
+				OrganizationId: transaction.BuyerId,
+				MaterialId:     line.MaterialId,
+				SiteId:         buyerSiteId,
+				Type:           models.InventoryReceipt,
+				Weight:         receivedWeight,
+				Value:          line.InvoicedValue(),
+				SourceType:     models.TransactionMaterialSource,
+				SourceId:       &line.Id,
+				OccurredAt:     occurredAt,
+			},
+		)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return tx.Create(&entries).Error
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobsService interface {
+	Retry(jobId uuid.UUID) error
+	Cleanup() error
+	Schedules() ([]models.JobSchedule, error)
+	Find(jobId uuid.UUID) (*models.Job, error)
+	List(clauses ...clause.Expression) ([]models.Job, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type backgroundJobs struct {
+	storage storage.Storage
+}
+
+func newJobsService(storage storage.Storage) jobsService {
+	return &backgroundJobs{
+		storage: storage,
+	}
+}
+
+// CleanupJobsJob is the kind of the scheduled jobs that delete old jobs.
+const CleanupJobsJob = "jobs.cleanup"
+
+// jobRetention is how long jobs that succeeded are kept.
+const jobRetention = 30 * 24 * time.Hour
+
+// Retry makes a dead job pending again with all of its attempts.
+func (s *backgroundJobs) Retry(jobId uuid.UUID) error {
+	result := s.storage.Postgres.
+		Model(&models.Job{}).
+		Where("id = ? AND status = ?", jobId, models.JobDead).
+		Updates(map[string]any{
+			"status":      models.JobPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+
+		if err := s.storage.Postgres.
+			Model(&models.Job{}).
+			Where("id = ?", jobId).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return ErrInvalidJobTransition
+	}
+
+	return nil
+}
+
+// Cleanup deletes the jobs that succeeded longer ago than they are kept for.
+// Dead jobs are kept until they are retried.
+func (s *backgroundJobs) Cleanup() error {
+	return s.storage.Postgres.
+		Unscoped().
+		Where("status = ? AND finished_at < ?", models.JobSucceeded, time.Now().Add(-jobRetention)).
+		Delete(&models.Job{}).Error
+}
+
+func (s *backgroundJobs) Schedules() ([]models.JobSchedule, error) {
+	var schedules []models.JobSchedule
+
+	if err := s.storage.Postgres.
+		Order("name ASC").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (s *backgroundJobs) Find(jobId uuid.UUID) (*models.Job, error) {
+	var job *models.Job
+
+	if err := s.storage.Postgres.
+		Where("id = ?", jobId).
+		First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *backgroundJobs) List(clauses ...clause.Expression) ([]models.Job, error) {
+	var jobs []models.Job
+
+	if err := s.storage.Postgres.
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (s *backgroundJobs) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Job{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
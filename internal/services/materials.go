@@ -4,15 +4,17 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type materialsService interface {
 	Create(payload models.CreateMaterialPayload) (uuid.UUID, error)
-	Update(materialId uuid.UUID, payload models.UpdateMaterialPayload) error
-	Delete(materialId uuid.UUID) error
+	Update(materialId uuid.UUID, version int64, payload models.UpdateMaterialPayload) error
+	Delete(materialId uuid.UUID, version int64) error
 	Find(materialId uuid.UUID) (*models.Material, error)
 	List(clauses ...clause.Expression) ([]models.Material, error)
+	Stream(handle func(models.Material) error, clauses ...clause.Expression) error
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
@@ -32,6 +34,7 @@ func (s *materials) Create(payload models.CreateMaterialPayload) (uuid.UUID, err
 	material.Name = payload.Name
 	material.GWCode = payload.GWCode
 	material.CarbonFactor = payload.CarbonFactor
+	material.Regulated = payload.Regulated
 
 	if err := s.storage.Postgres.
 		Create(&material).Error; err != nil {
@@ -41,7 +44,7 @@ func (s *materials) Create(payload models.CreateMaterialPayload) (uuid.UUID, err
 	return material.Id, nil
 }
 
-func (s *materials) Update(materialId uuid.UUID, payload models.UpdateMaterialPayload) error {
+func (s *materials) Update(materialId uuid.UUID, version int64, payload models.UpdateMaterialPayload) error {
 	var material models.Material
 
 	if err := s.storage.Postgres.
@@ -62,28 +65,29 @@ func (s *materials) Update(materialId uuid.UUID, payload models.UpdateMaterialPa
 		material.CarbonFactor = *payload.CarbonFactor
 	}
 
-	if err := s.storage.Postgres.
+	if payload.Regulated != nil {
+		material.Regulated = *payload.Regulated
+	}
+
+	result := s.storage.Postgres.
 		Model(&models.Material{}).
-		Where("id = ?", materialId).
+		Where("id = ? AND version = ?", materialId, version).
 		Updates(&map[string]any{
 			"name":          material.Name,
 			"gw_code":       material.GWCode,
 			"carbon_factor": material.CarbonFactor,
-		}).Error; err != nil {
-		return err
-	}
+			"regulated":     material.Regulated,
+		})
 
-	return nil
+	return checkVersion(s.storage.Postgres, &models.Material{}, materialId, result)
 }
 
-func (s *materials) Delete(materialId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", materialId).
-		Delete(&models.Material{}).Error; err != nil {
-		return err
-	}
+func (s *materials) Delete(materialId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", materialId, version).
+		Delete(&models.Material{})
 
-	return nil
+	return checkVersion(s.storage.Postgres, &models.Material{}, materialId, result)
 }
 
 func (s *materials) Find(materialId uuid.UUID) (*models.Material, error) {
@@ -110,6 +114,24 @@ func (s *materials) List(clauses ...clause.Expression) ([]models.Material, error
 	return materials, nil
 }
 
+// Stream passes each material matching the clauses to handle, loading them in
+// batches so that they are never all held in memory.
+func (s *materials) Stream(handle func(models.Material) error, clauses ...clause.Expression) error {
+	var materials []models.Material
+
+	return s.storage.Postgres.
+		Clauses(clauses...).
+		FindInBatches(&materials, streamBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, material := range materials {
+				if err := handle(material); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Error
+}
+
 func (s *materials) Count(clauses ...clause.Expression) (int64, error) {
 	var count int64
 
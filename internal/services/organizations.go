@@ -1,18 +1,22 @@
 package services
 
 import (
+	"time"
+
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type organizationsService interface {
 	Create(payload models.CreateOrganizationPayload) (uuid.UUID, error)
-	Update(organizationId uuid.UUID, payload models.UpdateOrganizationPayload) error
-	Delete(organizationId uuid.UUID) error
+	Update(organizationId uuid.UUID, version int64, payload models.UpdateOrganizationPayload) error
+	Delete(organizationId uuid.UUID, version int64) error
 	Find(organizationId uuid.UUID) (*models.Organization, error)
 	List(clauses ...clause.Expression) ([]models.Organization, error)
+	Stream(handle func(models.Organization) error, clauses ...clause.Expression) error
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
@@ -52,7 +56,7 @@ func (s *organizations) Create(payload models.CreateOrganizationPayload) (uuid.U
 	return organization.Id, nil
 }
 
-func (s *organizations) Update(organizationId uuid.UUID, payload models.UpdateOrganizationPayload) error {
+func (s *organizations) Update(organizationId uuid.UUID, version int64, payload models.UpdateOrganizationPayload) error {
 	var organization models.Organization
 
 	if err := s.storage.Postgres.Where("id = ?", organizationId).First(&organization).Error; err != nil {
@@ -63,12 +67,14 @@ func (s *organizations) Update(organizationId uuid.UUID, payload models.UpdateOr
 		organization.Name = *payload.Name
 	}
 
-	if err := s.storage.Postgres.
+	result := s.storage.Postgres.
 		Model(&models.Organization{}).
-		Where("id = ?", organizationId).
+		Where("id = ? AND version = ?", organizationId, version).
 		Updates(&map[string]any{
 			"name": organization.Name,
-		}).Error; err != nil {
+		})
+
+	if err := checkVersion(s.storage.Postgres, &models.Organization{}, organizationId, result); err != nil {
 		return err
 	}
 
@@ -89,14 +95,41 @@ func (s *organizations) Update(organizationId uuid.UUID, payload models.UpdateOr
 	return nil
 }
 
-func (s *organizations) Delete(organizationId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", organizationId).
-		Delete(&models.Organization{}).Error; err != nil {
-		return err
-	}
+// organizationDependents are moved to the trash along with their
+// organization. They share its deletion time, which is how they are found
+// again when the organization is restored.
+var organizationDependents = []any{
+	&models.Site{},
+	&models.Scale{},
+	&models.WebhookEndpoint{},
+}
 
-	return nil
+// Delete moves an organization to the trash along with its sites, scales and
+// webhooks. Its collections and transactions are kept as they are.
+func (s *organizations) Delete(organizationId uuid.UUID, version int64) error {
+	deletedAt := time.Now()
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Organization{}).
+			Where("id = ? AND version = ?", organizationId, version).
+			Update("deleted_at", deletedAt)
+
+		if err := checkVersion(tx, &models.Organization{}, organizationId, result); err != nil {
+			return err
+		}
+
+		for _, dependent := range organizationDependents {
+			if err := tx.
+				Model(dependent).
+				Where("organization_id = ?", organizationId).
+				Update("deleted_at", deletedAt).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (s *organizations) Find(organizationId uuid.UUID) (*models.Organization, error) {
@@ -123,6 +156,24 @@ func (s *organizations) List(clauses ...clause.Expression) ([]models.Organizatio
 	return organizations, nil
 }
 
+// Stream passes each organization matching the clauses to handle, loading them in
+// batches so that they are never all held in memory.
+func (s *organizations) Stream(handle func(models.Organization) error, clauses ...clause.Expression) error {
+	var organizations []models.Organization
+
+	return s.storage.Postgres.
+		Clauses(clauses...).
+		FindInBatches(&organizations, streamBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, organization := range organizations {
+				if err := handle(organization); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Error
+}
+
 func (s *organizations) Count(clauses ...clause.Expression) (int64, error) {
 	var count int64
 
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OwnedBy limits addresses or bank details to those referenced through the
+// given column by the user and, when includeOrganization is set, by the
+// organization and its members.
+func OwnedBy(column string, userId uuid.UUID, organizationId uuid.UUID, includeOrganization bool) clause.Expression {
+	sql := fmt.Sprintf("id IN (SELECT %s FROM users WHERE id = ? AND deleted_at IS NULL)", column)
+	vars := []any{userId}
+
+	if includeOrganization {
+		sql += fmt.Sprintf(" OR id IN (SELECT %[1]s FROM organizations WHERE id = ? AND deleted_at IS NULL) OR id IN (SELECT users.%[1]s FROM users JOIN organization_users ON organization_users.user_id = users.id WHERE organization_users.organization_id = ? AND users.deleted_at IS NULL)", column)
+		vars = append(vars, organizationId, organizationId)
+	}
+
+	return clause.Expr{SQL: "(" + sql + ")", Vars: vars}
+}
+
+// lockOwnerReference locks the owner's row and returns the id it references
+// through the given column.
+func lockOwnerReference(tx *gorm.DB, owner models.Owner, column string) (*uuid.UUID, error) {
+	var row struct {
+		Reference *uuid.UUID
+	}
+
+	if err := tx.
+		Table(owner.Table()).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select(column+" AS reference").
+		Where("id = ? AND deleted_at IS NULL", owner.Id).
+		Take(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return row.Reference, nil
+}
+
+// findOwnerReference returns the id the owner references through the given
+// column, or gorm.ErrRecordNotFound when the owner or the reference is missing.
+func findOwnerReference(tx *gorm.DB, owner models.Owner, column string) (uuid.UUID, error) {
+	var row struct {
+		Reference *uuid.UUID
+	}
+
+	if err := tx.
+		Table(owner.Table()).
+		Select(column+" AS reference").
+		Where("id = ? AND deleted_at IS NULL", owner.Id).
+		Take(&row).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if row.Reference == nil {
+		return uuid.Nil, gorm.ErrRecordNotFound
+	}
+
+	return *row.Reference, nil
+}
+
+// userOwner returns the user as an owner along with the organizations they
+// are a member of, or gorm.ErrRecordNotFound when there is no such user.
+func userOwner(tx *gorm.DB, userId uuid.UUID) (*models.Owner, error) {
+	owner := models.Owner{Type: models.UserOwner, Id: userId}
+
+	var count int64
+
+	if err := tx.
+		Model(&models.User{}).
+		Where("id = ?", userId).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := addOwnerOrganizations(tx, &owner); err != nil {
+		return nil, err
+	}
+
+	return &owner, nil
+}
+
+// addOwnerOrganizations records the organizations a user owner is a member
+// of, which organizations have no need of.
+func addOwnerOrganizations(tx *gorm.DB, owner *models.Owner) error {
+	if owner.Type != models.UserOwner {
+		return nil
+	}
+
+	return tx.
+		Table("organization_users").
+		Where("user_id = ?", owner.Id).
+		Pluck("organization_id", &owner.Organizations).Error
+}
+
+// findOwner returns the user or organization that references the record
+// through the given column, or nil when the record has no owner.
+func findOwner(tx *gorm.DB, column string, id uuid.UUID) (*models.Owner, error) {
+	for _, ownerType := range []models.OwnerType{models.UserOwner, models.OrganizationOwner} {
+		owner := models.Owner{Type: ownerType}
+
+		var ownerIds []uuid.UUID
+
+		if err := tx.
+			Table(owner.Table()).
+			Where(column+" = ?", id).
+			Limit(1).
+			Pluck("id", &ownerIds).Error; err != nil {
+			return nil, err
+		}
+
+		if len(ownerIds) > 0 {
+			owner.Id = ownerIds[0]
+
+			if err := addOwnerOrganizations(tx, &owner); err != nil {
+				return nil, err
+			}
+
+			return &owner, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// clearOwnerReferences removes every reference to the record through the given
+// column so that it can be deleted without affecting its owner.
+func clearOwnerReferences(tx *gorm.DB, column string, id uuid.UUID) error {
+	for _, table := range []string{"users", "organizations"} {
+		if err := tx.
+			Table(table).
+			Where(column+" = ?", id).
+			Update(column, nil).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+)
+
+// exportGenericCsv renders a batch as a plain CSV file with one row per payout
+// that can be imported into most banking and accounting systems.
+func exportGenericCsv(batch *models.PayoutBatch) ([]byte, error) {
+	records := [][]string{
+		{"Batch Reference", "Payout Id", "Seller", "Account Holder", "Account Number", "Account Type", "Bank Name", "Branch Code", "Amount"},
+	}
+
+	for _, payout := range batch.Payouts {
+		records = append(records, []string{
+			batch.Reference,
+			payout.Id.String(),
+			payout.Seller.Name,
+			stringValue(payout.AccountHolder),
+			stringValue(payout.AccountNumber),
+			string(accountTypeValue(payout.AccountType)),
+			stringValue(payout.BankName),
+			stringValue(payout.BranchCode),
+			fmt.Sprintf("%.2f", payout.Amount),
+		})
+	}
+
+	return writeCsv(records)
+}
+
+// exportFnbBulkPayments renders a batch in the FNB Online Banking bulk payment
+// import layout. The header identifies the file version, the action date and
+// the account the payments are made from, followed by one row per recipient.
+func exportFnbBulkPayments(batch *models.PayoutBatch, account *models.BankDetails) ([]byte, error) {
+	records := [][]string{
+		{"BInSol - U ver 1.00"},
+		{time.Now().Format("20060102")},
+		{account.AccountNumber},
+		{"RECIPIENT NAME", "RECIPIENT ACCOUNT", "RECIPIENT ACCOUNT TYPE", "BRANCHCODE", "AMOUNT", "OWN REFERENCE", "RECIPIENT REFERENCE"},
+	}
+
+	for _, payout := range batch.Payouts {
+		records = append(records, []string{
+			truncate(stringValue(payout.AccountHolder), 20),
+			stringValue(payout.AccountNumber),
+			accountTypeCode(accountTypeValue(payout.AccountType)),
+			stringValue(payout.BranchCode),
+			fmt.Sprintf("%.2f", payout.Amount),
+			truncate(batch.Reference, 20),
+			truncate(account.AccountHolder, 20),
+		})
+	}
+
+	return writeCsv(records)
+}
+
+func writeCsv(records [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func accountTypeValue(value *models.BankAccountType) models.BankAccountType {
+	if value == nil {
+		return models.ChequeAccount
+	}
+
+	return *value
+}
+
+// accountTypeCode returns the numeric account type used in bank payment files.
+func accountTypeCode(accountType models.BankAccountType) string {
+	switch accountType {
+	case models.SavingsAccount:
+		return "2"
+	case models.TransmissionAccount:
+		return "3"
+	default:
+		return "1"
+	}
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+
+	if len(runes) <= length {
+		return value
+	}
+
+	return string(runes[:length])
+}
@@ -0,0 +1,658 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type payoutsService interface {
+	CreateBatch(organizationId uuid.UUID, createdById uuid.UUID, payload models.CreatePayoutBatchPayload) (uuid.UUID, error)
+	ConfirmBatch(batchId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) error
+	ReverseBatch(batchId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.ReversePayoutPayload) error
+	ExportBatch(batchId uuid.UUID, organizationId uuid.UUID, format models.PayoutExportFormat) ([]byte, error)
+	FindBatch(batchId uuid.UUID) (*models.PayoutBatch, error)
+	ListBatches(clauses ...clause.Expression) ([]models.PayoutBatch, error)
+	CountBatches(clauses ...clause.Expression) (int64, error)
+	CreateCashPayout(organizationId uuid.UUID, performedById uuid.UUID, payload models.CreateCashPayoutPayload) (uuid.UUID, error)
+	Reverse(payoutId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.ReversePayoutPayload) error
+	List(clauses ...clause.Expression) ([]models.Payout, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type payouts struct {
+	storage storage.Storage
+}
+
+func newPayoutsService(storage storage.Storage) payoutsService {
+	return &payouts{
+		storage: storage,
+	}
+}
+
+// CreateBatch gathers the confirmed collections bought by the organization that
+// are not yet part of a payout and creates a draft batch with one electronic
+// payout per seller. Sellers without bank details are left out so that they
+// can be paid in cash instead.
+func (s *payouts) CreateBatch(organizationId uuid.UUID, createdById uuid.UUID, payload models.CreatePayoutBatchPayload) (uuid.UUID, error) {
+	var batch models.PayoutBatch
+
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		query := tx.
+			Preload("Materials").
+			Preload("Seller.BankDetails").
+			Where("buyer_id = ? AND status = ?", organizationId, models.CollectionConfirmed)
+
+		if payload.SiteId != nil {
+			query = query.Where("site_id = ?", *payload.SiteId)
+		}
+
+		if payload.ConfirmedUntil != nil {
+			query = query.Where("id IN (?)", tx.
+				Model(&models.CollectionTransition{}).
+				Select("collection_id").
+				Where("\"to\" = ? AND created_at <= ?", models.CollectionConfirmed, *payload.ConfirmedUntil))
+		}
+
+		var collections []models.Collection
+
+		if err := query.
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+			Order("created_at ASC").
+			Find(&collections).Error; err != nil {
+			return err
+		}
+
+		// Collections are only checked against open payouts once they are
+		// locked, so that a batch created at the same time, which holds the
+		// locks until it commits, is seen here and its collections left out.
+		collectionIds := make([]uuid.UUID, 0, len(collections))
+
+		for _, collection := range collections {
+			collectionIds = append(collectionIds, collection.Id)
+		}
+
+		var inPayoutIds []uuid.UUID
+
+		if len(collectionIds) > 0 {
+			if err := openPayoutCollections(tx).
+				Where("payout_collections.collection_id IN ?", collectionIds).
+				Pluck("payout_collections.collection_id", &inPayoutIds).Error; err != nil {
+				return err
+			}
+		}
+
+		payoutsBySeller := map[uuid.UUID]*models.Payout{}
+		sellerIds := []uuid.UUID{}
+
+		for _, collection := range collections {
+			if slices.Contains(inPayoutIds, collection.Id) {
+				continue
+			}
+
+			bankDetails := collection.Seller.BankDetails
+
+			if bankDetails == nil {
+				continue
+			}
+
+			payout, ok := payoutsBySeller[collection.SellerId]
+
+			if !ok {
+				payout = &models.Payout{
+					OrganizationId: organizationId,
+					SellerId:       collection.SellerId,
+					Method:         models.EftPayout,
+					Status:         models.PayoutPending,
+					AccountHolder:  &bankDetails.AccountHolder,
+					AccountNumber:  &bankDetails.AccountNumber,
+					AccountType:    &bankDetails.AccountType,
+					BankName:       &bankDetails.BankName,
+					BranchCode:     &bankDetails.BranchCode,
+				}
+
+				payoutsBySeller[collection.SellerId] = payout
+				sellerIds = append(sellerIds, collection.SellerId)
+			}
+
+			payout.Amount += collectionTotal(&collection)
+			payout.Collections = append(payout.Collections, models.Collection{Base: collection.Base})
+		}
+
+		if len(sellerIds) == 0 {
+			return ErrNoPayableCollections
+		}
+
+		var number int64
+
+		if err := tx.Raw(`
+			INSERT INTO payout_batch_sequences (organization_id, last_number)
+			VALUES (?, 1)
+			ON CONFLICT (organization_id)
+			DO UPDATE SET last_number = payout_batch_sequences.last_number + 1
+			RETURNING last_number
+		`, organizationId).Scan(&number).Error; err != nil {
+			return err
+		}
+
+		batch = models.PayoutBatch{
+			OrganizationId: organizationId,
+			Reference:      fmt.Sprintf("PAY-%06d", number),
+			Status:         models.PayoutBatchDraft,
+			CreatedById:    createdById,
+		}
+
+		for _, sellerId := range sellerIds {
+			payout := payoutsBySeller[sellerId]
+			payout.Amount = math.Round(payout.Amount*100) / 100
+
+			batch.Total += payout.Amount
+			batch.PayoutCount++
+		}
+
+		batch.Total = math.Round(batch.Total*100) / 100
+
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+
+		for _, sellerId := range sellerIds {
+			payout := payoutsBySeller[sellerId]
+			payout.BatchId = &batch.Id
+
+			if err := tx.
+				Omit("Collections.*").
+				Create(payout).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return batch.Id, nil
+}
+
+// ConfirmBatch records that the bank has processed a draft batch. Every payout
+// in the batch is marked paid and its collections move to paid.
+func (s *payouts) ConfirmBatch(batchId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		batch, err := lockPayoutBatch(tx, batchId, organizationId)
+
+		if err != nil {
+			return err
+		}
+
+		if batch.Status != models.PayoutBatchDraft {
+			return ErrInvalidPayoutTransition
+		}
+
+		now := time.Now()
+
+		for _, payout := range batch.Payouts {
+			if payout.Status != models.PayoutPending {
+				continue
+			}
+
+			if err := payCollections(tx, &payout, performedById); err != nil {
+				return err
+			}
+
+			if err := tx.
+				Model(&models.Payout{}).
+				Where("id = ?", payout.Id).
+				Updates(&map[string]any{
+					"status":     models.PayoutPaid,
+					"paid_at":    now,
+					"paid_by_id": performedById,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.
+			Model(&models.PayoutBatch{}).
+			Where("id = ?", batchId).
+			Updates(&map[string]any{
+				"status":       models.PayoutBatchConfirmed,
+				"confirmed_at": now,
+			}).Error
+	})
+}
+
+// ReverseBatch cancels a draft batch, releasing its collections for a later
+// payout, or reverses a confirmed batch, returning every paid collection in it
+// to confirmed.
+func (s *payouts) ReverseBatch(batchId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.ReversePayoutPayload) error {
+	if strings.TrimSpace(payload.Reason) == "" {
+		return ErrReversalReasonRequired
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		batch, err := lockPayoutBatch(tx, batchId, organizationId)
+
+		if err != nil {
+			return err
+		}
+
+		if batch.Status == models.PayoutBatchReversed {
+			return ErrInvalidPayoutTransition
+		}
+
+		for _, payout := range batch.Payouts {
+			if payout.Status == models.PayoutReversed {
+				continue
+			}
+
+			if err := reversePayout(tx, &payout, performedById, payload.Reason); err != nil {
+				return err
+			}
+		}
+
+		return tx.
+			Model(&models.PayoutBatch{}).
+			Where("id = ?", batchId).
+			Updates(&map[string]any{
+				"status":          models.PayoutBatchReversed,
+				"reversed_at":     time.Now(),
+				"reversal_reason": payload.Reason,
+			}).Error
+	})
+}
+
+// ExportBatch renders the payouts of a batch in the requested bulk payment
+// format and records when the batch was last exported.
+func (s *payouts) ExportBatch(batchId uuid.UUID, organizationId uuid.UUID, format models.PayoutExportFormat) ([]byte, error) {
+	var batch models.PayoutBatch
+
+	if err := s.storage.Postgres.
+		Preload("Payouts", "status <> ?", models.PayoutReversed).
+		Preload("Payouts.Seller").
+		Where("id = ? AND organization_id = ?", batchId, organizationId).
+		First(&batch).Error; err != nil {
+		return nil, err
+	}
+
+	if batch.Status == models.PayoutBatchReversed {
+		return nil, ErrInvalidPayoutTransition
+	}
+
+	var data []byte
+	var err error
+
+	switch format {
+	case models.GenericCsvExport:
+		data, err = exportGenericCsv(&batch)
+	case models.FnbBulkExport:
+		var organization models.Organization
+
+		if err := s.storage.Postgres.
+			Preload("BankDetails").
+			Where("id = ?", organizationId).
+			First(&organization).Error; err != nil {
+			return nil, err
+		}
+
+		if organization.BankDetails == nil {
+			return nil, ErrMissingBankDetails
+		}
+
+		data, err = exportFnbBulkPayments(&batch, organization.BankDetails)
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Postgres.
+		Model(&models.PayoutBatch{}).
+		Where("id = ?", batchId).
+		Update("exported_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *payouts) FindBatch(batchId uuid.UUID) (*models.PayoutBatch, error) {
+	var batch *models.PayoutBatch
+
+	if err := s.storage.Postgres.
+		Where("id = ?", batchId).
+		Preload("Payouts.Seller").
+		Preload("Payouts.Collections").
+		First(&batch).Error; err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (s *payouts) ListBatches(clauses ...clause.Expression) ([]models.PayoutBatch, error) {
+	var batches []models.PayoutBatch
+
+	if err := s.storage.Postgres.
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&batches).Error; err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+func (s *payouts) CountBatches(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.PayoutBatch{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CreateCashPayout records a cash payment made at the depot for confirmed
+// collections of a single seller. The collections are marked paid immediately.
+func (s *payouts) CreateCashPayout(organizationId uuid.UUID, performedById uuid.UUID, payload models.CreateCashPayoutPayload) (uuid.UUID, error) {
+	receiptNumber := strings.TrimSpace(payload.ReceiptNumber)
+
+	if receiptNumber == "" {
+		return uuid.Nil, ErrReceiptNumberRequired
+	}
+
+	// A collection listed more than once is paid once.
+	collectionIds := uniqueIds(payload.CollectionIds)
+
+	if len(collectionIds) == 0 {
+		return uuid.Nil, ErrNoPayableCollections
+	}
+
+	var payout models.Payout
+
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var collections []models.Collection
+
+		if err := tx.
+			Preload("Materials").
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+			Where("id IN ? AND buyer_id = ?", collectionIds, organizationId).
+			Find(&collections).Error; err != nil {
+			return err
+		}
+
+		if len(collections) != len(collectionIds) {
+			return gorm.ErrRecordNotFound
+		}
+
+		var receiptCount int64
+
+		if err := tx.
+			Model(&models.Payout{}).
+			Where("organization_id = ? AND receipt_number = ?", organizationId, receiptNumber).
+			Count(&receiptCount).Error; err != nil {
+			return err
+		}
+
+		if receiptCount > 0 {
+			return ErrReceiptNumberInUse
+		}
+
+		var openCount int64
+
+		if err := tx.
+			Table("payout_collections").
+			Joins("JOIN payouts ON payouts.id = payout_collections.payout_id").
+			Where("payout_collections.collection_id IN ?", collectionIds).
+			Where("payouts.status <> ?", models.PayoutReversed).
+			Count(&openCount).Error; err != nil {
+			return err
+		}
+
+		if openCount > 0 {
+			return ErrCollectionInPayout
+		}
+
+		now := time.Now()
+
+		payout = models.Payout{
+			OrganizationId: organizationId,
+			SellerId:       collections[0].SellerId,
+			Method:         models.CashPayout,
+			Status:         models.PayoutPaid,
+			ReceiptNumber:  &receiptNumber,
+			PaidAt:         &now,
+			PaidById:       &performedById,
+		}
+
+		for _, collection := range collections {
+			if collection.SellerId != payout.SellerId || collection.Status != models.CollectionConfirmed {
+				return ErrNoPayableCollections
+			}
+
+			payout.Amount += collectionTotal(&collection)
+			payout.Collections = append(payout.Collections, models.Collection{Base: collection.Base})
+		}
+
+		payout.Amount = math.Round(payout.Amount*100) / 100
+
+		if err := tx.
+			Omit("Collections.*").
+			Create(&payout).Error; err != nil {
+			return err
+		}
+
+		return payCollections(tx, &payout, performedById)
+	})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return payout.Id, nil
+}
+
+// Reverse reverses a single paid payout, for example when an electronic
+// payment bounces or a cash payment was recorded in error. Its collections
+// return to confirmed so that they can be paid again.
+func (s *payouts) Reverse(payoutId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.ReversePayoutPayload) error {
+	if strings.TrimSpace(payload.Reason) == "" {
+		return ErrReversalReasonRequired
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var payout models.Payout
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organization_id = ?", payoutId, organizationId).
+			First(&payout).Error; err != nil {
+			return err
+		}
+
+		if payout.Status != models.PayoutPaid {
+			return ErrInvalidPayoutTransition
+		}
+
+		return reversePayout(tx, &payout, performedById, payload.Reason)
+	})
+}
+
+func (s *payouts) List(clauses ...clause.Expression) ([]models.Payout, error) {
+	var payouts []models.Payout
+
+	if err := s.storage.Postgres.
+		Preload("Seller").
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&payouts).Error; err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+func (s *payouts) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Payout{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// lockPayoutBatch locks a batch of the organization and loads its payouts.
+func lockPayoutBatch(tx *gorm.DB, batchId uuid.UUID, organizationId uuid.UUID) (*models.PayoutBatch, error) {
+	var batch models.PayoutBatch
+
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND organization_id = ?", batchId, organizationId).
+		First(&batch).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.
+		Where("batch_id = ?", batchId).
+		Find(&batch.Payouts).Error; err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// payCollections moves every collection of a payout to paid.
+func payCollections(tx *gorm.DB, payout *models.Payout, performedById uuid.UUID) error {
+	var collectionIds []uuid.UUID
+
+	if err := tx.
+		Table("payout_collections").
+		Where("payout_id = ?", payout.Id).
+		Pluck("collection_id", &collectionIds).Error; err != nil {
+		return err
+	}
+
+	for _, collectionId := range collectionIds {
+		if err := transitionCollection(tx, collectionId, models.CollectionPaid, performedById, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reversePayout marks a payout reversed and returns any of its collections that
+// were paid by it to confirmed.
+func reversePayout(tx *gorm.DB, payout *models.Payout, performedById uuid.UUID, reason string) error {
+	if payout.Status == models.PayoutPaid {
+		var collections []models.Collection
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+			Joins("JOIN payout_collections ON payout_collections.collection_id = collections.id").
+			Where("payout_collections.payout_id = ?", payout.Id).
+			Find(&collections).Error; err != nil {
+			return err
+		}
+
+		for _, collection := range collections {
+			if collection.Status != models.CollectionPaid {
+				continue
+			}
+
+			if !collection.Status.CanTransitionTo(models.CollectionConfirmed) {
+				return ErrInvalidCollectionTransition
+			}
+
+			if err := recordCollectionTransition(tx, &collection, models.CollectionConfirmed, performedById, &reason); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.
+		Model(&models.Payout{}).
+		Where("id = ?", payout.Id).
+		Updates(&map[string]any{
+			"status":          models.PayoutReversed,
+			"reversed_at":     time.Now(),
+			"reversal_reason": reason,
+		}).Error
+}
+
+// openPayoutCollections selects the ids of collections that belong to a payout
+// that has not been reversed.
+func openPayoutCollections(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Table("payout_collections").
+		Select("payout_collections.collection_id").
+		Joins("JOIN payouts ON payouts.id = payout_collections.payout_id").
+		Where("payouts.status <> ?", models.PayoutReversed)
+}
+
+// ensureNotInOpenPayout returns ErrCollectionInPayout when the collection
+// belongs to a payout that has not been reversed, so that it is only paid or
+// voided through that payout.
+func ensureNotInOpenPayout(tx *gorm.DB, collectionId uuid.UUID) error {
+	var count int64
+
+	if err := tx.
+		Table("payout_collections").
+		Joins("JOIN payouts ON payouts.id = payout_collections.payout_id").
+		Where("payout_collections.collection_id = ?", collectionId).
+		Where("payouts.status <> ?", models.PayoutReversed).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrCollectionInPayout
+	}
+
+	return nil
+}
+
+// uniqueIds returns the ids without repeats, in the order they were first
+// given.
+func uniqueIds(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// collectionTotal returns the value of all lines of a collection.
+func collectionTotal(collection *models.Collection) float64 {
+	total := 0.0
+
+	for _, material := range collection.Materials {
+		total += material.Value
+	}
+
+	return total
+}
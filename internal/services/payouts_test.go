@@ -0,0 +1,242 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestUniqueIds(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	got := uniqueIds([]uuid.UUID{a, b, a, c, b, a})
+	want := []uuid.UUID{a, b, c}
+
+	if len(got) != len(want) {
+		t.Fatalf("uniqueIds() = %v, want %v", got, want)
+	}
+
+	for index := range want {
+		if got[index] != want[index] {
+			t.Fatalf("uniqueIds() = %v, want %v", got, want)
+		}
+	}
+
+	if got := uniqueIds(nil); len(got) != 0 {
+		t.Errorf("uniqueIds(nil) = %v, want none", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		value  string
+		length int
+		want   string
+	}{
+		{"Thabo", 20, "Thabo"},
+		{"Thabo Mokoena", 5, "Thabo"},
+		{"Zoë Müller-Ngcobo", 3, "Zoë"},
+		{"Zoë Müller-Ngcobo", 6, "Zoë Mü"},
+		{"ÉÉÉ", 2, "ÉÉ"},
+		{"", 20, ""},
+	}
+
+	for _, test := range tests {
+		if got := truncate(test.value, test.length); got != test.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", test.value, test.length, got, test.want)
+		}
+	}
+}
+
+func TestExportFnbBulkPayments(t *testing.T) {
+	holder := "Nomvula Ndlovu-Mthembu Trust"
+	number := "62000000001"
+	branch := "250655"
+	savings := models.SavingsAccount
+
+	batch := &models.PayoutBatch{
+		Reference: "PAY-000042",
+		Payouts: []models.Payout{
+			{
+				AccountHolder: &holder,
+				AccountNumber: &number,
+				AccountType:   &savings,
+				BranchCode:    &branch,
+				Amount:        1234.5,
+			},
+		},
+	}
+
+	account := &models.BankDetails{
+		AccountHolder: "Recycling Buyback Centre (Pty) Ltd",
+		AccountNumber: "62999999999",
+	}
+
+	content, err := exportFnbBulkPayments(batch, account)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5:\n%s", len(lines), content)
+	}
+
+	if lines[2] != account.AccountNumber {
+		t.Errorf("account line = %q, want %q", lines[2], account.AccountNumber)
+	}
+
+	want := "Nomvula Ndlovu-Mthem,62000000001,2,250655,1234.50,PAY-000042,Recycling Buyback Ce"
+
+	if lines[4] != want {
+		t.Errorf("payment line = %q, want %q", lines[4], want)
+	}
+}
+
+// testConfirmedCollection creates a collection of a single line bought from
+// the seller and confirms it.
+func testConfirmedCollection(tb testing.TB, s storage.Storage, buyerId uuid.UUID, sellerId uuid.UUID, materialId uuid.UUID, value float64) uuid.UUID {
+	tb.Helper()
+
+	service := newCollectionsService(s)
+
+	collectionId, err := service.Create(models.CreateCollectionPayload{
+		SellerId: sellerId,
+		BuyerId:  buyerId,
+		Materials: []models.CreateCollectionMaterialPayload{
+			{MaterialId: materialId, Weight: 10, Value: value},
+		},
+	})
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	for _, status := range []models.CollectionStatus{models.CollectionWeighed, models.CollectionConfirmed} {
+		if err := service.Transition(collectionId, status, sellerId, nil); err != nil {
+			tb.Fatalf("Transition(%s) error = %v", status, err)
+		}
+	}
+
+	return collectionId
+}
+
+func TestCreateCashPayout(t *testing.T) {
+	s := testStorage(t)
+
+	buyer := testOrganization(t, s)
+	seller := testUser(t, s, buyer.Id, models.IdentityVerified)
+	otherSeller := testUser(t, s, buyer.Id, models.IdentityVerified)
+	material := testMaterial(t, s, false)
+
+	service := newPayoutsService(s)
+	collections := newCollectionsService(s)
+
+	first := testConfirmedCollection(t, s, buyer.Id, seller.Id, material.Id, 25.1)
+	second := testConfirmedCollection(t, s, buyer.Id, seller.Id, material.Id, 10.2)
+	other := testConfirmedCollection(t, s, buyer.Id, otherSeller.Id, material.Id, 5)
+
+	t.Run("collections of more than one seller", func(t *testing.T) {
+		_, err := service.CreateCashPayout(buyer.Id, seller.Id, models.CreateCashPayoutPayload{
+			CollectionIds: []uuid.UUID{first, other},
+			ReceiptNumber: "R-" + uuid.NewString(),
+		})
+
+		if err != ErrNoPayableCollections {
+			t.Fatalf("CreateCashPayout() error = %v, want %v", err, ErrNoPayableCollections)
+		}
+	})
+
+	t.Run("collections of another buyer", func(t *testing.T) {
+		_, err := service.CreateCashPayout(testOrganization(t, s).Id, seller.Id, models.CreateCashPayoutPayload{
+			CollectionIds: []uuid.UUID{first},
+			ReceiptNumber: "R-" + uuid.NewString(),
+		})
+
+		if err != gorm.ErrRecordNotFound {
+			t.Fatalf("CreateCashPayout() error = %v, want %v", err, gorm.ErrRecordNotFound)
+		}
+	})
+
+	receiptNumber := "R-" + uuid.NewString()
+
+	payoutId, err := service.CreateCashPayout(buyer.Id, seller.Id, models.CreateCashPayoutPayload{
+		CollectionIds: []uuid.UUID{first, second, first},
+		ReceiptNumber: receiptNumber,
+	})
+
+	if err != nil {
+		t.Fatalf("CreateCashPayout() with a repeated collection error = %v", err)
+	}
+
+	var payout models.Payout
+
+	if err := s.Postgres.Preload("Collections").Where("id = ?", payoutId).First(&payout).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if payout.Amount != 35.3 || len(payout.Collections) != 2 || payout.Status != models.PayoutPaid {
+		t.Errorf("payout amount = %v, collections = %d, status = %s, want 35.3, 2 and %s", payout.Amount, len(payout.Collections), payout.Status, models.PayoutPaid)
+	}
+
+	for _, collectionId := range []uuid.UUID{first, second} {
+		collection, err := collections.Find(collectionId)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if collection.Status != models.CollectionPaid {
+			t.Errorf("collection status = %s, want %s", collection.Status, models.CollectionPaid)
+		}
+	}
+
+	t.Run("receipt number in use", func(t *testing.T) {
+		third := testConfirmedCollection(t, s, buyer.Id, seller.Id, material.Id, 1)
+
+		_, err := service.CreateCashPayout(buyer.Id, seller.Id, models.CreateCashPayoutPayload{
+			CollectionIds: []uuid.UUID{third},
+			ReceiptNumber: receiptNumber,
+		})
+
+		if err != ErrReceiptNumberInUse {
+			t.Fatalf("CreateCashPayout() error = %v, want %v", err, ErrReceiptNumberInUse)
+		}
+	})
+
+	t.Run("paid collections are not voided", func(t *testing.T) {
+		reason := "entered twice"
+
+		if err := collections.Transition(first, models.CollectionVoided, seller.Id, &reason); err != ErrCollectionInPayout {
+			t.Fatalf("Transition() error = %v, want %v", err, ErrCollectionInPayout)
+		}
+	})
+
+	t.Run("reversed payouts return their collections", func(t *testing.T) {
+		if err := service.Reverse(payoutId, buyer.Id, seller.Id, models.ReversePayoutPayload{Reason: "recorded in error"}); err != nil {
+			t.Fatal(err)
+		}
+
+		collection, err := collections.Find(first)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if collection.Status != models.CollectionConfirmed {
+			t.Errorf("collection status = %s, want %s", collection.Status, models.CollectionConfirmed)
+		}
+
+		reason := "entered twice"
+
+		if err := collections.Transition(first, models.CollectionVoided, seller.Id, &reason); err != nil {
+			t.Errorf("Transition() error = %v", err)
+		}
+	})
+}
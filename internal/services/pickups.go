@@ -0,0 +1,395 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type pickupsService interface {
+	Request(requestedById uuid.UUID, payload models.CreatePickupPayload) (uuid.UUID, error)
+	Assign(pickupId uuid.UUID, organizationId uuid.UUID, payload models.AssignPickupPayload) error
+	Cancel(pickupId uuid.UUID, payload models.CancelPickupPayload) error
+	Complete(pickupId uuid.UUID, organizationId uuid.UUID, payload models.CompletePickupPayload) (uuid.UUID, error)
+	Route(organizationId uuid.UUID, collectorId uuid.UUID, date time.Time, startSiteId *uuid.UUID) (*models.PickupRoute, error)
+	Find(pickupId uuid.UUID) (*models.Pickup, error)
+	List(clauses ...clause.Expression) ([]models.Pickup, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type pickups struct {
+	storage storage.Storage
+}
+
+func newPickupsService(storage storage.Storage) pickupsService {
+	return &pickups{
+		storage: storage,
+	}
+}
+
+// Request records a pickup at the seller's address. The seller must have an
+// address and the pickup must list at least one expected material.
+func (s *pickups) Request(requestedById uuid.UUID, payload models.CreatePickupPayload) (uuid.UUID, error) {
+	if payload.OrganizationId == nil || payload.SellerId == nil || len(payload.Materials) == 0 {
+		return uuid.Nil, ErrInvalidPickup
+	}
+
+	for _, material := range payload.Materials {
+		if material.ExpectedWeight <= 0 {
+			return uuid.Nil, ErrInvalidPickup
+		}
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, payload.SiteId, *payload.OrganizationId); err != nil {
+		return uuid.Nil, err
+	}
+
+	var seller models.User
+
+	if err := s.storage.Postgres.
+		Where("id = ?", *payload.SellerId).
+		First(&seller).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if seller.AddressId == nil {
+		return uuid.Nil, ErrPickupAddressRequired
+	}
+
+	pickup := models.Pickup{
+		OrganizationId: *payload.OrganizationId,
+		SellerId:       seller.Id,
+		AddressId:      seller.AddressId,
+		SiteId:         payload.SiteId,
+		Status:         models.PickupRequested,
+		Notes:          payload.Notes,
+		RequestedById:  requestedById,
+	}
+
+	for _, material := range payload.Materials {
+		pickup.Materials = append(pickup.Materials, models.PickupMaterial{
+			MaterialId:     material.MaterialId,
+			ExpectedWeight: material.ExpectedWeight,
+		})
+	}
+
+	if err := s.storage.Postgres.
+		Create(&pickup).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	return pickup.Id, nil
+}
+
+// Assign schedules a requested pickup, or reschedules a scheduled one, for a
+// collector on a date.
+func (s *pickups) Assign(pickupId uuid.UUID, organizationId uuid.UUID, payload models.AssignPickupPayload) error {
+	if payload.ScheduledFor.IsZero() {
+		return ErrInvalidPickup
+	}
+
+	var collector models.User
+
+	if err := s.storage.Postgres.
+		Where("id = ?", payload.CollectorId).
+		First(&collector).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidCollector
+		}
+
+		return err
+	}
+
+	if collector.Type != models.Collector {
+		return ErrInvalidCollector
+	}
+
+	location, err := time.LoadLocation(reportTimeZone)
+
+	if err != nil {
+		return err
+	}
+
+	scheduledFor := startOfDay(payload.ScheduledFor, location)
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		pickup, err := lockPickup(tx, pickupId, organizationId)
+
+		if err != nil {
+			return err
+		}
+
+		if pickup.Status != models.PickupRequested && pickup.Status != models.PickupScheduled {
+			return ErrInvalidPickupTransition
+		}
+
+		return tx.
+			Model(&models.Pickup{}).
+			Where("id = ?", pickupId).
+			Updates(&map[string]any{
+				"collector_id":  collector.Id,
+				"scheduled_for": scheduledFor.Format(time.DateOnly),
+				"status":        models.PickupScheduled,
+			}).Error
+	})
+}
+
+// Cancel cancels a pickup that has not yet been completed.
+func (s *pickups) Cancel(pickupId uuid.UUID, payload models.CancelPickupPayload) error {
+	reason := strings.TrimSpace(payload.Reason)
+
+	if reason == "" {
+		return ErrCancellationReasonRequired
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var pickup models.Pickup
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", pickupId).
+			First(&pickup).Error; err != nil {
+			return err
+		}
+
+		if pickup.Status != models.PickupRequested && pickup.Status != models.PickupScheduled {
+			return ErrInvalidPickupTransition
+		}
+
+		return tx.
+			Model(&models.Pickup{}).
+			Where("id = ?", pickupId).
+			Updates(&map[string]any{
+				"status":              models.PickupCancelled,
+				"cancellation_reason": reason,
+			}).Error
+	})
+}
+
+// Complete marks a scheduled pickup as completed and creates a draft collection
+// from the seller to the organization for the picked up materials.
+func (s *pickups) Complete(pickupId uuid.UUID, organizationId uuid.UUID, payload models.CompletePickupPayload) (uuid.UUID, error) {
+	var collection models.Collection
+
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		pickup, err := lockPickup(tx, pickupId, organizationId)
+
+		if err != nil {
+			return err
+		}
+
+		if pickup.Status != models.PickupScheduled {
+			return ErrInvalidPickupTransition
+		}
+
+		collection = models.Collection{
+			SellerId: pickup.SellerId,
+			BuyerId:  pickup.OrganizationId,
+			SiteId:   pickup.SiteId,
+			Status:   models.CollectionDraft,
+		}
+
+		materials := payload.Materials
+
+		if len(materials) == 0 {
+			var expected []models.PickupMaterial
+
+			if err := tx.
+				Where("pickup_id = ?", pickupId).
+				Find(&expected).Error; err != nil {
+				return err
+			}
+
+			for _, material := range expected {
+				materials = append(materials, models.CreateCollectionMaterialPayload{
+					MaterialId: material.MaterialId,
+					Weight:     material.ExpectedWeight,
+				})
+			}
+		}
+
+		// The collection is checked and weighed as any other collection is, as
+		// the seller, site and materials may have changed since the pickup was
+		// requested.
+		if err := addCollectionMaterials(tx, &collection, materials); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&collection).Error; err != nil {
+			return err
+		}
+
+		if err := publishCollectionEvents(tx, collection.Id, models.CollectionCreatedEvent); err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&models.Pickup{}).
+			Where("id = ?", pickupId).
+			Updates(&map[string]any{
+				"status":        models.PickupCompleted,
+				"collection_id": collection.Id,
+				"completed_at":  time.Now(),
+			}).Error
+	})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return collection.Id, nil
+}
+
+// Route orders the pickups scheduled for a collector on a day into a short
+// route. The route starts at the given site when it has coordinates.
+func (s *pickups) Route(organizationId uuid.UUID, collectorId uuid.UUID, date time.Time, startSiteId *uuid.UUID) (*models.PickupRoute, error) {
+	var start *Coordinates
+
+	if startSiteId != nil {
+		var site models.Site
+
+		if err := s.storage.Postgres.
+			Where("id = ? AND organization_id = ?", *startSiteId, organizationId).
+			First(&site).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, ErrInvalidSite
+			}
+
+			return nil, err
+		}
+
+		if site.Latitude != nil && site.Longitude != nil {
+			start = &Coordinates{
+				Latitude:  *site.Latitude,
+				Longitude: *site.Longitude,
+			}
+		}
+	}
+
+	var scheduled []models.Pickup
+
+	if err := s.storage.Postgres.
+		Preload("Seller").
+		Preload("Address").
+		Preload("Materials.Material").
+		Where("organization_id = ? AND collector_id = ?", organizationId, collectorId).
+		Where("scheduled_for = ? AND status = ?", date.Format(time.DateOnly), models.PickupScheduled).
+		Order("created_at ASC").
+		Find(&scheduled).Error; err != nil {
+		return nil, err
+	}
+
+	route := models.PickupRoute{
+		CollectorId: collectorId,
+		Date:        date.Format(time.DateOnly),
+		Stops:       []models.PickupRouteStop{},
+		Unrouted:    []models.Pickup{},
+	}
+
+	located := []models.Pickup{}
+	points := []Coordinates{}
+
+	for _, pickup := range scheduled {
+		if pickup.Address == nil || pickup.Address.Latitude == nil || pickup.Address.Longitude == nil {
+			route.Unrouted = append(route.Unrouted, pickup)
+
+			continue
+		}
+
+		located = append(located, pickup)
+		points = append(points, Coordinates{
+			Latitude:  *pickup.Address.Latitude,
+			Longitude: *pickup.Address.Longitude,
+		})
+	}
+
+	previous := start
+
+	for sequence, index := range planRoute(start, points) {
+		distance := 0.0
+
+		if previous != nil {
+			distance = math.Round(distanceKilometres(*previous, points[index])*100) / 100
+		}
+
+		route.Distance += distance
+		route.Stops = append(route.Stops, models.PickupRouteStop{
+			Sequence: sequence + 1,
+			Distance: distance,
+			Pickup:   located[index],
+		})
+
+		previous = &points[index]
+	}
+
+	route.Distance = math.Round(route.Distance*100) / 100
+
+	return &route, nil
+}
+
+func (s *pickups) Find(pickupId uuid.UUID) (*models.Pickup, error) {
+	var pickup *models.Pickup
+
+	if err := s.storage.Postgres.
+		Where("id = ?", pickupId).
+		Preload("Seller").
+		Preload("Address").
+		Preload("Site").
+		Preload("Collector").
+		Preload("Materials.Material").
+		First(&pickup).Error; err != nil {
+		return nil, err
+	}
+
+	return pickup, nil
+}
+
+func (s *pickups) List(clauses ...clause.Expression) ([]models.Pickup, error) {
+	var pickups []models.Pickup
+
+	if err := s.storage.Postgres.
+		Preload("Seller").
+		Preload("Address").
+		Preload("Collector").
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&pickups).Error; err != nil {
+		return nil, err
+	}
+
+	return pickups, nil
+}
+
+func (s *pickups) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Pickup{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// lockPickup locks a pickup of the organization for the rest of the
+// transaction.
+func lockPickup(tx *gorm.DB, pickupId uuid.UUID, organizationId uuid.UUID) (*models.Pickup, error) {
+	var pickup models.Pickup
+
+	if err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND organization_id = ?", pickupId, organizationId).
+		First(&pickup).Error; err != nil {
+		return nil, err
+	}
+
+	return &pickup, nil
+}
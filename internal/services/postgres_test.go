@@ -0,0 +1,108 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testStorage connects to and migrates the database named by
+// TEST_POSTGRES_DSN. Tests and benchmarks that need a database are skipped
+// when it is not set. The database should be a scratch database: the data
+// written to it is left behind.
+func testStorage(tb testing.TB) storage.Storage {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+
+	if dsn == "" {
+		tb.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+
+	if err != nil {
+		tb.Fatalf("failed to connect to Postgres: %v", err)
+	}
+
+	s := storage.Storage{
+		Postgres: database,
+	}
+
+	s.MigratePostgres()
+
+	return s
+}
+
+// testOrganization creates an organization with a unique name.
+func testOrganization(tb testing.TB, s storage.Storage) models.Organization {
+	tb.Helper()
+
+	organization := models.Organization{Name: "Organization " + uuid.NewString()}
+
+	if err := s.Postgres.Create(&organization).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	return organization
+}
+
+// testUser creates a user with a unique email and phone number, whose
+// identity has the given status, as a member of the organization.
+func testUser(tb testing.TB, s storage.Storage, organizationId uuid.UUID, status models.IdentityStatus) models.User {
+	tb.Helper()
+
+	id := uuid.NewString()
+
+	user := models.User{
+		Name:               "User " + id,
+		Email:              id + "@example.com",
+		Phone:              id,
+		Password:           []byte{0},
+		ActiveOrganization: organizationId,
+	}
+
+	if err := s.Postgres.Create(&user).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := s.Postgres.
+		Model(&models.User{}).
+		Where("id = ?", user.Id).
+		Update("identity_status", status).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := s.Postgres.
+		Exec("INSERT INTO organization_users (organization_id, user_id) VALUES (?, ?)", organizationId, user.Id).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	return user
+}
+
+// testMaterial creates a material with a unique name.
+func testMaterial(tb testing.TB, s storage.Storage, regulated bool) models.Material {
+	tb.Helper()
+
+	material := models.Material{
+		Name:         "Material " + uuid.NewString(),
+		GWCode:       "GW01",
+		CarbonFactor: "1.5",
+		Regulated:    regulated,
+	}
+
+	if err := s.Postgres.Create(&material).Error; err != nil {
+		tb.Fatal(err)
+	}
+
+	return material
+}
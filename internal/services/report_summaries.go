@@ -0,0 +1,263 @@
+package services
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reportSummariesService interface {
+	Refresh() error
+	Rebuild() error
+}
+
+type reportSummaries struct {
+	storage storage.Storage
+}
+
+func newReportSummariesService(storage storage.Storage) reportSummariesService {
+	return &reportSummaries{
+		storage: storage,
+	}
+}
+
+var reportSources = []models.ReportSource{
+	models.CollectionsReportSource,
+	models.TransactionsReportSource,
+}
+
+// RefreshReportSummariesJob is the kind of the scheduled jobs that refresh the
+// daily volumes.
+const RefreshReportSummariesJob = "report-summaries.refresh"
+
+// refreshOverlap is how far before the last refresh changed records are looked
+// for, so that records committed by transactions that were still running at the
+// last refresh are not missed. Summarising a day again is harmless.
+const refreshOverlap = 5 * time.Minute
+
+// Refresh summarises again the days of each source that have records or lines
+// changed since the last refresh. A source that has never been refreshed is
+// rebuilt.
+func (s *reportSummaries) Refresh() error {
+	for _, source := range reportSources {
+		if err := s.refresh(source, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rebuild discards and summarises again every day of each source.
+func (s *reportSummaries) Rebuild() error {
+	for _, source := range reportSources {
+		if err := s.refresh(source, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *reportSummaries) refresh(source models.ReportSource, rebuild bool) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		state := models.DailyVolumeRefresh{
+			Source: source,
+		}
+
+		if err := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&state).Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("source = ?", source).
+			First(&state).Error; err != nil {
+			return err
+		}
+
+		startedAt := time.Now()
+
+		var days []string
+
+		if !rebuild && !state.RefreshedAt.IsZero() {
+			changed, err := changedReportDays(tx, source, state.RefreshedAt.Add(-refreshOverlap))
+
+			if err != nil {
+				return err
+			}
+
+			if len(changed) == 0 {
+				return tx.
+					Model(&models.DailyVolumeRefresh{}).
+					Where("source = ?", source).
+					Update("refreshed_at", startedAt).Error
+			}
+
+			days = changed
+		}
+
+		if err := summariseReportDays(tx, source, days); err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&models.DailyVolumeRefresh{}).
+			Where("source = ?", source).
+			Update("refreshed_at", startedAt).Error
+	})
+}
+
+// changedReportDays returns the days, in South African time, of the records
+// of a source that were changed, deleted or restored since the given time, or
+// whose lines were. Deleting a record only sets its deleted_at, so deletions
+// are found by it, while restoring a record also sets its updated_at.
+func changedReportDays(tx *gorm.DB, source models.ReportSource, since time.Time) ([]string, error) {
+	days := []string{}
+
+	var query string
+
+	switch source {
+	case models.CollectionsReportSource:
+		query = `
+			SELECT DISTINCT (collections.created_at AT TIME ZONE @zone)::date::text
+			FROM collections
+			WHERE collections.updated_at >= @since
+			OR collections.deleted_at >= @since
+			OR EXISTS (
+				SELECT 1
+				FROM collections_materials
+				JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id
+				WHERE collections_materials.collection_id = collections.id
+				AND (collection_materials.updated_at >= @since OR collection_materials.deleted_at >= @since)
+			)
+		`
+	case models.TransactionsReportSource:
+		query = `
+			SELECT DISTINCT (transactions.created_at AT TIME ZONE @zone)::date::text
+			FROM transactions
+			WHERE transactions.updated_at >= @since
+			OR transactions.deleted_at >= @since
+			OR EXISTS (
+				SELECT 1
+				FROM transactions_materials
+				JOIN transaction_materials ON transaction_materials.id = transactions_materials.transaction_material_id
+				WHERE transactions_materials.transaction_id = transactions.id
+				AND (transaction_materials.updated_at >= @since OR transaction_materials.deleted_at >= @since)
+			)
+		`
+	default:
+		return nil, ErrInvalidReportSource
+	}
+
+	if err := tx.
+		Raw(query, map[string]any{
+			"zone":  reportTimeZone,
+			"since": since,
+		}).
+		Scan(&days).Error; err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
+// summariseReportDays replaces the daily volumes of a source on the given
+// days with totals computed from its lines, leaving out records and lines in
+// the trash. Every day is summarised when no days are given.
+func summariseReportDays(tx *gorm.DB, source models.ReportSource, days []string) error {
+	var summary string
+
+	switch source {
+	case models.CollectionsReportSource:
+		summary = `
+			INSERT INTO daily_volumes (source, side, organization_id, counterparty_id, site_id, material_id, day, weight, value, lines, created_at, updated_at)
+			SELECT
+				@source,
+				@buyer,
+				collections.buyer_id,
+				NULL,
+				collections.site_id,
+				collection_materials.material_id,
+				(collections.created_at AT TIME ZONE @zone)::date AS day,
+				SUM(collection_materials.weight),
+				SUM(collection_materials.value),
+				COUNT(*),
+				NOW(),
+				NOW()
+			FROM collections
+			JOIN collections_materials ON collections_materials.collection_id = collections.id
+			JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id
+			WHERE collections.status IN @collectionStatuses
+			AND collections.deleted_at IS NULL
+			AND collection_materials.deleted_at IS NULL
+		`
+
+		if days != nil {
+			summary += ` AND (collections.created_at AT TIME ZONE @zone)::date IN @days`
+		}
+
+		summary += ` GROUP BY collections.buyer_id, collections.site_id, collection_materials.material_id, day`
+	case models.TransactionsReportSource:
+		summary = `
+			INSERT INTO daily_volumes (source, side, organization_id, counterparty_id, site_id, material_id, day, weight, value, lines, created_at, updated_at)
+			SELECT
+				@source,
+				sides.side,
+				sides.organization_id,
+				sides.counterparty_id,
+				transactions.site_id,
+				transaction_materials.material_id,
+				(transactions.created_at AT TIME ZONE @zone)::date AS day,
+				SUM(COALESCE(transaction_materials.received_weight, transaction_materials.weight)),
+				SUM(transaction_materials.value),
+				COUNT(*),
+				NOW(),
+				NOW()
+			FROM transactions
+			CROSS JOIN LATERAL (
+				VALUES
+					(@seller, transactions.seller_id, transactions.buyer_id),
+					(@buyer, transactions.buyer_id, transactions.seller_id)
+			) AS sides (side, organization_id, counterparty_id)
+			JOIN transactions_materials ON transactions_materials.transaction_id = transactions.id
+			JOIN transaction_materials ON transaction_materials.id = transactions_materials.transaction_material_id
+			WHERE transactions.status IN @transactionStatuses
+			AND transactions.deleted_at IS NULL
+			AND transaction_materials.deleted_at IS NULL
+		`
+
+		if days != nil {
+			summary += ` AND (transactions.created_at AT TIME ZONE @zone)::date IN @days`
+		}
+
+		summary += ` GROUP BY sides.side, sides.organization_id, sides.counterparty_id, transactions.site_id, transaction_materials.material_id, day`
+	default:
+		return ErrInvalidReportSource
+	}
+
+	stale := tx.Unscoped().Where("source = ?", source)
+
+	if days != nil {
+		stale = stale.Where("day IN ?", days)
+	}
+
+	if err := stale.Delete(&models.DailyVolume{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(summary, map[string]any{
+		"source":              source,
+		"seller":              models.SellerSide,
+		"buyer":               models.BuyerSide,
+		"zone":                reportTimeZone,
+		"days":                days,
+		"collectionStatuses":  reportedCollectionStatuses,
+		"transactionStatuses": reportedTransactionStatuses,
+	}).Error
+}
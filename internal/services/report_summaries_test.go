@@ -0,0 +1,275 @@
+package services
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+)
+
+// benchmarkPrefix marks the rows written by seedReportBenchmark so that a
+// database that already holds them is not seeded again.
+const benchmarkPrefix = "Benchmark"
+
+// benchmarkLinesPerCollection is how many lines each generated collection has.
+const benchmarkLinesPerCollection = 4
+
+// benchmarkLines returns how many collection lines to generate, taken from
+// TEST_REPORT_LINES and three million by default.
+func benchmarkLines(b *testing.B) int {
+	b.Helper()
+
+	value := os.Getenv("TEST_REPORT_LINES")
+
+	if value == "" {
+		return 3_000_000
+	}
+
+	lines, err := strconv.Atoi(value)
+
+	if err != nil || lines <= 0 {
+		b.Fatalf("invalid TEST_REPORT_LINES %q", value)
+	}
+
+	return lines
+}
+
+// seedReportBenchmark generates confirmed collections spread over three years
+// between 20 buyers with 3 sites each, 5000 sellers and 30 materials, until
+// there are at least the given number of generated lines. Rows are generated
+// in the database so that millions of lines take seconds rather than hours.
+func seedReportBenchmark(b *testing.B, s storage.Storage, lines int) {
+	b.Helper()
+
+	var existing int64
+
+	if err := s.Postgres.Raw(`
+		SELECT COUNT(*)
+		FROM collections_materials
+		JOIN collections ON collections.id = collections_materials.collection_id
+		JOIN organizations ON organizations.id = collections.buyer_id
+		WHERE organizations.name LIKE @prefix || ' buyer %'
+	`, map[string]any{"prefix": benchmarkPrefix}).Scan(&existing).Error; err != nil {
+		b.Fatalf("failed to count generated lines: %v", err)
+	}
+
+	if existing >= int64(lines) {
+		return
+	}
+
+	statements := []string{
+		`
+			INSERT INTO organizations (id, name, created_at, updated_at)
+			SELECT uuid_generate_v4(), @prefix || ' buyer ' || i, NOW(), NOW()
+			FROM generate_series(1, 20) i
+			WHERE NOT EXISTS (SELECT 1 FROM organizations WHERE name LIKE @prefix || ' buyer %')
+		`,
+		`
+			INSERT INTO sites (id, organization_id, name, created_at, updated_at)
+			SELECT uuid_generate_v4(), organizations.id, @prefix || ' site ' || organizations.name || ' ' || i, NOW(), NOW()
+			FROM organizations
+			CROSS JOIN generate_series(1, 3) i
+			WHERE organizations.name LIKE @prefix || ' buyer %'
+			AND NOT EXISTS (SELECT 1 FROM sites WHERE name LIKE @prefix || ' site %')
+		`,
+		`
+			INSERT INTO materials (id, name, gw_code, carbon_factor, created_at, updated_at)
+			SELECT uuid_generate_v4(), @prefix || ' material ' || i, 'GW' || i, 1.5, NOW(), NOW()
+			FROM generate_series(1, 30) i
+			WHERE NOT EXISTS (SELECT 1 FROM materials WHERE name LIKE @prefix || ' material %')
+		`,
+		`
+			INSERT INTO users (id, name, email, phone, password, active_organization, created_at, updated_at)
+			SELECT uuid_generate_v4(), @prefix || ' seller ' || i, 'benchmark-seller-' || i || '@example.com', '+2700' || LPAD(i::text, 7, '0'), '\x00'::bytea, organizations.id, NOW(), NOW()
+			FROM generate_series(1, 5000) i
+			CROSS JOIN (SELECT id FROM organizations WHERE name LIKE @prefix || ' buyer %' LIMIT 1) organizations
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE email LIKE 'benchmark-seller-%')
+		`,
+		`
+			INSERT INTO collections (id, seller_id, buyer_id, site_id, status, created_at, updated_at)
+			SELECT uuid_generate_v4(), sellers.ids[1 + i % array_length(sellers.ids, 1)], sites.organization_ids[pick.site], sites.ids[pick.site], @status, pick.created_at, pick.created_at
+			FROM generate_series(1, @collections) i
+			CROSS JOIN (SELECT array_agg(id) AS ids FROM users WHERE email LIKE 'benchmark-seller-%') sellers
+			CROSS JOIN (
+				SELECT array_agg(id ORDER BY id) AS ids, array_agg(organization_id ORDER BY id) AS organization_ids
+				FROM sites
+				WHERE name LIKE @prefix || ' site %'
+			) sites
+			CROSS JOIN LATERAL (
+				SELECT
+					1 + (i * 7919) % array_length(sites.ids, 1) AS site,
+					NOW() - (i % 1095) * INTERVAL '1 day' - (i % 86400) * INTERVAL '1 second' AS created_at
+			) pick
+		`,
+		`
+			WITH generated AS (
+				SELECT collections.id AS collection_id, uuid_generate_v4() AS line_id, materials.ids[1 + (ABS(HASHTEXT(collections.id::text)) + n) % array_length(materials.ids, 1)] AS material_id, n, collections.created_at
+				FROM collections
+				JOIN organizations ON organizations.id = collections.buyer_id
+				CROSS JOIN generate_series(1, @linesPerCollection) n
+				CROSS JOIN (SELECT array_agg(id) AS ids FROM materials WHERE name LIKE @prefix || ' material %') materials
+				WHERE organizations.name LIKE @prefix || ' buyer %'
+				AND NOT EXISTS (SELECT 1 FROM collections_materials WHERE collections_materials.collection_id = collections.id)
+			), inserted AS (
+				INSERT INTO collection_materials (id, material_id, weight, value, created_at, updated_at)
+				SELECT line_id, material_id, 1 + n * 2.5, 10 + n * 7.25, created_at, created_at
+				FROM generated
+			)
+			INSERT INTO collections_materials (collection_id, collection_material_id)
+			SELECT collection_id, line_id
+			FROM generated
+		`,
+	}
+
+	parameters := map[string]any{
+		"prefix":             benchmarkPrefix,
+		"status":             models.CollectionConfirmed,
+		"collections":        (int64(lines) - existing + benchmarkLinesPerCollection - 1) / benchmarkLinesPerCollection,
+		"linesPerCollection": benchmarkLinesPerCollection,
+	}
+
+	for _, statement := range statements {
+		if err := s.Postgres.Exec(statement, parameters).Error; err != nil {
+			b.Fatalf("failed to generate report data: %v", err)
+		}
+	}
+
+	if err := s.Postgres.Exec("ANALYZE").Error; err != nil {
+		b.Fatalf("failed to analyze generated report data: %v", err)
+	}
+}
+
+// reportBenchmarkStorage returns the benchmark database seeded with the
+// generated dataset and its daily volumes rebuilt.
+func reportBenchmarkStorage(b *testing.B) storage.Storage {
+	b.Helper()
+
+	s := testStorage(b)
+
+	seedReportBenchmark(b, s, benchmarkLines(b))
+
+	if err := newReportSummariesService(s).Rebuild(); err != nil {
+		b.Fatalf("failed to rebuild report summaries: %v", err)
+	}
+
+	return s
+}
+
+func BenchmarkReportSummariesRebuild(b *testing.B) {
+	s := reportBenchmarkStorage(b)
+	summaries := newReportSummariesService(s)
+
+	for b.Loop() {
+		if err := summaries.Rebuild(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReportSummariesRefresh measures a refresh after a handful of
+// collections on different days were changed, which is what the scheduled
+// refresh sees between runs.
+func BenchmarkReportSummariesRefresh(b *testing.B) {
+	s := reportBenchmarkStorage(b)
+	summaries := newReportSummariesService(s)
+
+	for b.Loop() {
+		b.StopTimer()
+
+		if err := s.Postgres.Exec(`
+			UPDATE collections
+			SET updated_at = NOW()
+			WHERE id IN (
+				SELECT collections.id
+				FROM collections
+				JOIN organizations ON organizations.id = collections.buyer_id
+				WHERE organizations.name LIKE @prefix || ' buyer %'
+				ORDER BY RANDOM()
+				LIMIT 10
+			)
+		`, map[string]any{"prefix": benchmarkPrefix}).Error; err != nil {
+			b.Fatal(err)
+		}
+
+		b.StartTimer()
+
+		if err := summaries.Refresh(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkVolumeQuery is a year of collections by material and month across
+// every organization, the widest report the dashboards ask for.
+func benchmarkVolumeQuery() models.VolumeReportQuery {
+	to := time.Now()
+
+	return models.VolumeReportQuery{
+		Source:  models.CollectionsReportSource,
+		GroupBy: models.ByMaterial,
+		Period:  models.MonthlyPeriod,
+		From:    to.AddDate(-1, 0, 0),
+		To:      to,
+	}
+}
+
+func BenchmarkVolumesFromSummaries(b *testing.B) {
+	s := &reports{storage: reportBenchmarkStorage(b)}
+	query := benchmarkVolumeQuery()
+
+	for b.Loop() {
+		if _, err := s.volumes(query, s.summaryLines); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkVolumesFromLines runs the same report as BenchmarkVolumesFromSummaries
+// over the collection lines themselves, for comparison with reading the daily
+// volumes.
+func BenchmarkVolumesFromLines(b *testing.B) {
+	s := &reports{storage: reportBenchmarkStorage(b)}
+	query := benchmarkVolumeQuery()
+
+	for b.Loop() {
+		if _, err := s.volumes(query, s.materialCollectionLines); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVolumesByCollector(b *testing.B) {
+	s := &reports{storage: reportBenchmarkStorage(b)}
+	query := benchmarkVolumeQuery()
+	query.GroupBy = models.ByCollector
+
+	for b.Loop() {
+		if _, err := s.Volumes(query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// materialCollectionLines selects the collection lines grouped by material, as
+// the volume reports did before they read the daily volumes.
+func (s *reports) materialCollectionLines(query models.VolumeReportQuery) (*reportLines, error) {
+	lines, err := s.collectionLines(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines.db = s.storage.Postgres.
+		Table("collections").
+		Joins("JOIN collections_materials ON collections_materials.collection_id = collections.id").
+		Joins("JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id").
+		Joins("JOIN materials ON materials.id = collection_materials.material_id").
+		Where("collections.status IN ?", reportedCollectionStatuses).
+		Where("collections.deleted_at IS NULL AND collection_materials.deleted_at IS NULL")
+	lines.key, lines.name = "materials.id", "materials.name"
+
+	return lines, nil
+}
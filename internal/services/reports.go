@@ -0,0 +1,270 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"gorm.io/gorm"
+)
+
+type reportsService interface {
+	Volumes(query models.VolumeReportQuery) (*models.VolumeReport, error)
+}
+
+type reports struct {
+	storage storage.Storage
+}
+
+func newReportsService(storage storage.Storage) reportsService {
+	return &reports{
+		storage: storage,
+	}
+}
+
+// reportTimeZone is the time zone report periods are bucketed in.
+const reportTimeZone = "Africa/Johannesburg"
+
+var volumeReportPeriods = []models.ReportPeriod{
+	models.DailyPeriod,
+	models.WeeklyPeriod,
+	models.MonthlyPeriod,
+	models.QuarterlyPeriod,
+}
+
+// reportedTransactionStatuses are the statuses of transactions whose lines
+// have been delivered and count towards volume reports.
+var reportedTransactionStatuses = []models.TransactionStatus{
+	models.TransactionDelivered,
+	models.TransactionInvoiced,
+	models.TransactionSettled,
+}
+
+// reportLines describes how the lines of a report are selected: the date each
+// line is reported on and how range boundaries compare with it, the start of
+// the period a line falls in given the period as its only parameter, its
+// weight and value, and the expression and label of the group it belongs to.
+type reportLines struct {
+	db       *gorm.DB
+	date     string
+	boundary func(time.Time) any
+	bucket   string
+	weight   string
+	value    string
+	key      string
+	name     string
+}
+
+// Volumes aggregates the weight and value of the lines of a source by group
+// within the range of the query and the range of the same length before it,
+// and by group and period within the range of the query. The range is widened
+// to whole days in South African time. Reports read from the daily volumes
+// except when grouped by collector, which the daily volumes do not record.
+func (s *reports) Volumes(query models.VolumeReportQuery) (*models.VolumeReport, error) {
+	return s.volumes(query, s.lines)
+}
+
+// volumes builds a volume report from the lines selected for the query.
+func (s *reports) volumes(query models.VolumeReportQuery, selectLines func(models.VolumeReportQuery) (*reportLines, error)) (*models.VolumeReport, error) {
+	if !slices.Contains(volumeReportPeriods, query.Period) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	if !query.From.Before(query.To) {
+		return nil, ErrInvalidReportRange
+	}
+
+	location, err := time.LoadLocation(reportTimeZone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	from := startOfDay(query.From, location)
+	to := startOfDay(query.To, location)
+
+	if to.Before(query.To) {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	previousFrom := from.AddDate(0, 0, -int(to.Sub(from).Hours()/24))
+
+	totals := []models.VolumeTotal{}
+
+	lines, err := selectLines(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lines.db.
+		Select(`
+			`+lines.key+` AS key,
+			`+lines.name+` AS name,
+			COALESCE(SUM(`+lines.weight+`) FILTER (WHERE `+lines.date+` >= @from), 0) AS weight,
+			COALESCE(SUM(`+lines.value+`) FILTER (WHERE `+lines.date+` >= @from), 0) AS value,
+			COALESCE(SUM(`+lines.weight+`) FILTER (WHERE `+lines.date+` < @from), 0) AS previous_weight,
+			COALESCE(SUM(`+lines.value+`) FILTER (WHERE `+lines.date+` < @from), 0) AS previous_value
+		`, map[string]any{
+			"from": lines.boundary(from),
+		}).
+		Where(lines.date+" >= ? AND "+lines.date+" < ?", lines.boundary(previousFrom), lines.boundary(to)).
+		Group("1, 2").
+		Order("weight DESC").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	for index := range totals {
+		totals[index].WeightChange = percentageChange(totals[index].PreviousWeight, totals[index].Weight)
+		totals[index].ValueChange = percentageChange(totals[index].PreviousValue, totals[index].Value)
+	}
+
+	series := []models.VolumeBucket{}
+
+	lines, err = selectLines(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lines.db.
+		Select(`
+			`+lines.bucket+` AS bucket,
+			`+lines.key+` AS key,
+			`+lines.name+` AS name,
+			SUM(`+lines.weight+`) AS weight,
+			SUM(`+lines.value+`) AS value
+		`, string(query.Period)).
+		Where(lines.date+" >= ? AND "+lines.date+" < ?", lines.boundary(from), lines.boundary(to)).
+		Group("1, 2, 3").
+		Order("1 ASC, 3 ASC").
+		Scan(&series).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.VolumeReport{
+		Source:       query.Source,
+		GroupBy:      query.GroupBy,
+		Period:       query.Period,
+		From:         from,
+		To:           to,
+		PreviousFrom: previousFrom,
+		PreviousTo:   from,
+		Totals:       totals,
+		Series:       series,
+	}, nil
+}
+
+// lines selects the reportable lines of the source of the query, joined to the
+// records needed to group them.
+func (s *reports) lines(query models.VolumeReportQuery) (*reportLines, error) {
+	switch {
+	case query.Source == models.CollectionsReportSource && query.GroupBy == models.ByCollector:
+		return s.collectionLines(query)
+	case query.Source == models.CollectionsReportSource || query.Source == models.TransactionsReportSource:
+		return s.summaryLines(query)
+	default:
+		return nil, ErrInvalidReportSource
+	}
+}
+
+// summaryLines selects the daily volumes of the source of the query. Volumes
+// of transactions are recorded for both sides, so reports across every
+// organization only count the seller side and group organizations by seller.
+// Reports scoped to an organization group transactions by counterparty.
+func (s *reports) summaryLines(query models.VolumeReportQuery) (*reportLines, error) {
+	lines := reportLines{
+		db: s.storage.Postgres.
+			Table("daily_volumes").
+			Joins("JOIN materials ON materials.id = daily_volumes.material_id").
+			Where("daily_volumes.source = ?", query.Source),
+		date: "daily_volumes.day",
+		boundary: func(boundary time.Time) any {
+			return boundary.Format(time.DateOnly)
+		},
+		bucket: "DATE_TRUNC(?, daily_volumes.day::timestamp) AT TIME ZONE '" + reportTimeZone + "'",
+		weight: "daily_volumes.weight",
+		value:  "daily_volumes.value",
+	}
+
+	if query.OrganizationId != nil {
+		lines.db = lines.db.Where("daily_volumes.organization_id = ?", *query.OrganizationId)
+	} else if query.Source == models.TransactionsReportSource {
+		lines.db = lines.db.Where("daily_volumes.side = ?", models.SellerSide)
+	}
+
+	switch query.GroupBy {
+	case models.ByMaterial:
+		lines.key, lines.name = "materials.id", "materials.name"
+	case models.ByOrganization:
+		if query.OrganizationId != nil && query.Source == models.TransactionsReportSource {
+			lines.db = lines.db.Joins("JOIN organizations ON organizations.id = daily_volumes.counterparty_id")
+		} else {
+			lines.db = lines.db.Joins("JOIN organizations ON organizations.id = daily_volumes.organization_id")
+		}
+
+		lines.key, lines.name = "organizations.id", "organizations.name"
+	case models.BySite:
+		lines.db = lines.db.Joins("LEFT JOIN sites ON sites.id = daily_volumes.site_id")
+		lines.key, lines.name = "sites.id", "COALESCE(sites.name, '')"
+	default:
+		return nil, ErrInvalidReportGrouping
+	}
+
+	return &lines, nil
+}
+
+// collectionLines selects the lines of confirmed and paid collections grouped
+// by collector, the seller of each collection. Collections and lines in the
+// trash are left out.
+func (s *reports) collectionLines(query models.VolumeReportQuery) (*reportLines, error) {
+	lines := reportLines{
+		db: s.storage.Postgres.
+			Table("collections").
+			Joins("JOIN collections_materials ON collections_materials.collection_id = collections.id").
+			Joins("JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id").
+			Joins("JOIN materials ON materials.id = collection_materials.material_id").
+			Where("collections.status IN ?", reportedCollectionStatuses).
+			Where("collections.deleted_at IS NULL AND collection_materials.deleted_at IS NULL"),
+		date: "collections.created_at",
+		boundary: func(boundary time.Time) any {
+			return boundary
+		},
+		bucket: "DATE_TRUNC(?, collections.created_at, '" + reportTimeZone + "')",
+		weight: "collection_materials.weight",
+		value:  "collection_materials.value",
+	}
+
+	if query.OrganizationId != nil {
+		lines.db = lines.db.Where("collections.buyer_id = ?", *query.OrganizationId)
+	}
+
+	lines.db = lines.db.Joins("JOIN users ON users.id = collections.seller_id")
+	lines.key, lines.name = "users.id", "users.name"
+
+	return &lines, nil
+}
+
+// percentageChange returns the change from previous to current as a
+// percentage of previous, rounded to two decimals, or nil when previous is
+// zero.
+func percentageChange(previous float64, current float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+
+	change := math.Round((current-previous)/previous*10000) / 100
+
+	return &change
+}
+
+// startOfDay returns midnight at the start of the day of t in the location.
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	year, month, day := t.In(location).Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
@@ -9,8 +9,8 @@ import (
 
 type rolesService interface {
 	Create(payload models.CreateRolePayload) (uuid.UUID, error)
-	Update(roleId uuid.UUID, payload models.UpdateRolePayload) error
-	Delete(roleId uuid.UUID) error
+	Update(roleId uuid.UUID, version int64, payload models.UpdateRolePayload) error
+	Delete(roleId uuid.UUID, version int64) error
 	Find(roleId uuid.UUID) (*models.Role, error)
 	List(clauses ...clause.Expression) ([]models.Role, error)
 	Count(clauses ...clause.Expression) (int64, error)
@@ -41,7 +41,7 @@ func (s *roles) Create(payload models.CreateRolePayload) (uuid.UUID, error) {
 	return role.Id, nil
 }
 
-func (s *roles) Update(roleId uuid.UUID, payload models.UpdateRolePayload) error {
+func (s *roles) Update(roleId uuid.UUID, version int64, payload models.UpdateRolePayload) error {
 	var role models.Role
 
 	if err := s.storage.Postgres.
@@ -60,28 +60,24 @@ func (s *roles) Update(roleId uuid.UUID, payload models.UpdateRolePayload) error
 		role.Permissions = payload.Permissions
 	}
 
-	if err := s.storage.Postgres.
+	result := s.storage.Postgres.
 		Model(&models.Role{}).
-		Where("id = ?", roleId).
+		Where("id = ? AND version = ?", roleId, version).
 		Updates(&map[string]any{
 			"name":        role.Name,
 			"description": role.Description,
 			"permissions": role.Permissions,
-		}).Error; err != nil {
-		return err
-	}
+		})
 
-	return nil
+	return checkVersion(s.storage.Postgres, &models.Role{}, roleId, result)
 }
 
-func (s *roles) Delete(roleId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", roleId).
-		Delete(&models.Role{}).Error; err != nil {
-		return err
-	}
+func (s *roles) Delete(roleId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", roleId, version).
+		Delete(&models.Role{})
 
-	return nil
+	return checkVersion(s.storage.Postgres, &models.Role{}, roleId, result)
 }
 
 func (s *roles) Find(roleId uuid.UUID) (*models.Role, error) {
@@ -0,0 +1,96 @@
+package services
+
+import "math"
+
+const earthRadiusKilometres = 6371.0
+
+// distanceKilometres returns the great circle distance between two points.
+func distanceKilometres(from Coordinates, to Coordinates) float64 {
+	fromLatitude := from.Latitude * math.Pi / 180
+	toLatitude := to.Latitude * math.Pi / 180
+	deltaLatitude := (to.Latitude - from.Latitude) * math.Pi / 180
+	deltaLongitude := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(fromLatitude)*math.Cos(toLatitude)*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+
+	return earthRadiusKilometres * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// planRoute returns the order in which to visit the stops so that the path
+// from start, or from the first stop when there is no start, is short. The
+// order is built greedily by always visiting the nearest unvisited stop and is
+// then improved with 2-opt by reversing segments that cross.
+func planRoute(start *Coordinates, stops []Coordinates) []int {
+	nodes := stops
+	offset := 0
+
+	if start != nil {
+		nodes = append([]Coordinates{*start}, stops...)
+		offset = 1
+	}
+
+	if len(nodes) == 0 {
+		return []int{}
+	}
+
+	distance := func(i int, j int) float64 {
+		return distanceKilometres(nodes[i], nodes[j])
+	}
+
+	path := []int{0}
+	visited := make([]bool, len(nodes))
+	visited[0] = true
+
+	for len(path) < len(nodes) {
+		last := path[len(path)-1]
+		nearest := -1
+
+		for candidate := range nodes {
+			if visited[candidate] {
+				continue
+			}
+
+			if nearest < 0 || distance(last, candidate) < distance(last, nearest) {
+				nearest = candidate
+			}
+		}
+
+		visited[nearest] = true
+		path = append(path, nearest)
+	}
+
+	// The first node stays fixed as the start of the path. The path is open, so
+	// reversing a segment that ends at the last node only changes one edge.
+	for improved := true; improved; {
+		improved = false
+
+		for i := 1; i < len(path)-1; i++ {
+			for k := i + 1; k < len(path); k++ {
+				before := distance(path[i-1], path[i])
+				after := distance(path[i-1], path[k])
+
+				if k+1 < len(path) {
+					before += distance(path[k], path[k+1])
+					after += distance(path[i], path[k+1])
+				}
+
+				if after < before-1e-9 {
+					for left, right := i, k; left < right; left, right = left+1, right-1 {
+						path[left], path[right] = path[right], path[left]
+					}
+
+					improved = true
+				}
+			}
+		}
+	}
+
+	order := []int{}
+
+	for _, node := range path[offset:] {
+		order = append(order, node-offset)
+	}
+
+	return order
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDistanceKilometres(t *testing.T) {
+	tests := []struct {
+		name string
+		from Coordinates
+		to   Coordinates
+		want float64
+	}{
+		{name: "same point", from: Coordinates{Latitude: -26.2, Longitude: 28.04}, to: Coordinates{Latitude: -26.2, Longitude: 28.04}, want: 0},
+		{name: "one degree of latitude", from: Coordinates{Latitude: 0, Longitude: 0}, to: Coordinates{Latitude: 1, Longitude: 0}, want: 111.19},
+		{name: "one degree of longitude at the equator", from: Coordinates{Latitude: 0, Longitude: 0}, to: Coordinates{Latitude: 0, Longitude: 1}, want: 111.19},
+		{name: "Johannesburg to Cape Town", from: Coordinates{Latitude: -26.2041, Longitude: 28.0473}, to: Coordinates{Latitude: -33.9249, Longitude: 18.4241}, want: 1261.6},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := distanceKilometres(test.from, test.to)
+
+			if math.Abs(got-test.want) > 0.1 {
+				t.Errorf("distanceKilometres() = %.2f, want %.2f", got, test.want)
+			}
+
+			if reverse := distanceKilometres(test.to, test.from); math.Abs(reverse-got) > 1e-9 {
+				t.Errorf("distance is not symmetric: %.6f and %.6f", got, reverse)
+			}
+		})
+	}
+}
+
+func TestPlanRoute(t *testing.T) {
+	along := func(longitudes ...float64) []Coordinates {
+		stops := []Coordinates{}
+
+		for _, longitude := range longitudes {
+			stops = append(stops, Coordinates{Latitude: 0, Longitude: longitude})
+		}
+
+		return stops
+	}
+
+	tests := []struct {
+		name  string
+		start *Coordinates
+		stops []Coordinates
+		want  []int
+	}{
+		{name: "no stops", stops: []Coordinates{}, want: []int{}},
+		{name: "no stops from a start", start: &Coordinates{}, stops: []Coordinates{}, want: []int{}},
+		{name: "single stop", start: &Coordinates{}, stops: along(1), want: []int{0}},
+		{name: "stops on a line are visited in order from the start", start: &Coordinates{}, stops: along(0.3, 0.1, 0.2), want: []int{1, 2, 0}},
+		{name: "without a start the first stop stays first", stops: along(0, 0.3, 0.1, 0.2), want: []int{0, 2, 3, 1}},
+		{
+			// Nearest neighbour visits 2, 3, 0 and then crosses back to 1.
+			// Reversing 3 and 0 uncrosses the legs, which is the shortest
+			// route.
+			name:  "crossing legs are uncrossed",
+			start: &Coordinates{},
+			stops: []Coordinates{
+				{Latitude: 0, Longitude: 0.4},
+				{Latitude: 0.4, Longitude: 0},
+				{Latitude: 0.1, Longitude: 0.3},
+				{Latitude: 0.2, Longitude: 0.4},
+			},
+			want: []int{2, 0, 3, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := planRoute(test.start, test.stops)
+
+			if !slices.Equal(got, test.want) {
+				t.Errorf("planRoute() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlanRouteVisitsEveryStopOnce(t *testing.T) {
+	stops := []Coordinates{}
+
+	for i := 0; i < 25; i++ {
+		stops = append(stops, Coordinates{
+			Latitude:  -26 + math.Sin(float64(i)*1.7)/10,
+			Longitude: 28 + math.Cos(float64(i)*2.3)/10,
+		})
+	}
+
+	order := planRoute(&Coordinates{Latitude: -26, Longitude: 28}, stops)
+
+	sorted := slices.Clone(order)
+	slices.Sort(sorted)
+
+	for i, stop := range sorted {
+		if stop != i {
+			t.Fatalf("planRoute() = %v, want every stop exactly once", order)
+		}
+	}
+}
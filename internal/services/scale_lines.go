@@ -0,0 +1,148 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// scaleUnits converts the weight units indicators print to kilograms.
+var scaleUnits = map[string]float64{
+	"":    1,
+	"kg":  1,
+	"g":   0.001,
+	"t":   1000,
+	"lb":  0.45359237,
+	"lbs": 0.45359237,
+}
+
+// scaleWeightKinds maps the labels indicators print before a weight to whether
+// the weight is gross, tare or net.
+var scaleWeightKinds = map[string]string{
+	"G":     "gross",
+	"GS":    "gross",
+	"GR":    "gross",
+	"GROSS": "gross",
+	"T":     "tare",
+	"TR":    "tare",
+	"TA":    "tare",
+	"PT":    "tare",
+	"TARE":  "tare",
+	"N":     "net",
+	"NT":    "net",
+	"NET":   "net",
+}
+
+var scaleWeightPattern = regexp.MustCompile(`^([+-]?)\s*(\d+(?:\.\d+)?)\s*([a-zA-Z]*)$`)
+
+// indicatorReading is the weights parsed from the lines printed by an
+// indicator, in kilograms.
+type indicatorReading struct {
+	Gross  *float64
+	Tare   *float64
+	Net    *float64
+	Stable bool
+}
+
+// parseIndicatorLines reads the weights from the lines an indicator prints
+// over its serial or TCP port. Lines in the "ST,GS,+001234.5kg" format of
+// most indicators are understood, as are lines labelled "G", "T" and "N" or
+// "GROSS", "TARE" and "NET", and unlabelled lines, which are taken to be the
+// gross weight. A reading is only stable when no line was marked unstable.
+func parseIndicatorLines(raw string) (*indicatorReading, error) {
+	reading := indicatorReading{
+		Stable: true,
+	}
+
+	found := false
+
+	for _, line := range strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	}) {
+		line = strings.TrimFunc(line, func(r rune) bool {
+			return r < ' ' || r == ' '
+		})
+
+		if line == "" {
+			continue
+		}
+
+		kind := "gross"
+		weight := line
+
+		if fields := strings.Split(line, ","); len(fields) > 1 {
+			switch strings.ToUpper(strings.TrimSpace(fields[0])) {
+			case "ST":
+			case "US":
+				reading.Stable = false
+			default:
+				// Overloaded ("OL") and unknown statuses carry no weight.
+				return nil, ErrInvalidScaleReading
+			}
+
+			fields = fields[1:]
+
+			if label, ok := scaleWeightKinds[strings.ToUpper(strings.TrimSpace(fields[0]))]; ok && len(fields) > 1 {
+				kind = label
+				fields = fields[1:]
+			}
+
+			weight = strings.Join(fields, "")
+		} else if words := strings.Fields(line); len(words) > 1 {
+			if label, ok := scaleWeightKinds[strings.ToUpper(strings.TrimSuffix(words[0], ":"))]; ok {
+				kind = label
+				weight = strings.Join(words[1:], " ")
+			}
+		}
+
+		value, err := parseIndicatorWeight(weight)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case "gross":
+			reading.Gross = &value
+		case "tare":
+			reading.Tare = &value
+		case "net":
+			reading.Net = &value
+		}
+
+		found = true
+	}
+
+	if !found {
+		return nil, ErrInvalidScaleReading
+	}
+
+	return &reading, nil
+}
+
+// parseIndicatorWeight reads a weight such as "+001234.5kg" in kilograms.
+func parseIndicatorWeight(text string) (float64, error) {
+	match := scaleWeightPattern.FindStringSubmatch(strings.TrimSpace(text))
+
+	if match == nil {
+		return 0, ErrInvalidScaleReading
+	}
+
+	value, err := strconv.ParseFloat(match[2], 64)
+
+	if err != nil {
+		return 0, ErrInvalidScaleReading
+	}
+
+	factor, ok := scaleUnits[strings.ToLower(match[3])]
+
+	if !ok {
+		return 0, ErrInvalidScaleReading
+	}
+
+	if match[1] == "-" {
+		value = -value
+	}
+
+	return value * factor, nil
+}
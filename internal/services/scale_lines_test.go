@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseIndicatorWeight(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+		err  error
+	}{
+		{text: "+001234.5kg", want: 1234.5},
+		{text: "1234.5 kg", want: 1234.5},
+		{text: "  250  ", want: 250},
+		{text: "-12.5kg", want: -12.5},
+		{text: "- 12.5 KG", want: -12.5},
+		{text: "500g", want: 0.5},
+		{text: "1.2t", want: 1200},
+		{text: "100lb", want: 45.359237},
+		{text: "100 lbs", want: 45.359237},
+		{text: "", err: ErrInvalidScaleReading},
+		{text: "kg", err: ErrInvalidScaleReading},
+		{text: "12.kg", err: ErrInvalidScaleReading},
+		{text: "12oz", err: ErrInvalidScaleReading},
+		{text: "1,234kg", err: ErrInvalidScaleReading},
+	}
+
+	for _, test := range tests {
+		got, err := parseIndicatorWeight(test.text)
+
+		if err != test.err {
+			t.Errorf("parseIndicatorWeight(%q) error = %v, want %v", test.text, err, test.err)
+
+			continue
+		}
+
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("parseIndicatorWeight(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseIndicatorLines(t *testing.T) {
+	weight := func(value float64) *float64 {
+		return &value
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		want *indicatorReading
+		err  error
+	}{
+		{
+			name: "status and label",
+			raw:  "ST,GS,+001234.5kg\r\n",
+			want: &indicatorReading{Gross: weight(1234.5), Stable: true},
+		},
+		{
+			name: "unstable",
+			raw:  "US,GS,+001234.5kg",
+			want: &indicatorReading{Gross: weight(1234.5), Stable: false},
+		},
+		{
+			name: "status without label",
+			raw:  "ST,+000080.0kg",
+			want: &indicatorReading{Gross: weight(80), Stable: true},
+		},
+		{
+			name: "gross tare and net",
+			raw:  "ST,GS,+001234.5kg\r\nST,TR,+000234.5kg\r\nST,NT,+001000.0kg\r\n",
+			want: &indicatorReading{Gross: weight(1234.5), Tare: weight(234.5), Net: weight(1000), Stable: true},
+		},
+		{
+			name: "one unstable line",
+			raw:  "ST,GS,+001234.5kg\nUS,NT,+001000.0kg",
+			want: &indicatorReading{Gross: weight(1234.5), Net: weight(1000), Stable: false},
+		},
+		{
+			name: "labelled words",
+			raw:  "GROSS: 1234.5 kg\nTARE: 234.5 kg\nNET: 1000 kg",
+			want: &indicatorReading{Gross: weight(1234.5), Tare: weight(234.5), Net: weight(1000), Stable: true},
+		},
+		{
+			name: "short labels",
+			raw:  "G 12.5\nT 2.5\nN 10",
+			want: &indicatorReading{Gross: weight(12.5), Tare: weight(2.5), Net: weight(10), Stable: true},
+		},
+		{
+			name: "unlabelled",
+			raw:  "\x02  1234.5 kg\x03\r\n",
+			want: &indicatorReading{Gross: weight(1234.5), Stable: true},
+		},
+		{
+			name: "blank lines",
+			raw:  "\r\n\r\n  \nST,GS,+000010.0kg\n\n",
+			want: &indicatorReading{Gross: weight(10), Stable: true},
+		},
+		{
+			name: "later line wins",
+			raw:  "ST,GS,+000010.0kg\nST,GS,+000012.0kg",
+			want: &indicatorReading{Gross: weight(12), Stable: true},
+		},
+		{name: "empty", raw: "", err: ErrInvalidScaleReading},
+		{name: "only blank lines", raw: "\r\n \x03\n", err: ErrInvalidScaleReading},
+		{name: "overloaded", raw: "OL,GS,+999999.9kg", err: ErrInvalidScaleReading},
+		{name: "unknown status", raw: "XX,GS,+000010.0kg", err: ErrInvalidScaleReading},
+		{name: "unknown unit", raw: "ST,GS,+000010.0oz", err: ErrInvalidScaleReading},
+		{name: "not a weight", raw: "hello world", err: ErrInvalidScaleReading},
+		{name: "one bad line", raw: "ST,GS,+000010.0kg\nST,NT,abc", err: ErrInvalidScaleReading},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseIndicatorLines(test.raw)
+
+			if err != test.err {
+				t.Fatalf("parseIndicatorLines() error = %v, want %v", err, test.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got.Stable != test.want.Stable {
+				t.Errorf("Stable = %v, want %v", got.Stable, test.want.Stable)
+			}
+
+			for _, field := range []struct {
+				name string
+				got  *float64
+				want *float64
+			}{
+				{"Gross", got.Gross, test.want.Gross},
+				{"Tare", got.Tare, test.want.Tare},
+				{"Net", got.Net, test.want.Net},
+			} {
+				switch {
+				case field.got == nil && field.want == nil:
+				case field.got == nil || field.want == nil:
+					t.Errorf("%s = %v, want %v", field.name, field.got, field.want)
+				case math.Abs(*field.got-*field.want) > 1e-9:
+					t.Errorf("%s = %v, want %v", field.name, *field.got, *field.want)
+				}
+			}
+		})
+	}
+}
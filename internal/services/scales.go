@@ -0,0 +1,568 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type scalesService interface {
+	Create(organizationId uuid.UUID, payload models.CreateScalePayload) (*models.Scale, error)
+	Update(scaleId uuid.UUID, organizationId uuid.UUID, version int64, payload models.UpdateScalePayload) error
+	RotateKey(scaleId uuid.UUID, organizationId uuid.UUID) (*models.Scale, error)
+	Delete(scaleId uuid.UUID, organizationId uuid.UUID, version int64) error
+	Authenticate(key string) (*models.Scale, error)
+	Record(scale models.Scale, payload models.ScaleReadingPayload) (*models.ScaleReading, error)
+	Find(scaleId uuid.UUID) (*models.Scale, error)
+	List(clauses ...clause.Expression) ([]models.Scale, error)
+	Count(clauses ...clause.Expression) (int64, error)
+	ListReadings(clauses ...clause.Expression) ([]models.ScaleReading, error)
+	CountReadings(clauses ...clause.Expression) (int64, error)
+}
+
+type scales struct {
+	storage storage.Storage
+}
+
+func newScalesService(storage storage.Storage) scalesService {
+	return &scales{
+		storage: storage,
+	}
+}
+
+const (
+	// scaleKeyPrefix starts every device key, so that leaked keys are easy to
+	// recognise.
+	scaleKeyPrefix = "scale_"
+	// scaleClockSkew is how far in the future a scale's clock may be.
+	scaleClockSkew = 5 * time.Minute
+	// scaleReadingWindow is how long after it was captured a reading is
+	// available for a collection line. Weights typed by hand while a reading
+	// is available are flagged.
+	scaleReadingWindow = 15 * time.Minute
+)
+
+// Create registers a scale at a site of an organization with a new device key.
+// The key is only returned here and when it is rotated.
+func (s *scales) Create(organizationId uuid.UUID, payload models.CreateScalePayload) (*models.Scale, error) {
+	payload.Name = strings.TrimSpace(payload.Name)
+
+	if payload.Name == "" {
+		return nil, ErrInvalidScale
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, &payload.SiteId, organizationId); err != nil {
+		return nil, err
+	}
+
+	key, err := newScaleKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	scale := models.Scale{
+		OrganizationId: organizationId,
+		SiteId:         payload.SiteId,
+		Name:           payload.Name,
+		Active:         true,
+		KeyHash:        hashScaleKey(key),
+		KeyPrefix:      key[:len(scaleKeyPrefix)+6],
+	}
+
+	if err := s.storage.Postgres.
+		Create(&scale).Error; err != nil {
+		return nil, err
+	}
+
+	// Active defaults to true in the database, so a scale registered inactive
+	// is deactivated after it is created.
+	if payload.Active != nil && !*payload.Active {
+		if err := s.storage.Postgres.
+			Model(&models.Scale{}).
+			Where("id = ?", scale.Id).
+			Update("active", false).Error; err != nil {
+			return nil, err
+		}
+
+		scale.Active = false
+	}
+
+	scale.Key = key
+
+	return &scale, nil
+}
+
+func (s *scales) Update(scaleId uuid.UUID, organizationId uuid.UUID, version int64, payload models.UpdateScalePayload) error {
+	var scale models.Scale
+
+	if err := s.storage.Postgres.
+		Where("id = ? AND organization_id = ?", scaleId, organizationId).
+		First(&scale).Error; err != nil {
+		return err
+	}
+
+	if payload.SiteId != nil {
+		scale.SiteId = *payload.SiteId
+	}
+
+	if payload.Name != nil {
+		scale.Name = strings.TrimSpace(*payload.Name)
+	}
+
+	if payload.Active != nil {
+		scale.Active = *payload.Active
+	}
+
+	if scale.Name == "" {
+		return ErrInvalidScale
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, &scale.SiteId, organizationId); err != nil {
+		return err
+	}
+
+	result := s.storage.Postgres.
+		Model(&models.Scale{}).
+		Select("site_id", "name", "active").
+		Where("id = ? AND version = ?", scaleId, version).
+		Updates(&scale)
+
+	return checkVersion(s.storage.Postgres, &models.Scale{}, scaleId, result)
+}
+
+// RotateKey replaces the device key of a scale, returning the scale with its
+// new key. The old key stops working immediately.
+func (s *scales) RotateKey(scaleId uuid.UUID, organizationId uuid.UUID) (*models.Scale, error) {
+	var scale models.Scale
+
+	if err := s.storage.Postgres.
+		Where("id = ? AND organization_id = ?", scaleId, organizationId).
+		First(&scale).Error; err != nil {
+		return nil, err
+	}
+
+	key, err := newScaleKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	scale.KeyHash = hashScaleKey(key)
+	scale.KeyPrefix = key[:len(scaleKeyPrefix)+6]
+
+	if err := s.storage.Postgres.
+		Model(&models.Scale{}).
+		Select("key_hash", "key_prefix").
+		Where("id = ?", scaleId).
+		Updates(&scale).Error; err != nil {
+		return nil, err
+	}
+
+	scale.Key = key
+
+	return &scale, nil
+}
+
+// Delete removes a scale that has not recorded any readings. Scales with
+// readings are kept so that the weights taken from them can be checked, and
+// are deactivated instead.
+func (s *scales) Delete(scaleId uuid.UUID, organizationId uuid.UUID, version int64) error {
+	var scale models.Scale
+
+	if err := s.storage.Postgres.
+		Where("id = ? AND organization_id = ?", scaleId, organizationId).
+		First(&scale).Error; err != nil {
+		return err
+	}
+
+	var readings int64
+
+	if err := s.storage.Postgres.
+		Model(&models.ScaleReading{}).
+		Where("scale_id = ?", scaleId).
+		Count(&readings).Error; err != nil {
+		return err
+	}
+
+	if readings > 0 {
+		return ErrScaleHasReadings
+	}
+
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", scaleId, version).
+		Delete(&models.Scale{})
+
+	return checkVersion(s.storage.Postgres, &models.Scale{}, scaleId, result)
+}
+
+// Authenticate returns the active scale with the device key and records that
+// the scale was seen.
+func (s *scales) Authenticate(key string) (*models.Scale, error) {
+	var scale *models.Scale
+
+	if err := s.storage.Postgres.
+		Where("key_hash = ? AND active = ?", hashScaleKey(key), true).
+		First(&scale).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if err := s.storage.Postgres.
+		Model(&models.Scale{}).
+		Where("id = ?", scale.Id).
+		UpdateColumn("last_seen_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	scale.LastSeenAt = &now
+
+	return scale, nil
+}
+
+// Record stores a reading reported by a scale. Raw indicator lines fill in the
+// weights not given in the payload. Readings of a scale are recorded one at a
+// time, so that each is chained to the one before it.
+func (s *scales) Record(scale models.Scale, payload models.ScaleReadingPayload) (*models.ScaleReading, error) {
+	factor, ok := scaleUnits[strings.ToLower(strings.TrimSpace(payload.Unit))]
+
+	if !ok {
+		return nil, ErrInvalidScaleReading
+	}
+
+	convert := func(weight *float64) *float64 {
+		if weight == nil {
+			return nil
+		}
+
+		converted := *weight * factor
+
+		return &converted
+	}
+
+	gross, tare, net := convert(payload.Gross), convert(payload.Tare), convert(payload.Net)
+	stable := payload.Stable == nil || *payload.Stable
+
+	var raw *string
+
+	if strings.TrimSpace(payload.Raw) != "" {
+		parsed, err := parseIndicatorLines(payload.Raw)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if gross == nil {
+			gross = parsed.Gross
+		}
+
+		if tare == nil {
+			tare = parsed.Tare
+		}
+
+		if net == nil {
+			net = parsed.Net
+		}
+
+		stable = stable && parsed.Stable
+		raw = &payload.Raw
+	}
+
+	reading, err := completeScaleReading(gross, tare, net)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	reading.ScaleId = scale.Id
+	reading.Stable = stable
+	reading.Raw = raw
+	reading.CapturedAt = now
+
+	if payload.CapturedAt != nil {
+		if payload.CapturedAt.After(now.Add(scaleClockSkew)) {
+			return nil, ErrInvalidScaleReading
+		}
+
+		reading.CapturedAt = *payload.CapturedAt
+	}
+
+	// Postgres keeps times to the microsecond, so the checksum is taken of
+	// the time as it is stored.
+	reading.CapturedAt = reading.CapturedAt.Truncate(time.Microsecond)
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", scale.Id).
+			First(&models.Scale{}).Error; err != nil {
+			return err
+		}
+
+		var previous []models.ScaleReading
+
+		if err := tx.
+			Where("scale_id = ?", scale.Id).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&previous).Error; err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			reading.PreviousChecksum = &previous[0].Checksum
+		}
+
+		reading.Checksum = scaleReadingChecksum(*reading)
+
+		return tx.Create(reading).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return reading, nil
+}
+
+func (s *scales) Find(scaleId uuid.UUID) (*models.Scale, error) {
+	var scale *models.Scale
+
+	if err := s.storage.Postgres.
+		Where("id = ?", scaleId).
+		Preload("Site").
+		First(&scale).Error; err != nil {
+		return nil, err
+	}
+
+	return scale, nil
+}
+
+func (s *scales) List(clauses ...clause.Expression) ([]models.Scale, error) {
+	var scales []models.Scale
+
+	if err := s.storage.Postgres.
+		Preload("Site").
+		Clauses(clauses...).
+		Order("name ASC").
+		Find(&scales).Error; err != nil {
+		return nil, err
+	}
+
+	return scales, nil
+}
+
+func (s *scales) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Scale{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *scales) ListReadings(clauses ...clause.Expression) ([]models.ScaleReading, error) {
+	var readings []models.ScaleReading
+
+	if err := s.storage.Postgres.
+		Clauses(clauses...).
+		Order("captured_at DESC").
+		Find(&readings).Error; err != nil {
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func (s *scales) CountReadings(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.ScaleReading{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// completeScaleReading works out the weight of a reading that was not given
+// from the other two. A reading without a tare has a tare of zero.
+func completeScaleReading(gross *float64, tare *float64, net *float64) (*models.ScaleReading, error) {
+	var reading models.ScaleReading
+
+	switch {
+	case gross != nil && net != nil && tare == nil:
+		reading.Gross, reading.Net = *gross, *net
+		reading.Tare = *gross - *net
+	case gross != nil:
+		reading.Gross = *gross
+
+		if tare != nil {
+			reading.Tare = *tare
+		}
+
+		reading.Net = reading.Gross - reading.Tare
+
+		if net != nil && math.Abs(*net-reading.Net) > 0.01 {
+			return nil, ErrInvalidScaleReading
+		}
+	case net != nil:
+		reading.Net = *net
+
+		if tare != nil {
+			reading.Tare = *tare
+		}
+
+		reading.Gross = reading.Net + reading.Tare
+	default:
+		return nil, ErrInvalidScaleReading
+	}
+
+	reading.Gross = roundWeight(reading.Gross)
+	reading.Tare = roundWeight(reading.Tare)
+	reading.Net = roundWeight(reading.Net)
+
+	if reading.Gross < 0 || reading.Tare < 0 || reading.Net < 0 {
+		return nil, ErrInvalidScaleReading
+	}
+
+	return &reading, nil
+}
+
+// roundWeight rounds a weight to the 10 grams weights are stored to.
+func roundWeight(weight float64) float64 {
+	return math.Round(weight*100) / 100
+}
+
+// scaleReadingChecksum is the hex SHA-256 of the values of a reading and the
+// checksum of the reading before it.
+func scaleReadingChecksum(reading models.ScaleReading) string {
+	previous := ""
+
+	if reading.PreviousChecksum != nil {
+		previous = *reading.PreviousChecksum
+	}
+
+	raw := ""
+
+	if reading.Raw != nil {
+		raw = *reading.Raw
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		previous,
+		reading.ScaleId.String(),
+		strconv.FormatFloat(reading.Gross, 'f', 2, 64),
+		strconv.FormatFloat(reading.Tare, 'f', 2, 64),
+		strconv.FormatFloat(reading.Net, 'f', 2, 64),
+		strconv.FormatBool(reading.Stable),
+		reading.CapturedAt.UTC().Format(time.RFC3339Nano),
+		raw,
+	}, "\n")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func newScaleKey() (string, error) {
+	key := make([]byte, 24)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return scaleKeyPrefix + hex.EncodeToString(key), nil
+}
+
+func hashScaleKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// weighCollectionMaterial sets the weight of a line of a collection from the
+// scale reading it references, after checking that the reading is a stable,
+// untampered reading of a scale of the buying organization at the site of the
+// collection that no other line uses. A line weighed by hand is flagged when a
+// reading it could have used was available.
+func weighCollectionMaterial(tx *gorm.DB, collection models.Collection, line *models.CollectionMaterial) error {
+	scaleClauses := tx.
+		Model(&models.Scale{}).
+		Select("id").
+		Where("organization_id = ?", collection.BuyerId)
+
+	if collection.SiteId != nil {
+		scaleClauses = scaleClauses.Where("site_id = ?", *collection.SiteId)
+	}
+
+	if line.ScaleReadingId == nil {
+		var available int64
+
+		if err := tx.
+			Model(&models.ScaleReading{}).
+			Where("scale_id IN (?)", scaleClauses.Where("active = ?", true)).
+			Where("stable = ? AND net > 0 AND captured_at >= ?", true, time.Now().Add(-scaleReadingWindow)).
+			Where("NOT EXISTS (SELECT 1 FROM collection_materials WHERE collection_materials.scale_reading_id = scale_readings.id)").
+			Count(&available).Error; err != nil {
+			return err
+		}
+
+		line.ManualOverride = available > 0
+
+		return nil
+	}
+
+	var reading models.ScaleReading
+
+	if err := tx.
+		Where("id = ? AND scale_id IN (?)", *line.ScaleReadingId, scaleClauses).
+		First(&reading).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrScaleReadingUnavailable
+		}
+
+		return err
+	}
+
+	if !reading.Stable || reading.Net <= 0 {
+		return ErrScaleReadingUnavailable
+	}
+
+	if scaleReadingChecksum(reading) != reading.Checksum {
+		return ErrScaleReadingTampered
+	}
+
+	var used int64
+
+	if err := tx.
+		Model(&models.CollectionMaterial{}).
+		Where("scale_reading_id = ? AND id <> ?", reading.Id, line.Id).
+		Count(&used).Error; err != nil {
+		return err
+	}
+
+	if used > 0 {
+		return ErrScaleReadingUnavailable
+	}
+
+	line.Weight = reading.Net
+	line.ManualOverride = false
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCompleteScaleReading(t *testing.T) {
+	weight := func(value float64) *float64 {
+		return &value
+	}
+
+	tests := []struct {
+		name  string
+		gross *float64
+		tare  *float64
+		net   *float64
+		want  [3]float64
+		err   error
+	}{
+		{name: "gross only", gross: weight(120), want: [3]float64{120, 0, 120}},
+		{name: "gross and tare", gross: weight(120), tare: weight(20), want: [3]float64{120, 20, 100}},
+		{name: "gross and net", gross: weight(120), net: weight(100), want: [3]float64{120, 20, 100}},
+		{name: "net only", net: weight(100), want: [3]float64{100, 0, 100}},
+		{name: "net and tare", tare: weight(20), net: weight(100), want: [3]float64{120, 20, 100}},
+		{name: "all agree", gross: weight(120), tare: weight(20), net: weight(100), want: [3]float64{120, 20, 100}},
+		{name: "within 10 grams", gross: weight(120), tare: weight(20), net: weight(100.005), want: [3]float64{120, 20, 100}},
+		{name: "rounded to 10 grams", gross: weight(12.345), tare: weight(0.004), want: [3]float64{12.35, 0, 12.34}},
+		{name: "disagree", gross: weight(120), tare: weight(20), net: weight(90), err: ErrInvalidScaleReading},
+		{name: "tare over gross", gross: weight(20), tare: weight(30), err: ErrInvalidScaleReading},
+		{name: "negative", gross: weight(-5), err: ErrInvalidScaleReading},
+		{name: "tare only", tare: weight(20), err: ErrInvalidScaleReading},
+		{name: "nothing", err: ErrInvalidScaleReading},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reading, err := completeScaleReading(test.gross, test.tare, test.net)
+
+			if err != test.err {
+				t.Fatalf("completeScaleReading() error = %v, want %v", err, test.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := [3]float64{reading.Gross, reading.Tare, reading.Net}; got != test.want {
+				t.Errorf("completeScaleReading() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScaleReadingChecksum(t *testing.T) {
+	raw := "ST,GS,+001234.5kg"
+
+	reading := models.ScaleReading{
+		ScaleId:    uuid.MustParse("6f1c1d1a-9d54-4a55-8d3c-0a6a0f0e7b11"),
+		Gross:      1234.5,
+		Tare:       234.5,
+		Net:        1000,
+		Stable:     true,
+		CapturedAt: time.Date(2025, 3, 1, 10, 30, 0, 500000000, time.FixedZone("SAST", 2*60*60)),
+		Raw:        &raw,
+	}
+
+	want := "275e0119e7e036ac4da16f76b043c8d836d6a14d4e6dfdf5665d215921e25c2e"
+
+	if got := scaleReadingChecksum(reading); got != want {
+		t.Fatalf("scaleReadingChecksum() = %s, want %s", got, want)
+	}
+
+	changes := map[string]func(reading *models.ScaleReading){
+		"previous checksum": func(reading *models.ScaleReading) { reading.PreviousChecksum = &want },
+		"scale":             func(reading *models.ScaleReading) { reading.ScaleId = uuid.New() },
+		"gross":             func(reading *models.ScaleReading) { reading.Gross = 1234.6 },
+		"tare":              func(reading *models.ScaleReading) { reading.Tare = 234.6 },
+		"net":               func(reading *models.ScaleReading) { reading.Net = 1000.1 },
+		"stable":            func(reading *models.ScaleReading) { reading.Stable = false },
+		"captured at":       func(reading *models.ScaleReading) { reading.CapturedAt = reading.CapturedAt.Add(time.Millisecond) },
+		"raw":               func(reading *models.ScaleReading) { reading.Raw = nil },
+	}
+
+	for name, change := range changes {
+		changed := reading
+
+		change(&changed)
+
+		if scaleReadingChecksum(changed) == want {
+			t.Errorf("changing the %s does not change the checksum", name)
+		}
+	}
+}
+
+func TestScaleKeys(t *testing.T) {
+	key, err := newScaleKey()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(key, scaleKeyPrefix) || len(key) != len(scaleKeyPrefix)+48 {
+		t.Errorf("newScaleKey() = %q, want %s followed by 48 hex digits", key, scaleKeyPrefix)
+	}
+
+	if other, _ := newScaleKey(); other == key {
+		t.Error("newScaleKey() returned the same key twice")
+	}
+
+	if got, want := hashScaleKey("sk_test"), "12b2820cf1639904311da5771de1e5bb65c77073fdc7c555df395942df42896b"; got != want {
+		t.Errorf("hashScaleKey() = %s, want %s", got, want)
+	}
+}
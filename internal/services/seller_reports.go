@@ -0,0 +1,188 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sellerReportsService interface {
+	Earnings(sellerId uuid.UUID, query models.SellerReportQuery) (*models.SellerEarnings, error)
+	Impact(sellerId uuid.UUID, query models.SellerReportQuery) (*models.SellerImpact, error)
+}
+
+type sellerReports struct {
+	storage storage.Storage
+}
+
+func newSellerReportsService(storage storage.Storage) sellerReportsService {
+	return &sellerReports{
+		storage: storage,
+	}
+}
+
+var reportPeriods = []models.ReportPeriod{
+	models.DailyPeriod,
+	models.WeeklyPeriod,
+	models.MonthlyPeriod,
+	models.YearlyPeriod,
+}
+
+// reportedCollectionStatuses are the statuses of collections whose weights
+// and values are final and count towards a seller's earnings and impact.
+var reportedCollectionStatuses = []models.CollectionStatus{
+	models.CollectionConfirmed,
+	models.CollectionPaid,
+}
+
+// Earnings totals the value of the confirmed collections of a seller by
+// material and by period.
+func (s *sellerReports) Earnings(sellerId uuid.UUID, query models.SellerReportQuery) (*models.SellerEarnings, error) {
+	if !slices.Contains(reportPeriods, query.Period) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	var totals struct {
+		Total float64
+		Paid  float64
+	}
+
+	if err := s.lines(sellerId, query).
+		Select(`
+			COALESCE(SUM(collection_materials.value), 0) AS total,
+			COALESCE(SUM(collection_materials.value) FILTER (WHERE collections.status = ?), 0) AS paid
+		`, models.CollectionPaid).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	materials, err := s.materialTotals(sellerId, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.periodTotals(sellerId, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SellerEarnings{
+		Period:      query.Period,
+		Total:       totals.Total,
+		Paid:        totals.Paid,
+		Outstanding: totals.Total - totals.Paid,
+		Materials:   materials,
+		Periods:     periods,
+	}, nil
+}
+
+// Impact totals the weight of the confirmed collections of a seller and the
+// carbon avoided by recycling it, using the carbon factor of each material.
+func (s *sellerReports) Impact(sellerId uuid.UUID, query models.SellerReportQuery) (*models.SellerImpact, error) {
+	if !slices.Contains(reportPeriods, query.Period) {
+		return nil, ErrInvalidReportPeriod
+	}
+
+	var totals struct {
+		Collections   int64
+		Weight        float64
+		CarbonAvoided float64
+	}
+
+	if err := s.lines(sellerId, query).
+		Select(`
+			COUNT(DISTINCT collections.id) AS collections,
+			COALESCE(SUM(collection_materials.weight), 0) AS weight,
+			COALESCE(SUM(collection_materials.weight * materials.carbon_factor), 0) AS carbon_avoided
+		`).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	materials, err := s.materialTotals(sellerId, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.periodTotals(sellerId, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SellerImpact{
+		Period:        query.Period,
+		Collections:   totals.Collections,
+		Weight:        totals.Weight,
+		CarbonAvoided: totals.CarbonAvoided,
+		Materials:     materials,
+		Periods:       periods,
+	}, nil
+}
+
+func (s *sellerReports) materialTotals(sellerId uuid.UUID, query models.SellerReportQuery) ([]models.MaterialTotal, error) {
+	materials := []models.MaterialTotal{}
+
+	if err := s.lines(sellerId, query).
+		Select(`
+			materials.id AS material_id,
+			materials.name AS material_name,
+			SUM(collection_materials.weight) AS weight,
+			SUM(collection_materials.value) AS value,
+			SUM(collection_materials.weight * materials.carbon_factor) AS carbon_avoided
+		`).
+		Group("materials.id, materials.name").
+		Order("materials.name ASC").
+		Scan(&materials).Error; err != nil {
+		return nil, err
+	}
+
+	return materials, nil
+}
+
+func (s *sellerReports) periodTotals(sellerId uuid.UUID, query models.SellerReportQuery) ([]models.PeriodTotal, error) {
+	periods := []models.PeriodTotal{}
+
+	if err := s.lines(sellerId, query).
+		Select(`
+			DATE_TRUNC(?, collections.created_at, ?) AS period,
+			SUM(collection_materials.weight) AS weight,
+			SUM(collection_materials.value) AS value,
+			SUM(collection_materials.weight * materials.carbon_factor) AS carbon_avoided
+		`, string(query.Period), reportTimeZone).
+		Group("1").
+		Order("1 ASC").
+		Scan(&periods).Error; err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// lines selects the material lines of the reported collections of a seller
+// within the range of the query.
+func (s *sellerReports) lines(sellerId uuid.UUID, query models.SellerReportQuery) *gorm.DB {
+	db := s.storage.Postgres.
+		Model(&models.Collection{}).
+		Joins("JOIN collections_materials ON collections_materials.collection_id = collections.id").
+		Joins("JOIN collection_materials ON collection_materials.id = collections_materials.collection_material_id").
+		Joins("JOIN materials ON materials.id = collection_materials.material_id").
+		Where("collections.seller_id = ?", sellerId).
+		Where("collections.status IN ?", reportedCollectionStatuses)
+
+	if query.From != nil {
+		db = db.Where("collections.created_at >= ?", *query.From)
+	}
+
+	if query.To != nil {
+		db = db.Where("collections.created_at < ?", *query.To)
+	}
+
+	return db
+}
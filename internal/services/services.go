@@ -4,6 +4,9 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 )
 
+// streamBatchSize is how many records Stream methods load at a time.
+const streamBatchSize = 500
+
 type Services interface {
 	Users() usersService
 	Roles() rolesService
@@ -13,40 +16,109 @@ type Services interface {
 	Materials() materialsService
 	Collections() collectionsService
 	Transactions() transactionsService
+	Inventory() inventoryService
+	Sites() sitesService
+	Payouts() payoutsService
+	Pickups() pickupsService
+	SellerReports() sellerReportsService
+	Reports() reportsService
+	ReportSummaries() reportSummariesService
+	EprReports() eprReportsService
+	Imports() importsService
+	Jobs() jobsService
+	Webhooks() webhooksService
+	Changes() changesService
+	Scales() scalesService
+	Attachments() attachmentsService
+	Trash() trashService
+	Idempotency() idempotencyService
+	Sync() syncService
 }
 
 type services struct {
-	storage       storage.Storage
-	users         usersService
-	roles         rolesService
-	organizations organizationsService
-	addresses     addressesService
-	bankDetails   bankDetailsService
-	materials     materialsService
-	collections   collectionsService
-	transactions  transactionsService
-}
-
-func NewServices(storage storage.Storage) Services {
+	storage         storage.Storage
+	users           usersService
+	roles           rolesService
+	organizations   organizationsService
+	addresses       addressesService
+	bankDetails     bankDetailsService
+	materials       materialsService
+	collections     collectionsService
+	transactions    transactionsService
+	inventory       inventoryService
+	sites           sitesService
+	payouts         payoutsService
+	pickups         pickupsService
+	sellerReports   sellerReportsService
+	reports         reportsService
+	reportSummaries reportSummariesService
+	eprReports      eprReportsService
+	imports         importsService
+	jobs            jobsService
+	webhooks        webhooksService
+	changes         changesService
+	scales          scalesService
+	attachments     attachmentsService
+	trash           trashService
+	idempotency     idempotencyService
+	sync            syncService
+}
+
+func NewServices(storage storage.Storage, geocoder Geocoder) Services {
 	users := newUsersService(storage)
 	roles := newRolesService(storage)
 	organizations := newOrganizationsService(storage)
-	addresses := newAddressesService(storage)
-	bankDetails := newBankDetailsService(storage)
+
+	addresses := newAddressesService(storage, geocoder)
+	bankDetails := newBankDetailsService(storage, stubAccountVerifier{})
 	materials := newMaterialsService(storage)
 	collections := newCollectionsService(storage)
 	transactions := newTransactionsService(storage)
+	inventory := newInventoryService(storage)
+	sites := newSitesService(storage, geocoder)
+	payouts := newPayoutsService(storage)
+	pickups := newPickupsService(storage)
+	sellerReports := newSellerReportsService(storage)
+	reports := newReportsService(storage)
+	reportSummaries := newReportSummariesService(storage)
+	eprReports := newEprReportsService(storage)
+	imports := newImportsService(storage)
+	jobs := newJobsService(storage)
+	webhooks := newWebhooksService(storage)
+	changes := newChangesService(storage)
+	scales := newScalesService(storage)
+	attachments := newAttachmentsService(storage)
+	trash := newTrashService(storage)
+	idempotency := newIdempotencyService(storage)
+	sync := newSyncService(storage, collections)
 
 	return &services{
-		storage:       storage,
-		users:         users,
-		roles:         roles,
-		organizations: organizations,
-		addresses:     addresses,
-		bankDetails:   bankDetails,
-		materials:     materials,
-		collections:   collections,
-		transactions:  transactions,
+		storage:         storage,
+		users:           users,
+		roles:           roles,
+		organizations:   organizations,
+		addresses:       addresses,
+		bankDetails:     bankDetails,
+		materials:       materials,
+		collections:     collections,
+		transactions:    transactions,
+		inventory:       inventory,
+		sites:           sites,
+		payouts:         payouts,
+		pickups:         pickups,
+		sellerReports:   sellerReports,
+		reports:         reports,
+		reportSummaries: reportSummaries,
+		eprReports:      eprReports,
+		imports:         imports,
+		jobs:            jobs,
+		webhooks:        webhooks,
+		changes:         changes,
+		scales:          scales,
+		attachments:     attachments,
+		trash:           trash,
+		idempotency:     idempotency,
+		sync:            sync,
 	}
 }
 
@@ -81,3 +153,71 @@ func (s *services) Collections() collectionsService {
 func (s *services) Transactions() transactionsService {
 	return s.transactions
 }
+
+func (s *services) Inventory() inventoryService {
+	return s.inventory
+}
+
+func (s *services) Sites() sitesService {
+	return s.sites
+}
+
+func (s *services) Payouts() payoutsService {
+	return s.payouts
+}
+
+func (s *services) Pickups() pickupsService {
+	return s.pickups
+}
+
+func (s *services) SellerReports() sellerReportsService {
+	return s.sellerReports
+}
+
+func (s *services) Reports() reportsService {
+	return s.reports
+}
+
+func (s *services) ReportSummaries() reportSummariesService {
+	return s.reportSummaries
+}
+
+func (s *services) EprReports() eprReportsService {
+	return s.eprReports
+}
+
+func (s *services) Imports() importsService {
+	return s.imports
+}
+
+func (s *services) Jobs() jobsService {
+	return s.jobs
+}
+
+func (s *services) Webhooks() webhooksService {
+	return s.webhooks
+}
+
+func (s *services) Changes() changesService {
+	return s.changes
+}
+
+func (s *services) Scales() scalesService {
+	return s.scales
+}
+
+func (s *services) Attachments() attachmentsService {
+	return s.attachments
+}
+
+func (s *services) Trash() trashService {
+	return s.trash
+}
+
+func (s *services) Idempotency() idempotencyService {
+	return s.idempotency
+}
+
+func (s *services) Sync() syncService {
+	return s.sync
+}
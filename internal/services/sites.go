@@ -0,0 +1,325 @@
+package services
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sitesService interface {
+	Create(organizationId uuid.UUID, payload models.CreateSitePayload) (uuid.UUID, error)
+	Update(siteId uuid.UUID, version int64, payload models.UpdateSitePayload) error
+	Delete(siteId uuid.UUID, version int64) error
+	Find(siteId uuid.UUID) (*models.Site, error)
+	List(clauses ...clause.Expression) ([]models.Site, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type sites struct {
+	storage  storage.Storage
+	geocoder Geocoder
+}
+
+func newSitesService(storage storage.Storage, geocoder Geocoder) sitesService {
+	return &sites{
+		storage:  storage,
+		geocoder: geocoder,
+	}
+}
+
+func (s *sites) Create(organizationId uuid.UUID, payload models.CreateSitePayload) (uuid.UUID, error) {
+	if err := validateSite(payload.Latitude, payload.Longitude, payload.OperatingHours); err != nil {
+		return uuid.Nil, err
+	}
+
+	var site models.Site
+
+	site.OrganizationId = organizationId
+	site.Name = payload.Name
+	site.Latitude = payload.Latitude
+	site.Longitude = payload.Longitude
+
+	if payload.Address != nil {
+		address := newAddress(*payload.Address)
+
+		if err := prepareAddress(s.geocoder, &address); err != nil {
+			return uuid.Nil, err
+		}
+
+		site.Address = &address
+
+		locateSite(&site, address)
+	}
+
+	for _, hours := range payload.OperatingHours {
+		site.OperatingHours = append(site.OperatingHours, models.SiteOperatingHour{
+			DayOfWeek: hours.DayOfWeek,
+			Opens:     hours.Opens,
+			Closes:    hours.Closes,
+		})
+	}
+
+	if err := s.storage.Postgres.Create(&site).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if payload.Users != nil {
+		if err := s.storage.Postgres.
+			Model(&site).Association("Users").Append(payload.Users); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	return site.Id, nil
+}
+
+func (s *sites) Update(siteId uuid.UUID, version int64, payload models.UpdateSitePayload) error {
+	var site models.Site
+
+	if err := s.storage.Postgres.Where("id = ?", siteId).First(&site).Error; err != nil {
+		return err
+	}
+
+	if payload.Name != nil {
+		site.Name = *payload.Name
+	}
+
+	if payload.Latitude != nil {
+		site.Latitude = payload.Latitude
+	}
+
+	if payload.Longitude != nil {
+		site.Longitude = payload.Longitude
+	}
+
+	var address *models.Address
+
+	if payload.Address != nil {
+		prepared := newAddress(*payload.Address)
+
+		if err := prepareAddress(s.geocoder, &prepared); err != nil {
+			return err
+		}
+
+		address = &prepared
+
+		locateSite(&site, prepared)
+	}
+
+	if err := validateSite(site.Latitude, site.Longitude, payload.OperatingHours); err != nil {
+		return err
+	}
+
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Site{}).
+			Where("id = ? AND version = ?", siteId, version).
+			Updates(&map[string]any{
+				"name":      site.Name,
+				"latitude":  site.Latitude,
+				"longitude": site.Longitude,
+			})
+
+		if err := checkVersion(tx, &models.Site{}, siteId, result); err != nil {
+			return err
+		}
+
+		if address != nil {
+			if site.AddressId != nil {
+				columns := addressColumns(*address)
+
+				if err := tx.
+					Model(&models.Address{}).
+					Where("id = ?", *site.AddressId).
+					Updates(&columns).Error; err != nil {
+					return err
+				}
+			} else {
+				if err := tx.Create(address).Error; err != nil {
+					return err
+				}
+
+				if err := tx.
+					Model(&models.Site{}).
+					Where("id = ?", siteId).
+					Update("address_id", address.Id).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if payload.OperatingHours != nil {
+			if err := tx.
+				Unscoped().
+				Where("site_id = ?", siteId).
+				Delete(&models.SiteOperatingHour{}).Error; err != nil {
+				return err
+			}
+
+			for _, hours := range payload.OperatingHours {
+				if err := tx.Create(&models.SiteOperatingHour{
+					SiteId:    siteId,
+					DayOfWeek: hours.DayOfWeek,
+					Opens:     hours.Opens,
+					Closes:    hours.Closes,
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if payload.Users != nil {
+			if err := tx.
+				Model(&site).Association("Users").Replace(payload.Users); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *sites) Delete(siteId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", siteId, version).
+		Delete(&models.Site{})
+
+	return checkVersion(s.storage.Postgres, &models.Site{}, siteId, result)
+}
+
+func (s *sites) Find(siteId uuid.UUID) (*models.Site, error) {
+	var site *models.Site
+
+	if err := s.storage.Postgres.
+		Where("id = ?", siteId).
+		Preload("Address").
+		Preload("OperatingHours", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_of_week ASC")
+		}).
+		Preload("Users").
+		First(&site).Error; err != nil {
+		return nil, err
+	}
+
+	return site, nil
+}
+
+func (s *sites) List(clauses ...clause.Expression) ([]models.Site, error) {
+	var sites []models.Site
+
+	if err := s.storage.Postgres.
+		Preload("Address").
+		Clauses(clauses...).
+		Order("name ASC").
+		Find(&sites).Error; err != nil {
+		return nil, err
+	}
+
+	return sites, nil
+}
+
+func (s *sites) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.Site{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// validateSite checks that coordinates fall within their valid ranges and that
+// each day's operating hours are well formed and close after they open.
+func validateSite(latitude *float64, longitude *float64, operatingHours []models.SiteOperatingHourPayload) error {
+	if latitude != nil && (*latitude < -90 || *latitude > 90) {
+		return ErrInvalidSite
+	}
+
+	if longitude != nil && (*longitude < -180 || *longitude > 180) {
+		return ErrInvalidSite
+	}
+
+	for _, hours := range operatingHours {
+		if hours.DayOfWeek < 0 || hours.DayOfWeek > 6 {
+			return ErrInvalidSite
+		}
+
+		opens, err := time.Parse("15:04", hours.Opens)
+
+		if err != nil {
+			return ErrInvalidSite
+		}
+
+		closes, err := time.Parse("15:04", hours.Closes)
+
+		if err != nil || !closes.After(opens) {
+			return ErrInvalidSite
+		}
+	}
+
+	return nil
+}
+
+// siteForOrganization returns the site if it is operated by the given
+// organization, or nil otherwise. It is used to attribute stock movements of a
+// collection or transaction to the site of the party that operates it.
+func siteForOrganization(tx *gorm.DB, siteId *uuid.UUID, organizationId uuid.UUID) (*uuid.UUID, error) {
+	if siteId == nil {
+		return nil, nil
+	}
+
+	var count int64
+
+	if err := tx.
+		Model(&models.Site{}).
+		Where("id = ? AND organization_id = ?", *siteId, organizationId).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	return siteId, nil
+}
+
+// ensureSiteOperatedBy returns ErrInvalidSite when the site is not operated by
+// any of the given organizations.
+func ensureSiteOperatedBy(tx *gorm.DB, siteId *uuid.UUID, organizationIds ...uuid.UUID) error {
+	if siteId == nil {
+		return nil
+	}
+
+	var count int64
+
+	if err := tx.
+		Model(&models.Site{}).
+		Where("id = ? AND organization_id IN ?", *siteId, organizationIds).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrInvalidSite
+	}
+
+	return nil
+}
+
+// locateSite places a site without coordinates at its geocoded address.
+func locateSite(site *models.Site, address models.Address) {
+	if site.Latitude != nil || site.Longitude != nil {
+		return
+	}
+
+	site.Latitude = address.Latitude
+	site.Longitude = address.Longitude
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type syncService interface {
+	Collections(organizationId uuid.UUID, payload models.SyncCollectionsPayload) ([]models.SyncCollectionResult, error)
+	Changes(organizationId uuid.UUID, since *time.Time) (*models.SyncChanges, error)
+}
+
+type syncs struct {
+	storage     storage.Storage
+	collections collectionsService
+}
+
+func newSyncService(storage storage.Storage, collections collectionsService) syncService {
+	return &syncs{
+		storage:     storage,
+		collections: collections,
+	}
+}
+
+// syncBatchSize is the most records a client may upload in one sync.
+const syncBatchSize = 100
+
+// syncCursorOverlap is how far before its cursor the changes since a sync are
+// read again. A record is only seen once the transaction that changed it has
+// committed, which may be after the cursor of a sync that read the changes
+// in the meantime.
+const syncCursorOverlap = time.Minute
+
+// syncRejections are the errors that reject an uploaded record, which are
+// reported back to the client instead of failing the sync.
+var syncRejections = []error{
+	ErrInvalidSite,
+	ErrScaleReadingUnavailable,
+	ErrScaleReadingTampered,
+	ErrSellerNotVerified,
+	ErrMissingReference,
+}
+
+// Collections creates the collections a client recorded offline for the
+// organization it is syncing, which must be their buyer, with the same
+// validation as collections created online. Each collection is created on its
+// own, so that one that is rejected or conflicts does not hold back the
+// others, and a collection that was created by an earlier sync is not created
+// again.
+func (s *syncs) Collections(organizationId uuid.UUID, payload models.SyncCollectionsPayload) ([]models.SyncCollectionResult, error) {
+	if len(payload.Collections) > syncBatchSize {
+		return nil, ErrTooManySyncRecords
+	}
+
+	results := make([]models.SyncCollectionResult, 0, len(payload.Collections))
+
+	for _, upload := range payload.Collections {
+		result, err := s.collection(organizationId, upload)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (s *syncs) collection(organizationId uuid.UUID, upload models.SyncCollectionPayload) (models.SyncCollectionResult, error) {
+	result := models.SyncCollectionResult{
+		Id: upload.Id,
+	}
+
+	if upload.Id == uuid.Nil {
+		return rejectSync(result, ErrInvalidSyncId), nil
+	}
+
+	if upload.BuyerId != organizationId {
+		return rejectSync(result, ErrSyncBuyerNotActive), nil
+	}
+
+	// Clients recording offline may have their clocks set wrong, so a
+	// collection is never recorded as created in the future.
+	createdAt := upload.CreatedAt
+
+	if createdAt.IsZero() || createdAt.After(time.Now()) {
+		createdAt = time.Now()
+	}
+
+	err := s.collections.CreateWithId(upload.Id, createdAt, upload.CreateCollectionPayload)
+
+	if translateError(s.storage.Postgres, err) == gorm.ErrForeignKeyViolated {
+		err = ErrMissingReference
+	}
+
+	switch {
+	case err == nil:
+		result.Status = models.SyncCreated
+
+		return result, nil
+	case err == ErrCollectionExists:
+		return s.existingCollection(organizationId, result, upload)
+	case slices.Contains(syncRejections, err):
+		return rejectSync(result, err), nil
+	default:
+		return result, err
+	}
+}
+
+// existingCollection compares an uploaded collection with the collection that
+// already has its id. It is unchanged when it matches, which is the case when
+// a sync is retried, and conflicts otherwise. A collection that another
+// organization bought, including one in the trash, is not compared or
+// returned, and the upload is rejected without saying why.
+func (s *syncs) existingCollection(organizationId uuid.UUID, result models.SyncCollectionResult, upload models.SyncCollectionPayload) (models.SyncCollectionResult, error) {
+	var buyerIds []uuid.UUID
+
+	if err := s.storage.Postgres.
+		Unscoped().
+		Model(&models.Collection{}).
+		Where("id = ?", upload.Id).
+		Pluck("buyer_id", &buyerIds).Error; err != nil {
+		return result, err
+	}
+
+	if len(buyerIds) == 0 || buyerIds[0] != organizationId {
+		return rejectSync(result, ErrSyncIdUnavailable), nil
+	}
+
+	result.Status = models.SyncConflict
+
+	collection, err := s.collections.Find(upload.Id)
+
+	if err == gorm.ErrRecordNotFound {
+		message := ErrSyncDeleted.Error()
+
+		result.Message = &message
+
+		return result, nil
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.storage.Postgres.
+		Where("id IN (SELECT collection_material_id FROM collections_materials WHERE collection_id = ?)", collection.Id).
+		Preload("Material").
+		Find(&collection.Materials).Error; err != nil {
+		return result, err
+	}
+
+	if syncedCollectionMatches(*collection, upload.CreateCollectionPayload) {
+		result.Status = models.SyncUnchanged
+
+		return result, nil
+	}
+
+	message := ErrSyncChanged.Error()
+
+	result.Message = &message
+	result.Collection = collection
+
+	return result, nil
+}
+
+// syncedCollectionMatches reports whether a collection has the parties and
+// lines of the payload it was uploaded with. A line weighed on a scale takes
+// its weight from the reading, so only the reading is compared.
+func syncedCollectionMatches(collection models.Collection, payload models.CreateCollectionPayload) bool {
+	if collection.SellerId != payload.SellerId || collection.BuyerId != payload.BuyerId {
+		return false
+	}
+
+	if (collection.SiteId == nil) != (payload.SiteId == nil) ||
+		(collection.SiteId != nil && *collection.SiteId != *payload.SiteId) {
+		return false
+	}
+
+	if len(collection.Materials) != len(payload.Materials) {
+		return false
+	}
+
+	remaining := slices.Clone(collection.Materials)
+
+	for _, material := range payload.Materials {
+		index := slices.IndexFunc(remaining, func(line models.CollectionMaterial) bool {
+			if line.MaterialId != material.MaterialId || !sameAmount(line.Value, material.Value) {
+				return false
+			}
+
+			if line.ScaleReadingId != nil || material.ScaleReadingId != nil {
+				return line.ScaleReadingId != nil && material.ScaleReadingId != nil &&
+					*line.ScaleReadingId == *material.ScaleReadingId
+			}
+
+			return sameAmount(line.Weight, material.Weight)
+		})
+
+		if index < 0 {
+			return false
+		}
+
+		remaining = slices.Delete(remaining, index, index+1)
+	}
+
+	return true
+}
+
+// sameAmount compares weights and values as they are stored, to the cent.
+func sameAmount(a float64, b float64) bool {
+	return math.Round(a*100) == math.Round(b*100)
+}
+
+func rejectSync(result models.SyncCollectionResult, err error) models.SyncCollectionResult {
+	message := err.Error()
+
+	result.Status = models.SyncRejected
+	result.Message = &message
+
+	return result
+}
+
+// Changes returns the materials and the sellers of an organization that were
+// changed or deleted since the cursor of an earlier sync, or all of them for a
+// first sync. Sellers are the members of the organization and the users who
+// have sold collections to it, and only what is needed to record their
+// collections is returned of them.
+func (s *syncs) Changes(organizationId uuid.UUID, since *time.Time) (*models.SyncChanges, error) {
+	changes := models.SyncChanges{
+		Cursor:           time.Now(),
+		Materials:        []models.Material{},
+		Sellers:          []models.SyncSeller{},
+		DeletedMaterials: []uuid.UUID{},
+		DeletedSellers:   []uuid.UUID{},
+	}
+
+	sellers := clause.Expr{
+		SQL:  "(id IN (SELECT user_id FROM organization_users WHERE organization_id = ?) OR id IN (SELECT seller_id FROM collections WHERE buyer_id = ? AND deleted_at IS NULL))",
+		Vars: []any{organizationId, organizationId},
+	}
+
+	materialsQuery := s.storage.Postgres.Model(&models.Material{})
+	sellersQuery := s.storage.Postgres.Model(&models.User{}).Clauses(sellers)
+
+	if since != nil {
+		after := since.Add(-syncCursorOverlap)
+
+		if err := s.storage.Postgres.
+			Unscoped().
+			Model(&models.Material{}).
+			Where("deleted_at >= ?", after).
+			Pluck("id", &changes.DeletedMaterials).Error; err != nil {
+			return nil, err
+		}
+
+		if err := s.storage.Postgres.
+			Unscoped().
+			Model(&models.User{}).
+			Clauses(sellers).
+			Where("deleted_at >= ?", after).
+			Pluck("id", &changes.DeletedSellers).Error; err != nil {
+			return nil, err
+		}
+
+		materialsQuery = materialsQuery.Where("updated_at >= ?", after)
+		sellersQuery = sellersQuery.Where("updated_at >= ?", after)
+	}
+
+	if err := materialsQuery.
+		Order("name ASC").
+		Find(&changes.Materials).Error; err != nil {
+		return nil, err
+	}
+
+	if err := sellersQuery.
+		Select("id, name, masked_identity_number, identity_status, version").
+		Order("name ASC").
+		Find(&changes.Sellers).Error; err != nil {
+		return nil, err
+	}
+
+	return &changes, nil
+}
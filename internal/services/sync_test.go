@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestSyncedCollectionMatches(t *testing.T) {
+	sellerId, buyerId, siteId := uuid.New(), uuid.New(), uuid.New()
+	plastic, glass := uuid.New(), uuid.New()
+	reading, otherReading := uuid.New(), uuid.New()
+
+	collection := models.Collection{
+		SellerId: sellerId,
+		BuyerId:  buyerId,
+		SiteId:   &siteId,
+		Materials: []models.CollectionMaterial{
+			{MaterialId: plastic, Weight: 12.5, Value: 25},
+			{MaterialId: glass, Weight: 40.004, Value: 8.1, ScaleReadingId: &reading},
+			{MaterialId: plastic, Weight: 3, Value: 6},
+		},
+	}
+
+	payload := func(change func(payload *models.CreateCollectionPayload)) models.CreateCollectionPayload {
+		payload := models.CreateCollectionPayload{
+			SellerId: sellerId,
+			BuyerId:  buyerId,
+			SiteId:   &siteId,
+			Materials: []models.CreateCollectionMaterialPayload{
+				{MaterialId: plastic, Weight: 3, Value: 6},
+				{MaterialId: plastic, Weight: 12.5, Value: 25},
+				{MaterialId: glass, Value: 8.1, ScaleReadingId: &reading},
+			},
+		}
+
+		if change != nil {
+			change(&payload)
+		}
+
+		return payload
+	}
+
+	tests := []struct {
+		name    string
+		payload models.CreateCollectionPayload
+		want    bool
+	}{
+		{"same lines in another order", payload(nil), true},
+		{"amounts within a cent", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[1].Weight = 12.501
+			p.Materials[1].Value = 24.996
+		}), true},
+		{"another seller", payload(func(p *models.CreateCollectionPayload) {
+			p.SellerId = uuid.New()
+		}), false},
+		{"another buyer", payload(func(p *models.CreateCollectionPayload) {
+			p.BuyerId = uuid.New()
+		}), false},
+		{"another site", payload(func(p *models.CreateCollectionPayload) {
+			other := uuid.New()
+			p.SiteId = &other
+		}), false},
+		{"no site", payload(func(p *models.CreateCollectionPayload) {
+			p.SiteId = nil
+		}), false},
+		{"a line missing", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials = p.Materials[:2]
+		}), false},
+		{"a line repeated instead of another", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[0] = p.Materials[1]
+		}), false},
+		{"another weight", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[0].Weight = 3.02
+		}), false},
+		{"another value", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[0].Value = 6.5
+		}), false},
+		{"another material", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[0].MaterialId = glass
+		}), false},
+		{"another scale reading", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[2].ScaleReadingId = &otherReading
+		}), false},
+		{"weighed by hand instead of on the scale", payload(func(p *models.CreateCollectionPayload) {
+			p.Materials[2].ScaleReadingId = nil
+			p.Materials[2].Weight = 40.004
+		}), false},
+	}
+
+	for _, test := range tests {
+		if got := syncedCollectionMatches(collection, test.payload); got != test.want {
+			t.Errorf("%s: syncedCollectionMatches() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSyncChanges(t *testing.T) {
+	s := testStorage(t)
+
+	organization := testOrganization(t, s)
+	seller := testUser(t, s, organization.Id, models.IdentityVerified)
+	outsider := testUser(t, s, testOrganization(t, s).Id, models.IdentityVerified)
+
+	changes, err := newSyncService(s, newCollectionsService(s)).Changes(organization.Id, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found *models.SyncSeller
+
+	for index, synced := range changes.Sellers {
+		if synced.Id == outsider.Id {
+			t.Errorf("Changes() returned a seller of another organization")
+		}
+
+		if synced.Id == seller.Id {
+			found = &changes.Sellers[index]
+		}
+	}
+
+	if found == nil {
+		t.Fatalf("Changes() did not return the seller")
+	}
+
+	if found.Name != seller.Name || found.IdentityStatus != models.IdentityVerified || found.Version == 0 {
+		t.Errorf("seller = %+v, want %s, %s and a version", *found, seller.Name, models.IdentityVerified)
+	}
+}
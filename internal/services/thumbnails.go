@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	// thumbnailSize is the longest side of a thumbnail in pixels.
+	thumbnailSize = 320
+	// thumbnailMaxPixels is the most pixels an image may have to be
+	// thumbnailed, so that a small file cannot decode to a huge image.
+	thumbnailMaxPixels = 50_000_000
+)
+
+// makeThumbnail shrinks a JPEG, PNG or GIF image to fit a square of
+// thumbnailSize pixels, averaging the pixels each thumbnail pixel covers, and
+// encodes it as a JPEG. It reports false for images it cannot thumbnail.
+func makeThumbnail(content []byte) ([]byte, bool) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+
+	if err != nil || config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > thumbnailMaxPixels {
+		return nil, false
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, false
+	}
+
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := max(float64(width), float64(height)) / thumbnailSize
+
+	if scale < 1 {
+		scale = 1
+	}
+
+	thumbnailWidth := max(1, int(float64(width)/scale))
+	thumbnailHeight := max(1, int(float64(height)/scale))
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, thumbnailWidth, thumbnailHeight))
+
+	for y := range thumbnailHeight {
+		top := bounds.Min.Y + y*height/thumbnailHeight
+		bottom := max(top+1, bounds.Min.Y+(y+1)*height/thumbnailHeight)
+
+		for x := range thumbnailWidth {
+			left := bounds.Min.X + x*width/thumbnailWidth
+			right := max(left+1, bounds.Min.X+(x+1)*width/thumbnailWidth)
+
+			var r, g, b, a, count uint64
+
+			for sourceY := top; sourceY < bottom; sourceY++ {
+				for sourceX := left; sourceX < right; sourceX++ {
+					pixelR, pixelG, pixelB, pixelA := source.At(sourceX, sourceY).RGBA()
+
+					r += uint64(pixelR)
+					g += uint64(pixelG)
+					b += uint64(pixelB)
+					a += uint64(pixelA)
+					count++
+				}
+			}
+
+			// Transparent areas are drawn on white, as JPEG has no alpha.
+			average := func(channel uint64) uint8 {
+				return uint8((channel/count + (0xffff - a/count)) >> 8)
+			}
+
+			thumbnail.SetRGBA(x, y, color.RGBA{
+				R: average(r),
+				G: average(g),
+				B: average(b),
+				A: 0xff,
+			})
+		}
+	}
+
+	var encoded bytes.Buffer
+
+	if err := jpeg.Encode(&encoded, thumbnail, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, false
+	}
+
+	return encoded.Bytes(), true
+}
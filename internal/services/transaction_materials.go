@@ -4,13 +4,14 @@ import (
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type transactionMaterialsService interface {
 	Create(transactionMaterial models.CreateTransactionMaterialPayload) (uuid.UUID, error)
-	Update(transactionMaterialId uuid.UUID, transactionMaterial models.UpdateTransactionMaterialPayload) error
-	Delete(transactionMaterialId uuid.UUID) error
+	Update(transactionMaterialId uuid.UUID, version int64, transactionMaterial models.UpdateTransactionMaterialPayload) error
+	Delete(transactionMaterialId uuid.UUID, version int64) error
 	Find(transactionMaterialId uuid.UUID) (*models.TransactionMaterial, error)
 	List(clauses ...clause.Expression) ([]models.TransactionMaterial, error)
 	Count(clauses ...clause.Expression) (int64, error)
@@ -27,21 +28,47 @@ func newTransactionMaterialsService(storage storage.Storage) transactionMaterial
 }
 
 func (s *transactionMaterials) Create(payload models.CreateTransactionMaterialPayload) (uuid.UUID, error) {
+	var transaction models.Transaction
+
+	if err := s.storage.Postgres.
+		Where("id = ?", payload.TransactionId).
+		First(&transaction).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if transaction.Status.Locked() {
+		return uuid.Nil, ErrTransactionLocked
+	}
+
 	var transactionMaterial models.TransactionMaterial
 
 	transactionMaterial.MaterialId = payload.MaterialId
 	transactionMaterial.Weight = payload.Weight
 	transactionMaterial.Value = payload.Value
 
-	if err := s.storage.Postgres.
-		Create(&transactionMaterial).Error; err != nil {
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if !payload.OverrideStock {
+			if err := ensureQuotedStockAvailable(tx, &transaction, uuid.Nil, payload.MaterialId, payload.Weight); err != nil {
+				return err
+			}
+		}
+
+		return tx.
+			Model(&transaction).
+			Association("Materials").
+			Append(&transactionMaterial)
+	}); err != nil {
 		return uuid.Nil, err
 	}
 
 	return transactionMaterial.Id, nil
 }
 
-func (s *transactionMaterials) Update(transactionMaterialId uuid.UUID, payload models.UpdateTransactionMaterialPayload) error {
+func (s *transactionMaterials) Update(transactionMaterialId uuid.UUID, version int64, payload models.UpdateTransactionMaterialPayload) error {
+	if err := s.ensureUnlocked(transactionMaterialId); err != nil {
+		return err
+	}
+
 	var transactionMaterial models.TransactionMaterial
 
 	if err := s.storage.Postgres.
@@ -62,28 +89,48 @@ func (s *transactionMaterials) Update(transactionMaterialId uuid.UUID, payload m
 		transactionMaterial.Value = *payload.Value
 	}
 
-	if err := s.storage.Postgres.
-		Model(&models.TransactionMaterial{}).
-		Where("id = ?", transactionMaterialId).
-		Updates(&map[string]any{
-			"material_id": transactionMaterial.MaterialId,
-			"weight":      transactionMaterial.Weight,
-			"value":       transactionMaterial.Value,
-		}).Error; err != nil {
-		return err
-	}
-
-	return nil
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if !payload.OverrideStock {
+			var transaction models.Transaction
+
+			if err := tx.
+				Joins("JOIN transactions_materials ON transactions_materials.transaction_id = transactions.id").
+				Where("transactions_materials.transaction_material_id = ?", transactionMaterialId).
+				First(&transaction).Error; err != nil {
+				return err
+			}
+
+			if err := ensureQuotedStockAvailable(tx, &transaction, transactionMaterialId, transactionMaterial.MaterialId, transactionMaterial.Weight); err != nil {
+				return err
+			}
+		}
+
+		result := tx.
+			Model(&models.TransactionMaterial{}).
+			Where("id = ? AND version = ?", transactionMaterialId, version).
+			Updates(&map[string]any{
+				"material_id": transactionMaterial.MaterialId,
+				"weight":      transactionMaterial.Weight,
+				"value":       transactionMaterial.Value,
+			})
+
+		return checkVersion(tx, &models.TransactionMaterial{}, transactionMaterialId, result)
+	})
 }
 
-func (s *transactionMaterials) Delete(transactionMaterialId uuid.UUID) error {
-	if err := s.storage.Postgres.
-		Where("id = ?", transactionMaterialId).
-		Delete(&models.TransactionMaterial{}).Error; err != nil {
+func (s *transactionMaterials) Delete(transactionMaterialId uuid.UUID, version int64) error {
+	if err := s.ensureUnlocked(transactionMaterialId); err != nil {
 		return err
 	}
 
-	return nil
+	// Lines can only be removed while their parent is a draft, so they are
+	// removed outright rather than kept in the trash.
+	result := s.storage.Postgres.
+		Unscoped().
+		Where("id = ? AND version = ?", transactionMaterialId, version).
+		Delete(&models.TransactionMaterial{})
+
+	return checkVersion(s.storage.Postgres, &models.TransactionMaterial{}, transactionMaterialId, result)
 }
 
 func (s *transactionMaterials) Find(transactionMaterialId uuid.UUID) (*models.TransactionMaterial, error) {
@@ -122,3 +169,49 @@ func (s *transactionMaterials) Count(clauses ...clause.Expression) (int64, error
 
 	return count, nil
 }
+
+// ensureUnlocked returns ErrTransactionLocked when the transaction that owns
+// the given line is no longer an open quote.
+func (s *transactionMaterials) ensureUnlocked(transactionMaterialId uuid.UUID) error {
+	var statuses []models.TransactionStatus
+
+	if err := s.storage.Postgres.
+		Model(&models.Transaction{}).
+		Joins("JOIN transactions_materials ON transactions_materials.transaction_id = transactions.id").
+		Where("transactions_materials.transaction_material_id = ?", transactionMaterialId).
+		Pluck("transactions.status", &statuses).Error; err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Locked() {
+			return ErrTransactionLocked
+		}
+	}
+
+	return nil
+}
+
+// ensureQuotedStockAvailable checks that the seller has enough of a material
+// on hand to cover the given weight together with the transaction's other
+// lines of the same material. The line identified by excludeId is left out of
+// the total so that it is not counted twice when it is being updated. It must
+// be called within the database transaction that changes the line.
+func ensureQuotedStockAvailable(tx *gorm.DB, transaction *models.Transaction, excludeId uuid.UUID, materialId uuid.UUID, weight float64) error {
+	var quoted float64
+
+	if err := tx.
+		Model(&models.TransactionMaterial{}).
+		Select("COALESCE(SUM(transaction_materials.weight), 0)").
+		Joins("JOIN transactions_materials ON transactions_materials.transaction_material_id = transaction_materials.id").
+		Where("transactions_materials.transaction_id = ?", transaction.Id).
+		Where("transaction_materials.material_id = ?", materialId).
+		Where("transaction_materials.id <> ?", excludeId).
+		Scan(&quoted).Error; err != nil {
+		return err
+	}
+
+	return ensureStockAvailable(tx, transaction.SellerId, map[uuid.UUID]float64{
+		materialId: quoted + weight,
+	})
+}
@@ -1,19 +1,31 @@
 package services
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type transactionsService interface {
 	Materials() transactionMaterialsService
 	Create(transaction models.CreateTransactionPayload) (uuid.UUID, error)
-	Update(transactionId uuid.UUID, transaction models.UpdateTransactionPayload) error
-	Delete(transactionId uuid.UUID) error
+	Update(transactionId uuid.UUID, version int64, transaction models.UpdateTransactionPayload) error
+	Delete(transactionId uuid.UUID, version int64) error
+	Accept(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) error
+	Reject(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, reason *string) error
+	Cancel(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, reason *string) error
+	Deliver(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.DeliverTransactionPayload) error
+	Invoice(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) (uuid.UUID, error)
+	Settle(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.SettleInvoicePayload) error
 	Find(transactionId uuid.UUID) (*models.Transaction, error)
 	List(clauses ...clause.Expression) ([]models.Transaction, error)
+	Stream(handle func(models.Transaction) error, clauses ...clause.Expression) error
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
@@ -40,16 +52,48 @@ func (s *transactions) Create(payload models.CreateTransactionPayload) (uuid.UUI
 
 	transaction.SellerId = payload.SellerId
 	transaction.BuyerId = payload.BuyerId
+	transaction.SiteId = payload.SiteId
+	transaction.Status = models.TransactionQuoted
+	transaction.QuoteExpiresAt = payload.QuoteExpiresAt
 
-	if err := s.storage.Postgres.
-		Create(&transaction).Error; err != nil {
+	for _, material := range payload.Materials {
+		transaction.Materials = append(transaction.Materials, models.TransactionMaterial{
+			MaterialId: material.MaterialId,
+			Weight:     material.Weight,
+			Value:      material.Value,
+		})
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, transaction.SiteId, transaction.SellerId, transaction.BuyerId); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if !payload.OverrideStock {
+			requested := map[uuid.UUID]float64{}
+
+			for _, material := range transaction.Materials {
+				requested[material.MaterialId] += material.Weight
+			}
+
+			if err := ensureStockAvailable(tx, transaction.SellerId, requested); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		return publishTransactionEvents(tx, transaction.Id, models.TransactionCreatedEvent)
+	}); err != nil {
 		return uuid.Nil, err
 	}
 
 	return transaction.Id, nil
 }
 
-func (s *transactions) Update(transactionId uuid.UUID, payload models.UpdateTransactionPayload) error {
+func (s *transactions) Update(transactionId uuid.UUID, version int64, payload models.UpdateTransactionPayload) error {
 	var transaction models.Transaction
 
 	if err := s.storage.Postgres.
@@ -58,6 +102,10 @@ func (s *transactions) Update(transactionId uuid.UUID, payload models.UpdateTran
 		return err
 	}
 
+	if transaction.Status.Locked() {
+		return ErrTransactionLocked
+	}
+
 	if payload.SellerId != nil {
 		transaction.SellerId = *payload.SellerId
 	}
@@ -66,27 +114,249 @@ func (s *transactions) Update(transactionId uuid.UUID, payload models.UpdateTran
 		transaction.BuyerId = *payload.BuyerId
 	}
 
-	if err := s.storage.Postgres.
-		Model(&models.Transaction{}).
-		Where("id = ?", transactionId).
-		Updates(&map[string]any{
-			"seller_id": transaction.SellerId,
-			"buyer_id":  transaction.BuyerId,
-		}).Error; err != nil {
+	if payload.SiteId != nil {
+		transaction.SiteId = payload.SiteId
+	}
+
+	if payload.QuoteExpiresAt != nil {
+		transaction.QuoteExpiresAt = payload.QuoteExpiresAt
+	}
+
+	if err := ensureSiteOperatedBy(s.storage.Postgres, transaction.SiteId, transaction.SellerId, transaction.BuyerId); err != nil {
 		return err
 	}
 
-	return nil
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Transaction{}).
+			Where("id = ? AND version = ?", transactionId, version).
+			Updates(&map[string]any{
+				"seller_id":        transaction.SellerId,
+				"buyer_id":         transaction.BuyerId,
+				"site_id":          transaction.SiteId,
+				"quote_expires_at": transaction.QuoteExpiresAt,
+			})
+
+		if err := checkVersion(tx, &models.Transaction{}, transactionId, result); err != nil {
+			return err
+		}
+
+		return publishTransactionEvents(tx, transactionId, models.TransactionUpdatedEvent)
+	})
 }
 
-func (s *transactions) Delete(transactionId uuid.UUID) error {
+// Delete removes a transaction that never went ahead. Accepted transactions
+// form part of the commercial history and can only be cancelled.
+func (s *transactions) Delete(transactionId uuid.UUID, version int64) error {
+	var transaction models.Transaction
+
 	if err := s.storage.Postgres.
 		Where("id = ?", transactionId).
-		Delete(&models.Transaction{}).Error; err != nil {
+		First(&transaction).Error; err != nil {
 		return err
 	}
 
-	return nil
+	switch transaction.Status {
+	case models.TransactionQuoted, models.TransactionRejected, models.TransactionCancelled:
+	default:
+		return ErrTransactionLocked
+	}
+
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", transactionId, version).
+		Delete(&models.Transaction{})
+
+	return checkVersion(s.storage.Postgres, &models.Transaction{}, transactionId, result)
+}
+
+// Accept is performed by the buyer organization to accept the seller's quote.
+func (s *transactions) Accept(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) error {
+	return s.transition(transactionId, organizationId, performedById, models.BuyerSide, models.TransactionAccepted, nil,
+		func(tx *gorm.DB, transaction *models.Transaction) error {
+			if transaction.QuoteExpiresAt != nil && time.Now().After(*transaction.QuoteExpiresAt) {
+				return ErrQuoteExpired
+			}
+
+			return nil
+		})
+}
+
+// Reject is performed by the buyer organization to decline the seller's quote.
+func (s *transactions) Reject(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, reason *string) error {
+	return s.transition(transactionId, organizationId, performedById, models.BuyerSide, models.TransactionRejected, reason, nil)
+}
+
+// Cancel may be performed by either party before the material is delivered.
+func (s *transactions) Cancel(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, reason *string) error {
+	return s.transition(transactionId, organizationId, performedById, "", models.TransactionCancelled, reason, nil)
+}
+
+// Deliver is performed by the buyer organization to confirm receipt of the
+// material, recording the weight received on each line. Lines that are not
+// mentioned in the payload are taken to have been received in full. The
+// material is issued from the seller's stock and received into the buyer's.
+func (s *transactions) Deliver(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.DeliverTransactionPayload) error {
+	return s.transition(transactionId, organizationId, performedById, models.BuyerSide, models.TransactionDelivered, nil,
+		func(tx *gorm.DB, transaction *models.Transaction) error {
+			var lines []models.TransactionMaterial
+
+			if err := tx.Model(transaction).Association("Materials").Find(&lines); err != nil {
+				return err
+			}
+
+			receivedWeights := map[uuid.UUID]float64{}
+
+			for _, material := range payload.Materials {
+				if material.ReceivedWeight < 0 {
+					return ErrInvalidReceivedWeight
+				}
+
+				receivedWeights[material.Id] = material.ReceivedWeight
+			}
+
+			for index, line := range lines {
+				receivedWeight, ok := receivedWeights[line.Id]
+
+				if !ok {
+					receivedWeight = line.Weight
+				}
+
+				delete(receivedWeights, line.Id)
+
+				lines[index].ReceivedWeight = &receivedWeight
+
+				if err := tx.
+					Model(&models.TransactionMaterial{}).
+					Where("id = ?", line.Id).
+					Update("received_weight", receivedWeight).Error; err != nil {
+					return err
+				}
+			}
+
+			if len(receivedWeights) > 0 {
+				return ErrInvalidReceivedWeight
+			}
+
+			return postTransactionDelivery(tx, transaction, lines, payload.OverrideStock, time.Now())
+		})
+}
+
+// Invoice is performed by the seller organization once the material has been
+// delivered. The invoice takes the next number in the seller's sequence and is
+// totalled on the weights the buyer received.
+func (s *transactions) Invoice(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID) (uuid.UUID, error) {
+	var invoice models.Invoice
+
+	err := s.transition(transactionId, organizationId, performedById, models.SellerSide, models.TransactionInvoiced, nil,
+		func(tx *gorm.DB, transaction *models.Transaction) error {
+			var lines []models.TransactionMaterial
+
+			if err := tx.Model(transaction).Association("Materials").Find(&lines); err != nil {
+				return err
+			}
+
+			total := 0.0
+
+			for _, line := range lines {
+				total += line.InvoicedValue()
+			}
+
+			var number int64
+
+			if err := tx.Raw(`
+				INSERT INTO invoice_sequences (organization_id, last_number)
+				VALUES (?, 1)
+				ON CONFLICT (organization_id)
+				DO UPDATE SET last_number = invoice_sequences.last_number + 1
+				RETURNING last_number
+			`, transaction.SellerId).Scan(&number).Error; err != nil {
+				return err
+			}
+
+			invoice = models.Invoice{
+				TransactionId:    transaction.Id,
+				OrganizationId:   transaction.SellerId,
+				Number:           number,
+				Reference:        fmt.Sprintf("INV-%06d", number),
+				Total:            math.Round(total*100) / 100,
+				IssuedAt:         time.Now(),
+				SettlementStatus: models.Unsettled,
+			}
+
+			return tx.Create(&invoice).Error
+		})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return invoice.Id, nil
+}
+
+// Settle is performed by the seller organization to record payment received
+// against the transaction's invoice. The transaction is only marked as settled
+// once the invoice has been paid in full.
+func (s *transactions) Settle(transactionId uuid.UUID, organizationId uuid.UUID, performedById uuid.UUID, payload models.SettleInvoicePayload) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var transaction models.Transaction
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", transactionId).
+			First(&transaction).Error; err != nil {
+			return err
+		}
+
+		if transaction.SideOf(organizationId) != models.SellerSide {
+			return ErrNotTransactionParty
+		}
+
+		if transaction.Status != models.TransactionInvoiced {
+			return ErrInvalidTransactionTransition
+		}
+
+		var invoice models.Invoice
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("transaction_id = ?", transactionId).
+			First(&invoice).Error; err != nil {
+			return err
+		}
+
+		outstanding := math.Round((invoice.Total-invoice.SettledAmount)*100) / 100
+
+		if payload.Amount <= 0 || payload.Amount > outstanding {
+			return ErrInvalidSettlementAmount
+		}
+
+		invoice.SettledAmount = math.Round((invoice.SettledAmount+payload.Amount)*100) / 100
+		invoice.SettlementStatus = models.PartiallySettled
+
+		if invoice.SettledAmount >= invoice.Total {
+			now := time.Now()
+
+			invoice.SettlementStatus = models.Settled
+			invoice.SettledAt = &now
+		}
+
+		if err := tx.
+			Model(&models.Invoice{}).
+			Where("id = ?", invoice.Id).
+			Updates(&map[string]any{
+				"settled_amount":    invoice.SettledAmount,
+				"settlement_status": invoice.SettlementStatus,
+				"settled_at":        invoice.SettledAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		if invoice.SettlementStatus != models.Settled {
+			return nil
+		}
+
+		return s.recordTransition(tx, &transaction, organizationId, performedById, models.TransactionSettled, nil)
+	})
 }
 
 func (s *transactions) Find(transactionId uuid.UUID) (*models.Transaction, error) {
@@ -94,6 +364,11 @@ func (s *transactions) Find(transactionId uuid.UUID) (*models.Transaction, error
 
 	if err := s.storage.Postgres.
 		Where("id = ?", transactionId).
+		Preload("Site").
+		Preload("Invoice").
+		Preload("Transitions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
 		First(&transaction).Error; err != nil {
 		return nil, err
 	}
@@ -113,6 +388,28 @@ func (s *transactions) List(clauses ...clause.Expression) ([]models.Transaction,
 	return transactions, nil
 }
 
+// Stream passes each transaction matching the clauses to handle, loading them in
+// batches so that they are never all held in memory.
+func (s *transactions) Stream(handle func(models.Transaction) error, clauses ...clause.Expression) error {
+	var transactions []models.Transaction
+
+	return s.storage.Postgres.
+		Preload("Materials.Material").
+		Preload("Seller").
+		Preload("Buyer").
+		Preload("Site").
+		Clauses(clauses...).
+		FindInBatches(&transactions, streamBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, transaction := range transactions {
+				if err := handle(transaction); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Error
+}
+
 func (s *transactions) Count(clauses ...clause.Expression) (int64, error) {
 	var count int64
 
@@ -125,3 +422,86 @@ func (s *transactions) Count(clauses ...clause.Expression) (int64, error) {
 
 	return count, nil
 }
+
+// transition moves a transaction to the given status on behalf of an
+// organization. When side is set the organization must be that party to the
+// transaction, otherwise either party may perform the change. The optional
+// apply function runs inside the same database transaction before the status
+// is changed.
+func (s *transactions) transition(
+	transactionId uuid.UUID,
+	organizationId uuid.UUID,
+	performedById uuid.UUID,
+	side models.TransactionSide,
+	status models.TransactionStatus,
+	reason *string,
+	apply func(tx *gorm.DB, transaction *models.Transaction) error,
+) error {
+	return s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var transaction models.Transaction
+
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", transactionId).
+			First(&transaction).Error; err != nil {
+			return err
+		}
+
+		partySide := transaction.SideOf(organizationId)
+
+		if partySide == "" || (side != "" && partySide != side) {
+			return ErrNotTransactionParty
+		}
+
+		if !transaction.Status.CanTransitionTo(status) {
+			return ErrInvalidTransactionTransition
+		}
+
+		if apply != nil {
+			if err := apply(tx, &transaction); err != nil {
+				return err
+			}
+		}
+
+		return s.recordTransition(tx, &transaction, organizationId, performedById, status, reason)
+	})
+}
+
+func (s *transactions) recordTransition(
+	tx *gorm.DB,
+	transaction *models.Transaction,
+	organizationId uuid.UUID,
+	performedById uuid.UUID,
+	status models.TransactionStatus,
+	reason *string,
+) error {
+	if err := tx.
+		Model(&models.Transaction{}).
+		Where("id = ?", transaction.Id).
+		Update("status", status).Error; err != nil {
+		return err
+	}
+
+	transition := models.TransactionTransition{
+		TransactionId:  transaction.Id,
+		From:           transaction.Status,
+		To:             status,
+		Reason:         reason,
+		OrganizationId: organizationId,
+		PerformedById:  performedById,
+	}
+
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	transaction.Status = status
+
+	eventTypes := []models.WebhookEventType{models.TransactionUpdatedEvent}
+
+	if status == models.TransactionSettled {
+		eventTypes = append(eventTypes, models.TransactionSettledEvent)
+	}
+
+	return publishTransactionEvents(tx, transaction.Id, eventTypes...)
+}
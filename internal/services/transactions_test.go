@@ -0,0 +1,162 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestTransactionsLifecycle(t *testing.T) {
+	s := testStorage(t)
+
+	seller := testOrganization(t, s)
+	buyer := testOrganization(t, s)
+	outsider := testOrganization(t, s)
+	user := testUser(t, s, seller.Id, models.IdentityVerified)
+	material := testMaterial(t, s, false)
+
+	service := newTransactionsService(s)
+
+	create := func(expiresAt *time.Time) uuid.UUID {
+		t.Helper()
+
+		transactionId, err := service.Create(models.CreateTransactionPayload{
+			SellerId:       seller.Id,
+			BuyerId:        buyer.Id,
+			QuoteExpiresAt: expiresAt,
+			Materials: []models.CreateTransactionMaterialPayload{
+				{MaterialId: material.Id, Weight: 100, Value: 250},
+			},
+			OverrideStock: true,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return transactionId
+	}
+
+	status := func(transactionId uuid.UUID) models.TransactionStatus {
+		t.Helper()
+
+		transaction, err := service.Find(transactionId)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return transaction.Status
+	}
+
+	t.Run("quotes are accepted by the buyer only", func(t *testing.T) {
+		transactionId := create(nil)
+
+		if err := service.Accept(transactionId, seller.Id, user.Id); err != ErrNotTransactionParty {
+			t.Errorf("Accept() by the seller error = %v, want %v", err, ErrNotTransactionParty)
+		}
+
+		if err := service.Cancel(transactionId, outsider.Id, user.Id, nil); err != ErrNotTransactionParty {
+			t.Errorf("Cancel() by an outsider error = %v, want %v", err, ErrNotTransactionParty)
+		}
+
+		if got := status(transactionId); got != models.TransactionQuoted {
+			t.Errorf("status = %s, want %s", got, models.TransactionQuoted)
+		}
+	})
+
+	t.Run("expired quotes are not accepted", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		transactionId := create(&expiresAt)
+
+		if err := service.Accept(transactionId, buyer.Id, user.Id); err != ErrQuoteExpired {
+			t.Errorf("Accept() error = %v, want %v", err, ErrQuoteExpired)
+		}
+
+		if err := service.Reject(transactionId, buyer.Id, user.Id, nil); err != nil {
+			t.Errorf("Reject() error = %v", err)
+		}
+
+		if err := service.Accept(transactionId, buyer.Id, user.Id); err != ErrInvalidTransactionTransition {
+			t.Errorf("Accept() after Reject() error = %v, want %v", err, ErrInvalidTransactionTransition)
+		}
+	})
+
+	t.Run("delivered transactions are invoiced on the weight received and settled", func(t *testing.T) {
+		transactionId := create(nil)
+
+		if err := service.Accept(transactionId, buyer.Id, user.Id); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := service.Invoice(transactionId, seller.Id, user.Id); err != ErrInvalidTransactionTransition {
+			t.Errorf("Invoice() before delivery error = %v, want %v", err, ErrInvalidTransactionTransition)
+		}
+
+		var lineIds []uuid.UUID
+
+		if err := s.Postgres.
+			Table("transactions_materials").
+			Where("transaction_id = ?", transactionId).
+			Pluck("transaction_material_id", &lineIds).Error; err != nil || len(lineIds) != 1 {
+			t.Fatalf("lines = %v, error = %v, want one line", lineIds, err)
+		}
+
+		if err := service.Deliver(transactionId, buyer.Id, user.Id, models.DeliverTransactionPayload{
+			Materials: []models.DeliverTransactionMaterialPayload{
+				{Id: lineIds[0], ReceivedWeight: 80},
+			},
+			OverrideStock: true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := service.Invoice(transactionId, buyer.Id, user.Id); err != ErrNotTransactionParty {
+			t.Errorf("Invoice() by the buyer error = %v, want %v", err, ErrNotTransactionParty)
+		}
+
+		if _, err := service.Invoice(transactionId, seller.Id, user.Id); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := service.Cancel(transactionId, seller.Id, user.Id, nil); err != ErrInvalidTransactionTransition {
+			t.Errorf("Cancel() after delivery error = %v, want %v", err, ErrInvalidTransactionTransition)
+		}
+
+		transaction, err := service.Find(transactionId)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if transaction.Invoice == nil || transaction.Invoice.Total != 200 {
+			t.Fatalf("invoice = %+v, want a total of 200", transaction.Invoice)
+		}
+
+		settlements := []struct {
+			amount float64
+			err    error
+			want   models.TransactionStatus
+		}{
+			{amount: 0, err: ErrInvalidSettlementAmount, want: models.TransactionInvoiced},
+			{amount: 150, want: models.TransactionInvoiced},
+			{amount: 50.01, err: ErrInvalidSettlementAmount, want: models.TransactionInvoiced},
+			{amount: 50, want: models.TransactionSettled},
+			{amount: 1, err: ErrInvalidTransactionTransition, want: models.TransactionSettled},
+		}
+
+		for _, settlement := range settlements {
+			err := service.Settle(transactionId, seller.Id, user.Id, models.SettleInvoicePayload{Amount: settlement.amount})
+
+			if err != settlement.err {
+				t.Errorf("Settle(%v) error = %v, want %v", settlement.amount, err, settlement.err)
+			}
+
+			if got := status(transactionId); got != settlement.want {
+				t.Errorf("status after Settle(%v) = %s, want %s", settlement.amount, got, settlement.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type trashService interface {
+	Restore(resource models.TrashResource, id uuid.UUID, organizationId uuid.UUID) error
+	Purge(before time.Time) error
+	List(clauses ...clause.Expression) ([]models.TrashItem, error)
+	Count(clauses ...clause.Expression) (int64, error)
+}
+
+type trash struct {
+	storage storage.Storage
+}
+
+func newTrashService(storage storage.Storage) trashService {
+	return &trash{
+		storage: storage,
+	}
+}
+
+// PurgeTrashJob is the kind of the scheduled jobs that purge records that
+// have been in the trash for longer than they are kept.
+const PurgeTrashJob = "trash.purge"
+
+type PurgeTrashPayload struct {
+	RetentionDays int `json:"retentionDays"`
+}
+
+// trashTable describes how the deleted records of a table are listed,
+// restored and purged.
+type trashTable struct {
+	table string
+	// label is the SQL expression that names a record in the trash.
+	label string
+	// organizationColumn scopes restoring a record to the active organization
+	// of the user, for the resources that are deleted that way.
+	organizationColumn string
+	// lines is the join table of the lines of a collection or transaction,
+	// which are purged along with it.
+	lines *trashLines
+	// attachments is the parent type of the attachments of a record, which
+	// are moved to the trash when it is purged.
+	attachments models.AttachmentParent
+}
+
+type trashLines struct {
+	join      string
+	parent    string
+	line      string
+	lineTable string
+}
+
+var trashTables = map[models.TrashResource]trashTable{
+	models.UsersTrashResource: {
+		table:       "users",
+		label:       "name",
+		attachments: models.UserAttachment,
+	},
+	models.OrganizationsTrashResource: {
+		table:       "organizations",
+		label:       "name",
+		attachments: models.OrganizationAttachment,
+	},
+	models.MaterialsTrashResource: {
+		table: "materials",
+		label: "name",
+	},
+	models.RolesTrashResource: {
+		table: "roles",
+		label: "name",
+	},
+	models.SitesTrashResource: {
+		table: "sites",
+		label: "name",
+	},
+	models.CollectionsTrashResource: {
+		table: "collections",
+		label: "'Collection ' || to_char(created_at, 'YYYY-MM-DD')",
+		lines: &trashLines{
+			join:      "collections_materials",
+			parent:    "collection_id",
+			line:      "collection_material_id",
+			lineTable: "collection_materials",
+		},
+		attachments: models.CollectionAttachment,
+	},
+	models.TransactionsTrashResource: {
+		table: "transactions",
+		label: "'Transaction ' || to_char(created_at, 'YYYY-MM-DD')",
+		lines: &trashLines{
+			join:      "transactions_materials",
+			parent:    "transaction_id",
+			line:      "transaction_material_id",
+			lineTable: "transaction_materials",
+		},
+		attachments: models.TransactionAttachment,
+	},
+	models.ScalesTrashResource: {
+		table:              "scales",
+		label:              "name",
+		organizationColumn: "organization_id",
+	},
+	models.WebhooksTrashResource: {
+		table:              "webhook_endpoints",
+		label:              "url",
+		organizationColumn: "organization_id",
+	},
+}
+
+// trashResources is the order in which the listing is built and records are
+// purged. Records are purged before the records they may refer to, so that
+// an organization is only purged after its sites.
+var trashResources = []models.TrashResource{
+	models.WebhooksTrashResource,
+	models.ScalesTrashResource,
+	models.SitesTrashResource,
+	models.CollectionsTrashResource,
+	models.TransactionsTrashResource,
+	models.RolesTrashResource,
+	models.MaterialsTrashResource,
+	models.UsersTrashResource,
+	models.OrganizationsTrashResource,
+}
+
+// trashOnlyTables are deleted without being listed in the trash, and are
+// purged along with the other records.
+var trashOnlyTables = []string{
+	"addresses",
+	"bank_details",
+}
+
+// Restore takes a record out of the trash. Scales and webhooks are only
+// restored for the organization they belong to. An organization is restored
+// along with the sites, scales and webhooks that were deleted with it.
+func (s *trash) Restore(resource models.TrashResource, id uuid.UUID, organizationId uuid.UUID) error {
+	table, ok := trashTables[resource]
+
+	if !ok {
+		return ErrInvalidTrashResource
+	}
+
+	err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		var deletedAt []time.Time
+
+		query := tx.
+			Table(table.table).
+			Where("id = ? AND deleted_at IS NOT NULL", id)
+
+		if table.organizationColumn != "" {
+			query = query.Where(fmt.Sprintf("%s = ?", table.organizationColumn), organizationId)
+		}
+
+		if err := query.Pluck("deleted_at", &deletedAt).Error; err != nil {
+			return err
+		}
+
+		if len(deletedAt) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Restoring sets updated_at so that the record is seen to have
+		// changed, such as by the daily volumes.
+		if err := tx.
+			Table(table.table).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		if resource != models.OrganizationsTrashResource {
+			return nil
+		}
+
+		for _, dependent := range organizationDependents {
+			if err := tx.
+				Unscoped().
+				Model(dependent).
+				Where("organization_id = ? AND deleted_at = ?", id, deletedAt[0]).
+				Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if translateError(s.storage.Postgres, err) == gorm.ErrDuplicatedKey {
+		return ErrRestoreConflict
+	}
+
+	return err
+}
+
+// Purge permanently deletes the records that were moved to the trash before
+// the given time. Records that are still referred to, such as users who sold
+// collections, are kept in the trash so that no history is lost. The
+// attachments of purged records are moved to the trash, and are purged with
+// their files once they have been there as long.
+func (s *trash) Purge(before time.Time) error {
+	for _, resource := range trashResources {
+		table := trashTables[resource]
+
+		if err := s.purgeTable(table.table, before, func(tx *gorm.DB, id uuid.UUID) error {
+			return s.purgeRecord(tx, table, id)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range trashOnlyTables {
+		if err := s.purgeTable(table, before, func(tx *gorm.DB, id uuid.UUID) error {
+			return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), id).Error
+		}); err != nil {
+			return err
+		}
+	}
+
+	var attachments []models.Attachment
+
+	if err := s.storage.Postgres.
+		Unscoped().
+		Where("deleted_at < ?", before).
+		Find(&attachments).Error; err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if err := s.storage.Postgres.
+			Unscoped().
+			Where("id = ?", attachment.Id).
+			Delete(&models.Attachment{}).Error; err != nil {
+			return err
+		}
+
+		deleteAttachmentFiles(s.storage.Files, attachment)
+	}
+
+	return nil
+}
+
+// purgeTable deletes the records of a table that were moved to the trash
+// before the given time one at a time, each in its own transaction, and skips
+// the records that are still referred to.
+func (s *trash) purgeTable(table string, before time.Time, purge func(tx *gorm.DB, id uuid.UUID) error) error {
+	var ids []uuid.UUID
+
+	if err := s.storage.Postgres.
+		Table(table).
+		Where("deleted_at < ?", before).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+			return purge(tx, id)
+		})
+
+		if translateError(s.storage.Postgres, err) == gorm.ErrForeignKeyViolated {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *trash) purgeRecord(tx *gorm.DB, table trashTable, id uuid.UUID) error {
+	var lineIds []uuid.UUID
+
+	if table.lines != nil {
+		if err := tx.
+			Table(table.lines.join).
+			Where(fmt.Sprintf("%s = ?", table.lines.parent), id).
+			Pluck(table.lines.line, &lineIds).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table.table), id).Error; err != nil {
+		return err
+	}
+
+	if len(lineIds) > 0 {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", table.lines.lineTable), lineIds).Error; err != nil {
+			return err
+		}
+	}
+
+	if table.attachments != "" {
+		if err := tx.
+			Model(&models.Attachment{}).
+			Where("parent_type = ? AND parent_id = ?", table.attachments, id).
+			Update("deleted_at", time.Now()).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *trash) List(clauses ...clause.Expression) ([]models.TrashItem, error) {
+	var items []models.TrashItem
+
+	if err := s.items().
+		Clauses(clauses...).
+		Order("deleted_at DESC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (s *trash) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.items().
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// items selects every record in the trash as a trash item, from a table that
+// can be filtered on resource.
+func (s *trash) items() *gorm.DB {
+	selects := make([]string, 0, len(trashResources))
+
+	for _, resource := range trashResources {
+		table := trashTables[resource]
+
+		selects = append(selects, fmt.Sprintf(
+			"SELECT '%s' AS resource, id, %s AS label, deleted_at FROM %s WHERE deleted_at IS NOT NULL",
+			resource, table.label, table.table,
+		))
+	}
+
+	return s.storage.Postgres.
+		Table(fmt.Sprintf("(%s) AS trash", strings.Join(selects, " UNION ALL ")))
+}
+
+// translateError turns a database error into the gorm error it stands for,
+// such as gorm.ErrDuplicatedKey for a unique violation.
+func translateError(db *gorm.DB, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+
+	return err
+}
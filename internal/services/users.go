@@ -1,21 +1,28 @@
 package services
 
 import (
+	"slices"
+	"time"
+
 	"github.com/connor-davis/threereco-nextgen/internal/models"
 	"github.com/connor-davis/threereco-nextgen/internal/storage"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type usersService interface {
 	Create(payload models.CreateUserPayload) (uuid.UUID, error)
-	Update(userId uuid.UUID, payload models.UpdateUserPayload) error
-	Delete(userId uuid.UUID) error
+	Update(userId uuid.UUID, version int64, payload models.UpdateUserPayload) error
+	ReviewIdentity(userId uuid.UUID, reviewerId uuid.UUID, version int64, payload models.ReviewUserIdentityPayload) error
+	Delete(userId uuid.UUID, version int64) error
 	Find(userId uuid.UUID) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	FindByPhone(phone string) (*models.User, error)
+	Owner(userId uuid.UUID) (*models.Owner, error)
 	List(clauses ...clause.Expression) ([]models.User, error)
+	Stream(handle func(models.User) error, clauses ...clause.Expression) error
 	Count(clauses ...clause.Expression) (int64, error)
 }
 
@@ -45,23 +52,52 @@ func (s *users) Create(payload models.CreateUserPayload) (uuid.UUID, error) {
 	user.Password = hashedPassword
 	user.Type = payload.Type
 
-	if err := s.storage.Postgres.
-		Model(&models.User{}).
-		Create(&user).Error; err != nil {
-		return uuid.Nil, err
+	if payload.IdentityDocumentType != nil || payload.IdentityNumber != nil {
+		if payload.IdentityDocumentType == nil || payload.IdentityNumber == nil {
+			return uuid.Nil, ErrInvalidIdentityNumber
+		}
+
+		if err := setIdentity(&user, *payload.IdentityDocumentType, *payload.IdentityNumber); err != nil {
+			return uuid.Nil, err
+		}
 	}
 
-	if payload.Roles != nil {
-		if err := s.storage.Postgres.
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&models.User{}).
+			Create(&user).Error; err != nil {
+			return err
+		}
+
+		if payload.Roles == nil {
+			return nil
+		}
+
+		if err := tx.
 			Model(&user).Association("Roles").Append(payload.Roles); err != nil {
-			return uuid.Nil, err
+			return err
 		}
+
+		// A user with roles of organizations is published to them.
+		var organizationIds []uuid.UUID
+
+		if err := tx.
+			Table("organization_roles").
+			Distinct("organization_id").
+			Where("role_id IN (?)", tx.Table("user_roles").Select("role_id").Where("user_id = ?", user.Id)).
+			Pluck("organization_id", &organizationIds).Error; err != nil {
+			return err
+		}
+
+		return publishUserCreated(tx, user.Id, organizationIds)
+	}); err != nil {
+		return uuid.Nil, err
 	}
 
 	return user.Id, nil
 }
 
-func (s *users) Update(userId uuid.UUID, payload models.UpdateUserPayload) error {
+func (s *users) Update(userId uuid.UUID, version int64, payload models.UpdateUserPayload) error {
 	var user models.User
 
 	if err := s.storage.Postgres.
@@ -86,29 +122,89 @@ func (s *users) Update(userId uuid.UUID, payload models.UpdateUserPayload) error
 		user.Type = *payload.Type
 	}
 
-	if err := s.storage.Postgres.
+	// Changing the identity document sends it back for verification.
+	if payload.IdentityDocumentType != nil || payload.IdentityNumber != nil {
+		documentType := user.IdentityDocumentType
+		number := user.IdentityNumber
+
+		if payload.IdentityDocumentType != nil {
+			documentType = payload.IdentityDocumentType
+		}
+
+		if payload.IdentityNumber != nil {
+			number = payload.IdentityNumber
+		}
+
+		if documentType == nil || number == nil {
+			return ErrInvalidIdentityNumber
+		}
+
+		if err := setIdentity(&user, *documentType, *number); err != nil {
+			return err
+		}
+	}
+
+	result := s.storage.Postgres.
 		Model(&models.User{}).
-		Where("id = ?", userId).
+		Where("id = ? AND version = ?", userId, version).
 		Updates(&map[string]any{
-			"name":  user.Name,
-			"email": user.Email,
-			"phone": user.Phone,
-			"type":  user.Type,
-		}).Error; err != nil {
-		return err
+			"name":                    user.Name,
+			"email":                   user.Email,
+			"phone":                   user.Phone,
+			"type":                    user.Type,
+			"identity_document_type":  user.IdentityDocumentType,
+			"identity_number":         user.IdentityNumber,
+			"masked_identity_number":  user.MaskedIdentityNumber,
+			"date_of_birth":           user.DateOfBirth,
+			"identity_status":         user.IdentityStatus,
+			"identity_reviewed_at":    user.IdentityReviewedAt,
+			"identity_reviewed_by_id": user.IdentityReviewedById,
+			"identity_note":           user.IdentityNote,
+		})
+
+	return checkVersion(s.storage.Postgres, &models.User{}, userId, result)
+}
+
+// ReviewIdentity records whether the identity document of a user was verified
+// against the document itself or rejected. The review only applies to the
+// version of the user that was reviewed, so that an identity document changed
+// in the meantime is not verified unseen.
+func (s *users) ReviewIdentity(userId uuid.UUID, reviewerId uuid.UUID, version int64, payload models.ReviewUserIdentityPayload) error {
+	if !slices.Contains(identityReviewStatuses, payload.Status) {
+		return ErrInvalidIdentityStatus
 	}
 
-	return nil
-}
+	var user models.User
 
-func (s *users) Delete(userId uuid.UUID) error {
 	if err := s.storage.Postgres.
 		Where("id = ?", userId).
-		Delete(&models.User{}).Error; err != nil {
+		First(&user).Error; err != nil {
 		return err
 	}
 
-	return nil
+	if user.IdentityNumber == nil {
+		return ErrIdentityMissing
+	}
+
+	result := s.storage.Postgres.
+		Model(&models.User{}).
+		Where("id = ? AND version = ?", userId, version).
+		Updates(&map[string]any{
+			"identity_status":         payload.Status,
+			"identity_reviewed_at":    time.Now(),
+			"identity_reviewed_by_id": reviewerId,
+			"identity_note":           payload.Note,
+		})
+
+	return checkVersion(s.storage.Postgres, &models.User{}, userId, result)
+}
+
+func (s *users) Delete(userId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND version = ?", userId, version).
+		Delete(&models.User{})
+
+	return checkVersion(s.storage.Postgres, &models.User{}, userId, result)
 }
 
 func (s *users) Find(userId uuid.UUID) (*models.User, error) {
@@ -156,6 +252,12 @@ func (s *users) FindByPhone(phone string) (*models.User, error) {
 	return user, nil
 }
 
+// Owner returns the user as the owner of their records, along with the
+// organizations whose members may act on them.
+func (s *users) Owner(userId uuid.UUID) (*models.Owner, error) {
+	return userOwner(s.storage.Postgres, userId)
+}
+
 func (s *users) List(clauses ...clause.Expression) ([]models.User, error) {
 	var users []models.User
 
@@ -168,6 +270,24 @@ func (s *users) List(clauses ...clause.Expression) ([]models.User, error) {
 	return users, nil
 }
 
+// Stream passes each user matching the clauses to handle, loading them in
+// batches so that they are never all held in memory.
+func (s *users) Stream(handle func(models.User) error, clauses ...clause.Expression) error {
+	var users []models.User
+
+	return s.storage.Postgres.
+		Clauses(clauses...).
+		FindInBatches(&users, streamBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				if err := handle(user); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Error
+}
+
 func (s *users) Count(clauses ...clause.Expression) (int64, error) {
 	var count int64
 
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// checkVersion checks the result of an update or delete of a record that was
+// limited to the version the client last read. When nothing was changed, the
+// record has either been changed since, in which case ErrVersionMismatch is
+// returned, or it does not exist.
+func checkVersion(tx *gorm.DB, model any, id uuid.UUID, result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+
+	if err := tx.
+		Model(model).
+		Where("id = ?", id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return ErrVersionMismatch
+}
@@ -0,0 +1,719 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/internal/jobs"
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"github.com/connor-davis/threereco-nextgen/internal/storage"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhooksService interface {
+	Events() []models.WebhookEventDescription
+	Create(organizationId uuid.UUID, payload models.CreateWebhookEndpointPayload) (uuid.UUID, error)
+	Update(endpointId uuid.UUID, organizationId uuid.UUID, version int64, payload models.UpdateWebhookEndpointPayload) error
+	Delete(endpointId uuid.UUID, organizationId uuid.UUID, version int64) error
+	Redeliver(deliveryId uuid.UUID, endpointId uuid.UUID, organizationId uuid.UUID) (uuid.UUID, error)
+	Deliver(deliveryId uuid.UUID, final bool) error
+	Find(endpointId uuid.UUID) (*models.WebhookEndpoint, error)
+	List(clauses ...clause.Expression) ([]models.WebhookEndpoint, error)
+	Count(clauses ...clause.Expression) (int64, error)
+	ListDeliveries(clauses ...clause.Expression) ([]models.WebhookDelivery, error)
+	CountDeliveries(clauses ...clause.Expression) (int64, error)
+}
+
+type webhooks struct {
+	storage storage.Storage
+	client  *http.Client
+}
+
+func newWebhooksService(storage storage.Storage) webhooksService {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: dialPublicAddress,
+	}
+
+	return &webhooks{
+		storage: storage,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			// Webhooks are dialled directly rather than through a proxy, so
+			// that the address checked is the one connected to.
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// Redirects are not followed, so that a webhook can only be
+			// delivered to the URL it was configured with.
+			CheckRedirect: func(request *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// DeliverWebhookJob is the kind of the background jobs that post events to
+// webhooks.
+const DeliverWebhookJob = "webhooks.deliver"
+
+type DeliverWebhookPayload struct {
+	DeliveryId uuid.UUID `json:"deliveryId"`
+}
+
+const (
+	// webhookMaxAttempts is how many times a delivery is attempted. With the
+	// backoff of the job runner the last attempt is about three hours after
+	// the first.
+	webhookMaxAttempts = 10
+	// webhookResponseLimit is how much of the body of a response is read so
+	// that the connection can be reused. The body is not kept.
+	webhookResponseLimit = 2048
+)
+
+var webhookEvents = []models.WebhookEventDescription{
+	{Type: models.CollectionCreatedEvent, Description: "A collection was recorded. Sent to the buying organization."},
+	{Type: models.CollectionUpdatedEvent, Description: "The seller, buyer or site of a collection was changed. Sent to the buying organization."},
+	{Type: models.CollectionStatusChangedEvent, Description: "A collection moved to another status. Sent to the buying organization."},
+	{Type: models.CollectionConfirmedEvent, Description: "A collection was confirmed and its material received into stock. Sent to the buying organization."},
+	{Type: models.TransactionCreatedEvent, Description: "A transaction was quoted. Sent to both organizations."},
+	{Type: models.TransactionUpdatedEvent, Description: "A transaction was changed or moved to another status. Sent to both organizations."},
+	{Type: models.TransactionSettledEvent, Description: "The invoice of a transaction was paid in full. Sent to both organizations."},
+	{Type: models.UserCreatedEvent, Description: "A user was created with a role of the organization or imported into it."},
+}
+
+func (s *webhooks) Events() []models.WebhookEventDescription {
+	return webhookEvents
+}
+
+// Create adds a webhook to an organization with a new secret to sign its
+// deliveries with.
+func (s *webhooks) Create(organizationId uuid.UUID, payload models.CreateWebhookEndpointPayload) (uuid.UUID, error) {
+	if err := checkWebhook(payload.Url, payload.Events); err != nil {
+		return uuid.Nil, err
+	}
+
+	secret, err := newWebhookSecret()
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	endpoint := models.WebhookEndpoint{
+		OrganizationId: organizationId,
+		Url:            payload.Url,
+		Description:    payload.Description,
+		Events:         payload.Events,
+		Active:         true,
+		Secret:         secret,
+	}
+
+	if err := s.storage.Postgres.
+		Create(&endpoint).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	// Active defaults to true in the database, so a webhook created inactive
+	// is deactivated after it is created.
+	if payload.Active != nil && !*payload.Active {
+		if err := s.storage.Postgres.
+			Model(&models.WebhookEndpoint{}).
+			Where("id = ?", endpoint.Id).
+			Update("active", false).Error; err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	return endpoint.Id, nil
+}
+
+func (s *webhooks) Update(endpointId uuid.UUID, organizationId uuid.UUID, version int64, payload models.UpdateWebhookEndpointPayload) error {
+	var endpoint models.WebhookEndpoint
+
+	if err := s.storage.Postgres.
+		Where("id = ? AND organization_id = ?", endpointId, organizationId).
+		First(&endpoint).Error; err != nil {
+		return err
+	}
+
+	if payload.Url != nil {
+		endpoint.Url = *payload.Url
+	}
+
+	if payload.Description != nil {
+		endpoint.Description = payload.Description
+	}
+
+	if payload.Events != nil {
+		endpoint.Events = payload.Events
+	}
+
+	if payload.Active != nil {
+		endpoint.Active = *payload.Active
+	}
+
+	if err := checkWebhook(endpoint.Url, endpoint.Events); err != nil {
+		return err
+	}
+
+	if payload.RotateSecret {
+		secret, err := newWebhookSecret()
+
+		if err != nil {
+			return err
+		}
+
+		endpoint.Secret = secret
+	}
+
+	result := s.storage.Postgres.
+		Model(&models.WebhookEndpoint{}).
+		Select("url", "description", "events", "active", "secret").
+		Where("id = ? AND version = ?", endpointId, version).
+		Updates(&endpoint)
+
+	return checkVersion(s.storage.Postgres, &models.WebhookEndpoint{}, endpointId, result)
+}
+
+// Delete moves a webhook to the trash. Its pending deliveries fail, as the
+// webhook is no longer loaded with them.
+func (s *webhooks) Delete(endpointId uuid.UUID, organizationId uuid.UUID, version int64) error {
+	result := s.storage.Postgres.
+		Where("id = ? AND organization_id = ? AND version = ?", endpointId, organizationId, version).
+		Delete(&models.WebhookEndpoint{})
+
+	return checkVersion(s.storage.Postgres.Where("organization_id = ?", organizationId), &models.WebhookEndpoint{}, endpointId, result)
+}
+
+// Redeliver delivers the event of a delivery to its webhook again as a new
+// delivery, keeping the log of the original.
+func (s *webhooks) Redeliver(deliveryId uuid.UUID, endpointId uuid.UUID, organizationId uuid.UUID) (uuid.UUID, error) {
+	var delivery models.WebhookDelivery
+
+	if err := s.storage.Postgres.
+		Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.endpoint_id").
+		Where("webhook_deliveries.id = ? AND webhook_deliveries.endpoint_id = ? AND webhook_endpoints.organization_id = ?", deliveryId, endpointId, organizationId).
+		First(&delivery).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	redelivery := models.WebhookDelivery{
+		EndpointId: delivery.EndpointId,
+		EventId:    delivery.EventId,
+		Status:     models.WebhookDeliveryPending,
+	}
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		return enqueueWebhookDelivery(tx, &redelivery)
+	}); err != nil {
+		return uuid.Nil, err
+	}
+
+	return redelivery.Id, nil
+}
+
+// Deliver posts the event of a delivery to its webhook and records the
+// response. An error is returned when the webhook does not respond with a 2xx
+// status, so that the job is tried again, and the delivery only fails on the
+// final attempt.
+func (s *webhooks) Deliver(deliveryId uuid.UUID, final bool) error {
+	var delivery models.WebhookDelivery
+
+	if err := s.storage.Postgres.
+		Preload("Endpoint").
+		Preload("Event").
+		Where("id = ?", deliveryId).
+		First(&delivery).Error; err != nil {
+		// The webhook was purged along with its deliveries.
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	if delivery.Status != models.WebhookDeliveryPending {
+		return nil
+	}
+
+	if !delivery.Endpoint.Active {
+		return s.storage.Postgres.
+			Model(&models.WebhookDelivery{}).
+			Where("id = ?", deliveryId).
+			Update("status", models.WebhookDeliveryFailed).Error
+	}
+
+	attempt := models.WebhookDeliveryAttempt{
+		DeliveryId: deliveryId,
+	}
+
+	startedAt := time.Now()
+
+	deliveryErr := s.post(&delivery, &attempt)
+
+	attempt.Duration = time.Since(startedAt).Milliseconds()
+
+	if deliveryErr != nil {
+		message := deliveryErr.Error()
+
+		attempt.Error = &message
+	}
+
+	if err := s.storage.Postgres.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{}
+
+		switch {
+		case deliveryErr == nil:
+			updates["status"] = models.WebhookDeliverySucceeded
+			updates["delivered_at"] = time.Now()
+		case final:
+			updates["status"] = models.WebhookDeliveryFailed
+		default:
+			return nil
+		}
+
+		return tx.
+			Model(&models.WebhookDelivery{}).
+			Where("id = ?", deliveryId).
+			Updates(updates).Error
+	}); err != nil {
+		return err
+	}
+
+	return deliveryErr
+}
+
+// post sends the payload of an event to a webhook, recording the status of the
+// response on the attempt. The body of the response is not kept, so that a
+// webhook cannot be used to read the responses of other services. The body is
+// signed with HMAC-SHA256 over the timestamp and the body, joined by a full
+// stop, so that receivers can reject replayed requests.
+func (s *webhooks) post(delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	// Webhooks configured before URLs were checked as they are now are not
+	// posted to until they are changed.
+	if err := checkWebhook(delivery.Endpoint.Url, delivery.Endpoint.Events); err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(delivery.Endpoint.Secret))
+
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(delivery.Event.Payload)
+
+	request, err := http.NewRequest(http.MethodPost, delivery.Endpoint.Url, bytes.NewReader(delivery.Event.Payload))
+
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "3rEco-Webhooks")
+	request.Header.Set("X-Webhook-Id", delivery.Id.String())
+	request.Header.Set("X-Webhook-Event", string(delivery.Event.Type))
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	response, err := s.client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if _, err := io.Copy(io.Discard, io.LimitReader(response.Body, webhookResponseLimit)); err != nil {
+		log.Warnf("⚠️ Error reading response of webhook delivery %s: %s", delivery.Id, err.Error())
+	}
+
+	statusCode := response.StatusCode
+
+	attempt.StatusCode = &statusCode
+
+	if statusCode < 200 || statusCode > 299 {
+		return fmt.Errorf("the webhook responded with status %d", statusCode)
+	}
+
+	return nil
+}
+
+func (s *webhooks) Find(endpointId uuid.UUID) (*models.WebhookEndpoint, error) {
+	var endpoint *models.WebhookEndpoint
+
+	if err := s.storage.Postgres.
+		Where("id = ?", endpointId).
+		First(&endpoint).Error; err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s *webhooks) List(clauses ...clause.Expression) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+
+	if err := s.storage.Postgres.
+		Omit("secret").
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (s *webhooks) Count(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.WebhookEndpoint{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *webhooks) ListDeliveries(clauses ...clause.Expression) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	if err := s.storage.Postgres.
+		Preload("Event").
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Clauses(clauses...).
+		Order("created_at DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *webhooks) CountDeliveries(clauses ...clause.Expression) (int64, error) {
+	var count int64
+
+	if err := s.storage.Postgres.
+		Model(&models.WebhookDelivery{}).
+		Clauses(clauses...).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// checkWebhook checks that a webhook posts to an absolute HTTPS URL that is not
+// addressed to a local or private host, and subscribes to known event types.
+// Host names are checked again when they are dialled, as they may resolve to
+// other addresses by then.
+func checkWebhook(webhookUrl string, events []models.WebhookEventType) error {
+	parsed, err := url.Parse(webhookUrl)
+
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidWebhookUrl
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidWebhookUrl
+	}
+
+	if address, err := netip.ParseAddr(host); err == nil && !publicAddress(address) {
+		return ErrInvalidWebhookUrl
+	}
+
+	if len(events) == 0 {
+		return ErrInvalidWebhookEvents
+	}
+
+	for _, event := range events {
+		known := event == models.AllWebhookEvents || slices.ContainsFunc(webhookEvents, func(description models.WebhookEventDescription) bool {
+			return description.Type == event
+		})
+
+		if !known {
+			return ErrInvalidWebhookEvents
+		}
+	}
+
+	return nil
+}
+
+// nonPublicPrefixes are the ranges, other than those the netip package
+// recognises, that are shared, reserved or translate to other addresses.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// publicAddress reports whether an address is routable on the internet, as
+// opposed to loopback, private, link local, multicast or otherwise reserved.
+func publicAddress(address netip.Addr) bool {
+	address = address.Unmap()
+
+	if !address.IsValid() ||
+		address.IsUnspecified() ||
+		address.IsLoopback() ||
+		address.IsPrivate() ||
+		address.IsLinkLocalUnicast() ||
+		address.IsLinkLocalMulticast() ||
+		address.IsInterfaceLocalMulticast() ||
+		address.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dialPublicAddress refuses connections to addresses that are not public. It
+// runs after host names are resolved, so a host name that resolves to a
+// private address, including one that changed since the webhook was checked,
+// cannot be reached.
+func dialPublicAddress(network string, address string, connection syscall.RawConn) error {
+	addressPort, err := netip.ParseAddrPort(address)
+
+	if err != nil || !publicAddress(addressPort.Addr()) {
+		return ErrWebhookAddressNotPublic
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// publishEvent records an event of a type for each of the organizations that
+// has an active webhook subscribed to it, with a delivery to each of those
+// webhooks. It must be called within the database transaction of the change,
+// so that the event is stored if and only if the change is. The data of the
+// event is only loaded when a webhook is subscribed to it.
+func publishEvent(tx *gorm.DB, eventType models.WebhookEventType, organizationIds []uuid.UUID, load func(tx *gorm.DB) (any, error)) error {
+	var endpoints []models.WebhookEndpoint
+
+	if err := tx.
+		Select("id", "organization_id").
+		Where("organization_id IN ? AND active", organizationIds).
+		Where("events @> ?::jsonb OR events @> ?::jsonb", fmt.Sprintf("[%q]", eventType), fmt.Sprintf("[%q]", models.AllWebhookEvents)).
+		Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	data, err := load(tx)
+
+	if err != nil {
+		return err
+	}
+
+	events := map[uuid.UUID]uuid.UUID{}
+
+	for _, endpoint := range endpoints {
+		eventId, ok := events[endpoint.OrganizationId]
+
+		if !ok {
+			event := models.WebhookEvent{
+				Base: models.Base{
+					Id: uuid.New(),
+				},
+				OrganizationId: endpoint.OrganizationId,
+				Type:           eventType,
+			}
+
+			payload, err := json.Marshal(map[string]any{
+				"id":             event.Id,
+				"type":           eventType,
+				"organizationId": endpoint.OrganizationId,
+				"createdAt":      time.Now(),
+				"data":           data,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			event.Payload = payload
+
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+
+			eventId = event.Id
+			events[endpoint.OrganizationId] = eventId
+		}
+
+		if err := enqueueWebhookDelivery(tx, &models.WebhookDelivery{
+			EndpointId: endpoint.Id,
+			EventId:    eventId,
+			Status:     models.WebhookDeliveryPending,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func enqueueWebhookDelivery(tx *gorm.DB, delivery *models.WebhookDelivery) error {
+	if err := tx.
+		Omit("Endpoint", "Event").
+		Create(delivery).Error; err != nil {
+		return err
+	}
+
+	_, err := jobs.Enqueue(tx, DeliverWebhookJob, DeliverWebhookPayload{
+		DeliveryId: delivery.Id,
+	}, jobs.MaxAttempts(webhookMaxAttempts))
+
+	return err
+}
+
+// publishCollectionEvents publishes events of a collection to its buyer.
+func publishCollectionEvents(tx *gorm.DB, collectionId uuid.UUID, eventTypes ...models.WebhookEventType) error {
+	load := func(tx *gorm.DB) (any, error) {
+		var collection models.Collection
+
+		if err := tx.
+			Preload("Materials.Material").
+			Preload("Seller").
+			Preload("Buyer").
+			Preload("Site").
+			Where("id = ?", collectionId).
+			First(&collection).Error; err != nil {
+			return nil, err
+		}
+
+		return collection, nil
+	}
+
+	var buyerId uuid.UUID
+
+	if err := tx.
+		Model(&models.Collection{}).
+		Select("buyer_id").
+		Where("id = ?", collectionId).
+		Scan(&buyerId).Error; err != nil {
+		return err
+	}
+
+	for _, eventType := range eventTypes {
+		if err := publishEvent(tx, eventType, []uuid.UUID{buyerId}, load); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishTransactionEvents publishes events of a transaction to both of its
+// organizations.
+func publishTransactionEvents(tx *gorm.DB, transactionId uuid.UUID, eventTypes ...models.WebhookEventType) error {
+	var transaction models.Transaction
+
+	if err := tx.
+		Select("id", "seller_id", "buyer_id").
+		Where("id = ?", transactionId).
+		First(&transaction).Error; err != nil {
+		return err
+	}
+
+	load := func(tx *gorm.DB) (any, error) {
+		var transaction models.Transaction
+
+		if err := tx.
+			Preload("Materials.Material").
+			Preload("Seller").
+			Preload("Buyer").
+			Preload("Site").
+			Where("id = ?", transactionId).
+			First(&transaction).Error; err != nil {
+			return nil, err
+		}
+
+		return transaction, nil
+	}
+
+	for _, eventType := range eventTypes {
+		if err := publishEvent(tx, eventType, []uuid.UUID{transaction.SellerId, transaction.BuyerId}, load); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishUserCreated publishes the creation of a user to organizations.
+func publishUserCreated(tx *gorm.DB, userId uuid.UUID, organizationIds []uuid.UUID) error {
+	if len(organizationIds) == 0 {
+		return nil
+	}
+
+	return publishEvent(tx, models.UserCreatedEvent, organizationIds, func(tx *gorm.DB) (any, error) {
+		var user models.User
+
+		if err := tx.
+			Where("id = ?", userId).
+			First(&user).Error; err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	})
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/connor-davis/threereco-nextgen/env"
+	"github.com/lib/pq"
+)
+
+// ChangeEventsChannel is the channel change events are announced on.
+const ChangeEventsChannel = "change_events"
+
+// changeEventTriggers record a change event for every insert, update and
+// delete of the tables of resources that changes are streamed for. The first
+// argument of the trigger is the resource and the rest are the columns of the
+// organizations the change is recorded for. An update is recorded for both the
+// old and the new organizations, so that a record moving between
+// organizations is seen to leave one and join the other. Records are deleted
+// by setting deleted_at, which is recorded as a delete, and restored by
+// clearing it, which is recorded as an insert.
+var changeEventTriggers = []string{
+	`
+	CREATE OR REPLACE FUNCTION record_change_event() RETURNS trigger AS $$
+	DECLARE
+		target_organization uuid;
+		target_organizations uuid[] := '{}';
+		target_record uuid;
+		target_action text := lower(TG_OP);
+		event_row change_events%ROWTYPE;
+	BEGIN
+		IF TG_OP = 'UPDATE' THEN
+			IF to_jsonb(NEW) ->> 'deleted_at' IS NOT NULL AND to_jsonb(OLD) ->> 'deleted_at' IS NULL THEN
+				target_action := 'delete';
+			ELSIF to_jsonb(NEW) ->> 'deleted_at' IS NULL AND to_jsonb(OLD) ->> 'deleted_at' IS NOT NULL THEN
+				target_action := 'insert';
+			END IF;
+		END IF;
+
+		FOR i IN 1 .. TG_NARGS - 1 LOOP
+			IF TG_OP <> 'DELETE' THEN
+				target_organization := (to_jsonb(NEW) ->> TG_ARGV[i])::uuid;
+
+				IF target_organization IS NOT NULL AND NOT target_organization = ANY(target_organizations) THEN
+					target_organizations := target_organizations || target_organization;
+				END IF;
+			END IF;
+
+			IF TG_OP <> 'INSERT' THEN
+				target_organization := (to_jsonb(OLD) ->> TG_ARGV[i])::uuid;
+
+				IF target_organization IS NOT NULL AND NOT target_organization = ANY(target_organizations) THEN
+					target_organizations := target_organizations || target_organization;
+				END IF;
+			END IF;
+		END LOOP;
+
+		IF TG_OP = 'DELETE' THEN
+			target_record := OLD.id;
+		ELSE
+			target_record := NEW.id;
+		END IF;
+
+		FOREACH target_organization IN ARRAY target_organizations LOOP
+			INSERT INTO change_events (organization_id, resource, action, record_id, created_at)
+			VALUES (target_organization, TG_ARGV[0], target_action, target_record, now())
+			RETURNING * INTO event_row;
+
+			PERFORM pg_notify('` + ChangeEventsChannel + `', json_build_object(
+				'id', event_row.id,
+				'organizationId', event_row.organization_id,
+				'resource', event_row.resource,
+				'action', event_row.action,
+				'recordId', event_row.record_id,
+				'createdAt', event_row.created_at
+			)::text);
+		END LOOP;
+
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+	`,
+	`DROP TRIGGER IF EXISTS collections_change_events ON collections;`,
+	`
+	CREATE TRIGGER collections_change_events
+	AFTER INSERT OR UPDATE OR DELETE ON collections
+	FOR EACH ROW EXECUTE FUNCTION record_change_event('collections', 'buyer_id');
+	`,
+	`DROP TRIGGER IF EXISTS transactions_change_events ON transactions;`,
+	`
+	CREATE TRIGGER transactions_change_events
+	AFTER INSERT OR UPDATE OR DELETE ON transactions
+	FOR EACH ROW EXECUTE FUNCTION record_change_event('transactions', 'seller_id', 'buyer_id');
+	`,
+	`DROP TRIGGER IF EXISTS inventory_entries_change_events ON inventory_entries;`,
+	`
+	CREATE TRIGGER inventory_entries_change_events
+	AFTER INSERT OR UPDATE OR DELETE ON inventory_entries
+	FOR EACH ROW EXECUTE FUNCTION record_change_event('inventory', 'organization_id');
+	`,
+}
+
+// NewListener returns a listener on its own connection to Postgres, for
+// receiving notifications such as those of change events. It reconnects when
+// the connection is lost, reporting connection events to the callback.
+func (s *Storage) NewListener(callback pq.EventCallbackType) *pq.Listener {
+	return pq.NewListener(string(env.POSTGRES_DSN), time.Second, time.Minute, callback)
+}
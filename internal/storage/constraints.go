@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/connor-davis/threereco-nextgen/internal/models"
+	"gorm.io/gorm"
+)
+
+// restrictedRelationships are the foreign keys that once cascaded deletes
+// through the financial history and now restrict them. AutoMigrate only
+// creates missing constraints, so these are recreated when the rule of the
+// existing constraint differs from the model.
+var restrictedRelationships = []struct {
+	model any
+	field string
+}{
+	{model: &models.Collection{}, field: "Seller"},
+	{model: &models.Collection{}, field: "Buyer"},
+	{model: &models.CollectionMaterial{}, field: "Material"},
+	{model: &models.Transaction{}, field: "Seller"},
+	{model: &models.Transaction{}, field: "Buyer"},
+	{model: &models.TransactionMaterial{}, field: "Material"},
+}
+
+func (s *Storage) syncConstraintRules() error {
+	for _, relationship := range restrictedRelationships {
+		statement := &gorm.Statement{DB: s.Postgres}
+
+		if err := statement.Parse(relationship.model); err != nil {
+			return err
+		}
+
+		relation, ok := statement.Schema.Relationships.Relations[relationship.field]
+
+		if !ok {
+			return fmt.Errorf("%s has no relationship %s", statement.Schema.Name, relationship.field)
+		}
+
+		constraint := relation.ParseConstraint()
+
+		if constraint == nil {
+			continue
+		}
+
+		var deleteRule string
+
+		if err := s.Postgres.
+			Raw("SELECT delete_rule FROM information_schema.referential_constraints WHERE constraint_name = ?", constraint.Name).
+			Scan(&deleteRule).Error; err != nil {
+			return err
+		}
+
+		if deleteRule == "" || deleteRule == constraint.OnDelete {
+			continue
+		}
+
+		if err := s.Postgres.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropConstraint(relationship.model, constraint.Name); err != nil {
+				return err
+			}
+
+			return tx.Migrator().CreateConstraint(relationship.model, relationship.field)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// ErrFileNotFound is returned when no file is stored under a key.
+var ErrFileNotFound = errors.New("file not found")
+
+// FileStore keeps files such as attachments outside the database, under keys
+// of slash separated names.
+type FileStore interface {
+	Put(key string, content []byte, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// ConnectFiles opens the file store at a location, which is either a directory
+// or an s3:// URL of a bucket of an S3-compatible service in the form
+// s3://bucket/prefix?region=af-south-1&endpoint=https://minio.example.com.
+// The credentials of S3 are read from the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables that S3
+// clients share.
+func (s *Storage) ConnectFiles(location string) {
+	files, err := openFileStore(location)
+
+	if err != nil {
+		log.Errorf("🔥 Failed to open file store: %v", err)
+
+		return
+	}
+
+	log.Infof("✅ Storing files in %s", location)
+
+	s.Files = files
+}
+
+func openFileStore(location string) (FileStore, error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return newLocalFileStore(strings.TrimPrefix(location, "file://"))
+	}
+
+	parsed, err := url.Parse(location)
+
+	if err != nil {
+		return nil, err
+	}
+
+	region := parsed.Query().Get("region")
+
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	accessKeyId := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+
+	if accessKeyId == "" || secretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to store files in S3")
+	}
+
+	return newS3FileStore(s3Config{
+		Bucket:          parsed.Host,
+		Prefix:          strings.Trim(parsed.Path, "/"),
+		Region:          region,
+		Endpoint:        parsed.Query().Get("endpoint"),
+		AccessKeyId:     accessKeyId,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	})
+}
+
+// validFileKey reports whether a key only names files below the root of a
+// store.
+func validFileKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return false
+	}
+
+	for _, name := range strings.Split(key, "/") {
+		if name == "" || name == "." || name == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localFileStore keeps files in a directory of the local filesystem.
+type localFileStore struct {
+	directory string
+}
+
+func newLocalFileStore(directory string) (FileStore, error) {
+	if directory == "" {
+		return nil, errors.New("a directory is needed to store files in")
+	}
+
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &localFileStore{
+		directory: directory,
+	}, nil
+}
+
+func (s *localFileStore) path(key string) (string, error) {
+	if !validFileKey(key) {
+		return "", errors.New("invalid file key")
+	}
+
+	return filepath.Join(s.directory, filepath.FromSlash(key)), nil
+}
+
+// Put writes a file to a temporary file first and renames it into place, so
+// that a file is never read while it is partly written.
+func (s *localFileStore) Put(key string, content []byte, contentType string) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temporary.Name())
+
+	if _, err := temporary.Write(content); err != nil {
+		temporary.Close()
+
+		return err
+	}
+
+	if err := temporary.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary.Name(), path)
+}
+
+func (s *localFileStore) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+
+	return file, err
+}
+
+func (s *localFileStore) Delete(key string) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
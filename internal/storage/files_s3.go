@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type s3Config struct {
+	Bucket          string
+	Prefix          string
+	Region          string
+	Endpoint        string
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// s3FileStore keeps files in a bucket of S3 or a service compatible with it,
+// signing its requests with AWS Signature Version 4. Buckets of AWS are
+// addressed by host name, while buckets of a custom endpoint, such as MinIO,
+// are addressed by path.
+type s3FileStore struct {
+	config s3Config
+	base   *url.URL
+	client *http.Client
+}
+
+// s3EmptyPayload is the hash of the empty body of requests without content.
+var s3EmptyPayload = sha256Hex(nil)
+
+func newS3FileStore(config s3Config) (FileStore, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("a bucket is needed to store files in S3")
+	}
+
+	endpoint := config.Endpoint
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", config.Bucket, config.Region)
+	} else {
+		endpoint = strings.TrimSuffix(endpoint, "/") + "/" + config.Bucket
+	}
+
+	base, err := url.Parse(endpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3FileStore{
+		config: config,
+		base:   base,
+		client: &http.Client{
+			Timeout: time.Minute,
+		},
+	}, nil
+}
+
+func (s *s3FileStore) Put(key string, content []byte, contentType string) error {
+	response, err := s.do(http.MethodPut, key, content, contentType)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return s3Error(response)
+}
+
+func (s *s3FileStore) Get(key string) (io.ReadCloser, error) {
+	response, err := s.do(http.MethodGet, key, nil, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+
+		return nil, ErrFileNotFound
+	}
+
+	if err := s3Error(response); err != nil {
+		response.Body.Close()
+
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (s *s3FileStore) Delete(key string) error {
+	response, err := s.do(http.MethodDelete, key, nil, "")
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return s3Error(response)
+}
+
+func (s *s3FileStore) do(method string, key string, content []byte, contentType string) (*http.Response, error) {
+	if !validFileKey(key) {
+		return nil, errors.New("invalid file key")
+	}
+
+	if s.config.Prefix != "" {
+		key = s.config.Prefix + "/" + key
+	}
+
+	target := *s.base
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + key
+	target.RawPath = s3EscapePath(target.Path)
+
+	request, err := http.NewRequest(method, target.String(), bytes.NewReader(content))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(request, content, time.Now().UTC())
+
+	return s.client.Do(request)
+}
+
+// sign adds the headers of AWS Signature Version 4 to a request.
+func (s *s3FileStore) sign(request *http.Request, content []byte, now time.Time) {
+	payloadHash := s3EmptyPayload
+
+	if len(content) > 0 {
+		payloadHash = sha256Hex(content)
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	request.Header.Set("Host", request.URL.Host)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if s.config.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	names := []string{}
+	canonicalHeaders := ""
+
+	for name := range request.Header {
+		names = append(names, strings.ToLower(name))
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		canonicalHeaders += name + ":" + strings.TrimSpace(request.Header.Get(name)) + "\n"
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSha256(key, s.config.Region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyId, scope, signedHeaders, signature,
+	))
+
+	// The Host header is sent from the URL, not the header map.
+	request.Header.Del("Host")
+}
+
+// s3EscapePath escapes a path the way S3 expects it to be signed, leaving
+// only unreserved characters and slashes as they are.
+func s3EscapePath(path string) string {
+	var escaped strings.Builder
+
+	for _, b := range []byte(path) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || strings.IndexByte("-_.~/", b) >= 0 {
+			escaped.WriteByte(b)
+
+			continue
+		}
+
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+
+	return escaped.String()
+}
+
+func s3Error(response *http.Response) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+
+	return fmt.Errorf("s3 responded with %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -12,6 +12,7 @@ import (
 
 type Storage struct {
 	Postgres *gorm.DB
+	Files    FileStore
 }
 
 func New() Storage {
@@ -59,16 +60,85 @@ func (s *Storage) MigratePostgres() {
 		&models.Address{},
 		&models.BankDetails{},
 		&models.Material{},
+		&models.Site{},
+		&models.SiteOperatingHour{},
 		&models.Collection{},
 		&models.CollectionMaterial{},
+		&models.CollectionTransition{},
 		&models.Transaction{},
 		&models.TransactionMaterial{},
+		&models.TransactionTransition{},
+		&models.Invoice{},
+		&models.InvoiceSequence{},
+		&models.InventoryEntry{},
+		&models.PayoutBatch{},
+		&models.PayoutBatchSequence{},
+		&models.Payout{},
+		&models.Pickup{},
+		&models.PickupMaterial{},
+		&models.DailyVolume{},
+		&models.DailyVolumeRefresh{},
+		&models.EprReport{},
+		&models.ImportJob{},
+		&models.Job{},
+		&models.JobSchedule{},
+		&models.WebhookEndpoint{},
+		&models.WebhookEvent{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.ChangeEvent{},
+		&models.Scale{},
+		&models.ScaleReading{},
+		&models.Attachment{},
+		&models.IdempotencyKey{},
 	); err != nil {
 		log.Errorf("❌ AutoMigrate failed: %v", err)
 
 		return
 	}
 
+	// Webhook responses are no longer kept, so the bodies stored before are
+	// removed along with their column.
+	if s.Postgres.Migrator().HasColumn(&models.WebhookDeliveryAttempt{}, "response_body") {
+		if err := s.Postgres.Migrator().DropColumn(&models.WebhookDeliveryAttempt{}, "response_body"); err != nil {
+			log.Errorf("❌ Failed to drop webhook response bodies: %v", err)
+
+			return
+		}
+	}
+
+	// Idempotent responses are replayed with all of their headers, which
+	// replace the content type that was kept on its own before.
+	if s.Postgres.Migrator().HasColumn(&models.IdempotencyKey{}, "content_type") {
+		if err := s.Postgres.Migrator().DropColumn(&models.IdempotencyKey{}, "content_type"); err != nil {
+			log.Errorf("❌ Failed to drop idempotency key content types: %v", err)
+
+			return
+		}
+	}
+
+	if err := s.syncConstraintRules(); err != nil {
+		log.Errorf("❌ Failed to update foreign key constraints: %v", err)
+
+		return
+	}
+
+	for _, statement := range changeEventTriggers {
+		if err := s.Postgres.Exec(statement).Error; err != nil {
+			log.Errorf("❌ Failed to create change event triggers: %v", err)
+
+			return
+		}
+	}
+
+	for _, statement := range versionTriggers {
+		if err := s.Postgres.Exec(statement).Error; err != nil {
+			log.Errorf("❌ Failed to create version triggers: %v", err)
+
+			return
+		}
+	}
+
 	log.Info("✅ Postgres migrations completed successfully")
 }
 
@@ -124,7 +194,7 @@ func (s *Storage) SeedPostgres() {
 		},
 		Name:        "User",
 		Description: &organizationUserRoleDescription,
-		Permissions: []string{"users.view.self", "users.update.self", "users.delete.self"},
+		Permissions: []string{"users.view.self", "users.update.self", "users.delete.self", "collections.view.self"},
 	}
 
 	newUser := models.User{
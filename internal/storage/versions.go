@@ -0,0 +1,34 @@
+package storage
+
+// versionTriggers increase the version of a record whenever it is updated,
+// for every table with a version column, unless the update sets the version
+// itself. Versions are increased by the database so that every update counts,
+// including those made with raw SQL.
+var versionTriggers = []string{
+	`
+	CREATE OR REPLACE FUNCTION increase_version() RETURNS trigger AS $$
+	BEGIN
+		IF NEW.version = OLD.version THEN
+			NEW.version := OLD.version + 1;
+		END IF;
+
+		RETURN NEW;
+	END;
+	$$ LANGUAGE plpgsql;
+	`,
+	`
+	DO $$
+	DECLARE
+		versioned_table text;
+	BEGIN
+		FOR versioned_table IN
+			SELECT table_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND column_name = 'version'
+		LOOP
+			EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', versioned_table || '_version', versioned_table);
+			EXECUTE format('CREATE TRIGGER %I BEFORE UPDATE ON %I FOR EACH ROW EXECUTE FUNCTION increase_version()', versioned_table || '_version', versioned_table);
+		END LOOP;
+	END;
+	$$;
+	`,
+}